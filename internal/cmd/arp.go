@@ -0,0 +1,122 @@
+// Package cmd 提供 ARP/邻居表命令实现
+//
+// 作者: Catsayer
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/neigh"
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	arpScan      bool
+	arpInterface string
+	arpTimeout   float64
+)
+
+// arpCmd 表示 arp 命令
+var arpCmd = &cobra.Command{
+	Use:     "arp",
+	Aliases: []string{"neigh"},
+	Short:   "显示邻居表并发现局域网主机",
+	Long: `显示系统 ARP/邻居表，或主动探测本地子网发现在线主机。
+
+邻居表来源:
+  • Linux: /proc/net/arp
+  • macOS: arp -an
+  • Windows: arp -a
+
+--scan 会向所在子网的每个地址发送 UDP 数据报以触发 ARP 解析，
+无需 root 权限，随后从邻居表收集已解析的主机及其 MAC 与厂商。
+
+示例:
+  # 显示邻居表
+  ntx arp
+
+  # 仅显示 eth0 上的条目
+  ntx arp -i eth0
+
+  # 探测本地子网
+  ntx arp --scan
+
+  # 指定网卡并延长等待时间
+  ntx arp --scan -i wlan0 --timeout 3`,
+	Args: cobra.NoArgs,
+	Run:  runArp,
+}
+
+func init() {
+	rootCmd.AddCommand(arpCmd)
+
+	arpCmd.Flags().BoolVar(&arpScan, "scan", false,
+		"主动探测本地子网")
+	arpCmd.Flags().StringVarP(&arpInterface, "interface", "i", "",
+		"指定网卡")
+	arpCmd.Flags().Float64Var(&arpTimeout, "timeout", 2.0,
+		"探测后等待 ARP 应答的时间（秒）")
+}
+
+func runArp(cmd *cobra.Command, args []string) {
+	appCtx := mustAppContext(cmd)
+	outputFormat := types.OutputFormat(appCtx.Flags.Output)
+	noColor := appCtx.Flags.NoColor
+	reader := neigh.NewReader()
+
+	var (
+		neighbors []*types.Neighbor
+		err       error
+	)
+	if arpScan {
+		neighbors, err = reader.Scan(cmd.Context(), arpInterface, time.Duration(arpTimeout*float64(time.Second)))
+	} else {
+		logger.Info("查询邻居表", zap.String("interface", arpInterface))
+		neighbors, err = reader.List(arpInterface)
+	}
+	if err != nil {
+		logger.Error("获取邻居表失败", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+
+	if outputFormat == types.OutputText || outputFormat == "" {
+		printNeighborsText(neighbors, noColor)
+		return
+	}
+
+	f := formatter.NewFormatter(outputFormat, noColor)
+	output, err := f.Format(neighbors)
+	if err != nil {
+		logger.Error("格式化输出失败", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
+		exit(1)
+	}
+	fmt.Print(output)
+}
+
+func printNeighborsText(neighbors []*types.Neighbor, noColor bool) {
+	if len(neighbors) == 0 {
+		fmt.Println("无邻居条目")
+		return
+	}
+
+	table := formatter.NewTable(
+		[]string{"IP", "MAC", "厂商", "网卡", "状态"},
+		[]int{16, 18, 24, 10, 12},
+	)
+	for _, n := range neighbors {
+		table.AddRow(n.IP, n.MAC, n.Vendor, n.Interface, n.State)
+	}
+	table.Render(os.Stdout)
+
+	printer := termutil.NewColorPrinter(noColor)
+	fmt.Printf("\n%s\n", printer.Muted(fmt.Sprintf("共 %d 条", len(neighbors))))
+}
@@ -2,7 +2,7 @@
 //
 // 本文件实现批量任务命令，支持:
 // - YAML 配置文件驱动
-// - 多种任务类型（ping/dns/scan）
+// - 多种任务类型（ping/dns/scan/http/trace）
 // - 并发执行
 // - 结果汇总
 //
@@ -18,7 +18,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/catsayer/ntx/internal/app"
 	"github.com/catsayer/ntx/internal/core/batch"
@@ -31,8 +34,12 @@ import (
 )
 
 var (
-	batchFile   string
-	batchSample bool
+	batchFile        string
+	batchSample      bool
+	batchDryRun      bool
+	batchValidate    bool
+	batchConcurrency int
+	batchOutDir      string
 )
 
 var batchCmd = &cobra.Command{
@@ -45,13 +52,20 @@ var batchCmd = &cobra.Command{
   • Ping 监控任务
   • DNS 查询任务
   • 端口扫描任务
-  • 并发执行控制
-  • 结果汇总
+  • HTTP 可用性检查任务
+  • 路由追踪任务
+  • 并发执行控制（--concurrency 或 max_concurrency）
+  • 单任务超时（timeout），超时任务记为失败
+  • 结果汇总，可按任务写入结果目录（--out-dir）
 
 示例:
-  ntx batch -f tasks.yaml           # 执行配置文件中的任务
-  ntx batch --sample > tasks.yaml   # 生成示例配置
-  ntx batch -f tasks.yaml -o json   # JSON 输出`,
+  ntx batch -f tasks.yaml                     # 执行配置文件中的任务
+  ntx batch --sample > tasks.yaml             # 生成示例配置
+  ntx batch -f tasks.yaml -o json             # JSON 输出
+  ntx batch -f tasks.yaml --concurrency 8     # 最多同时执行 8 个任务
+  ntx batch -f tasks.yaml --out-dir results/  # 每个任务写入一个 JSON 文件
+  ntx batch -f tasks.yaml --validate          # 仅校验配置文件
+  ntx batch -f tasks.yaml --dry-run           # 仅显示执行计划，不发送探测`,
 	RunE: runBatch,
 }
 
@@ -60,6 +74,10 @@ func init() {
 
 	batchCmd.Flags().StringVarP(&batchFile, "file", "f", "", "任务配置文件路径（YAML 格式）")
 	batchCmd.Flags().BoolVar(&batchSample, "sample", false, "生成示例配置文件")
+	batchCmd.Flags().IntVar(&batchConcurrency, "concurrency", 0, "同时执行的最大任务数（默认取配置文件 max_concurrency，均未配置时为 4）")
+	batchCmd.Flags().StringVar(&batchOutDir, "out-dir", "", "将每个任务的结果写入该目录下以任务名命名的 JSON 文件，并生成 index.json 汇总")
+	batchCmd.Flags().BoolVar(&batchValidate, "validate", false, "仅校验配置文件（未知字段、类型错误、无效任务），不执行任务")
+	batchCmd.Flags().BoolVar(&batchDryRun, "dry-run", false, "仅显示解析后的目标、端口、并发与预计发包数，不执行任务")
 }
 
 func runBatch(cmd *cobra.Command, args []string) error {
@@ -76,13 +94,27 @@ func runBatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("请使用 -f 参数指定任务配置文件")
 	}
 
+	if batchValidate {
+		return validateBatchFile(batchFile)
+	}
+
+	if batchDryRun {
+		plans, err := batch.PlanFile(batchFile)
+		if err != nil {
+			return fmt.Errorf("生成执行计划失败: %w", err)
+		}
+		return outputBatchPlan(plans, appCtx)
+	}
+
 	logger.Info("开始执行批量任务", zap.String("file", batchFile))
 
 	// 创建执行器
 	executor := batch.NewExecutor()
+	executor.SetMaxConcurrency(batchConcurrency)
 
-	// 执行任务
-	ctx := context.Background()
+	// 执行任务，中断时停止后续重复执行并输出已完成的结果
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	result, err := executor.ExecuteFile(ctx, batchFile)
 	if err != nil {
 		return fmt.Errorf("执行批量任务失败: %w", err)
@@ -92,12 +124,35 @@ func runBatch(cmd *cobra.Command, args []string) error {
 	if err := outputBatchResult(result, appCtx); err != nil {
 		return err
 	}
+	if batchOutDir != "" {
+		index, err := batch.WriteResultDir(batchOutDir, result)
+		if err != nil {
+			return fmt.Errorf("写入结果目录失败: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "结果已写入 %s（%d 个任务，汇总见 %s）\n", batchOutDir, len(index.Tasks), batch.IndexFile)
+	}
 	if result.FailedTasks > 0 {
 		return fmt.Errorf("批量任务存在失败项: %d/%d", result.FailedTasks, result.TotalTasks)
 	}
 	return nil
 }
 
+// validateBatchFile 校验配置文件并输出任务数
+func validateBatchFile(path string) error {
+	tasks, err := batch.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	enabled := 0
+	for _, task := range tasks {
+		if task.Enabled {
+			enabled++
+		}
+	}
+	fmt.Printf("%s 校验通过: %d 个任务（%d 个已启用）\n", path, len(tasks), enabled)
+	return nil
+}
+
 // outputBatchResult 输出批量任务结果
 func outputBatchResult(result *batch.BatchResult, appCtx *app.Context) error {
 	output := types.OutputText
@@ -119,6 +174,49 @@ func outputBatchResult(result *batch.BatchResult, appCtx *app.Context) error {
 	return f.FormatTo(os.Stdout, result)
 }
 
+// outputBatchPlan 输出 --dry-run 执行计划
+func outputBatchPlan(plans []*batch.TaskPlan, appCtx *app.Context) error {
+	output := types.OutputFormat(appCtx.Flags.Output)
+	if output != types.OutputText && output != "" {
+		f := formatter.NewFormatter(output, appCtx.Flags.NoColor)
+		return f.FormatTo(os.Stdout, plans)
+	}
+
+	color.NoColor = appCtx.Flags.NoColor
+	fmt.Println(color.CyanString("批量任务执行计划 (dry-run，未发送任何数据包):"))
+	total := 0
+	for _, plan := range plans {
+		fmt.Println()
+		if !plan.Enabled {
+			fmt.Printf("任务: %s (%s) %s\n", plan.Name, plan.Type, color.YellowString("[已禁用]"))
+			continue
+		}
+		fmt.Printf("任务: %s (%s)\n", color.CyanString(plan.Name), plan.Type)
+		for _, target := range plan.Targets {
+			switch {
+			case target.Error != "":
+				fmt.Printf("  目标:     %s %s\n", target.Target, color.RedString("(解析失败: %s)", target.Error))
+			case target.IP != "":
+				fmt.Printf("  目标:     %s (%s)\n", target.Target, target.IP)
+			default:
+				fmt.Printf("  目标:     %s\n", target.Target)
+			}
+		}
+		if plan.Ports != "" {
+			fmt.Printf("  端口:     %s\n", plan.Ports)
+		}
+		fmt.Printf("  并发数:   %d\n", plan.Concurrency)
+		if plan.Runs > 1 {
+			fmt.Printf("  执行次数: %d\n", plan.Runs)
+		}
+		fmt.Printf("  预计发包: %d\n", plan.EstimatedPackets)
+		total += plan.EstimatedPackets
+	}
+	fmt.Println()
+	fmt.Printf("预计总发包: %d\n", total)
+	return nil
+}
+
 // outputBatchText 文本格式输出
 func outputBatchText(result *batch.BatchResult, verbose bool, noColor bool) error {
 	color.NoColor = noColor
@@ -132,8 +230,8 @@ func outputBatchText(result *batch.BatchResult, verbose bool, noColor bool) erro
 	// 显示任务执行结果
 	fmt.Println(">>> 任务执行结果")
 	table := formatter.NewTable(
-		[]string{"任务名称", "类型", "状态", "耗时"},
-		[]int{30, 10, 12, 20},
+		[]string{"任务名称", "类型", "状态", "成功率", "耗时"},
+		[]int{30, 10, 12, 12, 20},
 	)
 	for _, taskResult := range result.TaskResults {
 		statusStr := "✓ 成功"
@@ -143,10 +241,12 @@ func outputBatchText(result *batch.BatchResult, verbose bool, noColor bool) erro
 			statusColor = color.RedString
 		}
 
+		success, runs := taskResult.SuccessRatio()
 		table.AddRow(
 			taskResult.TaskName,
 			string(taskResult.TaskType),
 			statusColor(statusStr),
+			fmt.Sprintf("%d/%d", success, runs),
 			taskResult.Duration.Round(100).String(),
 		)
 
@@ -181,6 +281,12 @@ func outputBatchText(result *batch.BatchResult, verbose bool, noColor bool) erro
 			fmt.Printf("任务: %s\n", color.CyanString(taskResult.TaskName))
 			fmt.Println(strings.Repeat("-", 60))
 
+			// 重复执行的任务按次输出时间序列
+			if len(taskResult.Runs) > 0 {
+				outputTaskRuns(taskResult)
+				continue
+			}
+
 			switch taskResult.TaskType {
 			case batch.TaskTypePing:
 				outputPingTaskDetails(taskResult)
@@ -188,6 +294,10 @@ func outputBatchText(result *batch.BatchResult, verbose bool, noColor bool) erro
 				outputDNSTaskDetails(taskResult)
 			case batch.TaskTypeScan:
 				outputScanTaskDetails(taskResult)
+			case batch.TaskTypeHTTP:
+				outputHTTPTaskDetails(taskResult)
+			case batch.TaskTypeTrace:
+				outputTraceTaskDetails(taskResult)
 			}
 		}
 	}
@@ -195,6 +305,46 @@ func outputBatchText(result *batch.BatchResult, verbose bool, noColor bool) erro
 	return nil
 }
 
+// outputTaskRuns 逐次输出重复任务的执行结果，Ping 任务附带各次的平均丢包率与延迟
+func outputTaskRuns(taskResult *batch.TaskResult) {
+	offset := 0
+	for _, run := range taskResult.Runs {
+		results := taskResult.Results[offset : offset+run.ResultCount]
+		offset += run.ResultCount
+
+		status := color.GreenString("✓")
+		if !run.Success {
+			status = color.RedString("✗")
+		}
+		line := fmt.Sprintf("  #%-3d %s %s  耗时 %s", run.Index, run.StartTime.Format("15:04:05"), status, run.Duration.Round(time.Millisecond))
+		if loss, rtt, ok := pingRunStats(results); ok {
+			line += fmt.Sprintf("  丢包 %.1f%%  平均延迟 %.2fms", loss, float64(rtt.Microseconds())/1000)
+		}
+		if run.Error != nil {
+			line += "  " + color.RedString(run.Error.Error())
+		}
+		fmt.Println(line)
+	}
+}
+
+// pingRunStats 汇总单次执行中各 Ping 目标的平均丢包率与平均延迟
+func pingRunStats(results []interface{}) (loss float64, rtt time.Duration, ok bool) {
+	count := 0
+	for _, r := range results {
+		pingResult, isPing := r.(*types.PingResult)
+		if !isPing || pingResult.Statistics == nil {
+			continue
+		}
+		loss += pingResult.Statistics.LossRate
+		rtt += pingResult.Statistics.AvgRTT
+		count++
+	}
+	if count == 0 {
+		return 0, 0, false
+	}
+	return loss / float64(count), rtt / time.Duration(count), true
+}
+
 // outputPingTaskDetails 输出 Ping 任务详情
 func outputPingTaskDetails(taskResult *batch.TaskResult) {
 	for _, r := range taskResult.Results {
@@ -240,3 +390,29 @@ func outputScanTaskDetails(taskResult *batch.TaskResult) {
 		}
 	}
 }
+
+// outputHTTPTaskDetails 输出 HTTP 任务详情
+func outputHTTPTaskDetails(taskResult *batch.TaskResult) {
+	for _, r := range taskResult.Results {
+		if httpResult, ok := r.(*types.HTTPResult); ok {
+			fmt.Printf("  URL: %s\n", httpResult.URL)
+			fmt.Printf("    状态:     %s\n", httpResult.Status)
+			fmt.Printf("    耗时:     %.2fms\n", float64(httpResult.Duration.Microseconds())/1000)
+		}
+	}
+}
+
+// outputTraceTaskDetails 输出路由追踪任务详情
+func outputTraceTaskDetails(taskResult *batch.TaskResult) {
+	for _, r := range taskResult.Results {
+		if traceResult, ok := r.(*types.TraceResult); ok {
+			fmt.Printf("  目标: %s (%s)\n", traceResult.Target.Hostname, traceResult.Target.IP)
+			fmt.Printf("    跳数:     %d\n", traceResult.HopCount)
+			if traceResult.ReachedDestination {
+				fmt.Printf("    到达目标: 是\n")
+			} else {
+				fmt.Printf("    到达目标: 否\n")
+			}
+		}
+	}
+}
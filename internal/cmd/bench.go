@@ -0,0 +1,215 @@
+// Package cmd 提供 bench 命令实现
+//
+// 本文件实现 ntx 实例之间的带宽测试命令，支持:
+// - TCP 吞吐量与重传统计
+// - UDP 目标带宽发送与丢包统计
+// - 多条并行数据流
+//
+// 使用示例:
+//
+//	ntx bench server
+//	ntx bench client 192.168.1.10 -t 10 -P 4
+//	ntx bench client 192.168.1.10 --udp -b 100M
+//
+// 作者: Catsayer
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/bench"
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	benchPort       int
+	benchDuration   int
+	benchParallel   int
+	benchUDP        bool
+	benchBandwidth  string
+	benchPacketSize int
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "带宽测试",
+	Long: `在两个 ntx 实例之间测试 TCP/UDP 吞吐量。
+
+先在一端启动服务端，再在另一端运行客户端:
+
+示例:
+  ntx bench server                          # 在默认端口 5201 启动服务端
+  ntx bench client 192.168.1.10             # TCP 测试 10 秒
+  ntx bench client 192.168.1.10 -t 30 -P 4  # 4 条并行流测试 30 秒
+  ntx bench client 192.168.1.10 --udp -b 100M  # UDP 以 100 Mbit/s 发送`,
+}
+
+var benchServerCmd = &cobra.Command{
+	Use:          "server",
+	Short:        "启动带宽测试服务端",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runBenchServer,
+}
+
+var benchClientCmd = &cobra.Command{
+	Use:          "client <host>",
+	Short:        "连接服务端执行带宽测试",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runBenchClient,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchServerCmd, benchClientCmd)
+
+	benchCmd.PersistentFlags().IntVar(&benchPort, "port", types.DefaultBenchPort, "服务端端口")
+
+	benchClientCmd.Flags().IntVarP(&benchDuration, "duration", "t", 10, "测试时长（秒）")
+	benchClientCmd.Flags().IntVarP(&benchParallel, "parallel", "P", 1, "并行数据流数量")
+	benchClientCmd.Flags().BoolVarP(&benchUDP, "udp", "u", false, "使用 UDP 测试")
+	benchClientCmd.Flags().StringVarP(&benchBandwidth, "bandwidth", "b", "1M", "UDP 每条流的目标带宽 (bit/s，支持 K/M/G 后缀)")
+	benchClientCmd.Flags().IntVarP(&benchPacketSize, "length", "l", types.DefaultUDPPacketSize, "UDP 数据报大小（字节）")
+}
+
+func runBenchServer(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("带宽测试服务端监听端口 %d，按 Ctrl+C 退出\n", benchPort)
+	return bench.NewServer(benchPort).ListenAndServe(ctx)
+}
+
+func runBenchClient(cmd *cobra.Command, args []string) error {
+	appCtx := mustAppContext(cmd)
+
+	bandwidth, err := parseBandwidth(benchBandwidth)
+	if err != nil {
+		return err
+	}
+
+	opts := types.DefaultBenchOptions()
+	opts.Port = benchPort
+	opts.Duration = time.Duration(benchDuration) * time.Second
+	opts.Parallel = benchParallel
+	opts.Bandwidth = bandwidth
+	opts.PacketSize = benchPacketSize
+	if benchUDP {
+		opts.Protocol = types.ProtocolUDP
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := bench.NewClient(opts).Run(ctx, args[0])
+	if err != nil {
+		logger.Error("带宽测试失败", zap.Error(err))
+		return fmt.Errorf("带宽测试失败: %w", err)
+	}
+
+	outputFormat := types.OutputFormat(appCtx.Flags.Output)
+	if outputFormat == types.OutputText || outputFormat == "" {
+		printBenchText(result, appCtx.Flags.NoColor)
+		return nil
+	}
+
+	f := formatter.NewFormatter(outputFormat, appCtx.Flags.NoColor)
+	return f.FormatTo(os.Stdout, result)
+}
+
+// parseBandwidth 解析 "10M"、"500K"、"1G" 形式的带宽（bit/s）
+func parseBandwidth(s string) (int64, error) {
+	s = strings.TrimSpace(strings.ToUpper(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	switch s[len(s)-1] {
+	case 'K':
+		multiplier = 1_000
+	case 'M':
+		multiplier = 1_000_000
+	case 'G':
+		multiplier = 1_000_000_000
+	}
+	if multiplier > 1 {
+		s = s[:len(s)-1]
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("无效的带宽: %s", s)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// formatBitrate 格式化吞吐量
+func formatBitrate(bps float64) string {
+	switch {
+	case bps >= 1e9:
+		return fmt.Sprintf("%.2f Gbit/s", bps/1e9)
+	case bps >= 1e6:
+		return fmt.Sprintf("%.2f Mbit/s", bps/1e6)
+	case bps >= 1e3:
+		return fmt.Sprintf("%.2f Kbit/s", bps/1e3)
+	default:
+		return fmt.Sprintf("%.0f bit/s", bps)
+	}
+}
+
+func printBenchText(result *types.BenchResult, noColor bool) {
+	printer := termutil.NewColorPrinter(noColor)
+	udp := result.Protocol == types.ProtocolUDP
+
+	fmt.Printf("%s %s 带宽测试: %s\n\n", printer.Bold("==="), strings.ToUpper(string(result.Protocol)), result.Target)
+
+	headers := []string{"流", "时长", "传输量", "吞吐量", "重传"}
+	if udp {
+		headers = []string{"流", "时长", "传输量", "吞吐量", "丢包"}
+	}
+	table := formatter.NewTable(headers, []int{6, 12, 14, 18, 20})
+	for _, s := range result.Streams {
+		last := formatRetransmits(s.Retransmits)
+		if udp {
+			last = fmt.Sprintf("%d/%d (%.1f%%)", s.PacketsSent-s.PacketsReceived, s.PacketsSent, s.LossRate)
+		}
+		table.AddRow(
+			fmt.Sprintf("%d", s.ID),
+			s.Duration.Round(time.Millisecond).String(),
+			formatBytes(uint64(s.Bytes)),
+			formatBitrate(s.BitsPerSecond),
+			last,
+		)
+	}
+	table.Render(os.Stdout)
+
+	fmt.Println()
+	fmt.Printf("总传输量:   %s\n", formatBytes(uint64(result.Bytes)))
+	fmt.Printf("总吞吐量:   %s\n", printer.Success(formatBitrate(result.BitsPerSecond)))
+	if udp {
+		fmt.Printf("丢包:       %d/%d (%.1f%%)\n", result.PacketsLost, result.PacketsSent, result.LossRate)
+	} else {
+		fmt.Printf("重传:       %s\n", formatRetransmits(result.Retransmits))
+	}
+}
+
+func formatRetransmits(n int64) string {
+	if n < 0 {
+		return "n/a"
+	}
+	return strconv.FormatInt(n, 10)
+}
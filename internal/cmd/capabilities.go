@@ -0,0 +1,95 @@
+// Package cmd 提供 capabilities 命令实现
+//
+// 报告当前二进制在当前环境中可用的协议与功能（原始套接字、IPv6 等），
+// 与 version 不同，结果随权限与平台变化，供编排系统决定如何调用 ntx。
+//
+// 使用示例:
+//
+//	ntx capabilities -o json
+//
+// 作者: Catsayer
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/catsayer/ntx/internal/core/diag"
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var capabilitiesCmd = &cobra.Command{
+	Use:     "capabilities",
+	Aliases: []string{"caps"},
+	Short:   "显示当前环境可用的协议与功能",
+	Long: `探测当前二进制在当前环境中可用的协议与功能。
+
+探测项目:
+  • 是否以特权身份运行
+  • ICMP 原始套接字（IPv4/IPv6）是否可用
+  • 主机是否有可用的 IPv4/IPv6 地址
+  • 是否加载了外部 OUI 厂商文件
+  • ping/trace/scan 当前可用的协议
+
+探测只打开并立即关闭本地套接字，不发送任何数据包。
+
+示例:
+  ntx capabilities
+  ntx capabilities -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCapabilities,
+}
+
+func init() {
+	rootCmd.AddCommand(capabilitiesCmd)
+}
+
+func runCapabilities(cmd *cobra.Command, _ []string) error {
+	caps := diag.ProbeCapabilities()
+
+	outputFormat := outputFormatFromCmd(cmd)
+	noColor := noColorFromCmd(cmd)
+	if outputFormat != types.OutputText && outputFormat != "" {
+		return formatter.NewFormatter(outputFormat, noColor).FormatTo(os.Stdout, caps)
+	}
+
+	printer := termutil.NewColorPrinter(noColor)
+	yesNo := func(ok bool) string {
+		if ok {
+			return printer.Success("yes")
+		}
+		return printer.Error("no")
+	}
+
+	fmt.Printf("NTX %s (%s/%s)\n\n", caps.Version, caps.OS, caps.Arch)
+	table := formatter.NewTable([]string{printer.Bold("Feature"), printer.Bold("Available")}, []int{20, 10})
+	table.AddRow("Privileged", yesNo(caps.Privileged))
+	table.AddRow("Raw ICMPv4 socket", yesNo(caps.RawICMPv4))
+	table.AddRow("Raw ICMPv6 socket", yesNo(caps.RawICMPv6))
+	table.AddRow("Datagram ICMPv4", yesNo(caps.DatagramICMPv4))
+	table.AddRow("IPv4 address", yesNo(caps.IPv4))
+	table.AddRow("IPv6 address", yesNo(caps.IPv6))
+	table.AddRow("Custom OUI file", yesNo(caps.CustomOUI))
+	table.Render(os.Stdout)
+	fmt.Println()
+
+	commands := make([]string, 0, len(caps.Protocols))
+	for name := range caps.Protocols {
+		commands = append(commands, name)
+	}
+	sort.Strings(commands)
+	fmt.Println("Protocols:")
+	for _, name := range commands {
+		protocols := strings.Join(caps.Protocols[name], ", ")
+		if protocols == "" {
+			protocols = printer.Error("none (requires root or CAP_NET_RAW)")
+		}
+		fmt.Printf("  %-6s %s\n", name, protocols)
+	}
+	return nil
+}
@@ -1,21 +1,34 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/catsayer/ntx/internal/core/netstat"
+	"github.com/catsayer/ntx/internal/output/formatter"
 	"github.com/catsayer/ntx/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 var (
-	connTCP     bool
-	connUDP     bool
-	connListen  bool
-	connProcess bool
-	connState   string
-	connPort    int
-	connStats   bool
+	connTCP      bool
+	connUDP      bool
+	connListen   bool
+	connProcess  bool
+	connState    string
+	connPort     int
+	connStats    bool
+	connSummary  bool
+	connLimit    int
+	connResolve  bool
+	connColumns  string
+	connWide     bool
+	connWatch    bool
+	connInterval time.Duration
+	connCount    int
 )
 
 var connCmd = &cobra.Command{
@@ -47,15 +60,37 @@ var connCmd = &cobra.Command{
   # 显示进程信息 (需要 root)
   ntx conn --process
 
-  # 按状态过滤
+  # 按状态过滤，多个状态以逗号分隔（不区分大小写）
   ntx conn --state ESTABLISHED
+  ntx conn --state established,time_wait
 
   # 按端口过滤
   ntx conn --port 80
 
+  # 将远程地址反向解析为主机名
+  ntx conn --resolve
+
+  # 仅显示前 50 条连接
+  ntx conn --limit 50
+
+  # 自定义表格列及顺序
+  ntx conn --columns proto,local,remote,state,pid
+
+  # 不截断过长的地址（如 IPv6），便于管道处理
+  ntx conn --wide
+
   # 显示统计信息
   ntx conn --stats
 
+  # 统计信息附加协议栈计数器（分段、重传、错误等，仅 Linux）
+  ntx conn --summary
+
+  # 每 2 秒原地刷新，Ctrl+C 退出
+  ntx conn --watch --interval 2s
+
+  # 刷新 5 次后退出，JSON 输出每次一行
+  ntx conn --watch --count 5 -o json
+
   # JSON 输出
   ntx conn -o json`,
 	Run: runConn,
@@ -70,14 +105,32 @@ func init() {
 		"仅显示 UDP 连接")
 	connCmd.Flags().BoolVarP(&connListen, "listen", "l", false,
 		"仅显示监听端口")
+	connCmd.Flags().BoolVar(&connListen, "listening", false,
+		"同 --listen")
 	connCmd.Flags().BoolVarP(&connProcess, "process", "p", false,
 		"显示进程信息 (需要 root 权限)")
 	connCmd.Flags().StringVar(&connState, "state", "",
-		"按状态过滤 (ESTABLISHED, LISTEN, TIME_WAIT 等)")
+		"按状态过滤，逗号分隔 (ESTABLISHED, LISTEN, TIME_WAIT 等)")
 	connCmd.Flags().IntVar(&connPort, "port", 0,
 		"按端口过滤")
 	connCmd.Flags().BoolVar(&connStats, "stats", false,
 		"显示统计信息")
+	connCmd.Flags().BoolVar(&connSummary, "summary", false,
+		"显示统计信息及 TCP/UDP 协议栈计数器（分段、重传、错误等，仅 Linux）")
+	connCmd.Flags().IntVar(&connLimit, "limit", 0,
+		"最多显示 N 条（过滤后），0 表示不限制")
+	connCmd.Flags().BoolVarP(&connResolve, "resolve", "r", false,
+		"将远程地址反向解析为主机名")
+	connCmd.Flags().StringVar(&connColumns, "columns", "",
+		"表格显示的列及顺序，逗号分隔 (proto,local,remote,state,pid,process；仅文本输出)")
+	connCmd.Flags().BoolVar(&connWide, "wide", false,
+		"不截断过长的单元格，行宽可超出终端宽度（便于管道处理）")
+	connCmd.Flags().BoolVarP(&connWatch, "watch", "w", false,
+		"持续刷新输出，直到 Ctrl+C（JSON 输出每次刷新一行）")
+	connCmd.Flags().DurationVar(&connInterval, "interval", 2*time.Second,
+		"--watch 的刷新间隔")
+	connCmd.Flags().IntVar(&connCount, "count", 0,
+		"--watch 刷新 N 次后退出，0 表示不限制")
 }
 
 func runConn(cmd *cobra.Command, args []string) {
@@ -86,24 +139,50 @@ func runConn(cmd *cobra.Command, args []string) {
 
 	outputFormat := outputFormatFromCmd(cmd)
 	noColor := noColorFromCmd(cmd)
+	formatter.SetWideTables(connWide)
+
+	opts, err := buildConnOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+
+	if connWatch {
+		runConnWatch(cmd, reader, opts, outputFormat, noColor)
+		return
+	}
 
-	if connStats {
+	if connStats || connSummary {
 		runConnStats(reader, outputFormat, noColor)
 		return
 	}
 
-	opts := buildConnOptions()
+	if cmd.Flags().Changed("columns") && connStats {
+		fmt.Fprintln(os.Stderr, "错误: --columns 不能与 --stats 同时使用")
+		exit(1)
+	}
+
 	if connListen {
-		runConnListeners(reader, opts, outputFormat, noColor)
+		cols, err := listenerColumns()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+		runConnListeners(reader, opts, cols, outputFormat, noColor)
 	} else {
-		runConnConnections(reader, opts, outputFormat, noColor)
+		cols, err := connectionColumns()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+		runConnConnections(cmd.Context(), reader, opts, cols, outputFormat, noColor)
 	}
 }
 
-func buildConnOptions() *types.NetStatOptions {
+func buildConnOptions() (*types.NetStatOptions, error) {
 	opts := &types.NetStatOptions{
 		Protocol:       "all",
-		IncludeProcess: connProcess,
+		IncludeProcess: connProcess || columnsNeedProcess(connColumns),
 		ListenOnly:     connListen,
 	}
 
@@ -118,10 +197,39 @@ func buildConnOptions() *types.NetStatOptions {
 	}
 
 	if connState != "" {
-		opts.State = []types.ConnectionState{
-			types.ConnectionState(strings.ToUpper(connState)),
+		states, err := parseConnStates(connState)
+		if err != nil {
+			return nil, err
 		}
+		opts.State = states
 	}
 
-	return opts
+	return opts, nil
+}
+
+// parseConnStates 解析逗号分隔的连接状态，不区分大小写，"-" 视同 "_"
+func parseConnStates(spec string) ([]types.ConnectionState, error) {
+	known := types.ConnectionStates()
+	var states []types.ConnectionState
+	for _, part := range strings.Split(spec, ",") {
+		name := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(part), "-", "_"))
+		if name == "" {
+			continue
+		}
+		state := types.ConnectionState(name)
+		if !slices.Contains(known, state) {
+			valid := make([]string, len(known))
+			for i, s := range known {
+				valid[i] = string(s)
+			}
+			return nil, fmt.Errorf("未知的连接状态 %q，可选: %s", strings.TrimSpace(part), strings.Join(valid, ", "))
+		}
+		if !slices.Contains(states, state) {
+			states = append(states, state)
+		}
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("--state 不能为空")
+	}
+	return states, nil
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+func TestParseConnStates(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []types.ConnectionState
+	}{
+		{"ESTABLISHED", []types.ConnectionState{types.StateEstablished}},
+		{"established,time_wait", []types.ConnectionState{types.StateEstablished, types.StateTimeWait}},
+		{" Listen , close-wait ", []types.ConnectionState{types.StateListen, types.StateCloseWait}},
+		{"listen,LISTEN,", []types.ConnectionState{types.StateListen}},
+	}
+	for _, tt := range tests {
+		got, err := parseConnStates(tt.spec)
+		if err != nil {
+			t.Fatalf("parseConnStates(%q) error: %v", tt.spec, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("parseConnStates(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseConnStatesInvalid(t *testing.T) {
+	_, err := parseConnStates("ESTABLISHED,TIMEWAIT")
+	if err == nil {
+		t.Fatal("parseConnStates() expected error for unknown state")
+	}
+	// 错误信息应指出拼错的状态并列出可选值
+	for _, want := range []string{`"TIMEWAIT"`, "TIME_WAIT", "ESTABLISHED", "LISTEN"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "UNKNOWN") {
+		t.Fatalf("error %q should not list UNKNOWN", err)
+	}
+
+	if _, err := parseConnStates(" , "); err == nil {
+		t.Fatal("parseConnStates() expected error for empty spec")
+	}
+}
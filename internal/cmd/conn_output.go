@@ -10,105 +10,51 @@ import (
 	"github.com/catsayer/ntx/pkg/types"
 )
 
-func printConnectionsText(connections []*types.Connection, noColor bool) {
-	if len(connections) == 0 {
+func printConnectionsText(connections []*types.Connection, cols []formatter.Column[*types.Connection], omitted int, noColor bool) {
+	if len(connections) == 0 && omitted == 0 {
 		fmt.Println("无网络连接")
 		return
 	}
-
-	printer := termutil.NewColorPrinter(noColor)
-	bold := printer.Bold
-
-	var table *formatter.Table
-	if connProcess {
-		table = formatter.NewTable(
-			[]string{bold("Proto"), bold("Local Address"), bold("Remote Address"), bold("State"), bold("PID"), bold("Process")},
-			[]int{8, 23, 23, 12, 8, 20},
-		)
-	} else {
-		table = formatter.NewTable(
-			[]string{bold("Proto"), bold("Local Address"), bold("Remote Address"), bold("State")},
-			[]int{8, 23, 23, 12},
-		)
-	}
-
-	for _, conn := range connections {
-		localAddr := fmt.Sprintf("%s:%d", conn.LocalAddr, conn.LocalPort)
-		remoteAddr := fmt.Sprintf("%s:%d", conn.RemoteAddr, conn.RemotePort)
-
-		if connProcess {
-			processInfo := "-"
-			if conn.PID > 0 {
-				processInfo = fmt.Sprintf("%d/%s", conn.PID, conn.ProcessName)
-			}
-			table.AddRow(
-				conn.Protocol,
-				localAddr,
-				remoteAddr,
-				string(conn.State),
-				fmt.Sprintf("%d", conn.PID),
-				processInfo,
-			)
-		} else {
-			table.AddRow(
-				conn.Protocol,
-				localAddr,
-				remoteAddr,
-				string(conn.State),
-			)
-		}
-	}
-
-	table.Render(os.Stdout)
-	fmt.Printf("\nTotal: %d connections\n", len(connections))
+	fmt.Print(formatter.RenderColumns(connections, cols, noColor))
+	printOmitted(os.Stdout, omitted)
+	fmt.Printf("\nTotal: %d connections\n", len(connections)+omitted)
 }
 
-func printListenersText(listeners []*types.Listener, noColor bool) {
-	if len(listeners) == 0 {
+func printListenersText(listeners []*types.Listener, cols []formatter.Column[*types.Listener], omitted int, noColor bool) {
+	if len(listeners) == 0 && omitted == 0 {
 		fmt.Println("无监听端口")
 		return
 	}
+	fmt.Print(formatter.RenderColumns(listeners, cols, noColor))
+	printOmitted(os.Stdout, omitted)
+	fmt.Printf("\nTotal: %d listeners\n", len(listeners)+omitted)
+}
 
-	printer := termutil.NewColorPrinter(noColor)
-	bold := printer.Bold
-
-	var table *formatter.Table
-	if connProcess {
-		table = formatter.NewTable(
-			[]string{bold("Proto"), bold("Local Address"), bold("PID"), bold("Process")},
-			[]int{8, 23, 8, 20},
-		)
-	} else {
-		table = formatter.NewTable(
-			[]string{bold("Proto"), bold("Local Address")},
-			[]int{8, 23},
-		)
+// connectionColumns 返回连接表格的列：未指定 --columns 时使用默认布局
+func connectionColumns() ([]formatter.Column[*types.Connection], error) {
+	if connColumns == "" {
+		return formatter.DefaultConnectionColumns(connProcess), nil
 	}
+	return formatter.SelectColumns(formatter.ConnectionColumns, connColumns)
+}
 
-	for _, listener := range listeners {
-		localAddr := fmt.Sprintf("%s:%d", listener.Addr, listener.Port)
+// listenerColumns 返回监听端口表格的列：未指定 --columns 时使用默认布局
+func listenerColumns() ([]formatter.Column[*types.Listener], error) {
+	if connColumns == "" {
+		return formatter.DefaultListenerColumns(connProcess), nil
+	}
+	return formatter.SelectColumns(formatter.ListenerColumns, connColumns)
+}
 
-		if connProcess {
-			processInfo := "-"
-			if listener.PID > 0 {
-				processInfo = fmt.Sprintf("%d/%s", listener.PID, listener.ProcessName)
-			}
-			table.AddRow(
-				listener.Protocol,
-				localAddr,
-				fmt.Sprintf("%d", listener.PID),
-				processInfo,
-			)
-		} else {
-			table.AddRow(
-				listener.Protocol,
-				localAddr,
-			)
+// columnsNeedProcess 判断 --columns 是否包含进程相关列
+func columnsNeedProcess(spec string) bool {
+	for _, name := range strings.Split(spec, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "pid", "process":
+			return true
 		}
 	}
-
-	table.Render(os.Stdout)
-	fmt.Printf("\nTotal: %d listeners\n", len(listeners))
+	return false
 }
 
 func printStatsText(stats *types.NetStatistics, noColor bool) {
@@ -128,4 +74,50 @@ func printStatsText(stats *types.NetStatistics, noColor bool) {
 	fmt.Printf("UDP Connections: %s\n", green(stats.UDPTotal))
 	fmt.Println()
 	fmt.Printf("Total Connections: %s\n", bold(green(stats.TotalConnections)))
+
+	if connSummary {
+		printStackCountersText(stats.Counters, noColor)
+	}
+}
+
+// printStackCountersText 输出协议栈计数器，平台不支持时给出提示
+func printStackCountersText(c *types.StackCounters, noColor bool) {
+	printer := termutil.NewColorPrinter(noColor)
+	fmt.Println()
+	fmt.Println(printer.Bold("Protocol Counters"))
+	fmt.Println(strings.Repeat("-", 40))
+	if c == nil {
+		fmt.Println(printer.Muted("  当前平台不提供协议栈计数器"))
+		return
+	}
+
+	// 错误类计数非零时高亮
+	warn := func(v uint64) string {
+		if v > 0 {
+			return printer.Warning(v)
+		}
+		return fmt.Sprint(v)
+	}
+
+	fmt.Printf("TCP:\n")
+	fmt.Printf("  Active Opens:      %d\n", c.TCPActiveOpens)
+	fmt.Printf("  Passive Opens:     %d\n", c.TCPPassiveOpens)
+	fmt.Printf("  Segments In:       %d\n", c.TCPInSegs)
+	fmt.Printf("  Segments Out:      %d\n", c.TCPOutSegs)
+	fmt.Printf("  Retransmitted:     %s\n", warn(c.TCPRetransSegs))
+	fmt.Printf("  Timeouts:          %s\n", warn(c.TCPTimeouts))
+	fmt.Printf("  In Errors:         %s\n", warn(c.TCPInErrs))
+	fmt.Printf("  Resets Sent:       %d\n", c.TCPOutRsts)
+	fmt.Printf("  Failed Attempts:   %s\n", warn(c.TCPAttemptFails))
+	fmt.Printf("  Estab Resets:      %d\n", c.TCPEstabResets)
+	fmt.Printf("  Listen Overflows:  %s\n", warn(c.TCPListenOverflows))
+	fmt.Printf("  Listen Drops:      %s\n", warn(c.TCPListenDrops))
+	fmt.Println()
+	fmt.Printf("UDP:\n")
+	fmt.Printf("  Datagrams In:      %d\n", c.UDPInDatagrams)
+	fmt.Printf("  Datagrams Out:     %d\n", c.UDPOutDatagrams)
+	fmt.Printf("  No Port:           %d\n", c.UDPNoPorts)
+	fmt.Printf("  In Errors:         %s\n", warn(c.UDPInErrors))
+	fmt.Printf("  Rcvbuf Errors:     %s\n", warn(c.UDPRcvbufErrors))
+	fmt.Printf("  Sndbuf Errors:     %s\n", warn(c.UDPSndbufErrors))
 }
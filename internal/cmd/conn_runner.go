@@ -1,28 +1,37 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
+	"sync"
 
 	"github.com/catsayer/ntx/internal/core/netstat"
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"go.uber.org/zap"
 )
 
-func runConnConnections(reader *netstat.NetStatReader, opts *types.NetStatOptions, outputFormat types.OutputFormat, noColor bool) {
+func runConnConnections(ctx context.Context, reader *netstat.NetStatReader, opts *types.NetStatOptions, cols []formatter.Column[*types.Connection], outputFormat types.OutputFormat, noColor bool) {
 	logger.Info("查询网络连接")
 
 	connections, err := reader.GetConnections(opts)
 	if err != nil {
 		logger.Error("获取连接列表失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
+	}
+
+	connections, omitted := limitItems(connections, connLimit)
+	if connResolve {
+		resolveRemoteHosts(ctx, connections)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
-		printConnectionsText(connections, noColor)
+		printConnectionsText(connections, cols, omitted, noColor)
 		return
 	}
 
@@ -31,23 +40,46 @@ func runConnConnections(reader *netstat.NetStatReader, opts *types.NetStatOption
 	if err != nil {
 		logger.Error("格式化输出失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	fmt.Print(output)
+	// 结构化输出保持可解析，截断提示写入 stderr
+	printOmitted(os.Stderr, omitted)
 }
 
-func runConnListeners(reader *netstat.NetStatReader, opts *types.NetStatOptions, outputFormat types.OutputFormat, noColor bool) {
+// resolveRemoteHosts 并发反向解析远程地址，未指定地址与解析失败的条目保持不变
+func resolveRemoteHosts(ctx context.Context, connections []*types.Connection) {
+	var wg sync.WaitGroup
+	for _, conn := range connections {
+		ip := net.ParseIP(conn.RemoteAddr)
+		if ip == nil || ip.IsUnspecified() {
+			continue
+		}
+		wg.Add(1)
+		go func(conn *types.Connection) {
+			defer wg.Done()
+			if name, ok := netutil.ReverseLookup(ctx, conn.RemoteAddr); ok {
+				conn.RemoteHost = name
+			}
+		}(conn)
+	}
+	wg.Wait()
+}
+
+func runConnListeners(reader *netstat.NetStatReader, opts *types.NetStatOptions, cols []formatter.Column[*types.Listener], outputFormat types.OutputFormat, noColor bool) {
 	logger.Info("查询监听端口")
 
 	listeners, err := reader.GetListeners(opts)
 	if err != nil {
 		logger.Error("获取监听端口失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
+	listeners, omitted := limitItems(listeners, connLimit)
+
 	if outputFormat == types.OutputText || outputFormat == "" {
-		printListenersText(listeners, noColor)
+		printListenersText(listeners, cols, omitted, noColor)
 		return
 	}
 
@@ -56,19 +88,21 @@ func runConnListeners(reader *netstat.NetStatReader, opts *types.NetStatOptions,
 	if err != nil {
 		logger.Error("格式化输出失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	fmt.Print(output)
+	// 结构化输出保持可解析，截断提示写入 stderr
+	printOmitted(os.Stderr, omitted)
 }
 
 func runConnStats(reader *netstat.NetStatReader, outputFormat types.OutputFormat, noColor bool) {
 	logger.Info("查询连接统计")
 
-	stats, err := reader.GetStatistics()
+	stats, err := getConnStats(reader)
 	if err != nil {
 		logger.Error("获取统计信息失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
@@ -81,7 +115,19 @@ func runConnStats(reader *netstat.NetStatReader, outputFormat types.OutputFormat
 	if err != nil {
 		logger.Error("格式化输出失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	fmt.Print(output)
 }
+
+// getConnStats 读取统计信息，协议栈计数器仅在 --summary 时保留
+func getConnStats(reader *netstat.NetStatReader) (*types.NetStatistics, error) {
+	stats, err := reader.GetStatistics()
+	if err != nil {
+		return nil, err
+	}
+	if !connSummary {
+		stats.Counters = nil
+	}
+	return stats, nil
+}
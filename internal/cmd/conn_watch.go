@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/netstat"
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// connFrame --watch 模式下一次刷新的结果，JSON 输出时每行一个
+type connFrame struct {
+	Time        time.Time            `json:"time"`
+	Connections []*types.Connection  `json:"connections,omitempty"`
+	Listeners   []*types.Listener    `json:"listeners,omitempty"`
+	Stats       *types.NetStatistics `json:"stats,omitempty"`
+
+	omitted int
+}
+
+// watchLoop 立即执行一次 fn，此后每隔 interval 执行一次，直到 ctx 取消或执行满 count 次
+//
+// count <= 0 表示不限次数；after 提供定时器，测试中可替换为假时钟。
+func watchLoop(ctx context.Context, interval time.Duration, count int, after func(time.Duration) <-chan time.Time, fn func(iteration int) error) error {
+	for i := 1; ; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+		if count > 0 && i >= count {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-after(interval):
+		}
+	}
+}
+
+// runConnWatch 周期刷新连接、监听端口或统计信息
+func runConnWatch(cmd *cobra.Command, reader *netstat.NetStatReader, opts *types.NetStatOptions, outputFormat types.OutputFormat, noColor bool) {
+	if outputFormat != types.OutputText && outputFormat != "" && outputFormat != types.OutputJSON {
+		fmt.Fprintln(os.Stderr, "错误: --watch 仅支持 text 与 json 输出")
+		exit(1)
+	}
+	if connInterval <= 0 {
+		fmt.Fprintln(os.Stderr, "错误: --interval 必须大于 0")
+		exit(1)
+	}
+
+	connCols, err := connectionColumns()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+	listenCols, err := listenerColumns()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	encoder := json.NewEncoder(os.Stdout)
+	err = watchLoop(ctx, connInterval, connCount, time.After, func(iteration int) error {
+		frame, err := collectConnFrame(ctx, reader, opts)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == types.OutputJSON {
+			return encoder.Encode(frame)
+		}
+
+		termutil.ClearScreen(os.Stdout)
+		fmt.Printf("每 %s 刷新 · %s · 第 %d 次（Ctrl+C 退出）\n\n", connInterval, frame.Time.Format("15:04:05"), iteration)
+		switch {
+		case connStats || connSummary:
+			printStatsText(frame.Stats, noColor)
+		case connListen:
+			printListenersText(frame.Listeners, listenCols, frame.omitted, noColor)
+		default:
+			printConnectionsText(frame.Connections, connCols, frame.omitted, noColor)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+}
+
+// collectConnFrame 按当前模式读取一次数据
+func collectConnFrame(ctx context.Context, reader *netstat.NetStatReader, opts *types.NetStatOptions) (*connFrame, error) {
+	frame := &connFrame{Time: time.Now()}
+	var err error
+
+	switch {
+	case connStats || connSummary:
+		frame.Stats, err = getConnStats(reader)
+	case connListen:
+		frame.Listeners, err = reader.GetListeners(opts)
+		frame.Listeners, frame.omitted = limitItems(frame.Listeners, connLimit)
+	default:
+		frame.Connections, err = reader.GetConnections(opts)
+		frame.Connections, frame.omitted = limitItems(frame.Connections, connLimit)
+		if err == nil && connResolve {
+			resolveRemoteHosts(ctx, frame.Connections)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
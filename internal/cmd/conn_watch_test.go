@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock 手动触发的定时器，记录每次等待的时长
+type fakeClock struct {
+	ticks  chan time.Time
+	waited []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{ticks: make(chan time.Time)}
+}
+
+func (c *fakeClock) after(d time.Duration) <-chan time.Time {
+	c.waited = append(c.waited, d)
+	return c.ticks
+}
+
+func TestWatchLoopStopsAfterCount(t *testing.T) {
+	clock := newFakeClock()
+	done := make(chan error, 1)
+	var iterations []int
+	go func() {
+		done <- watchLoop(context.Background(), 2*time.Second, 3, clock.after, func(i int) error {
+			iterations = append(iterations, i)
+			return nil
+		})
+	}()
+
+	clock.ticks <- time.Now()
+	clock.ticks <- time.Now()
+	if err := <-done; err != nil {
+		t.Fatalf("watchLoop() error: %v", err)
+	}
+	if len(iterations) != 3 || iterations[2] != 3 {
+		t.Fatalf("iterations = %v, want [1 2 3]", iterations)
+	}
+	// 最后一次执行后不再等待
+	if len(clock.waited) != 2 || clock.waited[0] != 2*time.Second {
+		t.Fatalf("waited = %v, want two waits of 2s", clock.waited)
+	}
+}
+
+func TestWatchLoopStopsOnCancel(t *testing.T) {
+	clock := newFakeClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	calls := make(chan int, 10)
+	go func() {
+		done <- watchLoop(ctx, time.Second, 0, clock.after, func(i int) error {
+			calls <- i
+			return nil
+		})
+	}()
+
+	<-calls
+	clock.ticks <- time.Now()
+	<-calls
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watchLoop() error after cancel: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watchLoop() did not stop after context cancel")
+	}
+	if len(calls) != 0 {
+		t.Fatalf("unexpected extra iterations after cancel: %d", len(calls))
+	}
+}
+
+func TestWatchLoopReturnsError(t *testing.T) {
+	clock := newFakeClock()
+	boom := errors.New("boom")
+	err := watchLoop(context.Background(), time.Second, 0, clock.after, func(int) error { return boom })
+	if !errors.Is(err, boom) {
+		t.Fatalf("watchLoop() error = %v, want %v", err, boom)
+	}
+	if len(clock.waited) != 0 {
+		t.Fatalf("waited = %v, want no waits after error", clock.waited)
+	}
+}
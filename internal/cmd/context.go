@@ -9,10 +9,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// exitCodeThreshold 健康检查阈值（丢包、延迟、状态码等）未满足时的退出码
+const exitCodeThreshold = 2
+
 func mustAppContext(cmd *cobra.Command) *app.Context {
 	if cmd == nil {
 		fmt.Fprintln(os.Stderr, "命令未初始化")
-		os.Exit(1)
+		exit(1)
 	}
 
 	if ctx, ok := app.FromContext(cmd.Context()); ok && ctx != nil {
@@ -26,7 +29,7 @@ func mustAppContext(cmd *cobra.Command) *app.Context {
 	}
 
 	fmt.Fprintln(os.Stderr, "应用上下文未初始化")
-	os.Exit(1)
+	exit(1)
 	return nil
 }
 
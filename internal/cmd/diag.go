@@ -31,10 +31,13 @@ import (
 )
 
 var (
-	diagFast   bool
-	diagFull   bool
-	diagTarget string
-	diagReport bool
+	diagFast    bool
+	diagFull    bool
+	diagTarget  string
+	diagReport  bool
+	diagCaptive string
+	diagOnly    []string
+	diagSkip    []string
 )
 
 var diagCmd = &cobra.Command{
@@ -46,16 +49,30 @@ var diagCmd = &cobra.Command{
   • 网络接口配置检查
   • 本地连通性测试（网关）
   • 互联网连通性测试
-  • DNS 解析测试
+  • 强制门户（Wi-Fi 登录页）检测
+  • 路径 MTU 检测
+  • DNS 解析测试与解析器比对
   • 目标主机可达性测试（可选）
   • 问题分析和修复建议
 
+诊断级别:
+  --fast   仅检查网络接口、网关与 DNS 解析，不访问公网
+  默认     增加公网连通性、强制门户、路径 MTU、解析器比对与目标主机测试
+  --full   再增加 20 次持续 Ping 的丢包/抖动采样与 HTTP 首字节时间测量
+
+检查项（用于 --only/--skip）:
+  interfaces, gateway, internet, captive-portal, mtu, dns,
+  dns-consistency, latency, http
+
 示例:
   ntx diag                          # 标准诊断
   ntx diag --fast                   # 快速诊断
   ntx diag --full                   # 完整诊断
   ntx diag --target google.com      # 包含目标主机测试
   ntx diag --report                 # 生成详细报告
+  ntx diag --captive-url http://example.com/204  # 自定义强制门户检测地址
+  ntx diag --only dns,dns-consistency  # 只检查 DNS
+  ntx diag --skip mtu               # 跳过路径 MTU 检测
   ntx diag -o json                  # JSON 输出`,
 	RunE: runDiag,
 }
@@ -63,31 +80,40 @@ var diagCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(diagCmd)
 
-	diagCmd.Flags().BoolVar(&diagFast, "fast", false, "快速诊断模式")
-	diagCmd.Flags().BoolVar(&diagFull, "full", false, "完整诊断模式")
+	diagCmd.Flags().BoolVar(&diagFast, "fast", false, "快速诊断模式（不访问公网）")
+	diagCmd.Flags().BoolVar(&diagFull, "full", false, "完整诊断模式（包含丢包、抖动与 HTTP 响应时间测量）")
 	diagCmd.Flags().StringVar(&diagTarget, "target", "", "指定目标主机进行额外测试")
 	diagCmd.Flags().BoolVar(&diagReport, "report", false, "生成详细报告")
+	diagCmd.Flags().StringVar(&diagCaptive, "captive-url", "", "强制门户检测的控制地址，需返回 204（默认读取配置 diag.captive_portal_url）")
+	diagCmd.Flags().StringSliceVar(&diagOnly, "only", nil, "只执行指定的检查项（逗号分隔，忽略诊断级别）")
+	diagCmd.Flags().StringSliceVar(&diagSkip, "skip", nil, "跳过指定的检查项（逗号分隔）")
+	diagCmd.MarkFlagsMutuallyExclusive("fast", "full")
 }
 
 func runDiag(cmd *cobra.Command, args []string) error {
-	flags := mustAppContext(cmd).Flags
+	appCtx := mustAppContext(cmd)
+	flags := appCtx.Flags
 	outputFormat := types.OutputFormat(flags.Output)
 	logger.Info("开始网络诊断")
 
 	// 构建诊断选项
 	opts := diag.DiagnosticOptions{
-		Level:  diag.DiagLevelNormal,
+		Level:  diagLevel(diagFast, diagFull),
 		Target: diagTarget,
-	}
-
-	if diagFast {
-		opts.Level = diag.DiagLevelFast
-	} else if diagFull {
-		opts.Level = diag.DiagLevelFull
+		Only:   diagOnly,
+		Skip:   diagSkip,
 	}
 
 	// 创建诊断服务
 	diagService := diag.NewService()
+	captiveURL := diagCaptive
+	if captiveURL == "" && appCtx.Config != nil {
+		captiveURL = appCtx.Config.Diag.CaptivePortalURL
+	}
+	diagService.SetCaptivePortalURL(captiveURL)
+	if _, err := diagService.SelectChecks(opts); err != nil {
+		return err
+	}
 
 	// 仅文本模式显示 banner，避免污染结构化输出
 	if outputFormat == types.OutputText || outputFormat == "" {
@@ -107,8 +133,19 @@ func runDiag(cmd *cobra.Command, args []string) error {
 	return outputDiagResult(result, flags)
 }
 
+// diagLevel 将 --fast/--full 映射为诊断级别，均未指定时为标准诊断
+func diagLevel(fast, full bool) diag.DiagnosticLevel {
+	switch {
+	case fast:
+		return diag.DiagLevelFast
+	case full:
+		return diag.DiagLevelFull
+	}
+	return diag.DiagLevelNormal
+}
+
 // outputDiagResult 输出诊断结果
-func outputDiagResult(result *diag.DiagnosticResult, flags app.GlobalFlags) error {
+func outputDiagResult(result *types.DiagnosticResult, flags app.GlobalFlags) error {
 	outputFormat := types.OutputFormat(flags.Output)
 	if outputFormat == types.OutputText || outputFormat == "" {
 		return outputDiagText(result, flags)
@@ -119,120 +156,7 @@ func outputDiagResult(result *diag.DiagnosticResult, flags app.GlobalFlags) erro
 }
 
 // outputDiagText 文本格式输出
-func outputDiagText(result *diag.DiagnosticResult, flags app.GlobalFlags) error {
-	color.NoColor = flags.NoColor
-	f := formatter.NewTextFormatter(!flags.NoColor)
-
-	// 显示检查结果
-	f.PrintSubHeader("检查结果")
-	fmt.Println()
-
-	for _, check := range result.Checks {
-		statusSymbol := getStatusSymbol(check.Status, flags.NoColor)
-		statusColor := getStatusColor(check.Status, flags.NoColor)
-
-		fmt.Printf("%s %-30s %s\n",
-			statusSymbol,
-			check.Name,
-			statusColor(check.Message),
-		)
-
-		if flags.Verbose && check.Details != nil && len(check.Details) > 0 {
-			for key, value := range check.Details {
-				fmt.Printf("    %s: %v\n", key, value)
-			}
-		}
-	}
-
-	fmt.Println()
-
-	// 显示发现的问题
-	if len(result.Issues) > 0 {
-		f.PrintSubHeader("发现的问题")
-		fmt.Println()
-
-		for i, issue := range result.Issues {
-			fmt.Printf("%d. [%s] %s\n",
-				i+1,
-				getStatusColor(issue.Severity, flags.NoColor)(issue.Severity.String()),
-				issue.Description,
-			)
-			if issue.Suggestion != "" {
-				fmt.Printf("   建议: %s\n", color.YellowString(issue.Suggestion))
-			}
-			fmt.Println()
-		}
-	}
-
-	// 显示修复建议
-	if len(result.Suggestions) > 0 {
-		f.PrintSubHeader("修复建议")
-		fmt.Println()
-
-		for i, suggestion := range result.Suggestions {
-			fmt.Printf("%d. %s\n", i+1, suggestion)
-		}
-		fmt.Println()
-	}
-
-	// 显示总结
-	fmt.Println(strings.Repeat("=", 70))
-	fmt.Printf("整体状态: %s\n", getStatusColorBold(result.Status, flags.NoColor)(result.Status.String()))
-	fmt.Printf("诊断耗时: %s\n", result.Duration.Round(100))
-	fmt.Printf("检查项目: %d 项\n", len(result.Checks))
-	fmt.Printf("发现问题: %d 个\n", len(result.Issues))
-	fmt.Println(strings.Repeat("=", 70))
-
+func outputDiagText(result *types.DiagnosticResult, flags app.GlobalFlags) error {
+	fmt.Print(formatter.FormatDiagText(result, flags.Verbose, flags.NoColor))
 	return nil
 }
-
-// getStatusSymbol 获取状态符号
-func getStatusSymbol(status diag.DiagnosticStatus, noColor bool) string {
-	color.NoColor = noColor
-	switch status {
-	case diag.StatusHealthy:
-		return color.GreenString("✓")
-	case diag.StatusWarning:
-		return color.YellowString("⚠")
-	case diag.StatusCritical:
-		return color.RedString("✗")
-	default:
-		return "?"
-	}
-}
-
-// getStatusColor 获取状态颜色函数
-func getStatusColor(status diag.DiagnosticStatus, noColor bool) func(a ...interface{}) string {
-	if noColor {
-		return fmt.Sprint
-	}
-
-	switch status {
-	case diag.StatusHealthy:
-		return color.New(color.FgGreen).SprintFunc()
-	case diag.StatusWarning:
-		return color.New(color.FgYellow).SprintFunc()
-	case diag.StatusCritical:
-		return color.New(color.FgRed).SprintFunc()
-	default:
-		return color.New(color.FgWhite).SprintFunc()
-	}
-}
-
-// getStatusColorBold 获取加粗的状态颜色函数
-func getStatusColorBold(status diag.DiagnosticStatus, noColor bool) func(a ...interface{}) string {
-	if noColor {
-		return fmt.Sprint
-	}
-
-	switch status {
-	case diag.StatusHealthy:
-		return color.New(color.FgGreen, color.Bold).SprintFunc()
-	case diag.StatusWarning:
-		return color.New(color.FgYellow, color.Bold).SprintFunc()
-	case diag.StatusCritical:
-		return color.New(color.FgRed, color.Bold).SprintFunc()
-	default:
-		return color.New(color.FgWhite, color.Bold).SprintFunc()
-	}
-}
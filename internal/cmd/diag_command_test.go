@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/catsayer/ntx/internal/core/diag"
+)
+
+func TestDiagLevel(t *testing.T) {
+	tests := []struct {
+		fast, full bool
+		want       diag.DiagnosticLevel
+	}{
+		{false, false, diag.DiagLevelNormal},
+		{true, false, diag.DiagLevelFast},
+		{false, true, diag.DiagLevelFull},
+	}
+	for _, tt := range tests {
+		if got := diagLevel(tt.fast, tt.full); got != tt.want {
+			t.Fatalf("diagLevel(%v, %v) = %v, want %v", tt.fast, tt.full, got, tt.want)
+		}
+	}
+}
+
+func TestDiagFastFullExclusive(t *testing.T) {
+	flags := diagCmd.Flags()
+	t.Cleanup(func() {
+		diagFast, diagFull = false, false
+		flags.Lookup("fast").Changed = false
+		flags.Lookup("full").Changed = false
+	})
+	if err := flags.Parse([]string{"--fast", "--full"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := diagCmd.ValidateFlagGroups(); err == nil {
+		t.Fatal("expected --fast and --full to be mutually exclusive")
+	}
+}
@@ -6,6 +6,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/catsayer/ntx/internal/core/dns"
@@ -19,11 +21,13 @@ var (
 	dnsTimeout float64
 	dnsReverse bool
 	dnsAll     bool
+	dnsChain   bool
+	dnsDNSSEC  bool
 )
 
 // dnsCmd 表示 dns 命令
 var dnsCmd = &cobra.Command{
-	Use:   "dns <domain...>",
+	Use:   "dns [@server] <domain...>",
 	Short: "查询 DNS 记录",
 	Long: `查询域名的 DNS 记录。
 
@@ -37,6 +41,12 @@ var dnsCmd = &cobra.Command{
   SOA    - 授权起始记录
   PTR    - 指针记录 (用于反向查询)
   SRV    - 服务记录
+  NAPTR  - 名称权威指针记录
+  SVCB   - 服务绑定记录
+  HTTPS  - HTTPS 服务绑定记录
+  CAA    - 证书颁发机构授权记录
+
+也可以直接使用类型数值 (如 -t 65) 或 TYPEnnn 形式查询其他类型。
 
 示例:
   # 查询 A 记录 (默认)
@@ -48,12 +58,28 @@ var dnsCmd = &cobra.Command{
   # 查询 MX 记录
   ntx dns google.com --type MX
 
+  # 并发查询多种记录类型，按类型分组显示
+  ntx dns example.com -t A,AAAA,MX
+
+  # 按数值查询 HTTPS 记录
+  ntx dns cloudflare.com -t 65
+
+  # 显示 CNAME 解析链
+  ntx dns www.github.com --chain
+
+  # 请求 DNSSEC 记录并显示验证状态 (AUTHENTICATED/UNSIGNED/BOGUS)
+  ntx dns cloudflare.com --dnssec
+
   # 查询所有常见记录
   ntx dns google.com --all
 
   # 使用自定义 DNS 服务器
   ntx dns google.com --server 1.1.1.1
 
+  # dig 风格：以 @ 开头的参数指定服务器，可直接查询权威服务器
+  ntx dns @ns1.google.com google.com
+  ntx dns @127.0.0.1:5353 example.local
+
   # 反向 DNS 查询
   ntx dns --reverse 8.8.8.8
 
@@ -63,6 +89,10 @@ var dnsCmd = &cobra.Command{
   # JSON 输出
   ntx dns google.com --type A -o json`,
 	Args: func(cmd *cobra.Command, args []string) error {
+		_, args, err := splitDNSServerArg(args)
+		if err != nil {
+			return err
+		}
 		if dnsReverse {
 			if len(args) < 1 {
 				return fmt.Errorf("反向查询需要至少一个 IP 地址")
@@ -83,13 +113,17 @@ func init() {
 	dnsCmd.Flags().StringVarP(&dnsServer, "server", "s", types.DefaultDNSServer,
 		"DNS 服务器地址")
 	dnsCmd.Flags().StringVarP(&dnsType, "type", "t", "A",
-		"记录类型 (A, AAAA, CNAME, MX, NS, TXT, SOA, PTR, SRV)")
+		"记录类型 (A, AAAA, CNAME, MX, NS, TXT, SOA, PTR, SRV, NAPTR, SVCB, HTTPS, CAA, DS, DNSKEY 或数值如 65)，多个类型用逗号分隔")
 	dnsCmd.Flags().Float64Var(&dnsTimeout, "timeout", types.DefaultDNSTimeout.Seconds(),
 		"查询超时时间（秒）")
 	dnsCmd.Flags().BoolVarP(&dnsReverse, "reverse", "r", false,
 		"反向 DNS 查询 (IP 到域名)")
 	dnsCmd.Flags().BoolVarP(&dnsAll, "all", "a", false,
 		"查询所有常见记录类型")
+	dnsCmd.Flags().BoolVar(&dnsChain, "chain", false,
+		"按解析顺序显示 CNAME 链 (域名 → CNAME → ... → 最终记录)")
+	dnsCmd.Flags().BoolVar(&dnsDNSSEC, "dnssec", false,
+		"设置 DO 位请求 DNSSEC 记录，并显示应答的验证状态")
 }
 
 func runDNS(cmd *cobra.Command, args []string) {
@@ -97,7 +131,16 @@ func runDNS(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	server, args, err := splitDNSServerArg(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
 	opts := buildDNSOptions(cmd, appCtx)
+	if server != "" {
+		opts.Server = server
+		opts.FallbackServers = nil
+	}
 	resolver := dns.NewResolver(opts)
 	defer resolver.Close()
 
@@ -113,3 +156,24 @@ func runDNS(cmd *cobra.Command, args []string) {
 		runDNSStandard(ctx, resolver, args, outputFormat, opts.Server, noColor)
 	}
 }
+
+// splitDNSServerArg 提取 dig 风格的 "@server" 参数，返回服务器与其余参数；
+// "@server" 可出现在任意位置，优先于 --server 与配置文件
+func splitDNSServerArg(args []string) (string, []string, error) {
+	var server string
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") {
+			rest = append(rest, arg)
+			continue
+		}
+		if server != "" {
+			return "", nil, fmt.Errorf("只能指定一个 @server 参数")
+		}
+		server = strings.TrimPrefix(arg, "@")
+		if server == "" {
+			return "", nil, fmt.Errorf("@ 后缺少 DNS 服务器地址")
+		}
+	}
+	return server, rest, nil
+}
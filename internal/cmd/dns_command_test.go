@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/internal/app"
+	"github.com/catsayer/ntx/internal/config"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/spf13/pflag"
+)
+
+// parseDNSFlags 解析 dns 命令的参数，测试结束后恢复各 Flag 的默认值
+func parseDNSFlags(t *testing.T, args ...string) {
+	t.Helper()
+	if err := dnsCmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags(%v) error: %v", args, err)
+	}
+	t.Cleanup(func() {
+		dnsCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	})
+}
+
+// captureStdout 返回 fn 执行期间写入标准输出的内容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestSplitDNSServerArg(t *testing.T) {
+	server, rest, err := splitDNSServerArg([]string{"example.com", "@1.1.1.1", "example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server != "1.1.1.1" || !reflect.DeepEqual(rest, []string{"example.com", "example.org"}) {
+		t.Fatalf("got server %q rest %v", server, rest)
+	}
+
+	for _, args := range [][]string{{"@a", "@b"}, {"@"}} {
+		if _, _, err := splitDNSServerArg(args); err == nil {
+			t.Fatalf("splitDNSServerArg(%v) expected error", args)
+		}
+	}
+}
+
+func TestBuildDNSOptions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DNS.Server = "9.9.9.9"
+	appCtx := &app.Context{Config: cfg}
+
+	opts := buildDNSOptions(dnsCmd, appCtx)
+	if opts.Server != "9.9.9.9" || len(opts.FallbackServers) == 0 {
+		t.Fatalf("配置文件的服务器未生效: %+v", opts)
+	}
+
+	parseDNSFlags(t, "--server", "1.1.1.1", "--timeout", "2", "--dnssec")
+	opts = buildDNSOptions(dnsCmd, appCtx)
+	if opts.Server != "1.1.1.1" || opts.Timeout != 2*time.Second || !opts.DNSSEC {
+		t.Fatalf("命令行参数未生效: %+v", opts)
+	}
+	if len(opts.FallbackServers) != 0 {
+		t.Fatalf("显式指定服务器时不应回退: %v", opts.FallbackServers)
+	}
+}
+
+func TestPrintDNSResultText(t *testing.T) {
+	parseDNSFlags(t, "--dnssec")
+	result := &types.DNSResult{
+		Domain:        "example.com",
+		RecordType:    types.DNSTypeMX,
+		Server:        "1.1.1.1:53",
+		QueryTime:     3 * time.Millisecond,
+		Authenticated: true,
+		Records: []*types.DNSRecord{
+			{Name: "example.com", Type: types.DNSTypeMX, TTL: 300, Value: "10 mail.example.com"},
+		},
+	}
+
+	out := captureStdout(t, func() { printDNSResult(result, types.OutputText, true) })
+	for _, want := range []string{
+		"; <<>> NTX DNS Query <<>> example.com MX",
+		";; SERVER: 1.1.1.1:53",
+		";; DNSSEC: AUTHENTICATED",
+		";; ANSWER SECTION:",
+		"10 mail.example.com",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("输出缺少 %q:\n%s", want, out)
+		}
+	}
+}
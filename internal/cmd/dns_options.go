@@ -28,14 +28,20 @@ func buildDNSOptions(cmd *cobra.Command, appCtx *app.Context) *types.DNSOptions
 			if ctx.Config.DNS.Timeout > 0 {
 				opts.Timeout = ctx.Config.DNS.Timeout
 			}
+			opts.FallbackServers = ctx.Config.DNS.FallbackServers
 		}).
 		ApplyFlags(func(opts *types.DNSOptions, flags *pflag.FlagSet) {
+			// 显式指定的服务器只查询该服务器本身，不回退
 			if flags.Changed("server") {
 				opts.Server = dnsServer
+				opts.FallbackServers = nil
 			}
 			if flags.Changed("timeout") {
 				opts.Timeout = time.Duration(dnsTimeout * float64(time.Second))
 			}
+			if flags.Changed("dnssec") {
+				opts.DNSSEC = dnsDNSSEC
+			}
 		}).
 		Result()
 }
@@ -3,10 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/termutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"go.uber.org/zap"
 )
@@ -16,9 +16,15 @@ func printDNSResult(result *types.DNSResult, outputFormat types.OutputFormat, no
 		fmt.Printf("; <<>> NTX DNS Query <<>> %s %s\n", result.Domain, result.RecordType)
 		fmt.Printf(";; SERVER: %s\n", result.Server)
 		fmt.Printf(";; WHEN: %s\n", result.StartTime.Format("Mon Jan 2 15:04:05 MST 2006"))
+		if dnsDNSSEC {
+			fmt.Printf(";; DNSSEC: %s\n", dnssecLabel(result, noColor))
+		}
 		fmt.Printf(";; Query time: %v\n\n", result.QueryTime)
 
-		if len(result.Records) > 0 {
+		if dnsChain {
+			fmt.Println(";; RESOLUTION CHAIN:")
+			fmt.Print(formatter.FormatDNSChainText(formatter.BuildDNSChain(result), noColor))
+		} else if len(result.Records) > 0 {
 			fmt.Println(";; ANSWER SECTION:")
 			printDNSTable(result.Records)
 		}
@@ -35,16 +41,33 @@ func printDNSResult(result *types.DNSResult, outputFormat types.OutputFormat, no
 		return
 	}
 
+	var data interface{} = result
+	if dnsChain {
+		data = formatter.BuildDNSChain(result)
+	}
 	f := formatter.NewFormatter(outputFormat, noColor)
-	output, err := f.Format(result)
+	output, err := f.Format(data)
 	if err != nil {
 		logger.Error("格式化输出失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	fmt.Print(output)
 }
 
+// dnssecLabel 返回着色的 DNSSEC 验证状态
+func dnssecLabel(result *types.DNSResult, noColor bool) string {
+	printer := termutil.NewColorPrinter(noColor)
+	status := result.DNSSECStatus()
+	switch status {
+	case types.DNSSECAuthenticated:
+		return printer.Success(status)
+	case types.DNSSECBogus:
+		return printer.Error(status)
+	}
+	return printer.Warning(status)
+}
+
 func printDNSBatchResults(results []*types.DNSResult, outputFormat types.OutputFormat, noColor bool) {
 	if outputFormat == types.OutputText || outputFormat == "" {
 		for i, result := range results {
@@ -65,36 +88,11 @@ func printDNSBatchResults(results []*types.DNSResult, outputFormat types.OutputF
 	if err != nil {
 		logger.Error("格式化输出失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	fmt.Print(output)
 }
 
-func parseRecordType(typeStr string) types.DNSRecordType {
-	switch strings.ToUpper(typeStr) {
-	case "A":
-		return types.DNSTypeA
-	case "AAAA":
-		return types.DNSTypeAAAA
-	case "CNAME":
-		return types.DNSTypeCNAME
-	case "MX":
-		return types.DNSTypeMX
-	case "NS":
-		return types.DNSTypeNS
-	case "TXT":
-		return types.DNSTypeTXT
-	case "SOA":
-		return types.DNSTypeSOA
-	case "PTR":
-		return types.DNSTypePTR
-	case "SRV":
-		return types.DNSTypeSRV
-	default:
-		return types.DNSTypeA
-	}
-}
-
 func printDNSTable(records []*types.DNSRecord) {
 	if len(records) == 0 {
 		return
@@ -19,7 +19,7 @@ func runDNSReverse(ctx context.Context, resolver *dns.Resolver, ips []string, ou
 		if err != nil {
 			logger.Error("反向 DNS 查询失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		printDNSResult(result, outputFormat, noColor)
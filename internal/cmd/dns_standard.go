@@ -12,7 +12,17 @@ import (
 )
 
 func runDNSStandard(ctx context.Context, resolver *dns.Resolver, domains []string, outputFormat types.OutputFormat, server string, noColor bool) {
-	recordType := parseRecordType(dnsType)
+	recordTypes, err := types.ParseDNSRecordTypes(dnsType)
+	if err != nil {
+		logger.Error("解析记录类型失败", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+	if len(recordTypes) > 1 {
+		runDNSTypes(ctx, resolver, domains, recordTypes, outputFormat, server, noColor)
+		return
+	}
+	recordType := recordTypes[0]
 
 	logger.Info("开始 DNS 查询",
 		zap.Strings("domains", domains),
@@ -24,7 +34,7 @@ func runDNSStandard(ctx context.Context, resolver *dns.Resolver, domains []strin
 		if err != nil {
 			logger.Error("DNS 查询失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		printDNSResult(result, outputFormat, noColor)
@@ -35,7 +45,7 @@ func runDNSStandard(ctx context.Context, resolver *dns.Resolver, domains []strin
 	if err != nil {
 		logger.Error("批量 DNS 查询失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	printDNSBatchResults(results, outputFormat, noColor)
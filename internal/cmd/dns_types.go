@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/catsayer/ntx/internal/core/dns"
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+// dnsTypesResult 单个域名多类型查询的结构化输出，Errors 按类型记录失败原因
+type dnsTypesResult struct {
+	Domain  string             `json:"domain" yaml:"domain"`
+	Server  string             `json:"server" yaml:"server"`
+	Results []*types.DNSResult `json:"results" yaml:"results"`
+	Errors  map[string]string  `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+func runDNSTypes(ctx context.Context, resolver *dns.Resolver, domains []string, recordTypes []types.DNSRecordType, outputFormat types.OutputFormat, server string, noColor bool) {
+	logger.Info("查询多种 DNS 记录类型",
+		zap.Strings("domains", domains),
+		zap.Stringers("types", recordTypes))
+
+	failed := false
+	outputs := make([]*dnsTypesResult, 0, len(domains))
+	for i, domain := range domains {
+		results := resolver.QueryTypes(ctx, domain, recordTypes)
+		out := &dnsTypesResult{Domain: domain, Server: server}
+		for _, result := range results {
+			if result.Error != nil {
+				failed = true
+				if out.Errors == nil {
+					out.Errors = make(map[string]string)
+				}
+				out.Errors[result.RecordType.String()] = result.Error.Error()
+				continue
+			}
+			out.Results = append(out.Results, result)
+		}
+		outputs = append(outputs, out)
+
+		if outputFormat == types.OutputText || outputFormat == "" {
+			if i > 0 {
+				fmt.Println()
+			}
+			printDNSTypesText(domain, results, noColor)
+		}
+	}
+
+	if outputFormat != types.OutputText && outputFormat != "" {
+		var data interface{} = outputs
+		if len(outputs) == 1 {
+			data = outputs[0]
+		}
+		f := formatter.NewFormatter(outputFormat, noColor)
+		output, err := f.Format(data)
+		if err != nil {
+			logger.Error("格式化输出失败", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
+			exit(1)
+		}
+		fmt.Print(output)
+	}
+
+	if failed {
+		exit(1)
+	}
+}
+
+// printDNSTypesText 按记录类型分组输出，失败的类型显示错误而不中断其余类型
+func printDNSTypesText(domain string, results []*types.DNSResult, noColor bool) {
+	fmt.Printf("DNS records for %s:\n", domain)
+	for _, result := range results {
+		if dnsChain && result.Error == nil {
+			fmt.Printf("\n%s chain:\n", result.RecordType)
+			fmt.Print(formatter.FormatDNSChainText(formatter.BuildDNSChain(result), noColor))
+			continue
+		}
+		fmt.Printf("\n%s records:\n", result.RecordType)
+		switch {
+		case result.Error != nil:
+			fmt.Printf("  查询失败: %v\n", result.Error)
+		case len(result.Records) == 0:
+			fmt.Println("  (无记录)")
+		default:
+			printDNSTable(result.Records)
+		}
+	}
+}
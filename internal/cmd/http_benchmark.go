@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/catsayer/ntx/internal/core/http"
 	"github.com/catsayer/ntx/internal/logger"
@@ -13,37 +12,35 @@ import (
 	"go.uber.org/zap"
 )
 
-func runHTTPBenchmark(ctx context.Context, client *http.Client, url string, headers map[string]string, outputFormat types.OutputFormat, noColor bool) {
-	method := strings.ToUpper(httpMethod)
-
+func runHTTPBenchmark(ctx context.Context, client *http.Client, method, url string, body []byte, headers map[string]string, outputFormat types.OutputFormat, noColor bool) {
 	logger.Info("开始 HTTP 性能测试",
 		zap.String("method", method),
 		zap.String("url", url),
-		zap.Int("count", httpBenchCount))
-
-	var body []byte
-	if httpData != "" {
-		body = []byte(httpData)
-	}
+		zap.Int("count", httpBenchCount),
+		zap.Int("concurrency", httpBenchConc))
 
-	result, err := client.Benchmark(ctx, method, url, body, headers, httpBenchCount)
+	result, err := client.Benchmark(ctx, method, url, body, headers, httpBenchCount, httpBenchConc)
 	if err != nil {
 		logger.Error("HTTP 性能测试失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
 		printHTTPBenchmarkText(result, noColor)
-		return
+	} else {
+		f := formatter.NewFormatter(outputFormat, noColor)
+		output, err := f.Format(result)
+		if err != nil {
+			logger.Error("格式化输出失败", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
+			exit(1)
+		}
+		fmt.Print(output)
 	}
 
-	f := formatter.NewFormatter(outputFormat, noColor)
-	output, err := f.Format(result)
-	if err != nil {
-		logger.Error("格式化输出失败", zap.Error(err))
-		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+	if httpMaxLatency > 0 && result.AvgDuration > httpMaxLatency {
+		fmt.Fprintf(os.Stderr, "错误: 平均耗时 %v 超过阈值 %v\n", result.AvgDuration, httpMaxLatency)
+		exit(exitCodeThreshold)
 	}
-	fmt.Print(output)
 }
@@ -2,27 +2,41 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/catsayer/ntx/internal/core/http"
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 var (
-	httpMethod      string
-	httpData        string
-	httpHeaders     []string
-	httpTimeout     float64
-	httpNoRedirect  bool
-	httpIncludeHead bool
-	httpHeadOnly    bool
-	httpBench       bool
-	httpBenchCount  int
+	httpMethod       string
+	httpData         string
+	httpHeaders      []string
+	httpTimeout      float64
+	httpNoRedirect   bool
+	httpNoDecompress bool
+	httpIncludeHead  bool
+	httpHeadOnly     bool
+	httpCert         bool
+	httpTimings      bool
+	httpBench        bool
+	httpBenchCount   int
+	httpBenchConc    int
+	httpMaxLatency   time.Duration
+	httpExpectCode   int
+	httpResolve      []string
 )
 
 var httpCmd = &cobra.Command{
-	Use:   "http <url>",
+	Use:   "http [method] <url>",
 	Short: "发送 HTTP 请求",
 	Long: `发送 HTTP 请求并显示响应。
 
@@ -31,12 +45,20 @@ var httpCmd = &cobra.Command{
 支持的方法:
   GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS
 
+方法可以作为第一个参数给出，也可以使用 -X 指定。
+
 示例:
   # GET 请求
   ntx http https://api.github.com
 
   # POST JSON 数据
-  ntx http https://httpbin.org/post -X POST -d '{"key":"value"}'
+  ntx http POST https://httpbin.org/post -d '{"key":"value"}'
+
+  # 从文件读取请求体，按扩展名推断 Content-Type
+  ntx http PUT https://httpbin.org/put -d @payload.json
+
+  # 显示 DNS、连接、TLS、首字节各阶段耗时
+  ntx http https://api.github.com --timings
 
   # 添加请求头
   ntx http https://api.github.com -H "Authorization: token xxx"
@@ -47,15 +69,33 @@ var httpCmd = &cobra.Command{
   # 仅显示响应头
   ntx http https://api.github.com --head
 
+  # 连接指定后端，同时保留 SNI 与 Host（curl 风格 --resolve）
+  ntx http https://example.com --resolve example.com:443:192.0.2.10
+
+  # 查看服务端证书链（主题、签发者、SAN、有效期）
+  ntx http https://example.com --cert
+
   # 不跟随重定向
   ntx http https://example.com --no-redirect
 
+  # 不请求压缩、不解码响应体（查看服务端原始内容）
+  ntx http https://example.com --no-decompress
+
   # 性能测试（发送 100 次请求）
   ntx http https://api.github.com --bench -n 100
 
+  # 并发性能测试（10 个并发共发送 1000 次请求，Ctrl+C 中断时输出已完成部分的统计）
+  ntx http https://api.github.com --bench -n 1000 -c 10
+
+  # 性能测试中使用模板变量（{{.i}} 序号、{{uuid}}、{{randInt 1 100}}）
+  ntx http https://httpbin.org/post -X POST --bench -n 1000 -d '{"id":{{.i}}}'
+
+  # 健康检查：状态码不是 204 或耗时超过 500ms 时以退出码 2 结束
+  ntx http https://example.com/healthz --expect-status 204 --max-latency 500ms
+
   # JSON 输出
   ntx http https://api.github.com -o json`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.RangeArgs(1, 2),
 	Run:  runHTTP,
 }
 
@@ -65,46 +105,106 @@ func init() {
 	httpCmd.Flags().StringVarP(&httpMethod, "method", "X", "GET",
 		"HTTP 方法 (GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS)")
 	httpCmd.Flags().StringVarP(&httpData, "data", "d", "",
-		"请求体数据")
+		"请求体数据，@路径 表示从文件读取")
 	httpCmd.Flags().StringSliceVarP(&httpHeaders, "header", "H", nil,
 		"请求头 (可多次指定)")
 	httpCmd.Flags().Float64Var(&httpTimeout, "timeout", 30.0,
 		"请求超时时间（秒）")
 	httpCmd.Flags().BoolVar(&httpNoRedirect, "no-redirect", false,
 		"不跟随重定向")
+	httpCmd.Flags().BoolVar(&httpNoDecompress, "no-decompress", false,
+		"不发送 Accept-Encoding，也不解码 gzip/deflate/br 响应体")
 	httpCmd.Flags().BoolVarP(&httpIncludeHead, "include", "i", false,
 		"在输出中包含响应头")
 	httpCmd.Flags().BoolVarP(&httpHeadOnly, "head", "I", false,
 		"仅显示响应头（HEAD 请求）")
+	httpCmd.Flags().BoolVar(&httpCert, "cert", false,
+		"显示服务端 TLS 证书链")
+	httpCmd.Flags().BoolVar(&httpTimings, "timings", false,
+		"显示 DNS、连接、TLS、首字节各阶段耗时")
 	httpCmd.Flags().BoolVar(&httpBench, "bench", false,
 		"性能测试模式")
+	httpCmd.Flags().BoolVar(&httpBench, "benchmark", false,
+		"性能测试模式（--bench 的别名）")
+	_ = httpCmd.Flags().MarkHidden("benchmark")
 	httpCmd.Flags().IntVarP(&httpBenchCount, "count", "n", 10,
 		"性能测试请求次数")
+	httpCmd.Flags().IntVarP(&httpBenchConc, "concurrency", "c", 1,
+		"性能测试并发请求数")
+	httpCmd.Flags().DurationVar(&httpMaxLatency, "max-latency", 0,
+		"允许的最大耗时（性能测试时为平均耗时），超出时以退出码 2 结束")
+	httpCmd.Flags().IntVar(&httpExpectCode, "expect-status", 0,
+		"期望的响应状态码，不匹配时以退出码 2 结束（默认要求 2xx）")
+	httpCmd.Flags().StringArrayVar(&httpResolve, "resolve", nil,
+		"将 host:port 解析到指定地址，格式 host:port:addr（可多次指定），保留 SNI 与 Host")
 }
 
 func runHTTP(cmd *cobra.Command, args []string) {
 	appCtx := mustAppContext(cmd)
-	url := args[0]
+	method, url, err := parseHTTPArgs(args, httpMethod, cmd.Flags().Changed("method"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
 	opts := buildHTTPOptions(cmd, appCtx)
-
-	headers := buildHTTPHeaders(httpHeaders, httpData)
+	resolve, err := netutil.ParseResolveOverrides(httpResolve)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+	opts.Resolve = resolve
+
+	var body *http.Body
+	var data []byte
+	if httpData != "" {
+		body, err = http.ParseBodyArg(httpData)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+		data = body.Data
+	}
+	headers := buildHTTPHeaders(httpHeaders, body)
 
 	if httpHeadOnly {
-		httpMethod = "HEAD"
+		method = "HEAD"
 	}
 
 	client := http.NewClient(opts)
 	defer client.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout+5*time.Second)
-	defer cancel()
-
 	outputFormat := types.OutputFormat(appCtx.Flags.Output)
 	noColor := appCtx.Flags.NoColor
 
 	if httpBench {
-		runHTTPBenchmark(ctx, client, url, headers, outputFormat, noColor)
+		// 性能测试由单请求超时约束，不设置整体截止时间；Ctrl+C 时输出已完成部分的统计
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		runHTTPBenchmark(ctx, client, method, url, data, headers, outputFormat, noColor)
 		return
 	}
-	runHTTPRequest(ctx, client, url, headers, outputFormat, noColor)
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout+5*time.Second)
+	defer cancel()
+	runHTTPRequest(ctx, client, method, url, data, headers, outputFormat, noColor)
+}
+
+// httpMethods 可作为位置参数的 HTTP 方法
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+
+// parseHTTPArgs 解析 "[method] <url>" 位置参数，位置参数与 -X 同时指定且不一致时返回错误
+func parseHTTPArgs(args []string, flagMethod string, flagChanged bool) (method, url string, err error) {
+	method = strings.ToUpper(flagMethod)
+	if len(args) == 1 {
+		return method, args[0], nil
+	}
+
+	argMethod := strings.ToUpper(args[0])
+	if !slices.Contains(httpMethods, argMethod) {
+		return "", "", fmt.Errorf("无效的 HTTP 方法 %q，可选: %s", args[0], strings.Join(httpMethods, ", "))
+	}
+	if flagChanged && method != argMethod {
+		return "", "", fmt.Errorf("HTTP 方法 %s 与 -X %s 冲突", argMethod, method)
+	}
+	return argMethod, args[1], nil
 }
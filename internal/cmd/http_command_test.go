@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/internal/app"
+	"github.com/catsayer/ntx/internal/config"
+	"github.com/catsayer/ntx/internal/core/http"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/spf13/pflag"
+)
+
+// parseHTTPFlags 解析 http 命令的参数，测试结束后恢复各 Flag 的默认值
+func parseHTTPFlags(t *testing.T, args ...string) {
+	t.Helper()
+	if err := httpCmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags(%v) error: %v", args, err)
+	}
+	t.Cleanup(func() {
+		httpCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				_ = sv.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	})
+}
+
+func TestParseHTTPArgs(t *testing.T) {
+	tests := []struct {
+		args        []string
+		flagMethod  string
+		flagChanged bool
+		method, url string
+	}{
+		{[]string{"https://example.com"}, "GET", false, "GET", "https://example.com"},
+		{[]string{"https://example.com"}, "post", true, "POST", "https://example.com"},
+		{[]string{"put", "https://example.com"}, "GET", false, "PUT", "https://example.com"},
+		{[]string{"DELETE", "https://example.com"}, "delete", true, "DELETE", "https://example.com"},
+	}
+	for _, tt := range tests {
+		method, url, err := parseHTTPArgs(tt.args, tt.flagMethod, tt.flagChanged)
+		if err != nil {
+			t.Fatalf("parseHTTPArgs(%v) error: %v", tt.args, err)
+		}
+		if method != tt.method || url != tt.url {
+			t.Fatalf("parseHTTPArgs(%v) = %s %s, want %s %s", tt.args, method, url, tt.method, tt.url)
+		}
+	}
+
+	if _, _, err := parseHTTPArgs([]string{"FETCH", "https://example.com"}, "GET", false); err == nil {
+		t.Fatal("无效方法应返回错误")
+	}
+	if _, _, err := parseHTTPArgs([]string{"POST", "https://example.com"}, "PUT", true); err == nil {
+		t.Fatal("位置参数与 -X 冲突时应返回错误")
+	}
+}
+
+func TestBuildHTTPOptions(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.HTTP.Timeout = 7 * time.Second
+	cfg.HTTP.FollowRedirect = true
+	appCtx := &app.Context{Config: cfg}
+
+	opts := buildHTTPOptions(httpCmd, appCtx)
+	if opts.Timeout != 7*time.Second || !opts.FollowRedirect || !opts.Decompress {
+		t.Fatalf("配置文件未生效: %+v", opts)
+	}
+
+	parseHTTPFlags(t, "--timeout", "2.5", "--no-redirect", "--no-decompress", "--benchmark", "-n", "50", "-c", "5")
+	opts = buildHTTPOptions(httpCmd, appCtx)
+	if opts.Timeout != 2500*time.Millisecond || opts.FollowRedirect || opts.Decompress {
+		t.Fatalf("命令行参数未生效: %+v", opts)
+	}
+	if !httpBench || httpBenchCount != 50 || httpBenchConc != 5 {
+		t.Fatalf("性能测试参数未生效: bench=%v count=%d concurrency=%d", httpBench, httpBenchCount, httpBenchConc)
+	}
+}
+
+func TestBuildHTTPHeaders(t *testing.T) {
+	headers := buildHTTPHeaders([]string{"Authorization: token x", "invalid", "X-Trace:  a:b "}, &http.Body{Data: []byte(`{"k":1}`)})
+	want := map[string]string{
+		"Authorization": "token x",
+		"X-Trace":       "a:b",
+		"Content-Type":  "application/json",
+	}
+	if len(headers) != len(want) {
+		t.Fatalf("got %v, want %v", headers, want)
+	}
+	for k, v := range want {
+		if headers[k] != v {
+			t.Fatalf("%s = %q, want %q", k, headers[k], v)
+		}
+	}
+
+	if got := buildHTTPHeaders(nil, &http.Body{Data: []byte("a=1")})["Content-Type"]; got != "application/x-www-form-urlencoded" {
+		t.Fatalf("表单请求体 Content-Type = %q", got)
+	}
+	if got := buildHTTPHeaders(nil, nil)["Content-Type"]; got != "" {
+		t.Fatalf("无请求体时不应设置 Content-Type: %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "payload.xml")
+	if err := os.WriteFile(path, []byte("<a/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	body, err := http.ParseBodyArg("@" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buildHTTPHeaders(nil, body)["Content-Type"]; !strings.Contains(got, "xml") {
+		t.Fatalf("文件请求体 Content-Type = %q", got)
+	}
+	if got := buildHTTPHeaders([]string{"Content-Type: text/plain"}, body)["Content-Type"]; got != "text/plain" {
+		t.Fatalf("显式 Content-Type 被覆盖: %q", got)
+	}
+}
+
+func TestPrintHTTPResultText(t *testing.T) {
+	parseHTTPFlags(t, "--include", "--timings")
+	result := &types.HTTPResult{
+		Proto:           "HTTP/1.1",
+		StatusCode:      404,
+		Status:          "404 Not Found",
+		Headers:         map[string][]string{"Content-Type": {"application/json"}},
+		Body:            []byte(`{"error":"missing"}`),
+		ContentType:     "application/json",
+		Duration:        12 * time.Millisecond,
+		TransferredSize: 2048,
+		DecodedSize:     4096,
+		Uncompressed:    true,
+		Timings:         &types.HTTPTimings{DNS: time.Millisecond, Connect: 2 * time.Millisecond, TTFB: 10 * time.Millisecond, Total: 12 * time.Millisecond},
+	}
+
+	out := captureStdout(t, func() { printHTTPResultText(result, true) })
+	for _, want := range []string{
+		"HTTP/1.1 404 404 Not Found",
+		"Content-Type: application/json",
+		`"error": "missing"`,
+		"Time: 12ms",
+		"Timings: dns=",
+		"Size: 2.0 KiB (4.0 KiB decoded)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("输出缺少 %q:\n%s", want, out)
+		}
+	}
+}
@@ -3,9 +3,13 @@ package cmd
 import (
 	"encoding/json"
 	"strings"
+
+	"github.com/catsayer/ntx/internal/core/http"
 )
 
-func buildHTTPHeaders(rawHeaders []string, body string) map[string]string {
+// buildHTTPHeaders 解析 "Key: Value" 形式的请求头；未指定 Content-Type 时，
+// 优先使用请求体文件推断的类型，否则按内容是否为 JSON 推断
+func buildHTTPHeaders(rawHeaders []string, body *http.Body) map[string]string {
 	headers := make(map[string]string)
 	for _, h := range rawHeaders {
 		parts := strings.SplitN(h, ":", 2)
@@ -19,12 +23,16 @@ func buildHTTPHeaders(rawHeaders []string, body string) map[string]string {
 		}
 	}
 
-	if body == "" || headers["Content-Type"] != "" {
+	if body == nil || len(body.Data) == 0 || headers["Content-Type"] != "" {
+		return headers
+	}
+	if body.ContentType != "" {
+		headers["Content-Type"] = body.ContentType
 		return headers
 	}
 
 	var js json.RawMessage
-	if json.Unmarshal([]byte(body), &js) == nil {
+	if json.Unmarshal(body.Data, &js) == nil {
 		headers["Content-Type"] = "application/json"
 	} else {
 		headers["Content-Type"] = "application/x-www-form-urlencoded"
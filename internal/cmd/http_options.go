@@ -15,6 +15,7 @@ func buildHTTPOptions(cmd *cobra.Command, appCtx *app.Context) *types.HTTPOption
 		Timeout:        types.DefaultHTTPTimeout,
 		FollowRedirect: true,
 		MaxRedirects:   10,
+		Decompress:     true,
 	}
 	return options.NewBuilder(defaults).
 		WithContext(appCtx).
@@ -40,6 +41,9 @@ func buildHTTPOptions(cmd *cobra.Command, appCtx *app.Context) *types.HTTPOption
 			if flags.Changed("no-redirect") {
 				opts.FollowRedirect = !httpNoRedirect
 			}
+			if flags.Changed("no-decompress") {
+				opts.Decompress = !httpNoDecompress
+			}
 		}).
 		Result()
 }
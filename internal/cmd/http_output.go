@@ -3,8 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/stats"
 	"github.com/catsayer/ntx/pkg/termutil"
 	"github.com/catsayer/ntx/pkg/types"
 )
@@ -57,10 +60,47 @@ func printHTTPResultText(result *types.HTTPResult, noColor bool) {
 
 	fmt.Printf("\n")
 	fmt.Printf("Time: %v\n", result.Duration)
-	fmt.Printf("Size: %s\n", formatSize(result.TransferredSize))
+	if httpTimings && result.Timings != nil {
+		fmt.Printf("Timings: %s\n", formatter.FormatHTTPTimings(result.Timings))
+	}
+	if result.Uncompressed && result.DecodedSize != result.TransferredSize {
+		fmt.Printf("Size: %s (%s decoded)\n", formatSize(result.TransferredSize), formatSize(result.DecodedSize))
+	} else {
+		fmt.Printf("Size: %s\n", formatSize(result.TransferredSize))
+	}
 	if result.TLSUsed {
 		fmt.Printf("TLS: %s\n", green("Yes"))
 	}
+	if result.TLS != nil {
+		printTLSInfoText(result.TLS, httpCert, printer)
+	}
+}
+
+// printTLSInfoText 输出 TLS 信息；叶子证书即将到期时始终给出警告，showChain 时输出完整证书链
+func printTLSInfoText(info *types.TLSInfo, showChain bool, printer *termutil.ColorPrinter) {
+	if leaf := info.Leaf(); leaf.ExpiresWithin(types.DefaultCertExpiryWarnDays) {
+		fmt.Printf("%s\n", printer.Warning(fmt.Sprintf("Warning: certificate %s (%s)",
+			leaf.Subject, formatter.FormatCertExpiry(leaf))))
+	}
+	if !showChain {
+		return
+	}
+
+	fmt.Printf("TLS Version: %s (%s)\n", info.Version, info.CipherSuite)
+	fmt.Println()
+	fmt.Println(printer.Bold("Certificate Chain:"))
+	for i, cert := range info.Certificates {
+		validity := formatter.FormatCertValidity(cert)
+		if cert.ExpiresWithin(types.DefaultCertExpiryWarnDays) {
+			validity = printer.Warning(validity)
+		}
+		fmt.Printf("  [%d] %s\n", i, cert.Subject)
+		fmt.Printf("      Issuer: %s\n", cert.Issuer)
+		if sans := formatter.FormatCertSANs(cert); sans != "" {
+			fmt.Printf("      SAN:    %s\n", sans)
+		}
+		fmt.Printf("      Valid:  %s\n", validity)
+	}
 }
 
 func printHTTPBenchmarkText(result *types.HTTPBenchmarkResult, noColor bool) {
@@ -72,24 +112,52 @@ func printHTTPBenchmarkText(result *types.HTTPBenchmarkResult, noColor bool) {
 	fmt.Printf("%s HTTP Benchmark Results\n", bold("==="))
 	fmt.Printf("URL: %s\n", result.URL)
 	fmt.Printf("Method: %s\n", result.Method)
+	fmt.Printf("Concurrency: %d\n", result.Concurrency)
+	if result.Interrupted {
+		fmt.Printf("%s\n", printer.Warning("测试已中断，以下统计仅包含已完成的请求"))
+	}
 	fmt.Println()
 
 	fmt.Printf("Total Requests: %d\n", result.TotalRequests)
+	// 中断时可能尚无完成的请求
+	total := max(result.TotalRequests, 1)
 	fmt.Printf("Success: %s\n", green(fmt.Sprintf("%d (%.1f%%)",
 		result.SuccessCount,
-		float64(result.SuccessCount)/float64(result.TotalRequests)*100)))
+		float64(result.SuccessCount)/float64(total)*100)))
 	fmt.Printf("Failure: %s\n", red(fmt.Sprintf("%d (%.1f%%)",
 		result.FailureCount,
-		float64(result.FailureCount)/float64(result.TotalRequests)*100)))
+		float64(result.FailureCount)/float64(total)*100)))
 	fmt.Println()
 
 	fmt.Printf("Total Time: %v\n", result.TotalDuration)
 	fmt.Printf("Min Time: %v\n", result.MinDuration)
 	fmt.Printf("Max Time: %v\n", result.MaxDuration)
 	fmt.Printf("Avg Time: %v\n", result.AvgDuration)
+	fmt.Printf("P50 Time: %v\n", result.P50Duration)
+	fmt.Printf("P90 Time: %v\n", result.P90Duration)
+	fmt.Printf("P99 Time: %v\n", result.P99Duration)
 	fmt.Println()
 
+	if len(result.StatusCodes) > 0 {
+		codes := make([]int, 0, len(result.StatusCodes))
+		for code := range result.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		fmt.Println("Status Codes:")
+		for _, code := range codes {
+			fmt.Printf("  %d: %d\n", code, result.StatusCodes[code])
+		}
+		fmt.Println()
+	}
+
 	fmt.Printf("Requests/sec: %s\n", bold(fmt.Sprintf("%.2f", result.RequestsPerSec)))
+
+	if len(result.Histogram) > 0 {
+		fmt.Println()
+		fmt.Println("Latency Histogram:")
+		fmt.Print(stats.RenderHistogram(result.Histogram, termutil.Width()))
+	}
 }
 
 func formatSize(bytes int64) string {
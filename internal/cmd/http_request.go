@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/catsayer/ntx/internal/core/http"
 	"github.com/catsayer/ntx/internal/logger"
@@ -13,23 +12,16 @@ import (
 	"go.uber.org/zap"
 )
 
-func runHTTPRequest(ctx context.Context, client *http.Client, url string, headers map[string]string, outputFormat types.OutputFormat, noColor bool) {
-	method := strings.ToUpper(httpMethod)
-
+func runHTTPRequest(ctx context.Context, client *http.Client, method, url string, body []byte, headers map[string]string, outputFormat types.OutputFormat, noColor bool) {
 	logger.Info("发送 HTTP 请求",
 		zap.String("method", method),
 		zap.String("url", url))
 
-	var body []byte
-	if httpData != "" {
-		body = []byte(httpData)
-	}
-
 	result, err := client.Request(ctx, method, url, body, headers)
 	if err != nil {
 		logger.Error("HTTP 请求失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
@@ -40,12 +32,22 @@ func runHTTPRequest(ctx context.Context, client *http.Client, url string, header
 		if err != nil {
 			logger.Error("格式化输出失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		fmt.Print(output)
 	}
 
-	if result.StatusCode < 200 || result.StatusCode >= 300 {
-		os.Exit(1)
+	if httpExpectCode > 0 {
+		if result.StatusCode != httpExpectCode {
+			fmt.Fprintf(os.Stderr, "错误: 状态码 %d 与期望值 %d 不符\n", result.StatusCode, httpExpectCode)
+			exit(exitCodeThreshold)
+		}
+	} else if result.StatusCode < 200 || result.StatusCode >= 300 {
+		exit(1)
+	}
+
+	if httpMaxLatency > 0 && result.Duration > httpMaxLatency {
+		fmt.Fprintf(os.Stderr, "错误: 耗时 %v 超过阈值 %v\n", result.Duration, httpMaxLatency)
+		exit(exitCodeThreshold)
 	}
 }
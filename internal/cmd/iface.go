@@ -21,6 +21,7 @@ var (
 	ifaceDetail bool
 	ifaceStats  bool
 	ifaceRoutes bool
+	ifaceDNS    bool
 )
 
 // ifaceCmd 表示 iface 命令
@@ -32,11 +33,12 @@ var ifaceCmd = &cobra.Command{
 
 包括:
   • 网卡名称和索引
-  • MAC 地址
+  • MAC 地址（--detail 显示 OUI 厂商）
   • IPv4/IPv6 地址
   • MTU
   • 网卡状态标志
   • 流量统计信息
+  • DNS 服务器与默认网关
 
 示例:
   # 显示所有网卡
@@ -54,6 +56,9 @@ var ifaceCmd = &cobra.Command{
   # 显示路由表
   ntx iface --routes
 
+  # 显示 DNS 服务器与各网卡默认网关
+  ntx iface --dns
+
   # JSON 输出
   ntx iface -o json`,
 	Args: cobra.MaximumNArgs(1),
@@ -69,6 +74,8 @@ func init() {
 		"显示流量统计信息")
 	ifaceCmd.Flags().BoolVar(&ifaceRoutes, "routes", false,
 		"显示路由表")
+	ifaceCmd.Flags().BoolVar(&ifaceDNS, "dns", false,
+		"显示 DNS 服务器与默认网关")
 }
 
 func runIface(cmd *cobra.Command, args []string) {
@@ -85,6 +92,12 @@ func runIface(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if ifaceDNS {
+		// 显示 DNS 与网关配置
+		runIfaceNetworkConfig(reader, outputFormat, noColor)
+		return
+	}
+
 	if len(args) == 1 {
 		// 显示特定网卡
 		runIfaceSingle(reader, args[0], outputFormat, noColor)
@@ -101,18 +114,18 @@ func runIfaceSingle(reader *iface.InterfaceReader, name string, outputFormat typ
 	if err != nil {
 		logger.Error("获取网卡信息失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
-		printInterfaceText(iface, ifaceDetail || ifaceStats, noColor)
+		printInterfaceText(iface, ifaceDetail, ifaceDetail || ifaceStats, noColor)
 	} else {
 		f := formatter.NewFormatter(outputFormat, noColor)
 		output, err := f.Format(iface)
 		if err != nil {
 			logger.Error("格式化输出失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		fmt.Print(output)
 	}
@@ -125,7 +138,7 @@ func runIfaceAll(reader *iface.InterfaceReader, outputFormat types.OutputFormat,
 	if err != nil {
 		logger.Error("获取网卡列表失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
@@ -133,7 +146,7 @@ func runIfaceAll(reader *iface.InterfaceReader, outputFormat types.OutputFormat,
 			if i > 0 {
 				fmt.Println()
 			}
-			printInterfaceText(iface, ifaceDetail || ifaceStats, noColor)
+			printInterfaceText(iface, ifaceDetail, ifaceDetail || ifaceStats, noColor)
 		}
 	} else {
 		f := formatter.NewFormatter(outputFormat, noColor)
@@ -141,7 +154,7 @@ func runIfaceAll(reader *iface.InterfaceReader, outputFormat types.OutputFormat,
 		if err != nil {
 			logger.Error("格式化输出失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		fmt.Print(output)
 	}
@@ -154,7 +167,7 @@ func runIfaceRoutes(reader *iface.InterfaceReader, outputFormat types.OutputForm
 	if err != nil {
 		logger.Error("获取路由表失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if outputFormat == types.OutputText || outputFormat == "" {
@@ -165,13 +178,37 @@ func runIfaceRoutes(reader *iface.InterfaceReader, outputFormat types.OutputForm
 		if err != nil {
 			logger.Error("格式化输出失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-			os.Exit(1)
+			exit(1)
+		}
+		fmt.Print(output)
+	}
+}
+
+func runIfaceNetworkConfig(reader *iface.InterfaceReader, outputFormat types.OutputFormat, noColor bool) {
+	logger.Info("查询网络配置")
+
+	cfg, err := reader.GetNetworkConfig()
+	if err != nil {
+		logger.Error("获取网络配置失败", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
+	}
+
+	if outputFormat == types.OutputText || outputFormat == "" {
+		printNetworkConfigText(cfg, noColor)
+	} else {
+		f := formatter.NewFormatter(outputFormat, noColor)
+		output, err := f.Format(cfg)
+		if err != nil {
+			logger.Error("格式化输出失败", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
+			exit(1)
 		}
 		fmt.Print(output)
 	}
 }
 
-func printInterfaceText(iface *types.Interface, showStats bool, noColor bool) {
+func printInterfaceText(iface *types.Interface, detail bool, showStats bool, noColor bool) {
 	// 设置颜色
 	printer := termutil.NewColorPrinter(noColor)
 	bold := printer.Bold
@@ -182,7 +219,11 @@ func printInterfaceText(iface *types.Interface, showStats bool, noColor bool) {
 	fmt.Printf("%s: %s\n", bold(iface.Name), strings.Join(iface.Flags, ","))
 
 	if iface.HardwareAddr != "" {
-		fmt.Printf("    Link/ether %s\n", green(iface.HardwareAddr))
+		if detail && iface.Vendor != "" {
+			fmt.Printf("    Link/ether %s (%s)\n", green(iface.HardwareAddr), iface.Vendor)
+		} else {
+			fmt.Printf("    Link/ether %s\n", green(iface.HardwareAddr))
+		}
 	}
 
 	// IPv4 地址
@@ -218,6 +259,32 @@ func printInterfaceText(iface *types.Interface, showStats bool, noColor bool) {
 	}
 }
 
+func printNetworkConfigText(cfg *types.NetworkConfig, noColor bool) {
+	printer := termutil.NewColorPrinter(noColor)
+	bold := printer.Bold
+	green := printer.Success
+
+	fmt.Println(bold("DNS 服务器:"))
+	if len(cfg.DNSServers) == 0 {
+		fmt.Println("    (未配置)")
+	}
+	for _, server := range cfg.DNSServers {
+		fmt.Printf("    %s\n", green(server))
+	}
+
+	if len(cfg.SearchDomains) > 0 {
+		fmt.Printf("%s %s\n", bold("搜索域:"), strings.Join(cfg.SearchDomains, " "))
+	}
+
+	fmt.Println(bold("默认网关:"))
+	if len(cfg.Gateways) == 0 {
+		fmt.Println("    (未找到)")
+	}
+	for _, gw := range cfg.Gateways {
+		fmt.Printf("    %-10s %s\n", gw.Interface, green(gw.Gateway))
+	}
+}
+
 func printRoutesText(routes []*types.Route) {
 	if len(routes) == 0 {
 		fmt.Println("无路由信息")
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/catsayer/ntx/internal/output/formatter"
+)
+
+// limitItems 截取前 limit 项，返回截取结果与省略的数量；limit <= 0 表示不限制
+func limitItems[T any](items []T, limit int) ([]T, int) {
+	if limit <= 0 || len(items) <= limit {
+		return items, 0
+	}
+	return items[:limit], len(items) - limit
+}
+
+// printOmitted 在输出被 --limit 截断时打印提示
+func printOmitted(w io.Writer, omitted int) {
+	fmt.Fprint(w, formatter.OmittedNotice(omitted))
+}
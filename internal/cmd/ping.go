@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/catsayer/ntx/internal/cmd/options"
 	pingcmd "github.com/catsayer/ntx/internal/cmd/ping"
 	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -24,16 +27,41 @@ import (
 )
 
 var (
-	pingProtocol string
-	pingCount    int
-	pingInterval float64
-	pingTimeout  float64
-	pingSize     int
-	pingTTL      int
-	pingPort     int
-	pingIPv4     bool
-	pingIPv6     bool
-	pingMonitor  bool
+	pingProtocol  string
+	pingCount     int
+	pingInterval  float64
+	pingJitter    string
+	pingTimeout   float64
+	pingDeadline  time.Duration
+	pingSize      int
+	pingTTL       int
+	pingTOS       int
+	pingICMPID    int
+	pingStartSeq  int
+	pingPort      int
+	pingSrcPort   int
+	pingSource    string
+	pingReadProbe bool
+	pingSendData  string
+	pingExpStatus []int
+	pingExpBody   string
+	pingUA        string
+	pingHeaders   []string
+	pingResolve   []string
+	pingIPv4      bool
+	pingIPv6      bool
+	pingMonitor   bool
+	pingMaxLoss   float64
+	pingMaxRTT    time.Duration
+	pingFailFast  bool
+	pingUntilUp   bool
+	pingUntilDown bool
+	pingDownAfter int
+	pingSummary   bool
+	pingCollapse  bool
+	pingLossMap   bool
+	pingFlood     bool
+	pingMTU       bool
 )
 
 // pingCmd 表示 ping 命令
@@ -65,17 +93,81 @@ Examples:
   # TCP Ping a specific port
   ntx ping google.com --protocol tcp --port 443
 
+  # TCP Ping from a fixed source port (firewall rule testing)
+  ntx ping 10.0.0.1 --protocol tcp --port 22 --source-port 53
+
   # HTTP Ping
   ntx ping https://www.google.com --protocol http
 
+  # HTTP synthetic check: require 200/301 and a body containing "OK"
+  ntx ping https://example.com/health --protocol http --expect-status 200,301 --expect-body 'OK'
+
+  # HTTP Ping with a custom User-Agent and an auth header
+  ntx ping https://api.example.com/health --protocol http --user-agent 'curl/8.0' -H 'Authorization: Bearer TOKEN'
+
+  # Probe one backend behind a load balancer, keeping SNI and Host
+  ntx ping https://example.com --protocol http --resolve example.com:443:192.0.2.10
+
   # Specify count and interval
   ntx ping google.com -c 10 -i 0.5
 
-  # Real-time monitoring chart
+  # Randomize each interval by ±10% to avoid probing in lockstep with periodic traffic
+  ntx ping google.com -c 20 --jitter 10%
+
+  # Long-running ping that collapses repeated timeouts into one line per outage
+  ntx ping 192.168.1.10 -c 0 --collapse
+
+  # Real-time latency sparkline
   ntx ping google.com --monitor
 
+  # Print only the final statistics (JSON: just the statistics object)
+  ntx ping google.com -c 20 --summary-only -o json
+
   # JSON output for multiple hosts (executed concurrently)
-  ntx ping google.com baidu.com -c 3 -o json`,
+  ntx ping google.com baidu.com -c 3 -o json
+
+  # Stream one JSON record per reply, followed by a summary record
+  ntx ping google.com -c 10 -o ndjson
+
+  # Use a fixed ICMP identifier and sequence so probes are easy to filter in tcpdump
+  ntx ping 10.0.0.1 --icmp-id 4242 --start-seq 1000
+
+  # Verify DSCP EF marking is preserved on the return path
+  ntx ping 10.0.0.1 --tos 0xb8
+
+  # Health check: exit with code 2 on any loss or avg RTT above 100ms
+  ntx ping google.com -c 5 --max-loss 0 --max-rtt 100ms
+
+  # Ping for at most 10 seconds, then print statistics
+  ntx ping google.com -w 10s
+
+  # Show an at-a-glance loss map after the statistics
+  ntx ping 192.168.1.10 -c 50 -i 0.2 --loss-map
+
+  # Send probes from a specific interface or source address
+  ntx ping 8.8.8.8 -S eth1
+  ntx ping 8.8.8.8 --protocol tcp --port 53 -S 192.168.1.20
+
+  # Discover the path MTU (largest unfragmented packet)
+  ntx ping google.com --mtu-discover
+
+  # Measure application responsiveness: connect, send a request, wait for the first byte
+  ntx ping example.com --protocol tcp --port 80 --read-probe --send-data 'HEAD / HTTP/1.0\r\n\r\n'
+
+  # Wait for the SSH banner instead of just the TCP handshake
+  ntx ping 10.0.0.1 --protocol tcp --port 22 --read-probe
+
+  # Flood ping (root only): send the next probe as soon as a reply arrives
+  sudo ntx ping 192.168.1.10 --flood -c 1000
+
+  # Wait for a host to come up (give up after 60 probes)
+  ntx ping 192.168.1.10 --until-up -c 60
+
+  # Wait for a host to go down (3 consecutive timeouts by default)
+  ntx ping 192.168.1.10 --until-down
+
+  # Treat 5 consecutive timeouts as down
+  ntx ping 192.168.1.10 --until-down --down-after 5`,
 	Args: cobra.MinimumNArgs(1),
 	Run:  runPing,
 }
@@ -92,27 +184,87 @@ func init() {
 		"发送次数，0 表示无限次")
 	pingCmd.Flags().Float64VarP(&pingInterval, "interval", "i", 1.0,
 		"发送间隔（秒）")
-	pingCmd.Flags().Float64VarP(&pingTimeout, "timeout", "t", 5.0,
-		"超时时间（秒）")
+	pingCmd.Flags().BoolVar(&pingFlood, "flood", false,
+		"洪泛模式：收到响应后立即发送下一个探测（仅 ICMP，需要 root 权限）")
+	pingCmd.Flags().StringVar(&pingJitter, "jitter", "",
+		"在发送间隔上叠加 ±D 的随机抖动，如 200ms 或 10%（默认关闭）")
+	pingCmd.Flags().Float64VarP(&pingTimeout, "timeout", "t", 0,
+		"超时时间（秒），默认按协议: ICMP/TCP 5 秒，HTTP 10 秒")
+	pingCmd.Flags().DurationVarP(&pingDeadline, "deadline", "w", 0,
+		"总运行时间上限，到期后停止并输出统计，如 10s（未指定 -c 时持续发送直到到期）")
 
 	// 模式选项
-	pingCmd.Flags().BoolVar(&pingMonitor, "monitor", false, "显示实时延迟图表")
+	pingCmd.Flags().BoolVar(&pingMonitor, "monitor", false, "显示实时滚动延迟迷你图（输出不是终端时改为逐行输出）")
+	pingCmd.Flags().BoolVar(&pingSummary, "summary-only", false,
+		"仅输出最终统计信息，不输出逐次响应（JSON/YAML 仅输出统计对象）")
+	pingCmd.Flags().BoolVar(&pingCollapse, "collapse", false,
+		"将连续相同的超时/失败行合并为一行计数（如 Request timeout x15）")
+	pingCmd.Flags().BoolVar(&pingMTU, "mtu-discover", false,
+		"探测到目标的路径 MTU（设置 DF 位二分查找，仅 ICMP/IPv4）")
+	pingCmd.Flags().BoolVar(&pingLossMap, "loss-map", false,
+		"在统计信息后按序列号输出丢包图（. 成功，X 丢失）")
 
 	// ICMP 选项
 	pingCmd.Flags().IntVarP(&pingSize, "size", "s", 64,
 		"数据包大小（字节）")
 	pingCmd.Flags().IntVar(&pingTTL, "ttl", 64,
 		"Time To Live")
+	pingCmd.Flags().IntVar(&pingICMPID, "icmp-id", 0,
+		"固定 ICMP 标识符（1-65535），默认使用进程 PID，便于抓包过滤或穿越状态防火墙")
+	pingCmd.Flags().IntVar(&pingStartSeq, "start-seq", 1,
+		"起始 ICMP 序列号（1-65535），超过 65535 后在报文中回绕")
+	pingCmd.Flags().IntVar(&pingTOS, "tos", 0,
+		"IP TOS 字节（如 0xb8 即 DSCP EF），设置后显示响应包中的 TOS（ICMP/TCP，仅 IPv4 发送）")
+
+	pingCmd.Flags().StringVarP(&pingSource, "source", "S", "",
+		"源地址或网卡名（ICMP/TCP），多网卡主机上指定探测的出口")
 
 	// TCP/HTTP 选项
 	pingCmd.Flags().IntVar(&pingPort, "port", 0,
 		"端口号（TCP/HTTP）")
+	pingCmd.Flags().IntVar(&pingSrcPort, "source-port", 0,
+		"固定本地源端口（仅 TCP），用于测试基于源端口的防火墙规则")
+
+	pingCmd.Flags().BoolVar(&pingReadProbe, "read-probe", false,
+		"建连后读取一个响应字节再计时，测量应用层响应（仅 TCP）")
+	pingCmd.Flags().StringVar(&pingSendData, "send-data", "",
+		"--read-probe 模式下建连后发送的数据，支持 \\r\\n 等转义（默认只等待服务端先发送）")
+
+	// HTTP 请求选项
+	pingCmd.Flags().StringVar(&pingUA, "user-agent", "",
+		"自定义 User-Agent（HTTP），默认 NTX/<版本>")
+	pingCmd.Flags().StringArrayVarP(&pingHeaders, "header", "H", nil,
+		"附加请求头（HTTP），格式 'Key: Value'，可多次指定")
+	pingCmd.Flags().StringArrayVar(&pingResolve, "resolve", nil,
+		"将 host:port 解析到指定地址（HTTP），格式 host:port:addr，可多次指定；保留 SNI 与 Host")
+
+	// HTTP 校验选项
+	pingCmd.Flags().IntSliceVar(&pingExpStatus, "expect-status", nil,
+		"视为成功的状态码列表（HTTP），如 200,301；默认状态码 < 400 即成功")
+	pingCmd.Flags().StringVar(&pingExpBody, "expect-body", "",
+		"响应体需匹配的正则表达式（HTTP），不匹配视为失败")
 
 	// IP 版本选项
 	pingCmd.Flags().BoolVarP(&pingIPv4, "ipv4", "4", false,
 		"强制使用 IPv4")
 	pingCmd.Flags().BoolVarP(&pingIPv6, "ipv6", "6", false,
 		"强制使用 IPv6")
+
+	// 阈值选项
+	pingCmd.Flags().Float64Var(&pingMaxLoss, "max-loss", -1,
+		"允许的最大丢包率（百分比），超出时以退出码 2 结束")
+	pingCmd.Flags().DurationVar(&pingMaxRTT, "max-rtt", 0,
+		"允许的最大平均 RTT（如 100ms），超出时以退出码 2 结束")
+	pingCmd.Flags().BoolVar(&pingFailFast, "fail-fast", false,
+		"任一目标失败或超出阈值时立即停止")
+
+	// 提前结束选项
+	pingCmd.Flags().BoolVar(&pingUntilUp, "until-up", false,
+		"收到首个响应即结束（未指定 --count 时持续发送），未满足时退出码为 1")
+	pingCmd.Flags().BoolVar(&pingUntilDown, "until-down", false,
+		"连续 --down-after 次无响应即结束（未指定 --count 时持续发送），未满足时退出码为 1")
+	pingCmd.Flags().IntVar(&pingDownAfter, "down-after", 3,
+		"--until-down 判定为下线所需的连续无响应次数")
 }
 
 func runPing(cmd *cobra.Command, args []string) {
@@ -136,7 +288,129 @@ func runPing(cmd *cobra.Command, args []string) {
 	if protocol != types.ProtocolICMP && protocol != types.ProtocolTCP && protocol != types.ProtocolHTTP {
 		logger.Error("无效的协议", zap.String("protocol", string(protocol)))
 		fmt.Fprintf(os.Stderr, "错误: 无效的协议 '%s'，支持的协议: tcp, icmp, http\n", protocol)
-		os.Exit(1)
+		exit(1)
+	}
+	if opts.SourcePort != 0 {
+		if opts.SourcePort < types.MinPort || opts.SourcePort > types.MaxPort {
+			fmt.Fprintf(os.Stderr, "错误: 无效的源端口 %d\n", opts.SourcePort)
+			exit(1)
+		}
+		if protocol != types.ProtocolTCP {
+			fmt.Fprintln(os.Stderr, "错误: --source-port 仅支持 TCP Ping（--protocol tcp）")
+			exit(1)
+		}
+	}
+	if opts.Source != "" {
+		if protocol == types.ProtocolHTTP {
+			fmt.Fprintln(os.Stderr, "错误: --source 仅支持 ICMP/TCP Ping")
+			exit(1)
+		}
+		// 源地址为 IP 时按其地址族解析目标，避免解析出另一地址族的地址
+		if opts.IPVersion == types.IPvAny {
+			opts.IPVersion = netutil.SourceIPVersion(opts.Source)
+		}
+		if _, err := netutil.ResolveSourceAddr(opts.Source, opts.IPVersion); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+	}
+	if pingSendData != "" {
+		if !opts.TCPReadProbe {
+			fmt.Fprintln(os.Stderr, "错误: --send-data 需要同时指定 --read-probe")
+			exit(1)
+		}
+		data, err := strconv.Unquote(`"` + pingSendData + `"`)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 无效的 --send-data %q: %v\n", pingSendData, err)
+			exit(1)
+		}
+		opts.TCPSendData = []byte(data)
+	}
+	if opts.TCPReadProbe && protocol != types.ProtocolTCP {
+		fmt.Fprintln(os.Stderr, "错误: --read-probe 仅支持 TCP Ping（--protocol tcp）")
+		exit(1)
+	}
+	if cmd.Flags().Changed("icmp-id") && (opts.ICMPID < 1 || opts.ICMPID > types.ICMPIDMask) {
+		fmt.Fprintf(os.Stderr, "错误: 无效的 ICMP 标识符 %d，范围为 1-65535\n", opts.ICMPID)
+		exit(1)
+	}
+	if cmd.Flags().Changed("start-seq") && (opts.StartSeq < 1 || opts.StartSeq > 0xffff) {
+		fmt.Fprintf(os.Stderr, "错误: 无效的起始序列号 %d，范围为 1-65535\n", opts.StartSeq)
+		exit(1)
+	}
+	if (opts.ICMPID != 0 || opts.StartSeq != 0) && protocol != types.ProtocolICMP {
+		fmt.Fprintln(os.Stderr, "错误: --icmp-id/--start-seq 仅支持 ICMP Ping（--protocol icmp）")
+		exit(1)
+	}
+	if (len(opts.HTTPExpectStatus) > 0 || opts.HTTPExpectBody != "") && protocol != types.ProtocolHTTP {
+		fmt.Fprintln(os.Stderr, "错误: --expect-status/--expect-body 仅支持 HTTP Ping（--protocol http）")
+		exit(1)
+	}
+	for _, h := range pingHeaders {
+		if !strings.Contains(h, ":") {
+			fmt.Fprintf(os.Stderr, "错误: 无效的请求头 %q，格式应为 'Key: Value'\n", h)
+			exit(1)
+		}
+	}
+	if len(pingResolve) > 0 {
+		resolve, err := netutil.ParseResolveOverrides(pingResolve)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+		opts.HTTPResolve = resolve
+	}
+	if (opts.HTTPUserAgent != "" || len(opts.HTTPHeaders) > 0 || len(opts.HTTPResolve) > 0) && protocol != types.ProtocolHTTP {
+		fmt.Fprintln(os.Stderr, "错误: --user-agent/--header/--resolve 仅支持 HTTP Ping（--protocol http）")
+		exit(1)
+	}
+	if opts.HTTPExpectBody != "" {
+		if _, err := regexp.Compile(opts.HTTPExpectBody); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: 无效的 --expect-body 正则: %v\n", err)
+			exit(1)
+		}
+	}
+	if opts.Timeout <= 0 {
+		fmt.Fprintf(os.Stderr, "错误: 无效的超时时间 %s，必须大于 0\n", opts.Timeout)
+		exit(1)
+	}
+	if pingFlood {
+		if protocol != types.ProtocolICMP {
+			fmt.Fprintln(os.Stderr, "错误: --flood 仅支持 ICMP Ping（--protocol icmp）")
+			exit(1)
+		}
+		// 洪泛模式可能占满链路带宽，与系统 ping -f 一样仅允许特权用户使用
+		if os.Geteuid() != 0 {
+			fmt.Fprintln(os.Stderr, "错误: --flood 需要 root 权限")
+			exit(1)
+		}
+		if cmd.Flags().Changed("interval") || cmd.Flags().Changed("jitter") {
+			fmt.Fprintln(os.Stderr, "错误: --flood 不能与 --interval/--jitter 同时使用")
+			exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "警告: 洪泛模式会以最快速度持续发送探测，可能占满链路带宽，请仅在自有网络中使用")
+		opts.Flood = true
+	}
+	if cmd.Flags().Changed("deadline") {
+		if opts.Deadline <= 0 {
+			fmt.Fprintf(os.Stderr, "错误: 无效的运行时间上限 %s，必须大于 0\n", opts.Deadline)
+			exit(1)
+		}
+		if !cmd.Flags().Changed("count") {
+			opts.Count = 0
+		}
+	}
+	if cmd.Flags().Changed("jitter") {
+		jitter, err := pingcmd.ParseJitter(pingJitter, opts.Interval)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+		opts.Jitter = jitter
+	}
+	if opts.TOS < 0 || opts.TOS > 255 {
+		fmt.Fprintf(os.Stderr, "错误: 无效的 TOS 值 %d，范围为 0-255\n", opts.TOS)
+		exit(1)
 	}
 
 	// 3. 根据输出格式选择执行模式
@@ -149,27 +423,87 @@ func runPing(cmd *cobra.Command, args []string) {
 		}
 		opts.Count = 0
 	}
-	if outputFormat != types.OutputText && outputFormat != "" && mode != pingcmd.ModeMonitor {
+	switch {
+	case outputFormat == types.OutputNDJSON:
+		// NDJSON 逐条输出响应，监控模式的迷你图不适用于管道
+		mode = pingcmd.ModeStream
+	case outputFormat != types.OutputText && outputFormat != "" && mode != pingcmd.ModeMonitor:
 		mode = pingcmd.ModeBatch
 	}
+	if pingSummary {
+		if pingMonitor {
+			fmt.Fprintln(os.Stderr, "错误: --summary-only 不能与 --monitor 同时使用")
+			exit(1)
+		}
+		mode = pingcmd.ModeSummary
+	}
+
+	if pingMTU {
+		if protocol != types.ProtocolICMP || opts.IPVersion == types.IPv6 {
+			fmt.Fprintln(os.Stderr, "错误: --mtu-discover 仅支持 ICMP/IPv4")
+			exit(1)
+		}
+		if pingMonitor || pingSummary || pingFlood || pingUntilUp || pingUntilDown {
+			fmt.Fprintln(os.Stderr, "错误: --mtu-discover 不能与 --monitor/--summary-only/--flood/--until-* 同时使用")
+			exit(1)
+		}
+		mode = pingcmd.ModeMTU
+		opts.DontFragment = true
+	}
+
+	if cmd.Flags().Changed("down-after") && !pingUntilDown {
+		fmt.Fprintln(os.Stderr, "错误: --down-after 需要与 --until-down 同时使用")
+		exit(1)
+	}
+	if pingUntilDown && pingDownAfter < 1 {
+		fmt.Fprintln(os.Stderr, "错误: --down-after 必须大于 0")
+		exit(1)
+	}
+
+	var until *pingcmd.StopCondition
+	if pingUntilUp || pingUntilDown {
+		until = &pingcmd.StopCondition{UntilUp: pingUntilUp}
+		if pingUntilDown {
+			until.UntilDown = pingDownAfter
+		}
+		if !cmd.Flags().Changed("count") {
+			opts.Count = 0
+		}
+	}
+
+	var thresholds *pingcmd.Thresholds
+	if pingMaxLoss >= 0 || pingMaxRTT > 0 {
+		thresholds = &pingcmd.Thresholds{MaxLoss: pingMaxLoss, MaxRTT: pingMaxRTT}
+	}
 
 	runner := pingcmd.NewRunner(pingcmd.Config{
 		Mode:         mode,
 		OutputFormat: outputFormat,
 		NoColor:      appCtx.Flags.NoColor,
+		Verbose:      appCtx.Flags.Verbose,
+		LossMap:      pingLossMap,
+		Thresholds:   thresholds,
+		FailFast:     pingFailFast,
+		Collapse:     pingCollapse,
+		Until:        until,
 	}, appCtx.PingFactory)
 
 	if err := runner.Run(ctx, args, opts); err != nil {
-		if errors.Is(err, pingcmd.ErrPartialFailure) {
-			os.Exit(1)
+		if errors.Is(err, pingcmd.ErrThresholdExceeded) {
+			exit(exitCodeThreshold)
+		}
+		if errors.Is(err, pingcmd.ErrPartialFailure) || errors.Is(err, pingcmd.ErrConditionNotMet) {
+			exit(1)
 		}
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 }
 
 func buildPingOptions(cmd *cobra.Command, appCtx *app.Context) *types.PingOptions {
-	return options.NewBuilder(types.DefaultPingOptions()).
+	// 未通过配置或 --timeout 指定超时时，按最终协议取默认值
+	timeoutSet := false
+	opts := options.NewBuilder(types.DefaultPingOptions()).
 		WithContext(appCtx).
 		WithCommand(cmd).
 		ApplyConfig(func(opts *types.PingOptions, ctx *app.Context) {
@@ -186,6 +520,7 @@ func buildPingOptions(cmd *cobra.Command, appCtx *app.Context) *types.PingOption
 			}
 			if cfg.Timeout > 0 {
 				opts.Timeout = cfg.Timeout
+				timeoutSet = true
 			}
 			if cfg.Size > 0 {
 				opts.Size = cfg.Size
@@ -210,6 +545,10 @@ func buildPingOptions(cmd *cobra.Command, appCtx *app.Context) *types.PingOption
 			}
 			if flags.Changed("timeout") {
 				opts.Timeout = time.Duration(pingTimeout * float64(time.Second))
+				timeoutSet = true
+			}
+			if flags.Changed("deadline") {
+				opts.Deadline = pingDeadline
 			}
 			if flags.Changed("size") {
 				opts.Size = pingSize
@@ -217,9 +556,39 @@ func buildPingOptions(cmd *cobra.Command, appCtx *app.Context) *types.PingOption
 			if flags.Changed("ttl") {
 				opts.TTL = pingTTL
 			}
+			if flags.Changed("tos") {
+				opts.TOS = pingTOS
+			}
+			if flags.Changed("icmp-id") {
+				opts.ICMPID = pingICMPID
+			}
+			if flags.Changed("start-seq") {
+				opts.StartSeq = pingStartSeq
+			}
 			if flags.Changed("port") {
 				opts.Port = pingPort
 			}
+			if flags.Changed("source") {
+				opts.Source = pingSource
+			}
+			if flags.Changed("read-probe") {
+				opts.TCPReadProbe = pingReadProbe
+			}
+			if flags.Changed("source-port") {
+				opts.SourcePort = pingSrcPort
+			}
+			if flags.Changed("user-agent") {
+				opts.HTTPUserAgent = pingUA
+			}
+			if flags.Changed("header") {
+				opts.HTTPHeaders = buildHTTPHeaders(pingHeaders, nil)
+			}
+			if flags.Changed("expect-status") {
+				opts.HTTPExpectStatus = pingExpStatus
+			}
+			if flags.Changed("expect-body") {
+				opts.HTTPExpectBody = pingExpBody
+			}
 			if flags.Changed("ipv4") && pingIPv4 {
 				opts.IPVersion = types.IPv4
 			} else if flags.Changed("ipv6") && pingIPv6 {
@@ -227,4 +596,8 @@ func buildPingOptions(cmd *cobra.Command, appCtx *app.Context) *types.PingOption
 			}
 		}).
 		Result()
+	if !timeoutSet {
+		opts.Timeout = types.DefaultPingTimeoutFor(opts.Protocol)
+	}
+	return opts
 }
@@ -3,16 +3,16 @@ package ping
 import (
 	"context"
 	"fmt"
+	"os"
 	"runtime"
 	"sync"
 
 	"github.com/catsayer/ntx/internal/logger"
-	"github.com/catsayer/ntx/internal/output/formatter"
 	"github.com/catsayer/ntx/pkg/types"
 	"go.uber.org/zap"
 )
 
-func runPingBatchConcurrent(ctx context.Context, factory types.PingerFactory, targets []string, opts *types.PingOptions, outputFormat types.OutputFormat, noColor bool) error {
+func runPingBatchConcurrent(ctx context.Context, factory types.PingerFactory, targets []string, opts *types.PingOptions, cfg Config) error {
 	if len(targets) == 0 {
 		return nil
 	}
@@ -20,10 +20,34 @@ func runPingBatchConcurrent(ctx context.Context, factory types.PingerFactory, ta
 		return fmt.Errorf("pinger factory is not configured")
 	}
 
+	// fail-fast 时首个失败目标会取消其余任务
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 所有目标共享一个 Pinger：ICMP 共用同一套接字，由读取协程按目标分发响应
+	pingerOpts := *opts
+	pinger, err := factory.Create(&pingerOpts)
+	if err != nil {
+		logger.Error("创建 Pinger 失败", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if closeErr := pinger.Close(); closeErr != nil {
+			logger.Warn("关闭 Pinger 失败", zap.Error(closeErr))
+		}
+	}()
+
 	concurrency := batchWorkerCount(len(targets))
-	resultsChan := make(chan *types.PingResult, len(targets))
+	resultsChan := make(chan targetResult, len(targets))
 	jobs := make(chan string)
 
+	emit := func(t string, result *types.PingResult) {
+		if cfg.FailFast && (result.Status == types.StatusFailure || cfg.Thresholds.Check(t, result.Statistics) != nil) {
+			cancel()
+		}
+		resultsChan <- targetResult{target: t, result: result}
+	}
+
 	var workers sync.WaitGroup
 	workers.Add(concurrency)
 	for i := 0; i < concurrency; i++ {
@@ -32,42 +56,28 @@ func runPingBatchConcurrent(ctx context.Context, factory types.PingerFactory, ta
 			for t := range jobs {
 				select {
 				case <-ctx.Done():
-					resultsChan <- &types.PingResult{
-						Target: &types.Host{Hostname: t},
-						Status: types.StatusFailure,
-						Error:  ctx.Err(),
-					}
+					resultsChan <- targetResult{target: t, result: pingFailureResult(t, ctx.Err())}
 					continue
 				default:
 				}
 
-				targetOpts := *opts
+				// 工厂可能已回退协议，按实际协议补全端口
+				targetOpts := pingerOpts
 				targetOpts.EnsurePort(t)
 
-				pingerOpts := targetOpts
-				pinger, err := factory.Create(&pingerOpts)
-				if err != nil {
-					logger.Error("创建 Pinger 失败", zap.Error(err), zap.String("target", t))
-					resultsChan <- pingFailureResult(t, err)
-					continue
-				}
-
-				result, err := pinger.Ping(ctx, t, &pingerOpts)
-				if closeErr := pinger.Close(); closeErr != nil {
-					logger.Warn("关闭 Pinger 失败", zap.Error(closeErr), zap.String("target", t))
-				}
+				result, err := pinger.Ping(ctx, t, &targetOpts)
 				if err != nil {
 					logger.Error("Ping 失败", zap.Error(err), zap.String("target", t))
-					resultsChan <- pingFailureResult(t, err)
+					emit(t, pingFailureResult(t, err))
 					continue
 				}
 				if result == nil {
 					err = fmt.Errorf("ping result is nil")
 					logger.Error("Ping 失败", zap.Error(err), zap.String("target", t))
-					resultsChan <- pingFailureResult(t, err)
+					emit(t, pingFailureResult(t, err))
 					continue
 				}
-				resultsChan <- result
+				emit(t, result)
 			}
 		}()
 	}
@@ -81,28 +91,50 @@ func runPingBatchConcurrent(ctx context.Context, factory types.PingerFactory, ta
 	close(resultsChan)
 
 	allResults := make([]*types.PingResult, 0, len(targets))
+	byTarget := make(map[string][]*types.PingResult, len(targets))
 	allSuccess := true
-	for res := range resultsChan {
+	var thresholdErr error
+	for tr := range resultsChan {
+		res := tr.result
 		allResults = append(allResults, res)
+		byTarget[tr.target] = append(byTarget[tr.target], res)
+		// 先检查阈值：完全不可达的目标同样应按阈值失败处理
+		if err := cfg.Thresholds.Check(res.Target.Hostname, res.Statistics); err != nil && thresholdErr == nil {
+			thresholdErr = err
+		}
 		if res.Status == types.StatusFailure || (res.Statistics != nil && res.Statistics.Received == 0) {
 			allSuccess = false
 		}
 	}
 
-	f := formatter.NewFormatter(outputFormat, noColor)
-	output, err := f.Format(allResults)
-	if err != nil {
-		return fmt.Errorf("格式化输出失败: %w", err)
+	if cfg.Mode == ModeSummary {
+		if err := writeSummaries(os.Stdout, orderSummaries(targets, byTarget), cfg); err != nil {
+			return err
+		}
+	} else {
+		f := cfg.formatter()
+		output, err := f.Format(allResults)
+		if err != nil {
+			return fmt.Errorf("格式化输出失败: %w", err)
+		}
+		fmt.Print(output)
 	}
 
-	fmt.Print(output)
-
+	if thresholdErr != nil {
+		return thresholdErr
+	}
 	if !allSuccess {
 		return ErrPartialFailure
 	}
 	return nil
 }
 
+// targetResult 关联结果与命令行中的目标，便于按输入顺序输出
+type targetResult struct {
+	target string
+	result *types.PingResult
+}
+
 func pingFailureResult(target string, err error) *types.PingResult {
 	return &types.PingResult{
 		Target: &types.Host{Hostname: target},
@@ -0,0 +1,45 @@
+package ping
+
+import (
+	"fmt"
+	"io"
+)
+
+// collapser 将连续相同的失败行合并为一行计数，状态变化或结束时输出汇总
+type collapser struct {
+	w       io.Writer
+	enabled bool
+
+	key      string
+	style    func(...interface{}) string
+	count    int
+	firstSeq int
+	lastSeq  int
+}
+
+func newCollapser(w io.Writer, enabled bool) *collapser {
+	return &collapser{w: w, enabled: enabled}
+}
+
+// line 输出一行。key 非空且与上一行相同时只计数，不重复输出；
+// 成功响应等不应合并的行传入空 key。
+func (c *collapser) line(key string, seq int, text string, style func(...interface{}) string) {
+	if c.enabled && key != "" && key == c.key {
+		c.count++
+		c.lastSeq = seq
+		return
+	}
+	c.flush()
+	fmt.Fprintln(c.w, style(text))
+	if c.enabled {
+		c.key, c.style, c.count, c.firstSeq, c.lastSeq = key, style, 1, seq, seq
+	}
+}
+
+// flush 输出当前合并段的汇总（仅当合并了多行时）
+func (c *collapser) flush() {
+	if c.count > 1 {
+		fmt.Fprintln(c.w, c.style(fmt.Sprintf("%s x%d (icmp_seq=%d-%d)", c.key, c.count, c.firstSeq, c.lastSeq)))
+	}
+	c.key, c.count = "", 0
+}
@@ -0,0 +1,47 @@
+package ping
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCollapserMergesIdenticalFailures(t *testing.T) {
+	plain := func(a ...interface{}) string { return fmt.Sprint(a...) }
+
+	var buf bytes.Buffer
+	c := newCollapser(&buf, true)
+	c.line("", 1, "reply 1", plain)
+	for seq := 2; seq <= 4; seq++ {
+		c.line("Request timeout", seq, fmt.Sprintf("Request timeout for icmp_seq=%d", seq), plain)
+	}
+	c.line("", 5, "reply 5", plain)
+	c.line("Request timeout", 6, "Request timeout for icmp_seq=6", plain)
+	c.flush()
+
+	want := strings.Join([]string{
+		"reply 1",
+		"Request timeout for icmp_seq=2",
+		"Request timeout x3 (icmp_seq=2-4)",
+		"reply 5",
+		"Request timeout for icmp_seq=6",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestCollapserDisabled(t *testing.T) {
+	plain := func(a ...interface{}) string { return fmt.Sprint(a...) }
+
+	var buf bytes.Buffer
+	c := newCollapser(&buf, false)
+	c.line("Request timeout", 1, "t1", plain)
+	c.line("Request timeout", 2, "t2", plain)
+	c.flush()
+	if buf.String() != "t1\nt2\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,37 @@
+package ping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseJitter 解析 --jitter 参数，支持时长（如 200ms）或相对发送间隔的百分比（如 10%）。
+// 结果不得超过 interval，否则部分间隔会被截断为 0。
+func ParseJitter(s string, interval time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	var jitter time.Duration
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("无效的抖动百分比: %s", s)
+		}
+		jitter = time.Duration(float64(interval) * v / 100)
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("无效的抖动时长: %s（示例: 200ms 或 10%%）", s)
+		}
+		jitter = d
+	}
+
+	if jitter > interval {
+		return 0, fmt.Errorf("抖动 %s 不能大于发送间隔 %s", jitter, interval)
+	}
+	return jitter, nil
+}
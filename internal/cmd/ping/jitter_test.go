@@ -0,0 +1,30 @@
+package ping
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJitter(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":      0,
+		"200ms": 200 * time.Millisecond,
+		"10%":   100 * time.Millisecond,
+		"0%":    0,
+	}
+	for input, want := range cases {
+		got, err := ParseJitter(input, time.Second)
+		if err != nil {
+			t.Fatalf("ParseJitter(%q) error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ParseJitter(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"abc", "-1s", "-5%", "2s", "150%"} {
+		if _, err := ParseJitter(input, time.Second); err == nil {
+			t.Fatalf("ParseJitter(%q) expected error", input)
+		}
+	}
+}
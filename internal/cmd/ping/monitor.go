@@ -2,99 +2,211 @@ package ping
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
-	"github.com/catsayer/ntx/internal/ui"
+	pkgerrors "github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/termutil"
 	"github.com/catsayer/ntx/pkg/types"
-	"github.com/guptarohit/asciigraph"
 )
 
-func runPingMonitor(ctx context.Context, pinger types.Pinger, target string, opts *types.PingOptions) error {
+// sparkGlyphs 迷你图由低到高的字符
+var sparkGlyphs = []rune("▁▂▃▄▅▆▇█")
+
+const (
+	// lostSample 样本序列中表示丢包的值
+	lostSample time.Duration = -1
+	// lostLevel sparkLevels 中表示丢包的等级
+	lostLevel = -1
+	// lostGlyph 丢包在迷你图中的字符
+	lostGlyph = '×'
+	// maxMonitorSamples 保留的最近样本数，超出终端宽度的部分不显示
+	maxMonitorSamples = 1024
+	// monitorRedrawInterval 无响应时检查终端尺寸变化的间隔
+	monitorRedrawInterval = 500 * time.Millisecond
+)
+
+// sparkLevels 取 samples 中最后 width 个样本，按窗口内的最小/最大 RTT 线性映射到
+// 0..len(sparkGlyphs)-1，丢包映射为 lostLevel；窗口内 RTT 全部相同时均为最低等级
+func sparkLevels(samples []time.Duration, width int) []int {
+	if width <= 0 {
+		return nil
+	}
+	if len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+
+	lo, hi := time.Duration(-1), time.Duration(0)
+	for _, s := range samples {
+		if s == lostSample {
+			continue
+		}
+		if lo < 0 || s < lo {
+			lo = s
+		}
+		if s > hi {
+			hi = s
+		}
+	}
+
+	top := len(sparkGlyphs) - 1
+	levels := make([]int, len(samples))
+	for i, s := range samples {
+		switch {
+		case s == lostSample:
+			levels[i] = lostLevel
+		case hi > lo:
+			levels[i] = int(int64(s-lo) * int64(top) / int64(hi-lo))
+		default:
+			levels[i] = 0
+		}
+	}
+	return levels
+}
+
+// renderSparkline 将等级序列渲染为字符，丢包以 lost 着色
+func renderSparkline(levels []int, lost func(...interface{}) string) string {
+	var sb strings.Builder
+	for _, level := range levels {
+		if level == lostLevel {
+			sb.WriteString(lost(string(lostGlyph)))
+			continue
+		}
+		sb.WriteRune(sparkGlyphs[level])
+	}
+	return sb.String()
+}
+
+// monitorState 监控模式的滚动样本与累计统计
+type monitorState struct {
+	hostname, ip string
+	samples      []time.Duration
+	sent         int
+	received     int
+	last         time.Duration
+	min, max     time.Duration
+	total        time.Duration
+}
+
+func (s *monitorState) add(reply *types.PingReply) {
+	s.sent++
+	sample := lostSample
+	if reply.Status == types.StatusSuccess {
+		s.received++
+		sample = reply.RTT
+		s.last = reply.RTT
+		s.total += reply.RTT
+		if s.min == 0 || reply.RTT < s.min {
+			s.min = reply.RTT
+		}
+		if reply.RTT > s.max {
+			s.max = reply.RTT
+		}
+	}
+	s.samples = append(s.samples, sample)
+	if len(s.samples) > maxMonitorSamples {
+		s.samples = s.samples[len(s.samples)-maxMonitorSamples:]
+	}
+}
+
+func (s *monitorState) lossRate() float64 {
+	if s.sent == 0 {
+		return 0
+	}
+	return float64(s.sent-s.received) / float64(s.sent) * 100
+}
+
+// render 原地重绘监控画面：光标回到左上角逐行覆盖，避免整屏清除造成闪烁
+func (s *monitorState) render(w io.Writer, width int, printer *termutil.ColorPrinter) {
+	lines := []string{
+		printer.Bold(fmt.Sprintf("PING %s (%s)", s.hostname, s.ip)) + printer.Muted(" · 实时监控（Ctrl+C 退出）"),
+		"",
+		"  " + renderSparkline(sparkLevels(s.samples, width-2), printer.Error),
+		"",
+	}
+
+	loss := fmt.Sprintf("丢包 %.1f%%", s.lossRate())
+	if s.sent > s.received {
+		loss = printer.Error(loss)
+	}
+	lines = append(lines, fmt.Sprintf("  已发送 %d  已接收 %d  %s", s.sent, s.received, loss))
+	if s.received > 0 {
+		lines = append(lines, fmt.Sprintf("  当前 %s  最小 %s  平均 %s  最大 %s",
+			formatMonitorRTT(s.last), formatMonitorRTT(s.min),
+			formatMonitorRTT(s.total/time.Duration(s.received)), formatMonitorRTT(s.max)))
+	} else {
+		lines = append(lines, printer.Muted("  等待响应..."))
+	}
+
+	fmt.Fprint(w, "\033[H")
+	for _, line := range lines {
+		fmt.Fprint(w, termutil.Truncate(line, width, ""), "\033[K\n")
+	}
+	fmt.Fprint(w, "\033[J")
+}
+
+func formatMonitorRTT(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+}
+
+// runPingMonitor 以迷你图实时显示单个目标的延迟，终端宽度变化时按新宽度重绘
+func runPingMonitor(ctx context.Context, pinger types.Pinger, target string, opts *types.PingOptions, cfg Config) error {
 	targetOpts := *opts
 	targetOpts.EnsurePort(target)
 
-	pingCtx, cancel := context.WithTimeout(ctx, targetOpts.Timeout)
-	firstResult, err := pinger.Ping(pingCtx, target, &types.PingOptions{Count: 1, Timeout: targetOpts.Timeout})
-	cancel()
-	if err != nil || (firstResult != nil && firstResult.Statistics.Received == 0) {
-		return fmt.Errorf("ping: cannot resolve %s: Unknown host", target)
+	hostname, ip, streamTarget, err := resolveStreamTarget(ctx, pinger, target, &targetOpts)
+	if err != nil {
+		var netErr *pkgerrors.NetworkError
+		if stderrors.As(err, &netErr) && netErr.Op == "resolve" {
+			return fmt.Errorf("ping: cannot resolve %s: Unknown host", target)
+		}
+		return err
 	}
-	targetHostname := firstResult.Target.Hostname
-	targetIP := firstResult.Target.IP
 
-	replyChan, err := pinger.PingStream(ctx, target, &targetOpts)
+	replyChan, err := pinger.PingStream(ctx, streamTarget, &targetOpts)
 	if err != nil {
 		return err
 	}
 
-	var rtts []float64
-	sent := 0
-	received := 0
-	var lastRTT, minRTT, maxRTT, avgRTT time.Duration
+	printer := termutil.NewColorPrinter(cfg.NoColor)
+	state := &monitorState{hostname: hostname, ip: ip}
+	ticker := time.NewTicker(monitorRedrawInterval)
+	defer ticker.Stop()
 
-	ui.ClearScreen()
+	width := termutil.Width()
+	termutil.ClearScreen(os.Stdout)
+	state.render(os.Stdout, width, printer)
 
 	for {
 		select {
 		case <-ctx.Done():
 			fmt.Println("\n监控结束。")
 			return nil
+		case <-ticker.C:
+			if w := termutil.Width(); w != width {
+				width = w
+				termutil.ClearScreen(os.Stdout)
+				state.render(os.Stdout, width, printer)
+			}
 		case reply, ok := <-replyChan:
 			if !ok {
 				fmt.Println("\n监控完成。")
 				return nil
 			}
-
-			sent++
-			if reply.Status == types.StatusSuccess {
-				received++
-				rttMs := float64(reply.RTT.Microseconds()) / 1000.0
-				rtts = append(rtts, rttMs)
-				lastRTT = reply.RTT
-
-				if minRTT == 0 || reply.RTT < minRTT {
-					minRTT = reply.RTT
-				}
-				if reply.RTT > maxRTT {
-					maxRTT = reply.RTT
-				}
-				avgRTT = (avgRTT*time.Duration(received-1) + reply.RTT) / time.Duration(received)
-			} else {
-				rtts = append(rtts, 0)
-				lastRTT = 0
-			}
-
-			width, _, err := ui.TerminalSize()
-			if err != nil || width <= 0 {
-				width = 80
-			}
-			maxDataPoints := width - 15
-			if maxDataPoints < 10 {
-				maxDataPoints = 10
+			if reply.Duplicate {
+				// 迷你图按序列号绘制，重复响应不计入
+				continue
 			}
-			if len(rtts) > maxDataPoints {
-				rtts = rtts[len(rtts)-maxDataPoints:]
-			}
-
-			graph := asciigraph.Plot(rtts, asciigraph.Height(10), asciigraph.Width(width-15), asciigraph.Caption("RTT (ms)"))
-
-			lossRate := 0.0
-			if sent > 0 {
-				lossRate = float64(sent-received) / float64(sent) * 100
+			state.add(reply)
+			if w := termutil.Width(); w != width {
+				width = w
+				termutil.ClearScreen(os.Stdout)
 			}
-			statsLine := fmt.Sprintf(
-				"Target: %s (%s) | Sent: %d | Received: %d | Loss: %.1f%% | Last: %v | Avg: %v | Min: %v | Max: %v",
-				targetHostname, targetIP, sent, received, lossRate,
-				lastRTT.Round(time.Microsecond),
-				avgRTT.Round(time.Microsecond),
-				minRTT.Round(time.Microsecond),
-				maxRTT.Round(time.Microsecond),
-			)
-
-			ui.ClearScreen()
-			fmt.Println(statsLine)
-			fmt.Println(graph)
+			state.render(os.Stdout, width, printer)
 		}
 	}
 }
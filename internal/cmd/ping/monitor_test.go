@@ -0,0 +1,43 @@
+package ping
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSparkLevels(t *testing.T) {
+	ms := time.Millisecond
+	cases := []struct {
+		name    string
+		samples []time.Duration
+		width   int
+		want    []int
+	}{
+		{"线性映射", []time.Duration{10 * ms, 17 * ms, 24 * ms, 80 * ms}, 10, []int{0, 0, 1, 7}},
+		{"丢包", []time.Duration{10 * ms, lostSample, 20 * ms}, 10, []int{0, lostLevel, 7}},
+		{"相同 RTT", []time.Duration{5 * ms, 5 * ms}, 10, []int{0, 0}},
+		{"全部丢包", []time.Duration{lostSample, lostSample}, 10, []int{lostLevel, lostLevel}},
+		// 只保留最后 width 个样本，按窗口内的范围缩放
+		{"滚动窗口", []time.Duration{100 * ms, 10 * ms, 15 * ms, 20 * ms}, 3, []int{0, 3, 7}},
+		{"宽度为零", []time.Duration{10 * ms}, 0, nil},
+	}
+	for _, tc := range cases {
+		got := sparkLevels(tc.samples, tc.width)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: sparkLevels() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRenderSparkline(t *testing.T) {
+	ms := time.Millisecond
+	samples := []time.Duration{0, 1 * ms, 2 * ms, 3 * ms, 4 * ms, 5 * ms, 6 * ms, 7 * ms, lostSample}
+	lost := func(a ...interface{}) string { return "[" + fmt.Sprint(a...) + "]" }
+
+	got := renderSparkline(sparkLevels(samples, 20), lost)
+	if want := "▁▂▃▄▅▆▇█[×]"; got != want {
+		t.Fatalf("renderSparkline() = %q, want %q", got, want)
+	}
+}
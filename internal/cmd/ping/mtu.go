@@ -0,0 +1,69 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// mtuDiscoverer 支持路径 MTU 探测的 Pinger（ICMP）
+type mtuDiscoverer interface {
+	DiscoverMTU(ctx context.Context, target string, opts *types.PingOptions) (*types.MTUResult, error)
+}
+
+// runMTUDiscover 依次探测各目标的路径 MTU，无法确定或探测失败时返回 ErrPartialFailure
+func runMTUDiscover(ctx context.Context, pinger types.Pinger, targets []string, opts *types.PingOptions, cfg Config) error {
+	discoverer, ok := pinger.(mtuDiscoverer)
+	if !ok {
+		return fmt.Errorf("MTU 探测需要 ICMP 原始套接字权限（当前协议: %s）", opts.Protocol)
+	}
+
+	text := cfg.OutputFormat == types.OutputText || cfg.OutputFormat == ""
+	var results []*types.MTUResult
+	var firstErr error
+	for _, target := range targets {
+		result, err := discoverer.DiscoverMTU(ctx, target, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", target, err)
+			firstErr = ErrPartialFailure
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if !result.Determinate() {
+			firstErr = ErrPartialFailure
+		}
+		if text {
+			printMTUResult(os.Stdout, result)
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if text || len(results) == 0 {
+		return firstErr
+	}
+	var data interface{} = results
+	if len(targets) == 1 {
+		data = results[0]
+	}
+	if err := cfg.formatter().FormatTo(os.Stdout, data); err != nil {
+		return fmt.Errorf("格式化输出失败: %w", err)
+	}
+	return firstErr
+}
+
+// printMTUResult 输出单个目标的路径 MTU
+func printMTUResult(w io.Writer, r *types.MTUResult) {
+	if !r.Determinate() {
+		fmt.Fprintf(w, "PATH MTU to %s (%s): indeterminate (no reply, %d probes)\n",
+			r.Target.Hostname, r.Target.IP, r.Probes)
+		return
+	}
+	fmt.Fprintf(w, "PATH MTU to %s (%s): %d bytes (max ICMP payload %d, local MTU %d, %d probes)\n",
+		r.Target.Hostname, r.Target.IP, r.PathMTU, r.MaxPayload, r.LocalMTU, r.Probes)
+}
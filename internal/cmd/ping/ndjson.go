@@ -0,0 +1,107 @@
+package ping
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+// runPingNDJSON 以 NDJSON 实时输出每个响应，每个目标结束时输出一条 summary 记录
+func runPingNDJSON(ctx context.Context, pinger types.Pinger, targets []string, opts *types.PingOptions, cfg Config, w io.Writer) error {
+	out := formatter.NewNDJSONWriter(w)
+
+	var firstErr error
+	for _, target := range targets {
+		logger.Info("开始 Ping", zap.String("target", target), zap.String("protocol", string(opts.Protocol)))
+		statistics, err := streamTargetNDJSON(ctx, pinger, target, opts, cfg.Until, out)
+		var targetErr error
+		if stderrors.Is(err, ErrConditionNotMet) {
+			fmt.Fprintln(os.Stderr, err)
+			targetErr = err
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			targetErr = ErrPartialFailure
+		} else if err := cfg.Thresholds.Check(target, statistics); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			targetErr = err
+		}
+		if firstErr == nil {
+			firstErr = targetErr
+		}
+		if (firstErr != nil && cfg.FailFast) || ctx.Err() != nil {
+			break
+		}
+	}
+	return firstErr
+}
+
+// streamTargetNDJSON 输出单个目标的响应记录与统计记录
+func streamTargetNDJSON(ctx context.Context, pinger types.Pinger, target string, opts *types.PingOptions, until *StopCondition, out *formatter.NDJSONWriter) (*types.Statistics, error) {
+	targetOpts := *opts
+	targetOpts.EnsurePort(target)
+
+	_, _, streamTarget, err := resolveStreamTarget(ctx, pinger, target, &targetOpts)
+	if err != nil {
+		return nil, fmt.Errorf("ping: %s: %w", target, err)
+	}
+
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
+
+	replyChan, err := pinger.PingStream(streamCtx, streamTarget, &targetOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent, received, duplicates, consecutiveFailures int
+	var rtts []time.Duration
+	conditionMet := false
+	startTime := time.Now()
+
+	for reply := range replyChan {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := out.Write(formatter.NDJSONReply, target, reply); err != nil {
+			return nil, err
+		}
+		if reply.Duplicate {
+			duplicates++
+			continue
+		}
+		sent++
+		if reply.Status == types.StatusSuccess {
+			received++
+			consecutiveFailures = 0
+			rtts = append(rtts, reply.RTT)
+		} else {
+			consecutiveFailures++
+		}
+		if until.Met(reply, consecutiveFailures) {
+			conditionMet = true
+			break
+		}
+	}
+	// 提前结束时停止发送并排空通道，避免发送协程阻塞
+	cancelStream()
+	for range replyChan {
+	}
+
+	statistics := streamStatistics(sent, received, duplicates, rtts, time.Since(startTime))
+	if err := out.Write(formatter.NDJSONSummary, target, statistics); err != nil {
+		return nil, err
+	}
+
+	if until != nil && !conditionMet && ctx.Err() == nil {
+		return statistics, fmt.Errorf("%w: %s (%s)", ErrConditionNotMet, target, until)
+	}
+	return statistics, nil
+}
@@ -0,0 +1,85 @@
+package ping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// fakePinger 按固定序列返回响应
+type fakePinger struct {
+	replies []*types.PingReply
+}
+
+func (p *fakePinger) Ping(context.Context, string, *types.PingOptions) (*types.PingResult, error) {
+	return &types.PingResult{}, nil
+}
+
+func (p *fakePinger) PingStream(ctx context.Context, _ string, _ *types.PingOptions) (<-chan *types.PingReply, error) {
+	ch := make(chan *types.PingReply)
+	go func() {
+		defer close(ch)
+		for _, reply := range p.replies {
+			select {
+			case ch <- reply:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (p *fakePinger) Close() error { return nil }
+
+func TestRunPingNDJSONEmitsOneRecordPerLine(t *testing.T) {
+	pinger := &fakePinger{replies: []*types.PingReply{
+		{Seq: 1, Status: types.StatusSuccess, RTT: 10 * time.Millisecond},
+		{Seq: 2, Status: types.StatusTimeout},
+		{Seq: 3, Status: types.StatusSuccess, RTT: 20 * time.Millisecond},
+	}}
+	opts := types.DefaultPingOptions()
+	opts.Protocol = types.ProtocolICMP
+
+	var buf bytes.Buffer
+	if err := runPingNDJSON(context.Background(), pinger, []string{"127.0.0.1"}, opts, Config{}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 replies and a summary, got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var record struct {
+			Type   string          `json:"type"`
+			Target string          `json:"target"`
+			Data   json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v\n%s", i, err, line)
+		}
+		want := "reply"
+		if i == len(lines)-1 {
+			want = "summary"
+		}
+		if record.Type != want || record.Target != "127.0.0.1" {
+			t.Fatalf("line %d: unexpected record %+v", i, record)
+		}
+	}
+
+	var summary struct {
+		Data types.Statistics `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(lines[3]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Data.Sent != 3 || summary.Data.Received != 2 {
+		t.Fatalf("unexpected statistics: %+v", summary.Data)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"os"
 
 	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/termutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"go.uber.org/zap"
 )
@@ -14,12 +17,16 @@ import (
 type Mode int
 
 const (
-	// ModeStream 实时文本模式
+	// ModeStream 实时模式，输出文本或 NDJSON
 	ModeStream Mode = iota
 	// ModeMonitor 实时监控模式
 	ModeMonitor
 	// ModeBatch 批量结构化输出模式
 	ModeBatch
+	// ModeSummary 仅输出统计信息，不输出逐次响应
+	ModeSummary
+	// ModeMTU 探测路径 MTU
+	ModeMTU
 )
 
 // Config 控制运行参数
@@ -27,6 +34,18 @@ type Config struct {
 	Mode         Mode
 	OutputFormat types.OutputFormat
 	NoColor      bool
+	// Verbose 统计信息附加 RTT 百分位与抖动
+	Verbose bool
+	// LossMap 统计信息后附加按序列号排列的丢包图
+	LossMap bool
+	// Thresholds 为 nil 时不做阈值检查
+	Thresholds *Thresholds
+	// FailFast 首个目标失败或超出阈值后不再继续后续目标
+	FailFast bool
+	// Collapse 合并连续相同的失败行（仅实时文本模式）
+	Collapse bool
+	// Until 提前结束条件，仅实时文本模式支持，nil 表示按次数发送
+	Until *StopCondition
 }
 
 // Runner 负责执行 ping 任务
@@ -56,7 +75,16 @@ func (r *Runner) Run(ctx context.Context, targets []string, opts *types.PingOpti
 	}
 
 	targetOpts := *opts
-	switch r.cfg.Mode {
+	if r.cfg.Until != nil && r.cfg.Mode != ModeStream {
+		return fmt.Errorf("--until-up/--until-down 仅支持文本输出模式")
+	}
+	mode := r.cfg.Mode
+	if mode == ModeMonitor && !termutil.IsTerminal(os.Stdout) {
+		// 重定向或管道中无法原地重绘，改为逐行输出
+		fmt.Fprintln(os.Stderr, "提示: 标准输出不是终端，监控模式改为逐行输出")
+		mode = ModeStream
+	}
+	switch mode {
 	case ModeMonitor:
 		pinger, err := r.factory.Create(&targetOpts)
 		if err != nil {
@@ -64,9 +92,17 @@ func (r *Runner) Run(ctx context.Context, targets []string, opts *types.PingOpti
 			return err
 		}
 		defer pinger.Close()
-		return runPingMonitor(ctx, pinger, targets[0], &targetOpts)
-	case ModeBatch:
-		return runPingBatchConcurrent(ctx, r.factory, targets, &targetOpts, r.cfg.OutputFormat, r.cfg.NoColor)
+		return runPingMonitor(ctx, pinger, targets[0], &targetOpts, r.cfg)
+	case ModeMTU:
+		pinger, err := r.factory.Create(&targetOpts)
+		if err != nil {
+			logger.Error("创建 Pinger 失败", zap.Error(err))
+			return err
+		}
+		defer pinger.Close()
+		return runMTUDiscover(ctx, pinger, targets, &targetOpts, r.cfg)
+	case ModeBatch, ModeSummary:
+		return runPingBatchConcurrent(ctx, r.factory, targets, &targetOpts, r.cfg)
 	default:
 		pinger, err := r.factory.Create(&targetOpts)
 		if err != nil {
@@ -74,6 +110,20 @@ func (r *Runner) Run(ctx context.Context, targets []string, opts *types.PingOpti
 			return err
 		}
 		defer pinger.Close()
-		return runPingStream(ctx, pinger, targets, &targetOpts, r.cfg.NoColor)
+		if r.cfg.OutputFormat == types.OutputNDJSON {
+			return runPingNDJSON(ctx, pinger, targets, &targetOpts, r.cfg, os.Stdout)
+		}
+		return runPingStream(ctx, pinger, targets, &targetOpts, r.cfg)
 	}
 }
+
+// formatter 返回结构化输出使用的格式化器
+func (c Config) formatter() formatter.Formatter {
+	return formatter.NewFormatterWithConfig(formatter.Config{
+		Format:      c.OutputFormat,
+		NoColor:     c.NoColor,
+		Indent:      true,
+		Verbose:     c.Verbose,
+		ShowLossMap: c.LossMap,
+	})
+}
@@ -4,105 +4,289 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"time"
 
 	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/output/formatter"
 	pkgerrors "github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/stats"
 	"github.com/catsayer/ntx/pkg/termutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"go.uber.org/zap"
 )
 
-func runPingStream(ctx context.Context, pinger types.Pinger, targets []string, opts *types.PingOptions, noColor bool) error {
-	printer := termutil.NewColorPrinter(noColor)
+func runPingStream(ctx context.Context, pinger types.Pinger, targets []string, opts *types.PingOptions, cfg Config) error {
+	printer := termutil.NewColorPrinter(cfg.NoColor)
 
+	var firstErr error
 	for i, target := range targets {
 		logger.Info("开始 Ping", zap.String("target", target), zap.String("protocol", string(opts.Protocol)))
-		if err := streamSingleTarget(ctx, pinger, target, opts, printer); err != nil {
+		statistics, err := streamSingleTarget(ctx, pinger, target, opts, cfg, printer)
+		var targetErr error
+		if stderrors.Is(err, ErrConditionNotMet) {
+			fmt.Fprintln(os.Stderr, printer.Error(err.Error()))
+			targetErr = err
+		} else if err != nil {
 			fmt.Fprintln(os.Stderr, err)
+			targetErr = ErrPartialFailure
+		} else if err := cfg.Thresholds.Check(target, statistics); err != nil {
+			fmt.Fprintln(os.Stderr, printer.Error(err.Error()))
+			targetErr = err
+		}
+		// 只保留第一个失败，后续目标不覆盖退出码
+		if firstErr == nil {
+			firstErr = targetErr
+		}
+		if firstErr != nil && cfg.FailFast {
+			return firstErr
 		}
 		if i < len(targets)-1 {
 			fmt.Println()
 		}
 	}
-	return nil
+	return firstErr
 }
 
-func streamSingleTarget(ctx context.Context, pinger types.Pinger, target string, opts *types.PingOptions, printer *termutil.ColorPrinter) error {
+func streamSingleTarget(ctx context.Context, pinger types.Pinger, target string, opts *types.PingOptions, cfg Config, printer *termutil.ColorPrinter) (*types.Statistics, error) {
+	until := cfg.Until
 	targetOpts := *opts
 	targetOpts.EnsurePort(target)
 
-	preflightOpts := targetOpts
-	preflightOpts.Count = 1
-	pingCtx, cancel := context.WithTimeout(ctx, targetOpts.Timeout)
-	firstResult, err := pinger.Ping(pingCtx, target, &preflightOpts)
-	cancel()
+	targetHostname, targetIP, streamTarget, err := resolveStreamTarget(ctx, pinger, target, &targetOpts)
 	if err != nil {
 		var netErr *pkgerrors.NetworkError
 		if stderrors.As(err, &netErr) && netErr.Op == "resolve" {
-			return fmt.Errorf("ping: cannot resolve %s: Unknown host", target)
+			return nil, fmt.Errorf("ping: cannot resolve %s: Unknown host", target)
 		}
-		return err
-	}
-	targetHostname := target
-	targetIP := target
-	if firstResult != nil && firstResult.Target != nil {
-		targetHostname = firstResult.Target.Hostname
-		targetIP = firstResult.Target.IP
+		return nil, err
 	}
 
 	fmt.Printf("PING %s (%s) %d(%d) bytes of data.\n", targetHostname, targetIP, targetOpts.Size, targetOpts.Size+28)
 
-	replyChan, err := pinger.PingStream(ctx, target, &targetOpts)
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
+
+	replyChan, err := pinger.PingStream(streamCtx, streamTarget, &targetOpts)
 	if err != nil {
-		return fmt.Errorf("错误: %w", err)
+		return nil, fmt.Errorf("错误: %w", err)
 	}
 
 	sent := 0
 	received := 0
+	duplicates := 0
 	var rtts []time.Duration
+	var replies []*types.PingReply
 	var totalTime time.Duration
 	startTime := time.Now()
 
+	conditionMet := false
+	certReported := false
+	consecutiveFailures := 0
+	out := newCollapser(os.Stdout, cfg.Collapse)
+	if targetOpts.Flood {
+		// 洪泛模式不输出逐次响应，改为经典 ping -f 的点阵
+		out = newCollapser(io.Discard, false)
+	}
 	for reply := range replyChan {
-		sent++
+		if !reply.Duplicate {
+			sent++
+		}
 		if ctx.Err() != nil {
 			break
 		}
+		if targetOpts.Flood && !reply.Duplicate {
+			printFloodMark(os.Stdout, reply)
+		}
+		if cfg.LossMap {
+			replies = append(replies, reply)
+		}
+		if reply.Duplicate {
+			duplicates++
+			line := fmt.Sprintf("%d bytes from %s: icmp_seq=%d ttl=%d time=%.3f ms (DUP!)",
+				reply.Bytes,
+				targetIP,
+				reply.Seq,
+				reply.TTL,
+				float64(reply.RTT.Microseconds())/1000.0,
+			)
+			out.line("", reply.Seq, line, printer.Warning)
+			continue
+		}
 		if reply.Status == types.StatusSuccess {
 			received++
+			consecutiveFailures = 0
 			rtts = append(rtts, reply.RTT)
-			fmt.Println(printer.Success(fmt.Sprintf("%d bytes from %s: icmp_seq=%d ttl=%d time=%.3f ms",
+			line := fmt.Sprintf("%d bytes from %s: icmp_seq=%d ttl=%d time=%.3f ms",
 				reply.Bytes,
 				targetIP,
 				reply.Seq,
 				reply.TTL,
 				float64(reply.RTT.Microseconds())/1000.0,
-			)))
+			)
+			if targetOpts.TOS > 0 && opts.Protocol == types.ProtocolICMP {
+				line += formatReceivedTOS(targetOpts.TOS, reply.ReceivedTOS)
+			}
+			out.line("", reply.Seq, line, printer.Success)
+			if cfg.Verbose && reply.HTTPTimings != nil {
+				out.line("", reply.Seq, "    "+formatter.FormatHTTPTimings(reply.HTTPTimings), printer.Muted)
+			}
+			if leaf := reply.TLS.Leaf(); leaf != nil && !certReported {
+				// 证书只在首次握手时报告
+				certReported = true
+				if cfg.Verbose {
+					out.line("", reply.Seq, fmt.Sprintf("    cert: %s, issuer: %s, valid: %s",
+						leaf.Subject, leaf.Issuer, formatter.FormatCertValidity(leaf)), printer.Muted)
+				}
+				if leaf.ExpiresWithin(types.DefaultCertExpiryWarnDays) {
+					out.line("", reply.Seq, fmt.Sprintf("警告: 证书 %s 有效期不足 %d 天 (%s)",
+						leaf.Subject, types.DefaultCertExpiryWarnDays, formatter.FormatCertExpiry(leaf)), printer.Warning)
+				}
+			}
 		} else {
-			fmt.Println(printer.Error(fmt.Sprintf("Request timeout for icmp_seq=%d", reply.Seq)))
+			consecutiveFailures++
+			if reply.Status == types.StatusFailure && reply.Error != "" {
+				out.line("Request failed: "+reply.Error, reply.Seq,
+					fmt.Sprintf("Request failed for icmp_seq=%d: %s", reply.Seq, reply.Error), printer.Error)
+			} else {
+				out.line("Request timeout", reply.Seq,
+					fmt.Sprintf("Request timeout for icmp_seq=%d", reply.Seq), printer.Error)
+			}
+		}
+		if until.Met(reply, consecutiveFailures) {
+			out.flush()
+			conditionMet = true
+			if reply.Status == types.StatusSuccess {
+				fmt.Println(printer.Success("条件已满足: 目标可达"))
+			} else {
+				fmt.Println(printer.Warning(fmt.Sprintf("条件已满足: 连续 %d 次无响应，目标不可达", consecutiveFailures)))
+			}
+			break
 		}
 	}
+	out.flush()
+	// 提前结束时停止发送并排空通道，避免发送协程阻塞
+	cancelStream()
+	for range replyChan {
+	}
 	totalTime = time.Since(startTime)
 
-	fmt.Printf("\n--- %s ping statistics ---\n", targetHostname)
+	statistics := streamStatistics(sent, received, duplicates, rtts, totalTime)
+	fmt.Println()
+	printStatistics(os.Stdout, targetHostname, statistics, cfg.Verbose)
+	if cfg.LossMap && len(replies) > 0 {
+		fmt.Print("loss map:\n" + formatter.LossMap(replies, printer))
+	}
+
+	if until != nil && !conditionMet && ctx.Err() == nil {
+		return statistics, fmt.Errorf("%w: %s (%s)", ErrConditionNotMet, targetHostname, until)
+	}
+	return statistics, nil
+}
+
+// streamStatistics 根据实时模式累计的计数与 RTT 样本生成统计信息
+func streamStatistics(sent, received, duplicates int, rtts []time.Duration, totalTime time.Duration) *types.Statistics {
 	lossRate := 0.0
 	if sent > 0 {
 		lossRate = float64(sent-received) / float64(sent) * 100
 	}
-	fmt.Printf("%d packets transmitted, %d received, %.f%% packet loss, time %dms\n",
-		sent, received, lossRate, totalTime.Milliseconds())
-
+	statistics := &types.Statistics{
+		Sent:       sent,
+		Received:   received,
+		Duplicates: duplicates,
+		Loss:       sent - received,
+		LossRate:   lossRate,
+		TotalTime:  totalTime,
+	}
 	if len(rtts) > 0 {
 		min, max, avg, stddev := stats.ComputeRTTStats(rtts)
-		fmt.Printf("rtt min/avg/max/mdev = %.3f/%.3f/%.3f/%.3f ms\n",
-			float64(min.Microseconds())/1000.0,
-			float64(avg.Microseconds())/1000.0,
-			float64(max.Microseconds())/1000.0,
-			float64(stddev.Microseconds())/1000.0)
+		statistics.MinRTT, statistics.MaxRTT, statistics.AvgRTT, statistics.StdDevRTT = min, max, avg, stddev
+		statistics.P50RTT, statistics.P95RTT, statistics.P99RTT, statistics.JitterRTT = stats.ComputeRTTPercentiles(rtts)
+	}
+	return statistics
+}
+
+// resolveStreamTarget 只解析一次目标，并返回固定为该 IP 的探测目标，
+// 避免轮询 DNS 在长时间 Ping 中切换地址。HTTP 需保留主机名（Host/SNI），改用预检请求获取地址。
+func resolveStreamTarget(ctx context.Context, pinger types.Pinger, target string, opts *types.PingOptions) (hostname, ip, streamTarget string, err error) {
+	if opts.Protocol == types.ProtocolHTTP {
+		preflightOpts := *opts
+		preflightOpts.Count = 1
+		pingCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		firstResult, err := pinger.Ping(pingCtx, target, &preflightOpts)
+		cancel()
+		if err != nil {
+			return "", "", "", err
+		}
+		hostname, ip = target, target
+		if firstResult != nil && firstResult.Target != nil {
+			hostname, ip = firstResult.Target.Hostname, firstResult.Target.IP
+		}
+		return hostname, ip, target, nil
+	}
+
+	host, port := target, ""
+	if opts.Protocol == types.ProtocolTCP {
+		if h, p, splitErr := net.SplitHostPort(target); splitErr == nil {
+			host, port = h, p
+		}
+	}
+
+	hostInfo, err := netutil.ResolveHost(host, opts.IPVersion)
+	if err != nil {
+		return "", "", "", pkgerrors.NewNetworkError("resolve", target, err)
 	}
 
-	return nil
+	streamTarget = hostInfo.IP
+	if port != "" {
+		streamTarget = net.JoinHostPort(hostInfo.IP, port)
+	}
+	return host, hostInfo.IP, streamTarget, nil
+}
+
+// printFloodMark 每次发送输出 "."，收到响应时退格抹去，残留的点数即丢包数
+func printFloodMark(w io.Writer, reply *types.PingReply) {
+	if reply.Status == types.StatusSuccess {
+		fmt.Fprint(w, ".\b")
+		return
+	}
+	fmt.Fprint(w, ".")
+}
+
+// formatReceivedTOS 显示响应包的 TOS 及 DSCP，与发送值不一致时标注已改写
+func formatReceivedTOS(sent, received int) string {
+	s := fmt.Sprintf(" tos=0x%02x (dscp %d)", received, received>>2)
+	if received != sent {
+		s += fmt.Sprintf(" rewritten from 0x%02x", sent)
+	}
+	return s
+}
+
+// printStatistics 以 ping 风格输出统计信息，无成功响应时省略 RTT 行；
+// verbose 时追加 RTT 百分位与抖动
+func printStatistics(w io.Writer, hostname string, s *types.Statistics, verbose bool) {
+	fmt.Fprintf(w, "--- %s ping statistics ---\n", hostname)
+	dups := ""
+	if s.Duplicates > 0 {
+		dups = fmt.Sprintf(", +%d duplicates", s.Duplicates)
+	}
+	fmt.Fprintf(w, "%d packets transmitted, %d received%s, %.f%% packet loss, time %dms\n",
+		s.Sent, s.Received, dups, s.LossRate, s.TotalTime.Milliseconds())
+	if s.Received > 0 {
+		fmt.Fprintf(w, "rtt min/avg/max/mdev = %.3f/%.3f/%.3f/%.3f ms\n",
+			float64(s.MinRTT.Microseconds())/1000.0,
+			float64(s.AvgRTT.Microseconds())/1000.0,
+			float64(s.MaxRTT.Microseconds())/1000.0,
+			float64(s.StdDevRTT.Microseconds())/1000.0)
+		if verbose {
+			fmt.Fprintf(w, "rtt p50/p95/p99 = %.3f/%.3f/%.3f ms, jitter %.3f ms\n",
+				float64(s.P50RTT.Microseconds())/1000.0,
+				float64(s.P95RTT.Microseconds())/1000.0,
+				float64(s.P99RTT.Microseconds())/1000.0,
+				float64(s.JitterRTT.Microseconds())/1000.0)
+		}
+	}
 }
@@ -0,0 +1,67 @@
+package ping
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// TargetSummary 多目标 --summary-only 结构化输出的单个条目
+type TargetSummary struct {
+	Target     string            `json:"target" yaml:"target"`
+	Statistics *types.Statistics `json:"statistics,omitempty" yaml:"statistics,omitempty"`
+	Error      string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// writeSummaries 仅输出各目标的统计信息。单目标的结构化输出只包含统计对象本身，
+// 多目标时输出 TargetSummary 列表。
+func writeSummaries(w io.Writer, summaries []*TargetSummary, cfg Config) error {
+	if cfg.OutputFormat == types.OutputText || cfg.OutputFormat == "" {
+		for i, s := range summaries {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			if s.Statistics == nil {
+				fmt.Fprintf(w, "--- %s ping statistics ---\n%s\n", s.Target, s.Error)
+				continue
+			}
+			printStatistics(w, s.Target, s.Statistics, cfg.Verbose)
+		}
+		return nil
+	}
+
+	var data interface{} = summaries
+	if len(summaries) == 1 {
+		if summaries[0].Statistics == nil {
+			return fmt.Errorf("%s: %s", summaries[0].Target, summaries[0].Error)
+		}
+		data = summaries[0].Statistics
+	}
+	if err := cfg.formatter().FormatTo(w, data); err != nil {
+		return fmt.Errorf("格式化输出失败: %w", err)
+	}
+	return nil
+}
+
+// orderSummaries 将并发完成的结果还原为命令行中的目标顺序
+func orderSummaries(targets []string, byTarget map[string][]*types.PingResult) []*TargetSummary {
+	summaries := make([]*TargetSummary, 0, len(targets))
+	for _, t := range targets {
+		queue := byTarget[t]
+		if len(queue) == 0 {
+			continue
+		}
+		res := queue[0]
+		byTarget[t] = queue[1:]
+
+		s := &TargetSummary{Target: t, Statistics: res.Statistics}
+		if res.Error != nil {
+			s.Error = res.Error.Error()
+		} else if s.Statistics == nil {
+			s.Error = "no statistics"
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
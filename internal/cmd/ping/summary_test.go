@@ -0,0 +1,59 @@
+package ping
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+func TestOrderSummariesFollowsTargetOrder(t *testing.T) {
+	byTarget := map[string][]*types.PingResult{
+		"b": {{Statistics: &types.Statistics{Sent: 2}}},
+		"a": {{Status: types.StatusFailure, Error: stderrors.New("unreachable")}},
+	}
+
+	summaries := orderSummaries([]string{"a", "b"}, byTarget)
+	if len(summaries) != 2 || summaries[0].Target != "a" || summaries[1].Target != "b" {
+		t.Fatalf("unexpected order: %+v", summaries)
+	}
+	if summaries[0].Error != "unreachable" || summaries[0].Statistics != nil {
+		t.Fatalf("failure not preserved: %+v", summaries[0])
+	}
+}
+
+func TestWriteSummariesSingleTargetJSON(t *testing.T) {
+	summaries := []*TargetSummary{{Target: "a", Statistics: &types.Statistics{Sent: 4, Received: 3, Loss: 1, LossRate: 25}}}
+
+	var buf bytes.Buffer
+	if err := writeSummaries(&buf, summaries, Config{OutputFormat: types.OutputJSON}); err != nil {
+		t.Fatalf("writeSummaries: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if got["sent"] != float64(4) || got["loss_rate"] != float64(25) {
+		t.Fatalf("expected bare statistics object, got %v", got)
+	}
+	if _, ok := got["target"]; ok {
+		t.Fatalf("single target output should not be wrapped: %v", got)
+	}
+}
+
+func TestWriteSummariesText(t *testing.T) {
+	summaries := []*TargetSummary{{Target: "a", Statistics: &types.Statistics{Sent: 2, Received: 0, Loss: 2, LossRate: 100}}}
+
+	var buf bytes.Buffer
+	if err := writeSummaries(&buf, summaries, Config{OutputFormat: types.OutputText}); err != nil {
+		t.Fatalf("writeSummaries: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "2 packets transmitted, 0 received, 100% packet loss") || strings.Contains(out, "rtt") {
+		t.Fatalf("unexpected text summary: %q", out)
+	}
+}
@@ -0,0 +1,43 @@
+package ping
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// ErrThresholdExceeded 表示结果超出了 --max-loss / --max-rtt 阈值
+var ErrThresholdExceeded = stderrors.New("ping threshold exceeded")
+
+// Thresholds 健康检查阈值，MaxLoss < 0 或 MaxRTT <= 0 表示不检查对应项
+type Thresholds struct {
+	MaxLoss float64
+	MaxRTT  time.Duration
+}
+
+// Check 校验统计信息是否满足阈值，违反时返回包装了 ErrThresholdExceeded 的错误
+func (t *Thresholds) Check(target string, s *types.Statistics) error {
+	if t == nil {
+		return nil
+	}
+	if s == nil {
+		if t.MaxLoss >= 0 || t.MaxRTT > 0 {
+			return fmt.Errorf("%w: %s 无统计数据", ErrThresholdExceeded, target)
+		}
+		return nil
+	}
+	if t.MaxLoss >= 0 && s.LossRate > t.MaxLoss {
+		return fmt.Errorf("%w: %s 丢包率 %.1f%% 超过阈值 %.1f%%", ErrThresholdExceeded, target, s.LossRate, t.MaxLoss)
+	}
+	if t.MaxRTT > 0 {
+		if s.Received == 0 {
+			return fmt.Errorf("%w: %s 未收到任何响应", ErrThresholdExceeded, target)
+		}
+		if s.AvgRTT > t.MaxRTT {
+			return fmt.Errorf("%w: %s 平均 RTT %v 超过阈值 %v", ErrThresholdExceeded, target, s.AvgRTT, t.MaxRTT)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package ping
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+func TestThresholdsCheck(t *testing.T) {
+	cases := []struct {
+		name       string
+		thresholds *Thresholds
+		stats      *types.Statistics
+		violated   bool
+	}{
+		{
+			name:       "disabled",
+			thresholds: nil,
+			stats:      &types.Statistics{Sent: 4, Received: 0, LossRate: 100},
+		},
+		{
+			name:       "loss within limit",
+			thresholds: &Thresholds{MaxLoss: 25},
+			stats:      &types.Statistics{Sent: 4, Received: 3, LossRate: 25},
+		},
+		{
+			name:       "loss exceeded",
+			thresholds: &Thresholds{MaxLoss: 0},
+			stats:      &types.Statistics{Sent: 4, Received: 3, LossRate: 25},
+			violated:   true,
+		},
+		{
+			name:       "rtt exceeded",
+			thresholds: &Thresholds{MaxLoss: -1, MaxRTT: 10 * time.Millisecond},
+			stats:      &types.Statistics{Sent: 1, Received: 1, AvgRTT: 20 * time.Millisecond},
+			violated:   true,
+		},
+		{
+			name:       "rtt without replies",
+			thresholds: &Thresholds{MaxLoss: -1, MaxRTT: 10 * time.Millisecond},
+			stats:      &types.Statistics{Sent: 1, LossRate: 100},
+			violated:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.thresholds.Check("example.com", tc.stats)
+			if tc.violated != (err != nil) {
+				t.Fatalf("expected violated=%v, got err=%v", tc.violated, err)
+			}
+			if err != nil && !stderrors.Is(err, ErrThresholdExceeded) {
+				t.Fatalf("expected ErrThresholdExceeded, got %v", err)
+			}
+		})
+	}
+}
+
+// deadPinger 模拟完全不可达的目标
+type deadPinger struct{ fakePinger }
+
+func (p *deadPinger) Ping(_ context.Context, target string, _ *types.PingOptions) (*types.PingResult, error) {
+	return &types.PingResult{
+		Target:     &types.Host{Hostname: target},
+		Status:     types.StatusTimeout,
+		Statistics: &types.Statistics{Sent: 4, Received: 0, LossRate: 100},
+	}, nil
+}
+
+type staticFactory struct{ pinger types.Pinger }
+
+func (f staticFactory) Create(*types.PingOptions) (types.Pinger, error) { return f.pinger, nil }
+
+func TestBatchThresholdAppliesToDeadHost(t *testing.T) {
+	opts := types.DefaultPingOptions()
+	cfg := Config{Mode: ModeSummary, Thresholds: &Thresholds{MaxLoss: 50}}
+
+	err := runPingBatchConcurrent(context.Background(), staticFactory{&deadPinger{}}, []string{"192.0.2.1"}, opts, cfg)
+	if !stderrors.Is(err, ErrThresholdExceeded) {
+		t.Fatalf("err = %v, want ErrThresholdExceeded", err)
+	}
+
+	cfg.Thresholds = nil
+	err = runPingBatchConcurrent(context.Background(), staticFactory{&deadPinger{}}, []string{"192.0.2.1"}, opts, cfg)
+	if !stderrors.Is(err, ErrPartialFailure) {
+		t.Fatalf("err = %v, want ErrPartialFailure", err)
+	}
+}
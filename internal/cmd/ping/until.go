@@ -0,0 +1,45 @@
+package ping
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// ErrConditionNotMet 表示发送结束前 --until-up / --until-down 条件仍未满足
+var ErrConditionNotMet = stderrors.New("ping stop condition not met")
+
+// StopCondition 提前结束条件，满足后立即停止发送
+type StopCondition struct {
+	// UntilUp 收到首个成功响应即结束
+	UntilUp bool
+	// UntilDown 连续失败达到该次数即结束，0 表示不启用
+	UntilDown int
+}
+
+// Met 根据当前响应和连续失败次数判断条件是否满足，nil 条件永不满足
+func (c *StopCondition) Met(reply *types.PingReply, consecutiveFailures int) bool {
+	if c == nil || reply == nil {
+		return false
+	}
+	if c.UntilUp && reply.Status == types.StatusSuccess {
+		return true
+	}
+	return c.UntilDown > 0 && consecutiveFailures >= c.UntilDown
+}
+
+// String 返回条件描述
+func (c *StopCondition) String() string {
+	if c == nil {
+		return ""
+	}
+	switch {
+	case c.UntilUp && c.UntilDown > 0:
+		return fmt.Sprintf("until-up 或 until-down=%d", c.UntilDown)
+	case c.UntilUp:
+		return "until-up"
+	default:
+		return fmt.Sprintf("until-down=%d", c.UntilDown)
+	}
+}
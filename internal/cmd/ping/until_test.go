@@ -0,0 +1,33 @@
+package ping
+
+import (
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+func TestStopConditionMet(t *testing.T) {
+	success := &types.PingReply{Status: types.StatusSuccess}
+	timeout := &types.PingReply{Status: types.StatusTimeout}
+
+	var none *StopCondition
+	if none.Met(success, 0) {
+		t.Fatalf("nil condition should never be met")
+	}
+
+	up := &StopCondition{UntilUp: true}
+	if up.Met(timeout, 5) {
+		t.Fatalf("until-up should ignore failures")
+	}
+	if !up.Met(success, 0) {
+		t.Fatalf("until-up should be met on first success")
+	}
+
+	down := &StopCondition{UntilDown: 3}
+	if down.Met(success, 0) || down.Met(timeout, 2) {
+		t.Fatalf("until-down met too early")
+	}
+	if !down.Met(timeout, 3) {
+		t.Fatalf("until-down should be met after 3 consecutive failures")
+	}
+}
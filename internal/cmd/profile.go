@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	cpuProfile string
+	memProfile string
+
+	// cpuProfileFile 正在写入的 CPU profile，nil 表示未启用
+	cpuProfileFile *os.File
+)
+
+// startProfiling 按 --cpuprofile 开始采集 CPU profile
+func startProfiling() error {
+	if cpuProfile == "" {
+		return nil
+	}
+	f, err := os.Create(cpuProfile)
+	if err != nil {
+		return fmt.Errorf("创建 CPU profile 文件失败: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("启动 CPU profile 失败: %w", err)
+	}
+	cpuProfileFile = f
+	return nil
+}
+
+// exit 写出 profile 并刷新日志后以指定退出码结束进程
+//
+// 子命令需要非零退出码时应调用 exit 而不是 os.Exit，否则 Execute 中延迟的 stopProfiling 不会执行。
+func exit(code int) {
+	stopProfiling()
+	_ = logger.Sync()
+	os.Exit(code)
+}
+
+// stopProfiling 停止 CPU profile 并按 --memprofile 写出堆 profile
+//
+// 由 Execute 延迟调用，子命令提前退出时经 exit 调用。
+func stopProfiling() {
+	if cpuProfileFile != nil {
+		pprof.StopCPUProfile()
+		if err := cpuProfileFile.Close(); err != nil {
+			logger.Warn("关闭 CPU profile 文件失败", zap.Error(err))
+		}
+		cpuProfileFile = nil
+	}
+
+	if memProfile == "" {
+		return
+	}
+	f, err := os.Create(memProfile)
+	if err != nil {
+		logger.Warn("创建内存 profile 文件失败", zap.Error(err))
+		return
+	}
+	defer f.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		logger.Warn("写入内存 profile 失败", zap.Error(err))
+	}
+}
@@ -21,11 +21,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/catsayer/ntx/internal/app"
 	"github.com/catsayer/ntx/internal/config"
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/pkg/buildinfo"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -34,8 +37,10 @@ var (
 	globalFlags = app.GlobalFlags{
 		Output: "text",
 	}
-	configFile string
-	appCtx     *app.Context
+	configFile  string
+	dnsCacheTTL time.Duration
+	noDNSCache  bool
+	appCtx      *app.Context
 )
 
 // rootCmd 表示在没有任何子命令时调用的基本命令
@@ -67,11 +72,26 @@ var rootCmd = &cobra.Command{
 // 这被 main.main() 调用，只需要对 rootCmd 发生一次
 func Execute() error {
 	defer logger.Sync()
+	defer stopProfiling()
 	rootContext := rootCmd.Context()
 	if rootContext == nil {
 		rootContext = context.Background()
 	}
-	return rootCmd.ExecuteContext(rootContext)
+	err := rootCmd.ExecuteContext(rootContext)
+	printReverseCacheStats()
+	return err
+}
+
+// printReverseCacheStats 详细模式下输出反向解析缓存统计
+func printReverseCacheStats() {
+	if !globalFlags.Verbose {
+		return
+	}
+	stats := netutil.ReverseCacheStats()
+	if stats.Hits+stats.Misses == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "反向解析缓存: 命中 %d, 未命中 %d, 条目 %d\n", stats.Hits, stats.Misses, stats.Entries)
 }
 
 func init() {
@@ -81,9 +101,17 @@ func init() {
 
 	// 全局持久化标志
 	rootCmd.PersistentFlags().BoolVarP(&globalFlags.Verbose, "verbose", "v", false, "启用详细输出")
-	rootCmd.PersistentFlags().StringVarP(&globalFlags.Output, "output", "o", "text", "输出格式: text|json|yaml")
+	rootCmd.PersistentFlags().StringVarP(&globalFlags.Output, "output", "o", "text", "输出格式: text|json|yaml|ndjson")
 	rootCmd.PersistentFlags().BoolVar(&globalFlags.NoColor, "no-color", false, "禁用彩色输出")
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "配置文件路径 (默认自动搜索)")
+	rootCmd.PersistentFlags().DurationVar(&dnsCacheTTL, "dns-cache-ttl", types.DefaultDNSCacheTTL, "主机名解析缓存有效期")
+	rootCmd.PersistentFlags().BoolVar(&noDNSCache, "no-dns-cache", false, "禁用主机名解析缓存（调试用）")
+
+	// 性能分析标志，仅供开发调试，不在帮助中显示
+	rootCmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "将 CPU profile 写入文件")
+	rootCmd.PersistentFlags().StringVar(&memProfile, "memprofile", "", "命令结束时将堆 profile 写入文件")
+	_ = rootCmd.PersistentFlags().MarkHidden("cpuprofile")
+	_ = rootCmd.PersistentFlags().MarkHidden("memprofile")
 }
 
 // initConfig 初始化配置和日志系统
@@ -92,11 +120,11 @@ func initConfig() {
 	cfg, usedPath, err := loader.LoadWithEnv(configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "加载配置失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	if err := config.Validate(cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "配置验证失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	flags := rootCmd.PersistentFlags()
@@ -112,6 +140,13 @@ func initConfig() {
 	if globalFlags.Output == "" {
 		globalFlags.Output = "text"
 	}
+	if !flags.Changed("dns-cache-ttl") {
+		dnsCacheTTL = cfg.Global.DNSCacheTTL
+	}
+	if noDNSCache {
+		dnsCacheTTL = 0
+	}
+	netutil.SetDNSCacheTTL(dnsCacheTTL)
 
 	logConfig := logger.Config{
 		Level:             cfg.Global.LogLevel,
@@ -128,7 +163,18 @@ func initConfig() {
 
 	if err := logger.Init(logConfig); err != nil {
 		fmt.Fprintf(os.Stderr, "初始化日志系统失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
+	}
+
+	if cfg.Global.OUIFile != "" {
+		if err := netutil.LoadVendorFile(cfg.Global.OUIFile); err != nil {
+			logger.Warn("加载 OUI 文件失败，使用内置厂商表", zap.Error(err))
+		}
+	}
+
+	if err := startProfiling(); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		exit(1)
 	}
 
 	appCtx = app.NewContext(cfg, globalFlags)
@@ -145,7 +191,7 @@ func initConfig() {
 func injectAppContext(cmd *cobra.Command, _ []string) {
 	if appCtx == nil {
 		fmt.Fprintln(os.Stderr, "应用上下文未初始化")
-		os.Exit(1)
+		exit(1)
 	}
 	if ctx, ok := app.FromContext(cmd.Context()); ok && ctx != nil {
 		return
@@ -2,6 +2,7 @@
 //
 // 本文件实现端口扫描命令，支持:
 // - TCP Connect 扫描
+// - UDP 扫描
 // - 自定义端口列表
 // - 服务识别
 // - 多种输出格式
@@ -11,6 +12,7 @@
 //	ntx scan 192.168.1.1 -p 1-1024
 //	ntx scan example.com --service
 //	ntx scan 10.0.0.1 -o json
+//	ntx scan 10.0.0.1 -p 1-1024 -o ndjson
 //
 // 作者: Catsayer
 package cmd
@@ -18,9 +20,12 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/catsayer/ntx/internal/app"
@@ -28,6 +33,7 @@ import (
 	"github.com/catsayer/ntx/internal/core/scan"
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/types"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -41,6 +47,19 @@ var (
 	scanConcurrency int
 	scanService     bool
 	scanFast        bool
+	scanProbes      string
+	scanDryRun      bool
+	scanSourcePort  int
+	scanLimit       int
+	scanResolve     bool
+	scanExclPorts   string
+	scanExclHosts   string
+	scanColumns     string
+	scanWide        bool
+	scanUDP         bool
+	scanBanner      bool
+	scanOpenOnly    bool
+	scanTopPorts    int
 )
 
 var scanCmd = &cobra.Command{
@@ -50,8 +69,9 @@ var scanCmd = &cobra.Command{
 
 支持功能:
   • TCP Connect 扫描（无需特权）
+  • UDP 扫描（无响应的端口报告为 open|filtered）
   • 自定义端口列表
-  • 服务识别
+  • 服务识别与 Banner 抓取
   • 并发扫描
   • 多种输出格式
 
@@ -59,9 +79,20 @@ var scanCmd = &cobra.Command{
   ntx scan 192.168.1.1                  # 扫描常用端口
   ntx scan 192.168.1.1 -p 1-1024        # 扫描端口范围
   ntx scan 192.168.1.1 -p 80,443,8080   # 扫描指定端口
+  ntx scan 192.168.1.1 --top-ports 100  # 扫描最常见的 100 个端口
+  ntx scan 192.168.1.1 --udp -p 53,123,161  # UDP 扫描
   ntx scan example.com --service        # 启用服务识别
+  ntx scan example.com -p 21,22,80 --banner  # 抓取 Banner 并解析版本
+  ntx scan example.com --probes my.probes  # 使用自定义探测文件识别服务
   ntx scan 192.168.1.1 --fast           # 快速扫描
-  ntx scan 192.168.1.1 -o json          # JSON 输出`,
+  ntx scan 192.168.1.1 --source-port 53 # 从固定源端口发起连接
+  ntx scan 192.168.1.1 --resolve        # 反向解析目标主机名
+  ntx scan 192.168.1.1 -p 1-1024 --exclude-ports 22,3389  # 跳过敏感端口
+  ntx scan 192.168.1.1 --service --columns port,service,banner  # 自定义表格列
+  ntx scan 192.168.1.0 -p 1-1024 --dry-run  # 仅显示扫描范围，不发送数据包
+  ntx scan 192.168.1.1 -o json          # JSON 输出
+  ntx scan 192.168.1.1 -o ndjson        # 逐端口 NDJSON 流式输出
+  ntx scan 192.168.1.1 -o json --open-only  # 仅输出开放端口`,
 	Args: cobra.ExactArgs(1),
 	RunE: runScan,
 }
@@ -71,10 +102,23 @@ func init() {
 
 	// 扫描参数
 	scanCmd.Flags().StringVarP(&scanPorts, "ports", "p", "", "端口列表 (如: 80,443 或 1-1024)")
+	scanCmd.Flags().IntVar(&scanTopPorts, "top-ports", 0, fmt.Sprintf("扫描最常见的 N 个端口（最多 %d 个，-p 优先）", scan.MaxTopPorts))
 	scanCmd.Flags().IntVarP(&scanTimeout, "timeout", "t", 3, "单端口超时时间（秒）")
 	scanCmd.Flags().IntVarP(&scanConcurrency, "concurrency", "c", 100, "并发扫描数量")
+	scanCmd.Flags().BoolVar(&scanUDP, "udp", false, "UDP 扫描（收到应答为 open，ICMP 端口不可达为 closed，无响应为 open|filtered）")
 	scanCmd.Flags().BoolVar(&scanService, "service", false, "启用服务识别")
+	scanCmd.Flags().BoolVar(&scanBanner, "banner", false, "抓取开放端口的 Banner 并解析版本（HTTP 端口发送 GET 请求）")
 	scanCmd.Flags().BoolVar(&scanFast, "fast", false, "快速扫描模式（仅检测开放端口）")
+	scanCmd.Flags().StringVar(&scanProbes, "probes", "", "服务探测文件（nmap-service-probes 格式子集），隐含 --service")
+	scanCmd.Flags().IntVar(&scanSourcePort, "source-port", 0, "固定本地源端口（如 53），用于测试基于源端口的防火墙规则")
+	scanCmd.Flags().IntVar(&scanLimit, "limit", 0, "最多显示 N 个端口，0 表示不限制（统计信息不受影响）")
+	scanCmd.Flags().BoolVar(&scanResolve, "resolve", false, "反向解析目标 IP 的主机名")
+	scanCmd.Flags().StringVar(&scanExclPorts, "exclude-ports", "", "扫描前排除的端口列表（格式同 --ports）")
+	scanCmd.Flags().StringVar(&scanExclHosts, "exclude-hosts", "", "排除的主机列表（IP 或 CIDR，逗号分隔），目标命中时拒绝扫描")
+	scanCmd.Flags().StringVar(&scanColumns, "columns", "", "开放端口表格的列及顺序，逗号分隔 (port,proto,state,service,banner,rtt；仅文本输出)")
+	scanCmd.Flags().BoolVar(&scanOpenOnly, "open-only", false, "仅输出开放端口（统计信息不受影响）")
+	scanCmd.Flags().BoolVar(&scanWide, "wide", false, "不截断过长的单元格，行宽可超出终端宽度（便于管道处理）")
+	scanCmd.Flags().BoolVar(&scanDryRun, "dry-run", false, "仅显示解析后的目标、端口与预计发包数，不执行扫描")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -84,6 +128,19 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	logger.Info("开始端口扫描", zap.String("target", target))
 
+	formatter.SetWideTables(scanWide)
+	cols := formatter.DefaultScanPortColumns()
+	if scanBanner {
+		cols = formatter.BannerScanPortColumns()
+	}
+	if scanColumns != "" {
+		selected, err := formatter.SelectColumns(formatter.ScanPortColumns, scanColumns)
+		if err != nil {
+			return err
+		}
+		cols = selected
+	}
+
 	// 仅文本模式显示安全提示，避免污染结构化输出
 	if outputFormat == types.OutputText || outputFormat == "" {
 		color.NoColor = appCtx.Flags.NoColor
@@ -96,23 +153,91 @@ func runScan(cmd *cobra.Command, args []string) error {
 	// 构建扫描选项
 	opts := buildScanOptions(cmd, appCtx)
 
-	// 解析端口列表
+	// 解析端口列表，-p 优先于 --top-ports
 	if scanPorts != "" {
 		ports, err := parsePortList(scanPorts)
 		if err != nil {
 			return fmt.Errorf("解析端口列表失败: %w", err)
 		}
 		opts.Ports = ports
+	} else if cmd.Flags().Changed("top-ports") {
+		if scanTopPorts <= 0 {
+			return fmt.Errorf("无效的 --top-ports: %d，必须大于 0", scanTopPorts)
+		}
+		opts.Ports = scan.TopPorts(scanTopPorts)
 	}
 
-	// 快速扫描模式
-	if scanFast {
-		opts.Timeout = 1 * time.Second
-		opts.Concurrency = 200
+	if scanExclPorts != "" {
+		excluded, err := parsePortList(scanExclPorts)
+		if err != nil {
+			return fmt.Errorf("解析排除端口列表失败: %w", err)
+		}
+		opts.Ports = scan.ExcludePorts(opts.Ports, excluded)
+		if len(opts.Ports) == 0 {
+			return fmt.Errorf("排除后没有剩余需要扫描的端口")
+		}
+	}
+
+	if scanExclHosts != "" {
+		exclusions, err := scan.ParseHostExclusions(scanExclHosts)
+		if err != nil {
+			return fmt.Errorf("解析排除主机列表失败: %w", err)
+		}
+		ip, err := scan.ResolveTarget(target)
+		if err != nil {
+			return fmt.Errorf("解析目标失败: %w", err)
+		}
+		if exclusions.Contains(ip) {
+			return fmt.Errorf("目标 %s (%s) 在 --exclude-hosts 排除列表中", target, ip)
+		}
+	}
+
+	if opts.SourcePort < 0 || opts.SourcePort > types.MaxPort {
+		return fmt.Errorf("无效的源端口: %d", opts.SourcePort)
+	}
+
+	if opts.ScanMode == types.ScanUDP {
+		if scanProbes != "" {
+			return fmt.Errorf("--probes 仅支持 TCP 扫描")
+		}
+		if opts.SourcePort > 0 {
+			return fmt.Errorf("--source-port 仅支持 TCP 扫描")
+		}
+		if opts.BannerGrab {
+			return fmt.Errorf("--banner 仅支持 TCP 扫描")
+		}
 	}
 
 	// 创建扫描器
-	scanner := scan.NewTCPScanner()
+	var scanner scan.Scanner
+	if opts.ScanMode == types.ScanUDP {
+		scanner = scan.NewUDPScanner()
+	} else {
+		tcpScanner := scan.NewTCPScanner()
+		if scanProbes != "" {
+			probes, err := scan.LoadProbeFile(scanProbes)
+			if err != nil {
+				return fmt.Errorf("加载探测文件失败: %w", err)
+			}
+			tcpScanner.SetProbes(probes)
+			opts.ServiceDetect = true
+		}
+		scanner = tcpScanner
+	}
+
+	if scanDryRun {
+		plan, err := scan.NewPlan(target, opts)
+		if err != nil {
+			return fmt.Errorf("解析目标失败: %w", err)
+		}
+		return outputScanPlan(plan, appCtx.Flags)
+	}
+
+	if outputFormat == types.OutputNDJSON {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return streamScanNDJSON(ctx, scanner, target, opts, os.Stdout)
+	}
 
 	// 执行扫描
 	ctx := context.Background()
@@ -120,9 +245,73 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("扫描失败: %w", err)
 	}
+	if scanResolve && result.IP != nil {
+		if name, ok := netutil.ReverseLookup(ctx, result.IP.String()); ok {
+			result.Hostname = name
+		}
+	}
+
+	if scanOpenOnly {
+		result.Ports = openPortsOnly(result.Ports)
+	}
 
 	// 输出结果
-	return outputScanResult(result, appCtx.Flags)
+	return outputScanResult(result, cols, appCtx.Flags)
+}
+
+// streamScanNDJSON 以 NDJSON 实时输出每个端口结果，扫描结束后输出一条 summary 记录
+//
+// --open-only 与 --limit 只影响 port 记录，summary 统计全部已扫描端口；
+// 中断时输出已完成部分的统计。
+func streamScanNDJSON(ctx context.Context, scanner scan.Scanner, target string, opts types.ScanOptions, w io.Writer) error {
+	out := formatter.NewNDJSONWriter(w)
+	startTime := time.Now()
+
+	portCh, err := scanner.ScanStream(ctx, target, opts)
+	if err != nil {
+		return fmt.Errorf("扫描失败: %w", err)
+	}
+
+	result := &types.ScanResult{
+		Target:    target,
+		StartTime: startTime,
+		Technique: opts.ScanMode.String(),
+		Timing:    opts.Timing,
+	}
+	emitted, omitted := 0, 0
+	for port := range portCh {
+		if result.IP == nil {
+			result.IP = port.IP
+		}
+		result.Ports = append(result.Ports, port)
+		if scanOpenOnly && port.State != types.PortOpen {
+			continue
+		}
+		if scanLimit > 0 && emitted >= scanLimit {
+			omitted++
+			continue
+		}
+		if err := out.Write(formatter.NDJSONPort, target, port); err != nil {
+			return err
+		}
+		emitted++
+	}
+
+	result.EndTime = time.Now()
+	result.Summary = scan.CalculateSummary(result)
+	// 端口明细已逐条输出，summary 记录不再重复
+	result.Ports = nil
+	if scanResolve && result.IP != nil {
+		if name, ok := netutil.ReverseLookup(ctx, result.IP.String()); ok {
+			result.Hostname = name
+		}
+	}
+
+	if err := out.Write(formatter.NDJSONSummary, target, result); err != nil {
+		return err
+	}
+	printOmitted(os.Stderr, omitted)
+	return nil
 }
 
 func buildScanOptions(cmd *cobra.Command, appCtx *app.Context) types.ScanOptions {
@@ -152,6 +341,15 @@ func buildScanOptions(cmd *cobra.Command, appCtx *app.Context) types.ScanOptions
 			if flags.Changed("service") {
 				opts.ServiceDetect = scanService
 			}
+			if flags.Changed("source-port") {
+				opts.SourcePort = scanSourcePort
+			}
+			if scanUDP {
+				opts.ScanMode = types.ScanUDP
+			}
+			if flags.Changed("banner") {
+				opts.BannerGrab = scanBanner
+			}
 		}).
 		Result()
 
@@ -161,6 +359,7 @@ func buildScanOptions(cmd *cobra.Command, appCtx *app.Context) types.ScanOptions
 	if scanFast {
 		opts.Timeout = 1 * time.Second
 		opts.Concurrency = 200
+		opts.Timing = types.ScanTimingFast
 	}
 
 	return opts
@@ -217,69 +416,63 @@ func parsePortList(portStr string) ([]int, error) {
 	return ports, nil
 }
 
+// openPortsOnly 过滤出状态为 open 的端口
+func openPortsOnly(ports []*types.ScanPort) []*types.ScanPort {
+	open := make([]*types.ScanPort, 0, len(ports))
+	for _, port := range ports {
+		if port.State == types.PortOpen {
+			open = append(open, port)
+		}
+	}
+	return open
+}
+
 // outputScanResult 输出扫描结果
-func outputScanResult(result *types.ScanResult, flags app.GlobalFlags) error {
+func outputScanResult(result *types.ScanResult, cols []formatter.Column[*types.ScanPort], flags app.GlobalFlags) error {
 	outputFormat := types.OutputFormat(flags.Output)
 	if outputFormat == types.OutputText || outputFormat == "" {
-		return outputScanText(result, flags)
+		return outputScanText(result, cols, flags)
 	}
 
-	f := formatter.NewFormatter(outputFormat, flags.NoColor)
-	return f.FormatTo(os.Stdout, result)
-}
-
-// outputScanText 文本格式输出
-func outputScanText(result *types.ScanResult, flags app.GlobalFlags) error {
-	color.NoColor = flags.NoColor
-	// 打印标题
-	fmt.Println()
-	fmt.Println("================================================================================")
-	fmt.Printf("  扫描报告: %s (%s)\n", result.Target, result.IP.String())
-	fmt.Println("================================================================================")
-	fmt.Println()
+	limited := *result
+	var omitted int
+	limited.Ports, omitted = limitItems(result.Ports, scanLimit)
 
-	// 显示开放端口
-	openPorts := make([]*types.ScanPort, 0)
-	for _, port := range result.Ports {
-		if port.State == types.PortOpen {
-			openPorts = append(openPorts, port)
-		}
+	f := formatter.NewFormatter(outputFormat, flags.NoColor)
+	if err := f.FormatTo(os.Stdout, &limited); err != nil {
+		return err
 	}
+	printOmitted(os.Stderr, omitted)
+	return nil
+}
 
-	if len(openPorts) > 0 {
-		fmt.Println(color.GreenString("开放端口:"))
-		table := formatter.NewTable(
-			[]string{"端口", "状态", "服务", "响应时间"},
-			[]int{10, 15, 20, 15},
-		)
-		for _, port := range openPorts {
-			stateStr := color.GreenString(port.State.String())
-			serviceStr := port.Service
-			if serviceStr == "" || serviceStr == "unknown" {
-				serviceStr = "-"
-			}
-			table.AddRow(
-				fmt.Sprintf("%d", port.Port),
-				stateStr,
-				serviceStr,
-				port.ResponseTime.Round(time.Millisecond).String(),
-			)
-		}
-		table.Render(os.Stdout)
-		fmt.Println()
-	} else {
-		fmt.Println(color.YellowString("未发现开放端口"))
-		fmt.Println()
+// outputScanPlan 输出 --dry-run 扫描计划
+func outputScanPlan(plan *scan.Plan, flags app.GlobalFlags) error {
+	outputFormat := types.OutputFormat(flags.Output)
+	if outputFormat != types.OutputText && outputFormat != "" {
+		f := formatter.NewFormatter(outputFormat, flags.NoColor)
+		return f.FormatTo(os.Stdout, plan)
 	}
 
-	// 显示统计信息
-	fmt.Println(">>> 统计信息")
-	fmt.Printf("总端口数:   %d\n", result.Summary.TotalPorts)
-	fmt.Printf("开放端口:   %s\n", color.GreenString("%d", result.Summary.OpenPorts))
-	fmt.Printf("关闭端口:   %d\n", result.Summary.ClosedPorts)
-	fmt.Printf("过滤端口:   %d\n", result.Summary.FilteredPorts)
-	fmt.Printf("扫描耗时:   %s\n", result.Summary.Duration.Round(time.Millisecond))
+	color.NoColor = flags.NoColor
+	fmt.Println(color.CyanString("扫描计划 (dry-run，未发送任何数据包):"))
+	printScanPlanFields(plan, "  ")
 	fmt.Println()
+	return nil
+}
 
+func printScanPlanFields(plan *scan.Plan, indent string) {
+	fmt.Printf("%s目标:       %s (%s)\n", indent, plan.Target, plan.IP)
+	fmt.Printf("%s端口:       %s (%d 个)\n", indent, plan.Ports, plan.PortCount)
+	fmt.Printf("%s并发数:     %d\n", indent, plan.Concurrency)
+	fmt.Printf("%s超时:       %s\n", indent, plan.Timeout)
+	fmt.Printf("%s服务识别:   %t\n", indent, plan.ServiceDetect)
+	fmt.Printf("%s扫描方式:   %s (时序: %s)\n", indent, plan.Technique, plan.Timing)
+	fmt.Printf("%s预计发包:   %d\n", indent, plan.EstimatedPackets)
+}
+
+// outputScanText 文本格式输出
+func outputScanText(result *types.ScanResult, cols []formatter.Column[*types.ScanPort], flags app.GlobalFlags) error {
+	fmt.Print(formatter.FormatScanText(result, cols, scanLimit, flags.NoColor))
 	return nil
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/scan"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+func TestStreamScanNDJSON(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	openPort := ln.Addr().(*net.TCPAddr).Port
+
+	// 获取一个已释放的端口作为关闭端口
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{openPort, closedPort}
+	opts.Timeout = time.Second
+
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = streamScanNDJSON(context.Background(), scan.NewTCPScanner(), "127.0.0.1", opts, os.Stdout)
+	})
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 port records and a summary, got %d lines:\n%s", len(lines), out)
+	}
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v\n%s", i, err, line)
+		}
+		want := "port"
+		if i == len(lines)-1 {
+			want = "summary"
+		}
+		if record["type"] != want || record["target"] != "127.0.0.1" {
+			t.Fatalf("line %d: unexpected record %v", i, record)
+		}
+	}
+
+	var summary struct {
+		Data types.ScanResult `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Data.Summary == nil || summary.Data.Summary.TotalPorts != 2 || summary.Data.Summary.OpenPorts != 1 {
+		t.Fatalf("unexpected summary: %+v", summary.Data.Summary)
+	}
+	if len(summary.Data.Ports) != 0 {
+		t.Fatalf("summary should not repeat port records: %+v", summary.Data.Ports)
+	}
+}
@@ -0,0 +1,95 @@
+// Package cmd 提供 serve 命令实现
+//
+// 本文件实现常驻监控服务命令，支持:
+// - 按 schedule 周期执行批量任务配置中的任务
+// - 通过 HTTP 暴露最近一次结果（JSON / Prometheus）
+// - 健康检查端点
+//
+// 使用示例:
+//
+//	ntx serve -f tasks.yaml --port 9100
+//
+// 作者: Catsayer
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/batch"
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/internal/server"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	serveFile     string
+	servePort     int
+	serveBind     string
+	serveInterval time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:          "serve",
+	Short:        "常驻监控服务",
+	SilenceUsage: true,
+	Long: `以常驻服务方式周期执行批量任务，并通过 HTTP 暴露最近一次结果，
+可作为轻量的合成监控守护进程供 Prometheus 抓取。
+
+任务配置与 batch 命令相同，schedule 字段指定执行间隔（如 "30s" 或 "@every 5m"），
+未配置时使用 --interval。
+
+HTTP 端点:
+  /results  JSON 格式的任务结果
+  /metrics  Prometheus 文本格式指标
+  /healthz  健康检查
+
+示例:
+  ntx serve -f tasks.yaml                        # 在 9100 端口提供服务
+  ntx serve -f tasks.yaml --port 9200 --interval 30s
+  ntx serve -f tasks.yaml --bind 127.0.0.1       # 仅本机可访问`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&serveFile, "file", "f", "", "任务配置文件路径（YAML 格式，同 batch）")
+	serveCmd.Flags().IntVar(&servePort, "port", 9100, "HTTP 监听端口")
+	serveCmd.Flags().StringVar(&serveBind, "bind", "", "HTTP 监听地址，默认监听所有地址")
+	serveCmd.Flags().DurationVar(&serveInterval, "interval", time.Minute, "未配置 schedule 的任务的执行间隔")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if serveFile == "" {
+		return fmt.Errorf("请使用 -f 参数指定任务配置文件")
+	}
+	if servePort <= 0 || servePort > 65535 {
+		return fmt.Errorf("无效的端口: %d", servePort)
+	}
+
+	tasks, err := batch.LoadFile(serveFile)
+	if err != nil {
+		return err
+	}
+	srv, err := server.New(batch.NewExecutor(), tasks, serveInterval)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	addr := net.JoinHostPort(serveBind, strconv.Itoa(servePort))
+	logger.Info("启动监控服务", zap.String("file", serveFile), zap.String("addr", addr))
+	fmt.Fprintf(os.Stderr, "监控服务监听 %s (/results, /metrics, /healthz)，按 Ctrl+C 停止\n", addr)
+
+	return srv.ListenAndServe(ctx, addr)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/catsayer/ntx/internal/app"
@@ -29,6 +30,15 @@ var (
 	traceIPv4     bool
 	traceIPv6     bool
 	traceFirstTTL int
+	traceProtocol string
+	traceNoDNS    bool
+	traceRepeat   int
+	traceInterval time.Duration
+	traceParis    bool
+	traceFlows    int
+	traceWide     bool
+	traceASN      bool
+	traceParallel bool
 )
 
 // traceCmd 表示 trace 命令
@@ -42,10 +52,18 @@ Traceroute 通过发送具有递增 TTL (Time To Live) 值的数据包来追踪
 每个路由器在转发数据包时会将 TTL 减 1，当 TTL 为 0 时会返回 ICMP Time Exceeded 消息。
 通过这种方式，可以逐跳发现数据包经过的路由器。
 
-ICMP Traceroute:
+ICMP Traceroute (默认):
   使用 ICMP Echo Request 数据包
   需要 root 权限或 CAP_NET_RAW 能力
 
+UDP Traceroute (--protocol udp):
+  经典 traceroute 方式，向目标端口（默认 33434 起递增）发送 UDP 数据报，
+  收到 ICMP Port Unreachable 即到达目标，需要 root 权限或 CAP_NET_RAW 能力
+
+TCP Traceroute (--protocol tcp):
+  向目标端口（默认 80）发送 SYN，收到 SYN/ACK 或 RST 即到达目标
+  常可穿透过滤 ICMP/UDP 的防火墙，需要 root 权限或 CAP_NET_RAW 能力
+
 示例:
   # 基本 traceroute
   ntx trace google.com
@@ -56,12 +74,39 @@ ICMP Traceroute:
   # 每跳查询 5 次
   ntx trace google.com --queries 5
 
+  # UDP traceroute（类似传统 traceroute 默认行为）
+  ntx trace google.com --protocol udp
+
+  # TCP traceroute 到 443 端口
+  ntx trace google.com --protocol tcp --port 443
+
+  # 不做反向 DNS 解析（类似 traceroute -n）
+  ntx trace google.com -n
+
+  # 并发探测所有跳，缩短远端目标的追踪时间（仅 ICMP）
+  ntx trace google.com --parallel
+
+  # 显示各跳所属的 AS 与国家（经 Team Cymru whois 查询）
+  ntx trace google.com --asn
+
+  # 重复 10 轮并汇总各跳出现率与路径变化，识别负载均衡/抖动路由
+  ntx trace google.com --repeat 10 --interval 5s
+
+  # Paris traceroute：保持流标识不变，避免 ECMP 负载均衡造成的虚假跳
+  ntx trace google.com --paris
+
+  # 依次使用 8 个不同的流标识，枚举负载均衡下的多条路径
+  ntx trace google.com --paris --flows 8
+
   # 从第 5 跳开始
   ntx trace google.com --first-ttl 5
 
   # 表格输出
   ntx trace google.com -o table
 
+  # 不截断过长的主机名（便于管道处理）
+  ntx trace google.com --wide
+
   # JSON 输出
   ntx trace google.com -o json`,
 	Args: cobra.ExactArgs(1),
@@ -79,9 +124,27 @@ func init() {
 	traceCmd.Flags().IntVarP(&traceQueries, "queries", "q", 3,
 		"每跳查询次数")
 	traceCmd.Flags().IntVarP(&tracePort, "port", "p", 33434,
-		"起始端口号（UDP）")
+		"目标端口号（UDP 起始端口，TCP 默认 80）")
+	traceCmd.Flags().StringVar(&traceProtocol, "protocol", "icmp",
+		"探测协议 (icmp, udp, tcp)")
 	traceCmd.Flags().IntVar(&traceFirstTTL, "first-ttl", 1,
 		"起始 TTL 值")
+	traceCmd.Flags().BoolVarP(&traceNoDNS, "no-resolve", "n", false,
+		"不解析各跳主机名，仅显示 IP")
+	traceCmd.Flags().IntVar(&traceRepeat, "repeat", 1,
+		"重复追踪 N 轮并汇总各跳统计与路径变化")
+	traceCmd.Flags().DurationVar(&traceInterval, "interval", time.Second,
+		"重复追踪时每轮之间的间隔（配合 --repeat）")
+	traceCmd.Flags().BoolVar(&traceParis, "paris", false,
+		"Paris traceroute：所有探测保持相同的流标识（ICMP 校验和 / TCP 源端口）")
+	traceCmd.Flags().IntVar(&traceFlows, "flows", 1,
+		"使用 N 个不同的流标识分别追踪并汇总，枚举等价多路径（隐含 --paris）")
+	traceCmd.Flags().BoolVar(&traceParallel, "parallel", false,
+		"并发探测所有跳而非逐跳等待，缩短总耗时（仅 ICMP）")
+	traceCmd.Flags().BoolVar(&traceASN, "asn", false,
+		"查询各跳地址所属的 AS 号、AS 名称与国家（私有地址不查询）")
+	traceCmd.Flags().BoolVar(&traceWide, "wide", false,
+		"不截断过长的单元格，行宽可超出终端宽度（便于管道处理）")
 
 	// IP 版本选项
 	traceCmd.Flags().BoolVarP(&traceIPv4, "ipv4", "4", false,
@@ -102,36 +165,69 @@ func runTrace(cmd *cobra.Command, args []string) {
 	// 验证参数
 	if opts.MaxHops <= 0 || opts.MaxHops > 255 {
 		fmt.Fprintf(os.Stderr, "错误: 无效的最大跳数 %d，必须在 1-255 之间\n", opts.MaxHops)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if opts.Queries <= 0 || opts.Queries > 10 {
 		fmt.Fprintf(os.Stderr, "错误: 无效的查询次数 %d，必须在 1-10 之间\n", opts.Queries)
-		os.Exit(1)
+		exit(1)
 	}
 
 	if opts.FirstTTL <= 0 || opts.FirstTTL > opts.MaxHops {
 		fmt.Fprintf(os.Stderr, "错误: 无效的起始 TTL %d，必须在 1-%d 之间\n", opts.FirstTTL, opts.MaxHops)
-		os.Exit(1)
+		exit(1)
+	}
+	if traceRepeat <= 0 {
+		fmt.Fprintf(os.Stderr, "错误: 无效的重复次数 %d，必须大于 0\n", traceRepeat)
+		exit(1)
+	}
+	if traceFlows <= 0 {
+		fmt.Fprintf(os.Stderr, "错误: 无效的流数量 %d，必须大于 0\n", traceFlows)
+		exit(1)
+	}
+	if traceFlows > 1 && traceRepeat > 1 {
+		fmt.Fprintln(os.Stderr, "错误: --flows 不能与 --repeat 同时使用")
+		exit(1)
 	}
-	if opts.Protocol != "" && opts.Protocol != types.ProtocolICMP {
-		fmt.Fprintf(os.Stderr, "错误: 当前仅支持 ICMP traceroute，收到协议: %s\n", opts.Protocol)
-		os.Exit(1)
+	if traceFlows > 1 {
+		opts.Paris = true
+	}
+	if traceInterval < 0 {
+		fmt.Fprintf(os.Stderr, "错误: 无效的间隔 %s\n", traceInterval)
+		exit(1)
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = types.ProtocolICMP
+	}
+	if opts.Parallel && opts.Protocol != types.ProtocolICMP {
+		fmt.Fprintln(os.Stderr, "错误: --parallel 仅支持 icmp 协议")
+		exit(1)
+	}
+	switch opts.Protocol {
+	case types.ProtocolICMP, types.ProtocolTCP:
+	case types.ProtocolUDP:
+		if opts.Paris {
+			fmt.Fprintln(os.Stderr, "错误: --paris/--flows 暂不支持 udp 协议")
+			exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "错误: 不支持的 traceroute 协议: %s (支持 icmp, udp, tcp)\n", opts.Protocol)
+		exit(1)
 	}
 
 	// 创建 Tracer
-	tracer, err := trace.NewICMPTracer()
+	tracer, err := trace.NewTracer(opts.Protocol)
 	if err != nil {
 		if errors.IsPermissionDenied(err) {
-			logger.Error("ICMP Traceroute 需要 root 权限", zap.Error(err))
-			fmt.Fprintf(os.Stderr, "错误: ICMP Traceroute 需要 root 权限或 CAP_NET_RAW 能力\n")
+			logger.Error("Traceroute 需要 root 权限", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "错误: %s Traceroute 需要 root 权限或 CAP_NET_RAW 能力\n", strings.ToUpper(string(opts.Protocol)))
 			fmt.Fprintf(os.Stderr, "提示: 请使用 sudo 运行命令\n")
 			fmt.Fprintf(os.Stderr, "      sudo %s trace %s\n", os.Args[0], target)
 		} else {
 			logger.Error("创建 Tracer 失败", zap.Error(err))
 			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
 		}
-		os.Exit(1)
+		exit(1)
 	}
 	defer tracer.Close()
 
@@ -140,30 +236,94 @@ func runTrace(cmd *cobra.Command, args []string) {
 	if traceCtx == nil {
 		traceCtx = context.Background()
 	}
+	outputFormat := types.OutputFormat(appCtx.Flags.Output)
+	noColor := appCtx.Flags.NoColor
+	formatter.SetWideTables(traceWide)
+
+	if traceFlows > 1 {
+		runTraceRepeat(traceCtx, tracer, target, opts, traceFlows, 0, true, outputFormat, noColor)
+		return
+	}
+	if traceRepeat > 1 {
+		runTraceRepeat(traceCtx, tracer, target, opts, traceRepeat, traceInterval, false, outputFormat, noColor)
+		return
+	}
+
 	result, err := tracer.Trace(traceCtx, target, opts)
 	if err != nil {
 		logger.Error("Traceroute 失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	// 格式化输出
-	outputFormat := types.OutputFormat(appCtx.Flags.Output)
-	noColor := appCtx.Flags.NoColor
-
 	f := formatter.NewFormatter(outputFormat, noColor)
 	output, err := f.Format(result)
 	if err != nil {
 		logger.Error("格式化输出失败", zap.Error(err))
 		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	fmt.Print(output)
 
 	// 根据结果设置退出码
 	if !result.ReachedDestination {
-		os.Exit(1)
+		exit(1)
+	}
+}
+
+// runTraceRepeat 执行 runs 轮 Traceroute 并输出汇总；varyFlow 为 true 时每轮使用不同的流标识。
+// 任何一轮都未到达目标时退出码为 1
+func runTraceRepeat(ctx context.Context, tracer types.Tracer, target string, opts *types.TraceOptions, runs int, interval time.Duration, varyFlow bool, outputFormat types.OutputFormat, noColor bool) {
+	label := "轮"
+	if varyFlow {
+		label = "流"
+	}
+	results := make([]*types.TraceResult, 0, runs)
+	for i := 0; i < runs; i++ {
+		if i > 0 && interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		runOpts := *opts
+		if varyFlow {
+			runOpts.FlowID = i
+		}
+		result, err := tracer.Trace(ctx, target, &runOpts)
+		if err != nil {
+			logger.Error("Traceroute 失败", zap.Error(err))
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			exit(1)
+		}
+		results = append(results, result)
+		if outputFormat == types.OutputText || outputFormat == "" {
+			status := "未到达目标"
+			if result.ReachedDestination {
+				status = "到达目标"
+			}
+			fmt.Fprintf(os.Stderr, "第 %d/%d %s: %s，%d 跳\n", i+1, runs, label, status, result.HopCount)
+		}
+	}
+
+	agg := trace.Aggregate(results)
+	f := formatter.NewFormatter(outputFormat, noColor)
+	output, err := f.Format(agg)
+	if err != nil {
+		logger.Error("格式化输出失败", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "错误: 格式化输出失败: %v\n", err)
+		exit(1)
+	}
+	fmt.Print(output)
+
+	if agg.Reached == 0 {
+		exit(1)
 	}
 }
 
@@ -217,6 +377,25 @@ func buildTraceOptions(cmd *cobra.Command, appCtx *app.Context) *types.TraceOpti
 			if flags.Changed("first-ttl") {
 				opts.FirstTTL = traceFirstTTL
 			}
+			if flags.Changed("protocol") {
+				opts.Protocol = types.Protocol(strings.ToLower(traceProtocol))
+			}
+			if flags.Changed("no-resolve") {
+				opts.NoResolve = traceNoDNS
+			}
+			if flags.Changed("paris") {
+				opts.Paris = traceParis
+			}
+			if flags.Changed("parallel") {
+				opts.Parallel = traceParallel
+			}
+			if flags.Changed("asn") {
+				opts.ResolveASN = traceASN
+			}
+			// TCP 模式下未指定端口时使用 80，而不是 UDP 的 33434
+			if opts.Protocol == types.ProtocolTCP && !flags.Changed("port") && opts.Port == types.DefaultTraceroutePort {
+				opts.Port = trace.DefaultTCPTracePort
+			}
 			if flags.Changed("ipv4") && traceIPv4 {
 				opts.IPVersion = types.IPv4
 			} else if flags.Changed("ipv6") && traceIPv6 {
@@ -19,21 +19,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/catsayer/ntx/internal/app"
 	"github.com/catsayer/ntx/internal/core/whois"
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/internal/output/formatter"
 	"github.com/catsayer/ntx/pkg/types"
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
 var (
-	whoisServer string
-	whoisRaw    bool
+	whoisServer      string
+	whoisRaw         bool
+	whoisTimeout     float64
+	whoisConcurrency int
+	whoisNoFollow    bool
+	whoisRateLimit   float64
+	whoisExpiryWarn  int
 )
 
 var whoisCmd = &cobra.Command{
@@ -54,7 +61,11 @@ var whoisCmd = &cobra.Command{
   ntx whois AS15169                   # AS 号查询
   ntx whois google.com --server whois.verisign-grs.com
   ntx whois google.com baidu.com      # 批量查询
+  ntx whois a.com b.org c.net -c 8 -t 5  # 批量查询，8 并发，单次超时 5 秒
+  ntx whois a.com b.com --rate-limit 3   # 同一 Whois 服务器每 3 秒最多查询一次
   ntx whois google.com --raw          # 显示原始响应
+  ntx whois a.com b.com --expiry-warn 30  # 任一域名 30 天内到期时以退出码 2 结束
+  ntx whois google.com --no-follow    # 不跟随注册商 Whois 服务器（仅查询注册局）
   ntx whois google.com -o json        # JSON 输出`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runWhois,
@@ -65,21 +76,47 @@ func init() {
 
 	whoisCmd.Flags().StringVar(&whoisServer, "server", "", "指定 Whois 服务器")
 	whoisCmd.Flags().BoolVar(&whoisRaw, "raw", false, "显示原始响应")
+	whoisCmd.Flags().Float64VarP(&whoisTimeout, "timeout", "t", types.DefaultWhoisOptions().Timeout.Seconds(),
+		"单次查询超时时间（秒）")
+	whoisCmd.Flags().IntVarP(&whoisConcurrency, "concurrency", "c", types.DefaultWhoisOptions().Concurrency,
+		"批量查询并发数（同一 Whois 服务器始终串行）")
+	whoisCmd.Flags().Float64Var(&whoisRateLimit, "rate-limit", types.DefaultWhoisOptions().RateLimit.Seconds(),
+		"批量查询时同一 Whois 服务器两次查询的最小间隔（秒，0 表示不限制）")
+	whoisCmd.Flags().IntVar(&whoisExpiryWarn, "expiry-warn", 0,
+		"域名已过期或在 N 天内到期时输出警告并以退出码 2 结束（0 表示不检查）")
+	whoisCmd.Flags().BoolVar(&whoisNoFollow, "no-follow", false,
+		"不跟随响应中的注册商 Whois 服务器再次查询")
 }
 
 func runWhois(cmd *cobra.Command, args []string) error {
 	appCtx := mustAppContext(cmd)
 	queries := args
+	if whoisExpiryWarn < 0 {
+		return fmt.Errorf("无效的 --expiry-warn: %d，必须大于等于 0", whoisExpiryWarn)
+	}
 
 	logger.Info("开始 Whois 查询", zap.Int("queries", len(queries)))
 
 	// 构建查询选项
 	opts := types.DefaultWhoisOptions()
 	opts.Server = whoisServer
+	if whoisTimeout > 0 {
+		opts.Timeout = time.Duration(whoisTimeout * float64(time.Second))
+	}
+	if whoisConcurrency > 0 {
+		opts.Concurrency = whoisConcurrency
+	}
+	if whoisRateLimit >= 0 {
+		opts.RateLimit = time.Duration(whoisRateLimit * float64(time.Second))
+	}
+	if whoisNoFollow {
+		opts.FollowReferrals = false
+	}
 
 	// 创建 Whois 客户端
 	client := whois.NewClient()
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// 如果是单个查询
 	if len(queries) == 1 {
@@ -87,7 +124,11 @@ func runWhois(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("Whois 查询失败: %w", err)
 		}
-		return outputWhoisResult(result, appCtx.Flags)
+		if err := outputWhoisResult(result, appCtx.Flags); err != nil {
+			return err
+		}
+		checkWhoisExpiry([]*types.WhoisResult{result})
+		return nil
 	}
 
 	// 批量查询
@@ -97,12 +138,16 @@ func runWhois(cmd *cobra.Command, args []string) error {
 	}
 
 	// 输出批量结果
+	failures := 0
 	for i, result := range results {
 		if i > 0 {
 			fmt.Println()
 			fmt.Println(strings.Repeat("=", 70))
 			fmt.Println()
 		}
+		if result.Error != "" {
+			failures++
+		}
 		if err := outputWhoisResult(result, appCtx.Flags); err != nil {
 			logger.Error("输出结果失败",
 				zap.String("query", result.Query),
@@ -111,140 +156,56 @@ func runWhois(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if failures > 0 {
+		return fmt.Errorf("批量查询存在失败项: %d/%d", failures, len(results))
+	}
+	checkWhoisExpiry(results)
 	return nil
 }
 
-// outputWhoisResult 输出 Whois 查询结果
-func outputWhoisResult(result *types.WhoisResult, flags app.GlobalFlags) error {
-	outputFormat := types.OutputFormat(flags.Output)
-	if outputFormat == types.OutputText || outputFormat == "" {
-		return outputWhoisText(result, flags)
+// checkWhoisExpiry 启用 --expiry-warn 时，存在已过期或即将到期的域名则输出警告并以阈值退出码（2）结束，
+// 与查询失败（1）区分
+func checkWhoisExpiry(results []*types.WhoisResult) {
+	expiring := expiringDomains(results, whoisExpiryWarn, time.Now())
+	if len(expiring) > 0 {
+		fmt.Fprintf(os.Stderr, "警告: 域名已过期或将在 %d 天内到期: %s\n", whoisExpiryWarn, strings.Join(expiring, ", "))
+		exit(exitCodeThreshold)
 	}
-
-	f := formatter.NewFormatter(outputFormat, flags.NoColor)
-	return f.FormatTo(os.Stdout, result)
 }
 
-// outputWhoisText 文本格式输出
-func outputWhoisText(result *types.WhoisResult, flags app.GlobalFlags) error {
-	f := formatter.NewTextFormatter(!flags.NoColor)
-
-	// 显示查询信息
-	f.PrintHeader(fmt.Sprintf("Whois 查询: %s", result.Query))
-	fmt.Printf("查询服务器: %s\n", result.Server)
-	fmt.Printf("查询耗时:   %s\n", result.QueryTime)
-	fmt.Println()
-
-	// 如果显示原始响应
-	if whoisRaw {
-		f.PrintSubHeader("原始响应")
-		fmt.Println(result.RawResponse)
-		return nil
-	}
-
-	// 显示解析后的数据
-	data := result.ParsedData
-	if data == nil {
-		color.NoColor = flags.NoColor
-		fmt.Println(color.YellowString("无法解析响应数据"))
+// expiringDomains 返回已过期或在 warnDays 天内到期的域名，warnDays 为 0 时不检查
+func expiringDomains(results []*types.WhoisResult, warnDays int, now time.Time) []string {
+	if warnDays <= 0 {
 		return nil
 	}
-
-	switch result.Type {
-	case types.WhoisDomain:
-		outputDomainInfo(data, flags)
-	case types.WhoisIP:
-		outputIPInfo(data, flags)
-	case types.WhoisAS:
-		outputASInfo(data, flags)
-	}
-
-	return nil
-}
-
-// outputDomainInfo 输出域名信息
-func outputDomainInfo(data *types.WhoisData, flags app.GlobalFlags) {
-	f := formatter.NewTextFormatter(!flags.NoColor)
-	color.NoColor = flags.NoColor
-
-	f.PrintSubHeader("域名信息")
-	if data.Domain != "" {
-		fmt.Printf("域名:         %s\n", color.CyanString(data.Domain))
-	}
-	if data.Registrar != "" {
-		fmt.Printf("注册商:       %s\n", data.Registrar)
-	}
-	if data.RegistrantOrg != "" {
-		fmt.Printf("注册组织:     %s\n", data.RegistrantOrg)
-	}
-	if data.RegistrantName != "" {
-		fmt.Printf("注册人:       %s\n", data.RegistrantName)
-	}
-	if !data.CreationDate.IsZero() {
-		fmt.Printf("创建日期:     %s\n", data.CreationDate.Format("2006-01-02"))
-	}
-	if !data.ExpirationDate.IsZero() {
-		fmt.Printf("过期日期:     %s\n", data.ExpirationDate.Format("2006-01-02"))
-	}
-	if !data.UpdatedDate.IsZero() {
-		fmt.Printf("更新日期:     %s\n", data.UpdatedDate.Format("2006-01-02"))
-	}
-
-	if len(data.NameServers) > 0 {
-		fmt.Println()
-		f.PrintSubHeader("域名服务器")
-		for _, ns := range data.NameServers {
-			fmt.Printf("  • %s\n", ns)
+	var expiring []string
+	for _, result := range results {
+		if result.Type != types.WhoisDomain || result.Error != "" {
+			continue
 		}
-	}
-
-	if len(data.Status) > 0 {
-		fmt.Println()
-		f.PrintSubHeader("域名状态")
-		for _, status := range data.Status {
-			fmt.Printf("  • %s\n", status)
+		if _, warn := formatter.WhoisExpiry(result.ParsedData, warnDays, now); warn {
+			expiring = append(expiring, result.Query)
 		}
 	}
+	return expiring
 }
 
-// outputIPInfo 输出 IP 信息
-func outputIPInfo(data *types.WhoisData, flags app.GlobalFlags) {
-	f := formatter.NewTextFormatter(!flags.NoColor)
-	color.NoColor = flags.NoColor
-
-	f.PrintSubHeader("IP 信息")
-	if data.IPRange != "" {
-		fmt.Printf("IP 范围:      %s\n", color.CyanString(data.IPRange))
-	}
-	if data.Organization != "" {
-		fmt.Printf("组织:         %s\n", data.Organization)
-	}
-	if data.Country != "" {
-		fmt.Printf("国家:         %s\n", data.Country)
-	}
-	if data.City != "" {
-		fmt.Printf("城市:         %s\n", data.City)
+// outputWhoisResult 输出 Whois 查询结果
+func outputWhoisResult(result *types.WhoisResult, flags app.GlobalFlags) error {
+	outputFormat := types.OutputFormat(flags.Output)
+	if outputFormat == types.OutputText || outputFormat == "" {
+		return outputWhoisText(result, flags)
 	}
 
-	if len(data.Address) > 0 {
-		fmt.Println()
-		f.PrintSubHeader("地址信息")
-		for _, addr := range data.Address {
-			fmt.Printf("  %s\n", addr)
-		}
-	}
+	f := formatter.NewFormatter(outputFormat, flags.NoColor)
+	return f.FormatTo(os.Stdout, result)
 }
 
-// outputASInfo 输出 AS 信息
-func outputASInfo(data *types.WhoisData, flags app.GlobalFlags) {
-	f := formatter.NewTextFormatter(!flags.NoColor)
-	color.NoColor = flags.NoColor
-
-	f.PrintSubHeader("AS 信息")
-	if data.ASName != "" {
-		fmt.Printf("AS 名称:      %s\n", color.CyanString(data.ASName))
-	}
-	if data.Organization != "" {
-		fmt.Printf("组织:         %s\n", data.Organization)
-	}
+// outputWhoisText 文本格式输出
+func outputWhoisText(result *types.WhoisResult, flags app.GlobalFlags) error {
+	fmt.Print(formatter.FormatWhoisText(result, formatter.WhoisTextOptions{
+		Raw:            whoisRaw,
+		ExpiryWarnDays: whoisExpiryWarn,
+	}, flags.NoColor))
+	return nil
 }
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+func TestExpiringDomains(t *testing.T) {
+	now := time.Now()
+	results := []*types.WhoisResult{
+		{Query: "soon.example", Type: types.WhoisDomain, ParsedData: &types.WhoisData{ExpirationDate: now.AddDate(0, 0, 5)}},
+		{Query: "later.example", Type: types.WhoisDomain, ParsedData: &types.WhoisData{ExpirationDate: now.AddDate(1, 0, 0)}},
+		{Query: "unknown.example", Type: types.WhoisDomain, ParsedData: &types.WhoisData{}},
+	}
+
+	if got := expiringDomains(results, 0, now); len(got) != 0 {
+		t.Fatalf("expiringDomains() without threshold = %v, want none", got)
+	}
+
+	got := expiringDomains(results, 30, now)
+	if len(got) != 1 || got[0] != "soon.example" {
+		t.Fatalf("expiringDomains() = %v, want [soon.example]", got)
+	}
+
+	// 未启用检查时 checkWhoisExpiry 直接返回，不会退出进程
+	old := whoisExpiryWarn
+	t.Cleanup(func() { whoisExpiryWarn = old })
+	whoisExpiryWarn = 0
+	checkWhoisExpiry(results)
+}
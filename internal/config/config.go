@@ -15,6 +15,7 @@ type Config struct {
 	HTTP   HTTPConfig   `yaml:"http" json:"http"`
 	Scan   ScanConfig   `yaml:"scan" json:"scan"`
 	Trace  TraceConfig  `yaml:"trace" json:"trace"`
+	Diag   DiagConfig   `yaml:"diag" json:"diag"`
 }
 
 // GlobalConfig 全局配置
@@ -24,6 +25,10 @@ type GlobalConfig struct {
 	NoColor  bool   `yaml:"no_color" json:"no_color"`
 	LogLevel string `yaml:"log_level" json:"log_level"`
 	LogFile  string `yaml:"log_file" json:"log_file"`
+	// DNSCacheTTL 进程内主机名解析缓存有效期，0 表示禁用
+	DNSCacheTTL time.Duration `yaml:"dns_cache_ttl" json:"dns_cache_ttl"`
+	// OUIFile 额外的 MAC 厂商（OUI）数据文件，优先于内置表
+	OUIFile string `yaml:"oui_file" json:"oui_file"`
 }
 
 // PingConfig Ping 相关配置
@@ -72,21 +77,28 @@ type TraceConfig struct {
 	FirstTTL   int             `yaml:"first_ttl" json:"first_ttl"`
 }
 
+// DiagConfig 网络诊断配置
+type DiagConfig struct {
+	// CaptivePortalURL 强制门户检测的控制地址，需返回 204 No Content
+	CaptivePortalURL string `yaml:"captive_portal_url" json:"captive_portal_url"`
+}
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
 		Global: GlobalConfig{
-			Verbose:  false,
-			Output:   "text",
-			NoColor:  false,
-			LogLevel: "info",
-			LogFile:  "",
+			Verbose:     false,
+			Output:      "text",
+			NoColor:     false,
+			LogLevel:    "info",
+			LogFile:     "",
+			DNSCacheTTL: types.DefaultDNSCacheTTL,
 		},
 		Ping: PingConfig{
 			Protocol:  types.ProtocolICMP,
 			Count:     4,
 			Interval:  time.Second,
-			Timeout:   5 * time.Second,
+			Timeout:   0, // 0 表示按协议取默认值
 			Size:      64,
 			TTL:       64,
 			Port:      0,
@@ -123,5 +135,8 @@ func DefaultConfig() *Config {
 			IPVersion:  types.IPvAny,
 			FirstTTL:   1,
 		},
+		Diag: DiagConfig{
+			CaptivePortalURL: types.DefaultCaptivePortalURL,
+		},
 	}
 }
@@ -120,6 +120,14 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("NTX_LOG_LEVEL"); v != "" {
 		cfg.Global.LogLevel = strings.ToLower(v)
 	}
+	if v := os.Getenv("NTX_DNS_CACHE_TTL"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			cfg.Global.DNSCacheTTL = parsed
+		}
+	}
+	if v := os.Getenv("NTX_OUI_FILE"); v != "" {
+		cfg.Global.OUIFile = v
+	}
 
 	if v := os.Getenv("NTX_PING_COUNT"); v != "" {
 		if parsed, err := strconv.Atoi(v); err == nil {
@@ -197,4 +205,8 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("NTX_TRACE_PROTOCOL"); v != "" {
 		cfg.Trace.Protocol = types.Protocol(strings.ToLower(v))
 	}
+
+	if v := os.Getenv("NTX_DIAG_CAPTIVE_PORTAL_URL"); v != "" {
+		cfg.Diag.CaptivePortalURL = v
+	}
 }
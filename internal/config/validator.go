@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/catsayer/ntx/pkg/types"
@@ -21,6 +22,7 @@ func Validate(cfg *Config) error {
 	result = multierr.Append(result, validateHTTP(cfg.HTTP))
 	result = multierr.Append(result, validateScan(cfg.Scan))
 	result = multierr.Append(result, validateTrace(cfg.Trace))
+	result = multierr.Append(result, validateDiag(cfg.Diag))
 
 	return result
 }
@@ -29,7 +31,7 @@ func validateGlobal(cfg GlobalConfig) error {
 	var err error
 	output := strings.ToLower(cfg.Output)
 	switch output {
-	case "", "text", "json", "yaml", "table":
+	case "", "text", "json", "yaml", "table", "ndjson":
 	default:
 		err = multierr.Append(err, fmt.Errorf("global.output 不支持的值: %s", cfg.Output))
 	}
@@ -40,6 +42,10 @@ func validateGlobal(cfg GlobalConfig) error {
 		err = multierr.Append(err, fmt.Errorf("global.log_level 不支持的值: %s", cfg.LogLevel))
 	}
 
+	if cfg.DNSCacheTTL < 0 {
+		err = multierr.Append(err, fmt.Errorf("global.dns_cache_ttl 不能为负数"))
+	}
+
 	return err
 }
 
@@ -51,8 +57,8 @@ func validatePing(cfg PingConfig) error {
 	if cfg.Interval <= 0 {
 		err = multierr.Append(err, fmt.Errorf("ping.interval 必须大于 0"))
 	}
-	if cfg.Timeout <= 0 {
-		err = multierr.Append(err, fmt.Errorf("ping.timeout 必须大于 0"))
+	if cfg.Timeout < 0 {
+		err = multierr.Append(err, fmt.Errorf("ping.timeout 不能为负数"))
 	}
 	if cfg.Size <= 0 {
 		err = multierr.Append(err, fmt.Errorf("ping.size 必须大于 0"))
@@ -124,3 +130,14 @@ func validateTrace(cfg TraceConfig) error {
 	}
 	return err
 }
+
+func validateDiag(cfg DiagConfig) error {
+	if cfg.CaptivePortalURL == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.CaptivePortalURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("diag.captive_portal_url 必须是 http(s) 地址: %s", cfg.CaptivePortalURL)
+	}
+	return nil
+}
@@ -3,28 +3,128 @@ package batch
 import (
 	"fmt"
 	"os"
-
-	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
 )
 
+// LoadFile 读取任务文件并展开 include，返回全部任务（含已禁用的任务）
+func LoadFile(path string) ([]Task, error) {
+	return loadTaskConfig(path)
+}
+
+// loadTaskConfig 读取任务文件并展开 include，被包含文件的任务排在当前文件任务之前
 func loadTaskConfig(path string) ([]Task, error) {
-	data, err := os.ReadFile(path)
+	config, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Tasks, nil
+}
+
+// loadConfig 读取任务文件并展开 include，返回顶层文件的设置与合并后的任务，
+// 未配置 timeout 的任务使用顶层 timeout
+func loadConfig(path string) (*TaskConfig, error) {
+	loader := &configLoader{loaded: make(map[string]bool)}
+	tasks, err := loader.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := loader.root
+	config.Include = nil
+	config.Tasks = tasks
+	for i := range config.Tasks {
+		if config.Tasks[i].Timeout == 0 {
+			config.Tasks[i].Timeout = config.Timeout
+		}
+	}
+	return config, nil
+}
+
+// configLoader 跟踪 include 展开状态
+type configLoader struct {
+	// stack 当前展开链，用于检测循环引用
+	stack []string
+	// loaded 已展开的文件，菱形引用时只合并一次
+	loaded map[string]bool
+	// root 顶层文件的配置
+	root *TaskConfig
+}
+
+func (l *configLoader) load(path string) ([]Task, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件路径失败: %w", err)
+	}
+	for i, p := range l.stack {
+		if p == absPath {
+			chain := append(append([]string{}, l.stack[i:]...), absPath)
+			return nil, fmt.Errorf("配置文件存在循环 include: %s", strings.Join(chain, " -> "))
+		}
+	}
+	if l.loaded[absPath] {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
-	var config TaskConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	config, err := decodeTaskConfig(path, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(l.stack) == 0 {
+		l.root = config
+	}
+
+	l.stack = append(l.stack, absPath)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	var tasks []Task
+	dir := filepath.Dir(absPath)
+	for _, include := range config.Include {
+		if strings.TrimSpace(include) == "" {
+			continue
+		}
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(dir, includePath)
+		}
+		if _, err := os.Stat(includePath); err != nil {
+			return nil, fmt.Errorf("%s 包含的文件 %s 不可用: %w", path, include, err)
+		}
+		included, err := l.load(includePath)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, included...)
 	}
 
-	return config.Tasks, nil
+	l.loaded[absPath] = true
+	return append(tasks, config.Tasks...), nil
 }
 
 // GenerateSampleConfig 生成示例配置文件
 func GenerateSampleConfig() string {
 	return `# NTX 批量任务配置示例
 
+# 引用其他任务文件（相对路径相对于本文件），其任务合并到本文件任务之前
+# include:
+#   - shared/common-tasks.yaml
+
+# 可使用标准 YAML 锚点/别名复用选项，锚点放在 defaults 下（加载时忽略）；
+# 其他未知字段会报错并指出行号
+defaults: &ping-defaults
+  count: 5
+  timeout: 3
+
+# 同时执行的最大任务数（命令行 --concurrency 优先）
+max_concurrency: 4
+# 单个任务单次执行的超时时间，超时的任务记为失败；任务内的 timeout 字段优先
+timeout: "2m"
+
 tasks:
   # Ping 监控任务
   - name: "monitor-servers"
@@ -34,10 +134,22 @@ tasks:
       - "google.com"
       - "baidu.com"
       - "github.com"
-    options:
-      count: 5
-      timeout: 3
+    options: *ping-defaults
     concurrency: 3
+    # ntx serve 常驻运行时的执行间隔（如 "30s" 或 "@every 5m"），batch 命令忽略此字段
+    schedule: "1m"
+
+  # 轻量监控：每 10 秒执行一次，共 6 次，输出逐次丢包/延迟
+  # 也可用 duration 限定总时长（如 "5m"），与 repeat 同时配置时先到者为准
+  - name: "gateway-watch"
+    type: "ping"
+    enabled: false
+    targets:
+      - "192.168.1.1"
+    options:
+      count: 3
+    interval: "10s"
+    repeat: 6
 
   # DNS 查询任务
   - name: "dns-check"
@@ -49,6 +161,7 @@ tasks:
     options:
       type: "A"
       server: "8.8.8.8"
+    timeout: "10s"
 
   # 端口扫描任务
   - name: "scan-servers"
@@ -60,5 +173,33 @@ tasks:
       ports: [22, 80, 443, 3306, 8080]
       timeout: 2
       concurrency: 50
+
+  # HTTP 可用性检查任务
+  - name: "web-check"
+    type: "http"
+    enabled: true
+    targets:
+      - "https://example.com"
+    options:
+      method: "GET"
+      timeout: 10
+      # 期望的状态码，省略时任何小于 400 的状态码视为成功
+      expect_status: 200
+      # 响应体必须包含的内容
+      contains: "Example Domain"
+      headers:
+        Accept: "text/html"
+
+  # 路由追踪任务
+  - name: "trace-gateway"
+    type: "trace"
+    enabled: false
+    targets:
+      - "8.8.8.8"
+    options:
+      protocol: "icmp"
+      max_hops: 20
+      queries: 1
+      timeout: 2
 `
 }
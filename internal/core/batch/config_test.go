@@ -0,0 +1,161 @@
+package batch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTaskFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestLoadTaskConfigInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeTaskFile(t, filepath.Join(dir, "lib", "common.yaml"), `
+include: [dns.yaml]
+tasks:
+  - name: common
+    type: ping
+    targets: [127.0.0.1]
+`)
+	writeTaskFile(t, filepath.Join(dir, "lib", "dns.yaml"), `
+tasks:
+  - name: dns
+    type: dns
+    targets: [example.com]
+`)
+	writeTaskFile(t, filepath.Join(dir, "main.yaml"), `
+include:
+  - lib/common.yaml
+  - lib/dns.yaml
+defaults: &opts
+  count: 2
+tasks:
+  - name: main
+    type: ping
+    targets: [127.0.0.1]
+    options: *opts
+`)
+
+	tasks, err := loadTaskConfig(filepath.Join(dir, "main.yaml"))
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	require.Equal(t, "dns", tasks[0].Name)
+	require.Equal(t, "common", tasks[1].Name)
+	require.Equal(t, "main", tasks[2].Name)
+	require.Equal(t, 2, tasks[2].Options["count"])
+}
+
+func TestLoadTaskConfigIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTaskFile(t, filepath.Join(dir, "a.yaml"), "include: [b.yaml]\ntasks: []\n")
+	writeTaskFile(t, filepath.Join(dir, "b.yaml"), "include: [a.yaml]\ntasks: []\n")
+	_, err := loadTaskConfig(filepath.Join(dir, "a.yaml"))
+	require.ErrorContains(t, err, "循环 include")
+
+	writeTaskFile(t, filepath.Join(dir, "c.yaml"), "include: [missing.yaml]\ntasks: []\n")
+	_, err = loadTaskConfig(filepath.Join(dir, "c.yaml"))
+	require.ErrorContains(t, err, "missing.yaml")
+}
+
+func TestLoadTaskConfigStrict(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "unknown-field",
+			content: `
+tasks:
+  - name: web
+    type: ping
+    targts: [127.0.0.1]
+`,
+			want: []string{`第 5 行: 任务不支持字段 "targts"，是否为 "targets"?`},
+		},
+		{
+			name:    "unknown-top-level",
+			content: "concurrency: 4\ntasks: []\n",
+			want:    []string{`第 1 行: 顶层配置不支持字段 "concurrency"`},
+		},
+		{
+			name: "type-mismatch",
+			content: `
+tasks:
+  - name: web
+    type: ping
+    targets: [127.0.0.1]
+    interval: 5
+    repeat: many
+`,
+			want: []string{
+				`第 6 行: 无法将 "5" 解析为时长`,
+				`第 7 行: 无法将 "many" 解析为整数`,
+			},
+		},
+		{
+			name:    "syntax",
+			content: "tasks:\n  - name: web\n   type: [\n",
+			want:    []string{"YAML 语法错误"},
+		},
+		{
+			name: "invalid-task",
+			content: `
+tasks:
+  - name: web
+    type: pign
+    targets: [127.0.0.1]
+  - type: dns
+    interval: 1s
+    duration: 1m
+`,
+			want: []string{
+				`第 4 行: 任务 "web"的 type "pign" 无效，可选: ping, dns, scan, http, trace`,
+				"第 6 行: 第 2 个任务缺少 name",
+				"第 6 行: 第 2 个任务未配置 targets",
+			},
+		},
+		{
+			name: "duration-without-interval",
+			content: `
+tasks:
+  - name: watch
+    type: ping
+    targets: [127.0.0.1]
+    duration: 1m
+`,
+			want: []string{`第 6 行: 任务 "watch"配置了 duration 但未配置 interval`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name+".yaml")
+			writeTaskFile(t, path, tc.content)
+
+			_, err := loadTaskConfig(path)
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			require.Len(t, verr.Problems, len(tc.want))
+			for i, want := range tc.want {
+				require.Contains(t, verr.Problems[i], want)
+			}
+			require.Contains(t, err.Error(), path)
+		})
+	}
+}
+
+func TestSampleConfigIsValid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sample.yaml")
+	writeTaskFile(t, path, GenerateSampleConfig())
+	tasks, err := loadTaskConfig(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, tasks)
+}
@@ -1,8 +1,17 @@
 package batch
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 // ErrUnsupportedTaskType 返回标准化的任务类型错误
 func ErrUnsupportedTaskType(taskType TaskType) error {
 	return fmt.Errorf("不支持的任务类型: %s", taskType)
 }
+
+// ErrTaskTimeout 返回任务执行超时错误，可通过 errors.Is 匹配 context.DeadlineExceeded
+func ErrTaskTimeout(timeout time.Duration) error {
+	return fmt.Errorf("任务执行超时 (%s): %w", timeout, context.DeadlineExceeded)
+}
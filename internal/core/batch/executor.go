@@ -2,23 +2,36 @@ package batch
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/catsayer/ntx/internal/core/dns"
+	"github.com/catsayer/ntx/internal/core/http"
 	"github.com/catsayer/ntx/internal/core/ping"
 	"github.com/catsayer/ntx/internal/core/scan"
+	"github.com/catsayer/ntx/internal/core/trace"
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/pkg/types"
 	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
 )
 
 // Executor 任务执行器
 type Executor struct {
-	pinger   types.Pinger
-	resolver *dns.Resolver
-	scanner  *scan.TCPScanner
+	pinger     types.Pinger
+	resolver   *dns.Resolver
+	scanner    *scan.TCPScanner
+	httpClient *http.Client
+	// newTracer 按协议创建 Tracer，每个 trace 任务独立创建并在结束时关闭
+	newTracer func(types.Protocol) (types.Tracer, error)
+	// maxConcurrency 同时执行的最大任务数，<= 0 时使用配置文件或默认值
+	maxConcurrency int
 }
 
+// DefaultMaxConcurrency 未配置时同时执行的最大任务数
+const DefaultMaxConcurrency = 4
+
 // NewExecutor 创建新的任务执行器
 func NewExecutor() *Executor {
 	opts := types.DefaultPingOptions()
@@ -36,42 +49,81 @@ func NewExecutor() *Executor {
 	}
 
 	return &Executor{
-		pinger:   pinger,
-		resolver: dns.NewResolver(dnsOpts),
-		scanner:  scan.NewTCPScanner(),
+		pinger:     pinger,
+		resolver:   dns.NewResolver(dnsOpts),
+		scanner:    scan.NewTCPScanner(),
+		httpClient: http.NewClient(nil),
+		newTracer:  trace.NewTracer,
 	}
 }
 
+// SetMaxConcurrency 设置同时执行的最大任务数，优先于配置文件的 max_concurrency，n <= 0 时不覆盖
+func (e *Executor) SetMaxConcurrency(n int) {
+	e.maxConcurrency = n
+}
+
 // ExecuteFile 执行配置文件中的任务
 func (e *Executor) ExecuteFile(ctx context.Context, configFile string) (*BatchResult, error) {
 	logger.Info("加载任务配置文件", zap.String("file", configFile))
 
-	tasks, err := loadTaskConfig(configFile)
+	config, err := loadConfig(configFile)
 	if err != nil {
 		return nil, err
 	}
 
-	return e.ExecuteTasks(ctx, tasks)
+	concurrency := e.maxConcurrency
+	if concurrency <= 0 {
+		concurrency = config.MaxConcurrency
+	}
+	return e.executeTasks(ctx, config.Tasks, concurrency), nil
 }
 
 // ExecuteTasks 执行任务列表
 func (e *Executor) ExecuteTasks(ctx context.Context, tasks []Task) (*BatchResult, error) {
+	return e.executeTasks(ctx, tasks, e.maxConcurrency), nil
+}
+
+// executeTasks 以最多 concurrency 个任务并发执行，结果按任务顺序排列
+func (e *Executor) executeTasks(ctx context.Context, tasks []Task, concurrency int) *BatchResult {
 	startTime := time.Now()
+	if concurrency <= 0 {
+		concurrency = DefaultMaxConcurrency
+	}
 
-	logger.Info("开始执行批量任务", zap.Int("count", len(tasks)))
+	logger.Info("开始执行批量任务", zap.Int("count", len(tasks)), zap.Int("concurrency", concurrency))
 
 	result := &BatchResult{
 		TotalTasks:  len(tasks),
 		TaskResults: make([]*TaskResult, 0),
 	}
 
-	for _, task := range tasks {
+	taskResults := make([]*TaskResult, len(tasks))
+	sem := semaphore.NewWeighted(int64(concurrency))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
 		if !task.Enabled {
 			logger.Info("跳过禁用的任务", zap.String("name", task.Name))
 			continue
 		}
 
-		taskResult := e.executeTask(ctx, task)
+		if err := sem.Acquire(ctx, 1); err != nil {
+			// 已中断，剩余任务不再启动
+			taskResults[i] = skippedTaskResult(task, err)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, task Task) {
+			defer wg.Done()
+			defer sem.Release(1)
+			taskResults[i] = e.runTask(ctx, task)
+		}(i, task)
+	}
+	wg.Wait()
+
+	for _, taskResult := range taskResults {
+		if taskResult == nil {
+			continue
+		}
 		result.TaskResults = append(result.TaskResults, taskResult)
 
 		if taskResult.Success {
@@ -90,10 +142,26 @@ func (e *Executor) ExecuteTasks(ctx context.Context, tasks []Task) (*BatchResult
 		zap.Duration("duration", result.TotalDuration),
 	)
 
-	return result, nil
+	return result
 }
 
-func (e *Executor) executeTask(ctx context.Context, task Task) *TaskResult {
+// skippedTaskResult 因中断未启动的任务结果
+func skippedTaskResult(task Task, err error) *TaskResult {
+	now := time.Now()
+	return &TaskResult{
+		TaskName:  task.Name,
+		TaskType:  task.Type,
+		StartTime: now,
+		EndTime:   now,
+		Error:     fmt.Errorf("任务未执行: %w", err),
+		Results:   make([]interface{}, 0),
+	}
+}
+
+// ExecuteTask 执行单个任务一次，不检查 Enabled，也不处理重复执行
+//
+// 配置了 Timeout 时超时即返回失败，不等待仍在执行的探测结束，其结果被丢弃。
+func (e *Executor) ExecuteTask(ctx context.Context, task Task) *TaskResult {
 	startTime := time.Now()
 
 	logger.Info("执行任务", zap.String("name", task.Name), zap.String("type", string(task.Type)))
@@ -105,17 +173,31 @@ func (e *Executor) executeTask(ctx context.Context, task Task) *TaskResult {
 		Results:   make([]interface{}, 0),
 	}
 
-	var err error
+	taskCtx := ctx
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
 
-	switch task.Type {
-	case TaskTypePing:
-		err = e.executePingTask(ctx, task, result)
-	case TaskTypeDNS:
-		err = e.executeDNSTask(ctx, task, result)
-	case TaskTypeScan:
-		err = e.executeScanTask(ctx, task, result)
-	default:
-		err = ErrUnsupportedTaskType(task.Type)
+	// 处理器写入独立的结果对象，超时返回后不会与其继续写入竞争
+	partial := &TaskResult{Results: make([]interface{}, 0)}
+	done := make(chan error, 1)
+	go func() {
+		done <- e.dispatch(taskCtx, task, partial)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+		result.Results = partial.Results
+	case <-taskCtx.Done():
+		if ctx.Err() == nil {
+			err = ErrTaskTimeout(task.Timeout)
+			logger.Warn("任务执行超时", zap.String("name", task.Name), zap.Duration("timeout", task.Timeout))
+		} else {
+			err = fmt.Errorf("任务已中断: %w", ctx.Err())
+		}
 	}
 
 	result.EndTime = time.Now()
@@ -125,3 +207,20 @@ func (e *Executor) executeTask(ctx context.Context, task Task) *TaskResult {
 
 	return result
 }
+
+// dispatch 按任务类型调用对应的处理器
+func (e *Executor) dispatch(ctx context.Context, task Task, result *TaskResult) error {
+	switch task.Type {
+	case TaskTypePing:
+		return e.executePingTask(ctx, task, result)
+	case TaskTypeDNS:
+		return e.executeDNSTask(ctx, task, result)
+	case TaskTypeScan:
+		return e.executeScanTask(ctx, task, result)
+	case TaskTypeHTTP:
+		return e.executeHTTPTask(ctx, task, result)
+	case TaskTypeTrace:
+		return e.executeTraceTask(ctx, task, result)
+	}
+	return ErrUnsupportedTaskType(task.Type)
+}
@@ -0,0 +1,199 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/http"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTracer 记录调用参数并返回预设结果
+type fakeTracer struct {
+	targets []string
+	opts    *types.TraceOptions
+	closed  bool
+}
+
+func (f *fakeTracer) Trace(_ context.Context, target string, opts *types.TraceOptions) (*types.TraceResult, error) {
+	f.targets = append(f.targets, target)
+	f.opts = opts
+	return &types.TraceResult{
+		Target:             &types.Host{Hostname: target, IP: target},
+		Protocol:           opts.Protocol,
+		ReachedDestination: true,
+		HopCount:           3,
+		Status:             types.StatusSuccess,
+	}, nil
+}
+
+func (f *fakeTracer) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestExecuteHTTPAndTraceTasks(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path == "/missing" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-Probe") != "ntx" {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "service ok")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	writeTaskFile(t, path, fmt.Sprintf(`
+tasks:
+  - name: web
+    type: http
+    enabled: true
+    targets: [%[1]s/health]
+    options:
+      expect_status: 200
+      contains: "ok"
+      headers:
+        X-Probe: ntx
+  - name: web-missing
+    type: http
+    enabled: true
+    targets: [%[1]s/missing]
+  - name: route
+    type: trace
+    enabled: true
+    targets: [192.0.2.1, 192.0.2.2]
+    options:
+      protocol: UDP
+      max_hops: 5
+      queries: 1
+`, server.URL))
+
+	tasks, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+
+	tracer := &fakeTracer{}
+	var protocol types.Protocol
+	e := &Executor{
+		httpClient: http.NewClient(nil),
+		newTracer: func(p types.Protocol) (types.Tracer, error) {
+			protocol = p
+			return tracer, nil
+		},
+	}
+
+	web := e.ExecuteTask(context.Background(), tasks[0])
+	require.NoError(t, web.Error)
+	require.True(t, web.Success)
+	require.Len(t, web.Results, 1)
+	httpResult, ok := web.Results[0].(*types.HTTPResult)
+	require.True(t, ok)
+	require.Equal(t, 200, httpResult.StatusCode)
+	require.Nil(t, httpResult.Body)
+
+	missing := e.ExecuteTask(context.Background(), tasks[1])
+	require.False(t, missing.Success)
+	require.ErrorContains(t, missing.Error, "1/1")
+	require.Len(t, missing.Results, 1)
+
+	route := e.ExecuteTask(context.Background(), tasks[2])
+	require.NoError(t, route.Error)
+	require.Len(t, route.Results, 2)
+	require.IsType(t, &types.TraceResult{}, route.Results[0])
+	require.Equal(t, types.ProtocolUDP, protocol)
+	require.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, tracer.targets)
+	require.Equal(t, 5, tracer.opts.MaxHops)
+	require.True(t, tracer.closed)
+}
+
+// blockingPinger 对 hung 目标一直阻塞且忽略 ctx，模拟卡死的探测，并记录其他目标的最大并发数
+type blockingPinger struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (p *blockingPinger) Ping(_ context.Context, target string, _ *types.PingOptions) (*types.PingResult, error) {
+	if target == "hung" {
+		<-p.release
+		return nil, errors.New("released")
+	}
+
+	p.mu.Lock()
+	p.running++
+	p.peak = max(p.peak, p.running)
+	p.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	p.mu.Lock()
+	p.running--
+	p.mu.Unlock()
+	return &types.PingResult{Target: &types.Host{Hostname: target}, Status: types.StatusSuccess}, nil
+}
+
+func (p *blockingPinger) PingStream(context.Context, string, *types.PingOptions) (<-chan *types.PingReply, error) {
+	return nil, nil
+}
+
+func (p *blockingPinger) Close() error { return nil }
+
+func TestExecuteFileTimeoutAndConcurrency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	writeTaskFile(t, path, `
+max_concurrency: 2
+timeout: 100ms
+tasks:
+  - {name: slow, type: ping, enabled: true, targets: [hung]}
+  - {name: a, type: ping, enabled: true, targets: [a]}
+  - {name: b, type: ping, enabled: true, targets: [b]}
+  - {name: c, type: ping, enabled: true, targets: [c], timeout: 5s}
+`)
+
+	pinger := &blockingPinger{release: make(chan struct{})}
+	t.Cleanup(func() { close(pinger.release) })
+	e := &Executor{pinger: pinger}
+
+	start := time.Now()
+	result, err := e.ExecuteFile(context.Background(), path)
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), 2*time.Second)
+
+	require.Len(t, result.TaskResults, 4)
+	require.Equal(t, 3, result.SuccessTasks)
+	require.Equal(t, 1, result.FailedTasks)
+
+	slow := result.TaskResults[0]
+	require.Equal(t, "slow", slow.TaskName)
+	require.False(t, slow.Success)
+	require.True(t, errors.Is(slow.Error, context.DeadlineExceeded))
+	require.Contains(t, slow.Error.Error(), "100ms")
+	require.Empty(t, slow.Results)
+
+	for _, r := range result.TaskResults[1:] {
+		require.True(t, r.Success, r.TaskName)
+		require.Len(t, r.Results, 1)
+	}
+
+	// slow 占用一个并发槽位直到超时，其余任务只能逐个执行
+	pinger.mu.Lock()
+	require.Equal(t, 1, pinger.peak)
+	pinger.mu.Unlock()
+
+	tasks, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Millisecond, tasks[1].Timeout)
+	require.Equal(t, 5*time.Second, tasks[3].Timeout)
+}
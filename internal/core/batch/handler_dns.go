@@ -17,25 +17,13 @@ func (e *Executor) executeDNSTask(ctx context.Context, task Task, result *TaskRe
 	recordType := types.DNSTypeA
 
 	if task.Options != nil {
-		if recordTypeStr, ok := task.Options["type"].(string); ok {
-			switch recordTypeStr {
-			case "A":
-				recordType = types.DNSTypeA
-			case "AAAA":
-				recordType = types.DNSTypeAAAA
-			case "CNAME":
-				recordType = types.DNSTypeCNAME
-			case "MX":
-				recordType = types.DNSTypeMX
-			case "NS":
-				recordType = types.DNSTypeNS
-			case "TXT":
-				recordType = types.DNSTypeTXT
-			case "SOA":
-				recordType = types.DNSTypeSOA
-			case "PTR":
-				recordType = types.DNSTypePTR
+		// 支持名称（"CAA"）与数值（65）两种写法
+		if v, ok := task.Options["type"]; ok {
+			parsed, err := types.ParseDNSRecordType(fmt.Sprint(v))
+			if err != nil {
+				return err
 			}
+			recordType = parsed
 		}
 	}
 
@@ -0,0 +1,121 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+// httpTaskOptions HTTP 任务选项
+type httpTaskOptions struct {
+	method  string
+	headers map[string]string
+	body    []byte
+	timeout time.Duration
+	// expectStatus 期望的状态码，为 0 时任何小于 400 的状态码视为成功
+	expectStatus int
+	// contains 响应体必须包含的内容
+	contains string
+}
+
+func (e *Executor) executeHTTPTask(ctx context.Context, task Task, result *TaskResult) error {
+	if len(task.Targets) == 0 {
+		return fmt.Errorf("http 任务未配置目标")
+	}
+
+	opts := httpOptionsFromTask(task)
+
+	failures := 0
+	for _, target := range task.Targets {
+		httpResult, err := e.requestHTTP(ctx, target, opts)
+		if err != nil {
+			logger.Error("HTTP 请求失败", zap.String("url", target), zap.Error(err))
+			failures++
+			continue
+		}
+
+		if err := checkHTTPResult(httpResult, opts); err != nil {
+			logger.Error("HTTP 检查未通过", zap.String("url", target), zap.Error(err))
+			failures++
+		}
+		// 响应体仅用于检查，不保留在结果中
+		httpResult.Body = nil
+		result.Results = append(result.Results, httpResult)
+
+		logger.Info("HTTP 请求完成",
+			zap.String("url", target),
+			zap.Int("status", httpResult.StatusCode),
+			zap.Duration("duration", httpResult.Duration),
+		)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("http 任务部分失败: %d/%d 个目标失败", failures, len(task.Targets))
+	}
+	if len(result.Results) == 0 {
+		return fmt.Errorf("http 任务未产生有效结果")
+	}
+
+	return nil
+}
+
+// requestHTTP 在任务超时内请求单个 URL
+func (e *Executor) requestHTTP(ctx context.Context, target string, opts httpTaskOptions) (*types.HTTPResult, error) {
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+	return e.httpClient.Request(ctx, opts.method, target, opts.body, opts.headers)
+}
+
+// checkHTTPResult 检查状态码与响应内容是否符合预期
+func checkHTTPResult(r *types.HTTPResult, opts httpTaskOptions) error {
+	if opts.expectStatus > 0 {
+		if r.StatusCode != opts.expectStatus {
+			return fmt.Errorf("状态码 %d 不符合预期 %d", r.StatusCode, opts.expectStatus)
+		}
+	} else if r.StatusCode >= 400 {
+		return fmt.Errorf("状态码 %d", r.StatusCode)
+	}
+	if opts.contains != "" && !strings.Contains(string(r.Body), opts.contains) {
+		return fmt.Errorf("响应体不包含 %q", opts.contains)
+	}
+	return nil
+}
+
+// httpOptionsFromTask 根据任务配置构建 HTTP 任务选项
+func httpOptionsFromTask(task Task) httpTaskOptions {
+	opts := httpTaskOptions{method: "GET"}
+
+	if task.Options != nil {
+		if method, ok := task.Options["method"].(string); ok && method != "" {
+			opts.method = strings.ToUpper(method)
+		}
+		if timeout, ok := task.Options["timeout"].(int); ok {
+			opts.timeout = time.Duration(timeout) * time.Second
+		}
+		if status, ok := task.Options["expect_status"].(int); ok {
+			opts.expectStatus = status
+		}
+		if contains, ok := task.Options["contains"].(string); ok {
+			opts.contains = contains
+		}
+		if body, ok := task.Options["body"].(string); ok {
+			opts.body = []byte(body)
+		}
+		if headers, ok := task.Options["headers"].(map[string]interface{}); ok {
+			opts.headers = make(map[string]string, len(headers))
+			for key, value := range headers {
+				opts.headers[key] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	return opts
+}
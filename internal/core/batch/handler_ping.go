@@ -16,21 +16,8 @@ func (e *Executor) executePingTask(ctx context.Context, task Task, result *TaskR
 		return fmt.Errorf("ping 任务未配置目标")
 	}
 
-	opts := types.DefaultPingOptions()
-
-	if task.Options != nil {
-		if count, ok := task.Options["count"].(int); ok {
-			opts.Count = count
-		}
-		if timeout, ok := task.Options["timeout"].(int); ok {
-			opts.Timeout = time.Duration(timeout) * time.Second
-		}
-	}
-
-	concurrency := task.Concurrency
-	if concurrency == 0 {
-		concurrency = 10
-	}
+	opts := pingOptionsFromTask(task)
+	concurrency := pingConcurrency(task)
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -95,3 +82,26 @@ func (e *Executor) executePingTask(ctx context.Context, task Task, result *TaskR
 
 	return nil
 }
+
+// pingOptionsFromTask 根据任务配置构建 Ping 选项
+func pingOptionsFromTask(task Task) *types.PingOptions {
+	opts := types.DefaultPingOptions()
+
+	if task.Options != nil {
+		if count, ok := task.Options["count"].(int); ok {
+			opts.Count = count
+		}
+		if timeout, ok := task.Options["timeout"].(int); ok {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+	}
+
+	return opts
+}
+
+func pingConcurrency(task Task) int {
+	if task.Concurrency == 0 {
+		return 10
+	}
+	return task.Concurrency
+}
@@ -15,24 +15,7 @@ func (e *Executor) executeScanTask(ctx context.Context, task Task, result *TaskR
 		return fmt.Errorf("scan 任务未配置目标")
 	}
 
-	opts := types.DefaultScanOptions()
-
-	if task.Options != nil {
-		if timeout, ok := task.Options["timeout"].(int); ok {
-			opts.Timeout = time.Duration(timeout) * time.Second
-		}
-		if concurrency, ok := task.Options["concurrency"].(int); ok {
-			opts.Concurrency = concurrency
-		}
-		if ports, ok := task.Options["ports"].([]interface{}); ok {
-			opts.Ports = make([]int, 0)
-			for _, p := range ports {
-				if port, ok := p.(int); ok {
-					opts.Ports = append(opts.Ports, port)
-				}
-			}
-		}
-	}
+	opts := scanOptionsFromTask(task)
 
 	failures := 0
 	for _, target := range task.Targets {
@@ -60,3 +43,27 @@ func (e *Executor) executeScanTask(ctx context.Context, task Task, result *TaskR
 
 	return nil
 }
+
+// scanOptionsFromTask 根据任务配置构建扫描选项
+func scanOptionsFromTask(task Task) types.ScanOptions {
+	opts := types.DefaultScanOptions()
+
+	if task.Options != nil {
+		if timeout, ok := task.Options["timeout"].(int); ok {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+		if concurrency, ok := task.Options["concurrency"].(int); ok {
+			opts.Concurrency = concurrency
+		}
+		if ports, ok := task.Options["ports"].([]interface{}); ok {
+			opts.Ports = make([]int, 0)
+			for _, p := range ports {
+				if port, ok := p.(int); ok {
+					opts.Ports = append(opts.Ports, port)
+				}
+			}
+		}
+	}
+
+	return opts
+}
@@ -0,0 +1,81 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+func (e *Executor) executeTraceTask(ctx context.Context, task Task, result *TaskResult) error {
+	if len(task.Targets) == 0 {
+		return fmt.Errorf("trace 任务未配置目标")
+	}
+
+	opts := traceOptionsFromTask(task)
+
+	tracer, err := e.newTracer(opts.Protocol)
+	if err != nil {
+		return fmt.Errorf("创建 Tracer 失败: %w", err)
+	}
+	defer tracer.Close()
+
+	failures := 0
+	for _, target := range task.Targets {
+		traceResult, err := tracer.Trace(ctx, target, opts)
+		if err != nil {
+			logger.Error("路由追踪失败", zap.String("target", target), zap.Error(err))
+			failures++
+			continue
+		}
+
+		result.Results = append(result.Results, traceResult)
+		if !traceResult.ReachedDestination {
+			failures++
+		}
+
+		logger.Info("路由追踪完成",
+			zap.String("target", target),
+			zap.Int("hops", traceResult.HopCount),
+			zap.Bool("reached", traceResult.ReachedDestination),
+		)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("trace 任务部分失败: %d/%d 个目标失败", failures, len(task.Targets))
+	}
+	if len(result.Results) == 0 {
+		return fmt.Errorf("trace 任务未产生有效结果")
+	}
+
+	return nil
+}
+
+// traceOptionsFromTask 根据任务配置构建路由追踪选项
+func traceOptionsFromTask(task Task) *types.TraceOptions {
+	opts := types.DefaultTraceOptions()
+
+	if task.Options != nil {
+		if protocol, ok := task.Options["protocol"].(string); ok && protocol != "" {
+			opts.Protocol = types.Protocol(strings.ToLower(protocol))
+		}
+		if maxHops, ok := task.Options["max_hops"].(int); ok {
+			opts.MaxHops = maxHops
+		}
+		if queries, ok := task.Options["queries"].(int); ok {
+			opts.Queries = queries
+		}
+		if timeout, ok := task.Options["timeout"].(int); ok {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+		if port, ok := task.Options["port"].(int); ok {
+			opts.Port = port
+		}
+	}
+
+	return opts
+}
@@ -0,0 +1,110 @@
+package batch
+
+import (
+	"net/url"
+
+	"github.com/catsayer/ntx/internal/core/scan"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// PlanTarget 计划中的单个目标及其解析结果
+type PlanTarget struct {
+	Target string `json:"target" yaml:"target"`
+	IP     string `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// TaskPlan 单个任务的执行计划（--dry-run）
+type TaskPlan struct {
+	Name        string        `json:"name" yaml:"name"`
+	Type        TaskType      `json:"type" yaml:"type"`
+	Enabled     bool          `json:"enabled" yaml:"enabled"`
+	Targets     []*PlanTarget `json:"targets" yaml:"targets"`
+	Ports       string        `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Concurrency int           `json:"concurrency" yaml:"concurrency"`
+	// Runs 预计执行次数，配置 repeat/duration 时大于 1
+	Runs int `json:"runs" yaml:"runs"`
+	// EstimatedPackets 预计发送的探测包数（ping 为次数，dns 为查询数，scan 为 SYN 数，
+	// http 为请求数，trace 为最大跳数与每跳探测数之积），重复执行时乘以执行次数
+	EstimatedPackets int `json:"estimated_packets" yaml:"estimated_packets"`
+}
+
+// PlanFile 加载配置文件并生成执行计划，不发送任何探测流量（目标解析除外）
+func PlanFile(configFile string) ([]*TaskPlan, error) {
+	tasks, err := loadTaskConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return PlanTasks(tasks), nil
+}
+
+// PlanTasks 为任务列表生成执行计划
+func PlanTasks(tasks []Task) []*TaskPlan {
+	plans := make([]*TaskPlan, 0, len(tasks))
+	for _, task := range tasks {
+		plans = append(plans, planTask(task))
+	}
+	return plans
+}
+
+func planTask(task Task) *TaskPlan {
+	plan := &TaskPlan{
+		Name:    task.Name,
+		Type:    task.Type,
+		Enabled: task.Enabled,
+		Targets: make([]*PlanTarget, 0, len(task.Targets)),
+		Runs:    task.plannedRuns(),
+	}
+
+	perTarget := 0
+	switch task.Type {
+	case TaskTypePing:
+		perTarget = pingOptionsFromTask(task).Count
+		plan.Concurrency = pingConcurrency(task)
+	case TaskTypeDNS:
+		perTarget = 1
+		plan.Concurrency = 1
+	case TaskTypeScan:
+		opts := scanOptionsFromTask(task)
+		perTarget = len(opts.Ports)
+		plan.Ports = scan.FormatPortRanges(opts.Ports)
+		plan.Concurrency = opts.Concurrency
+	case TaskTypeHTTP:
+		perTarget = 1
+		plan.Concurrency = 1
+	case TaskTypeTrace:
+		opts := traceOptionsFromTask(task)
+		perTarget = opts.MaxHops * opts.Queries
+		plan.Concurrency = 1
+	}
+
+	for _, target := range task.Targets {
+		pt := &PlanTarget{Target: target}
+		// DNS 任务的目标即查询对象，无需预先解析
+		if task.Type != TaskTypeDNS {
+			if host, err := netutil.ResolveHost(planHost(task.Type, target), types.IPvAny); err != nil {
+				pt.Error = err.Error()
+			} else {
+				pt.IP = host.IP
+			}
+		}
+		plan.Targets = append(plan.Targets, pt)
+	}
+
+	if task.Enabled {
+		plan.EstimatedPackets = perTarget * len(task.Targets) * plan.Runs
+	}
+	return plan
+}
+
+// planHost 返回目标中需要解析的主机名，HTTP 任务的目标为 URL
+func planHost(taskType TaskType, target string) string {
+	if taskType != TaskTypeHTTP {
+		return target
+	}
+	if u, err := url.Parse(target); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return target
+}
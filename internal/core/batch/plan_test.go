@@ -0,0 +1,68 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanTasks(t *testing.T) {
+	tasks := []Task{
+		{
+			Name:    "ping",
+			Type:    TaskTypePing,
+			Enabled: true,
+			Targets: []string{"127.0.0.1", "127.0.0.2"},
+			Options: map[string]interface{}{"count": 3},
+		},
+		{
+			Name:    "scan",
+			Type:    TaskTypeScan,
+			Enabled: true,
+			Targets: []string{"127.0.0.1"},
+			Options: map[string]interface{}{"ports": []interface{}{22, 80, 81, 82}},
+		},
+		{
+			Name:    "disabled",
+			Type:    TaskTypeDNS,
+			Targets: []string{"example.com"},
+		},
+	}
+
+	plans := PlanTasks(tasks)
+	require.Len(t, plans, 3)
+
+	require.Equal(t, 6, plans[0].EstimatedPackets)
+	require.Equal(t, "127.0.0.2", plans[0].Targets[1].IP)
+
+	require.Equal(t, "22,80-82", plans[1].Ports)
+	require.Equal(t, 4, plans[1].EstimatedPackets)
+
+	require.False(t, plans[2].Enabled)
+	require.Equal(t, 0, plans[2].EstimatedPackets)
+	require.Empty(t, plans[2].Targets[0].IP)
+}
+
+func TestPlanHTTPAndTraceTasks(t *testing.T) {
+	plans := PlanTasks([]Task{
+		{
+			Name:    "web",
+			Type:    TaskTypeHTTP,
+			Enabled: true,
+			Targets: []string{"http://127.0.0.1:8080/health"},
+		},
+		{
+			Name:    "route",
+			Type:    TaskTypeTrace,
+			Enabled: true,
+			Targets: []string{"127.0.0.1"},
+			Options: map[string]interface{}{"max_hops": 10, "queries": 2},
+		},
+	})
+	require.Len(t, plans, 2)
+
+	require.Equal(t, "127.0.0.1", plans[0].Targets[0].IP)
+	require.Equal(t, 1, plans[0].EstimatedPackets)
+
+	require.Equal(t, 20, plans[1].EstimatedPackets)
+}
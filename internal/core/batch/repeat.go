@@ -0,0 +1,119 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"go.uber.org/zap"
+)
+
+// repeating 任务是否配置了重复执行
+func (t Task) repeating() bool {
+	return t.Repeat > 1 || t.Duration > 0
+}
+
+// plannedRuns 预计执行次数，仅配置 Duration 时按 Interval 推算
+func (t Task) plannedRuns() int {
+	runs := t.Repeat
+	if t.Duration > 0 && t.Interval > 0 {
+		window := int((t.Duration + t.Interval - 1) / t.Interval)
+		if runs <= 0 || window < runs {
+			runs = window
+		}
+	}
+	if runs <= 0 {
+		return 1
+	}
+	return runs
+}
+
+// runTask 按 Interval/Repeat/Duration 执行任务，未配置重复时只执行一次
+func (e *Executor) runTask(ctx context.Context, task Task) *TaskResult {
+	if !task.repeating() {
+		return e.ExecuteTask(ctx, task)
+	}
+
+	result := &TaskResult{
+		TaskName:  task.Name,
+		TaskType:  task.Type,
+		StartTime: time.Now(),
+		Results:   make([]interface{}, 0),
+	}
+
+	if task.Duration > 0 && task.Interval <= 0 {
+		result.EndTime = result.StartTime
+		result.Error = fmt.Errorf("任务 %s 配置了 duration 但未配置 interval", task.Name)
+		return result
+	}
+
+	var deadline time.Time
+	if task.Duration > 0 {
+		deadline = result.StartTime.Add(task.Duration)
+	}
+
+	failures := 0
+	var lastErr error
+	for i := 0; task.Repeat <= 0 || i < task.Repeat; i++ {
+		// 按固定节拍对齐各次执行的开始时间，避免单次耗时累积漂移
+		next := result.StartTime.Add(time.Duration(i) * task.Interval)
+		if !deadline.IsZero() && !next.Before(deadline) {
+			break
+		}
+		if !sleepUntil(ctx, next) {
+			break
+		}
+
+		run := e.ExecuteTask(ctx, task)
+		result.Runs = append(result.Runs, &TaskRun{
+			Index:       i + 1,
+			Success:     run.Success,
+			Error:       run.Error,
+			StartTime:   run.StartTime,
+			Duration:    run.Duration,
+			ResultCount: len(run.Results),
+		})
+		result.Results = append(result.Results, run.Results...)
+		if !run.Success {
+			failures++
+			lastErr = run.Error
+		}
+	}
+
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	switch {
+	case len(result.Runs) == 0:
+		result.Error = fmt.Errorf("任务未执行: %w", ctx.Err())
+	case failures > 0:
+		result.Error = fmt.Errorf("%d/%d 次执行失败，最近一次: %w", failures, len(result.Runs), lastErr)
+	default:
+		result.Success = true
+	}
+
+	logger.Info("重复任务执行完成",
+		zap.String("name", task.Name),
+		zap.Int("runs", len(result.Runs)),
+		zap.Int("failures", failures),
+	)
+
+	return result
+}
+
+// sleepUntil 等待到指定时间，上下文取消时返回 false
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package batch
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPinger 记录调用次数，每次返回成功结果
+type countingPinger struct {
+	calls atomic.Int32
+}
+
+func (p *countingPinger) Ping(_ context.Context, target string, _ *types.PingOptions) (*types.PingResult, error) {
+	p.calls.Add(1)
+	return &types.PingResult{
+		Target:     &types.Host{Hostname: target},
+		Status:     types.StatusSuccess,
+		Statistics: &types.Statistics{Sent: 1, Received: 1},
+	}, nil
+}
+
+func (p *countingPinger) PingStream(context.Context, string, *types.PingOptions) (<-chan *types.PingReply, error) {
+	return nil, nil
+}
+
+func (p *countingPinger) Close() error { return nil }
+
+func TestRepeatingTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	writeTaskFile(t, path, `
+tasks:
+  - name: repeat
+    type: ping
+    enabled: true
+    targets: [127.0.0.1, 127.0.0.2]
+    interval: 10ms
+    repeat: 3
+  - name: window
+    type: ping
+    enabled: true
+    targets: [127.0.0.1]
+    interval: 20ms
+    duration: 50ms
+`)
+	tasks, err := LoadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Millisecond, tasks[0].Interval)
+	require.Equal(t, 50*time.Millisecond, tasks[1].Duration)
+
+	pinger := &countingPinger{}
+	e := &Executor{pinger: pinger}
+
+	result, err := e.ExecuteTasks(context.Background(), tasks)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.SuccessTasks)
+
+	repeat := result.TaskResults[0]
+	require.Len(t, repeat.Runs, 3)
+	require.Len(t, repeat.Results, 6)
+	require.Equal(t, 2, repeat.Runs[2].ResultCount)
+	require.GreaterOrEqual(t, repeat.Duration, 20*time.Millisecond)
+	success, total := repeat.SuccessRatio()
+	require.Equal(t, 3, success)
+	require.Equal(t, 3, total)
+
+	// 20ms 间隔在 50ms 内执行 0/20/40ms 三次
+	window := result.TaskResults[1]
+	require.Len(t, window.Runs, 3)
+	require.Len(t, window.Results, 3)
+	require.Equal(t, int32(9), pinger.calls.Load())
+
+	plans := PlanTasks(tasks)
+	require.Equal(t, 3, plans[0].Runs)
+	require.Equal(t, 3, plans[1].Runs)
+	require.Equal(t, 4*2*3, plans[0].EstimatedPackets)
+}
+
+func TestRepeatingTaskStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	e := &Executor{pinger: &countingPinger{}}
+	result := e.runTask(ctx, Task{
+		Name:     "endless",
+		Type:     TaskTypePing,
+		Targets:  []string{"127.0.0.1"},
+		Interval: 20 * time.Millisecond,
+		Duration: time.Hour,
+	})
+
+	require.NotEmpty(t, result.Runs)
+	require.Less(t, len(result.Runs), 5)
+	require.True(t, result.Success)
+}
+
+func TestRepeatingTaskRequiresInterval(t *testing.T) {
+	e := &Executor{pinger: &countingPinger{}}
+	result := e.runTask(context.Background(), Task{
+		Name:     "no-interval",
+		Type:     TaskTypePing,
+		Targets:  []string{"127.0.0.1"},
+		Duration: time.Second,
+	})
+	require.False(t, result.Success)
+	require.ErrorContains(t, result.Error, "interval")
+}
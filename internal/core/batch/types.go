@@ -10,11 +10,20 @@ const (
 	TaskTypeDNS   TaskType = "dns"
 	TaskTypeScan  TaskType = "scan"
 	TaskTypeTrace TaskType = "trace"
+	TaskTypeHTTP  TaskType = "http"
 )
 
 // TaskConfig 任务配置文件结构
 type TaskConfig struct {
-	Tasks []Task `yaml:"tasks"`
+	// Include 引用的其他任务文件，相对路径相对于当前文件所在目录
+	Include []string `yaml:"include,omitempty"`
+	// MaxConcurrency 同时执行的最大任务数，仅顶层文件生效
+	MaxConcurrency int `yaml:"max_concurrency,omitempty"`
+	// Timeout 未单独配置 timeout 的任务使用的单次执行超时，仅顶层文件生效
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Defaults 放置供任务引用的 YAML 锚点，加载时忽略
+	Defaults interface{} `yaml:"defaults,omitempty"`
+	Tasks    []Task      `yaml:"tasks"`
 }
 
 // Task 单个任务定义
@@ -26,6 +35,13 @@ type Task struct {
 	Schedule    string                 `yaml:"schedule,omitempty"`
 	Enabled     bool                   `yaml:"enabled"`
 	Concurrency int                    `yaml:"concurrency,omitempty"`
+	// Timeout 单次执行超时，超时后任务记为失败
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Interval/Repeat/Duration 在一次 batch 执行中重复运行任务：每隔 Interval 执行一次，
+	// 直到达到 Repeat 次或超过 Duration；ntx serve 忽略这些字段
+	Interval time.Duration `yaml:"interval,omitempty"`
+	Repeat   int           `yaml:"repeat,omitempty"`
+	Duration time.Duration `yaml:"duration,omitempty"`
 }
 
 // TaskResult 任务执行结果
@@ -38,6 +54,35 @@ type TaskResult struct {
 	EndTime   time.Time
 	Duration  time.Duration
 	Results   []interface{}
+	// Runs 重复执行时每次执行的记录，各次结果按顺序合并在 Results 中
+	Runs []*TaskRun
+}
+
+// TaskRun 重复执行任务中的单次执行
+type TaskRun struct {
+	Index     int
+	Success   bool
+	Error     error
+	StartTime time.Time
+	Duration  time.Duration
+	// ResultCount 本次执行在 TaskResult.Results 中的结果数
+	ResultCount int
+}
+
+// SuccessRatio 返回成功执行次数与总执行次数，未重复执行的任务按一次计算
+func (r *TaskResult) SuccessRatio() (success, total int) {
+	if len(r.Runs) == 0 {
+		if r.Success {
+			return 1, 1
+		}
+		return 0, 1
+	}
+	for _, run := range r.Runs {
+		if run.Success {
+			success++
+		}
+	}
+	return success, len(r.Runs)
 }
 
 // BatchResult 批量任务结果
@@ -0,0 +1,238 @@
+package batch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError 任务文件校验错误，Problems 中每项为一条带行号的说明
+type ValidationError struct {
+	File     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return fmt.Sprintf("解析配置文件 %s 失败: %s", e.File, e.Problems[0])
+	}
+	return fmt.Sprintf("解析配置文件 %s 失败:\n  %s", e.File, strings.Join(e.Problems, "\n  "))
+}
+
+var (
+	unknownFieldRe = regexp.MustCompile(`^line (\d+): field (\S+) not found in type (\S+)$`)
+	mismatchRe     = regexp.MustCompile("^line (\\d+): cannot unmarshal !!\\w+ `(.*)` into (.+)$")
+	syntaxRe       = regexp.MustCompile(`^yaml: line (\d+): (.+)$`)
+)
+
+// yamlTypes 解码目标类型对应的字段集合与中文描述
+var yamlTypes = map[string]struct {
+	desc string
+	typ  reflect.Type
+}{
+	"batch.TaskConfig": {"顶层配置", reflect.TypeOf(TaskConfig{})},
+	"batch.Task":       {"任务", reflect.TypeOf(Task{})},
+}
+
+// valueTypes 常见目标类型的中文描述
+var valueTypes = map[string]string{
+	"int":                     "整数",
+	"bool":                    "布尔值 (true/false)",
+	"string":                  "字符串",
+	"time.Duration":           `时长 (如 "30s"、"5m")`,
+	"batch.TaskType":          "任务类型",
+	"[]string":                "字符串列表",
+	"[]batch.Task":            "任务列表",
+	"batch.Task":              "任务",
+	"map[string]interface {}": "键值映射",
+}
+
+// decodeTaskConfig 严格解析任务文件：未知字段、类型错误与无效任务均返回带行号的 ValidationError
+func decodeTaskConfig(path string, data []byte) (*TaskConfig, error) {
+	var config TaskConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, &ValidationError{File: path, Problems: translateYAMLError(err)}
+	}
+
+	if problems := validateTasks(data, config.Tasks); len(problems) > 0 {
+		return nil, &ValidationError{File: path, Problems: problems}
+	}
+	return &config, nil
+}
+
+// translateYAMLError 将 yaml.v3 的错误转换为中文说明
+func translateYAMLError(err error) []string {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		msg := err.Error()
+		if m := syntaxRe.FindStringSubmatch(msg); m != nil {
+			return []string{fmt.Sprintf("第 %s 行: YAML 语法错误: %s", m[1], m[2])}
+		}
+		return []string{msg}
+	}
+
+	problems := make([]string, 0, len(typeErr.Errors))
+	for _, e := range typeErr.Errors {
+		problems = append(problems, translateTypeError(e))
+	}
+	return problems
+}
+
+func translateTypeError(msg string) string {
+	if m := unknownFieldRe.FindStringSubmatch(msg); m != nil {
+		line, field, target := m[1], m[2], m[3]
+		desc := target
+		var hint string
+		if t, ok := yamlTypes[target]; ok {
+			desc = t.desc
+			if s := closestField(field, t.typ); s != "" {
+				hint = fmt.Sprintf("，是否为 %q?", s)
+			}
+		}
+		return fmt.Sprintf("第 %s 行: %s不支持字段 %q%s", line, desc, field, hint)
+	}
+	if m := mismatchRe.FindStringSubmatch(msg); m != nil {
+		line, value, target := m[1], m[2], m[3]
+		if desc, ok := valueTypes[target]; ok {
+			target = desc
+		}
+		return fmt.Sprintf("第 %s 行: 无法将 %q 解析为%s", line, value, target)
+	}
+	return msg
+}
+
+// closestField 在结构体的 yaml 字段中查找与 field 编辑距离最近的字段，距离过大时返回空
+func closestField(field string, t reflect.Type) string {
+	best, bestDist := "", len(field)/2+1
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if d := editDistance(field, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的 Levenshtein 距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// knownTaskTypes 执行器支持的任务类型
+var knownTaskTypes = []TaskType{TaskTypePing, TaskTypeDNS, TaskTypeScan, TaskTypeHTTP, TaskTypeTrace}
+
+// validateTasks 检查任务的必填字段与取值，行号取自 YAML 节点
+func validateTasks(data []byte, tasks []Task) []string {
+	nodes := taskNodes(data)
+
+	var problems []string
+	for i, task := range tasks {
+		var node *yaml.Node
+		if i < len(nodes) {
+			node = nodes[i]
+		}
+		label := strconv.Quote(task.Name)
+		if task.Name == "" {
+			label = fmt.Sprintf("第 %d 个任务", i+1)
+			problems = append(problems, fmt.Sprintf("第 %d 行: %s缺少 name", lineOf(node, ""), label))
+		} else {
+			label = "任务 " + label
+		}
+
+		switch {
+		case task.Type == "":
+			problems = append(problems, fmt.Sprintf("第 %d 行: %s缺少 type", lineOf(node, ""), label))
+		case !isKnownTaskType(task.Type):
+			problems = append(problems, fmt.Sprintf("第 %d 行: %s的 type %q 无效，可选: %s",
+				lineOf(node, "type"), label, task.Type, joinTaskTypes(knownTaskTypes)))
+		}
+		if len(task.Targets) == 0 {
+			problems = append(problems, fmt.Sprintf("第 %d 行: %s未配置 targets", lineOf(node, ""), label))
+		}
+		if task.Repeat < 0 {
+			problems = append(problems, fmt.Sprintf("第 %d 行: %s的 repeat 不能为负数", lineOf(node, "repeat"), label))
+		}
+		if task.Duration > 0 && task.Interval <= 0 {
+			problems = append(problems, fmt.Sprintf("第 %d 行: %s配置了 duration 但未配置 interval", lineOf(node, "duration"), label))
+		}
+	}
+	return problems
+}
+
+// taskNodes 返回 tasks 序列中各任务的映射节点
+func taskNodes(data []byte) []*yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "tasks" && root.Content[i+1].Kind == yaml.SequenceNode {
+			return root.Content[i+1].Content
+		}
+	}
+	return nil
+}
+
+// lineOf 返回任务节点中 key 的行号，key 为空或不存在时返回任务本身的行号
+func lineOf(node *yaml.Node, key string) int {
+	if node == nil {
+		return 0
+	}
+	if key != "" && node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i].Line
+			}
+		}
+	}
+	return node.Line
+}
+
+func isKnownTaskType(t TaskType) bool {
+	for _, known := range knownTaskTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func joinTaskTypes(types []TaskType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,190 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/catsayer/ntx/internal/output/formatter"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// IndexFile 结果目录中的汇总文件名
+const IndexFile = "index.json"
+
+// TaskRecord 写入结果目录的单个任务记录
+type TaskRecord struct {
+	Name      string        `json:"name"`
+	Type      TaskType      `json:"type"`
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+	StartTime time.Time     `json:"start_time"`
+	EndTime   time.Time     `json:"end_time"`
+	Duration  time.Duration `json:"duration"`
+	Runs      []*RunRecord  `json:"runs,omitempty"`
+	Results   []interface{} `json:"results"`
+}
+
+// RunRecord 重复执行任务中单次执行的记录
+type RunRecord struct {
+	Index       int           `json:"index"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+	StartTime   time.Time     `json:"start_time"`
+	Duration    time.Duration `json:"duration"`
+	ResultCount int           `json:"result_count"`
+}
+
+// ResultIndex 结果目录的汇总
+type ResultIndex struct {
+	GeneratedAt   time.Time     `json:"generated_at"`
+	TotalTasks    int           `json:"total_tasks"`
+	SuccessTasks  int           `json:"success_tasks"`
+	FailedTasks   int           `json:"failed_tasks"`
+	TotalDuration time.Duration `json:"total_duration"`
+	Tasks         []*IndexEntry `json:"tasks"`
+}
+
+// IndexEntry 汇总中的单个任务，File 为相对结果目录的文件名
+type IndexEntry struct {
+	Name        string        `json:"name"`
+	Type        TaskType      `json:"type"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	SuccessRuns int           `json:"success_runs"`
+	TotalRuns   int           `json:"total_runs"`
+	File        string        `json:"file"`
+}
+
+// WriteResultDir 将每个任务的结果写入 dir 下以任务名命名的 JSON 文件，并生成 index.json
+//
+// 目录不存在时自动创建；任务名重复时依次追加 -2、-3 等后缀，同名的旧文件会被覆盖。
+func WriteResultDir(dir string, result *BatchResult) (*ResultIndex, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建结果目录失败: %w", err)
+	}
+
+	f := formatter.NewFormatter(types.OutputJSON, true)
+	index := &ResultIndex{
+		GeneratedAt:   time.Now(),
+		TotalTasks:    result.TotalTasks,
+		SuccessTasks:  result.SuccessTasks,
+		FailedTasks:   result.FailedTasks,
+		TotalDuration: result.TotalDuration,
+		Tasks:         make([]*IndexEntry, 0, len(result.TaskResults)),
+	}
+
+	names := newFileNamer(IndexFile)
+	for _, taskResult := range result.TaskResults {
+		file := names.next(taskResult.TaskName)
+		if err := writeJSONFile(f, filepath.Join(dir, file), newTaskRecord(taskResult)); err != nil {
+			return nil, err
+		}
+
+		success, total := taskResult.SuccessRatio()
+		index.Tasks = append(index.Tasks, &IndexEntry{
+			Name:        taskResult.TaskName,
+			Type:        taskResult.TaskType,
+			Success:     taskResult.Success,
+			Error:       errorString(taskResult.Error),
+			Duration:    taskResult.Duration,
+			SuccessRuns: success,
+			TotalRuns:   total,
+			File:        file,
+		})
+	}
+
+	if err := writeJSONFile(f, filepath.Join(dir, IndexFile), index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// newTaskRecord 将任务结果转换为可序列化的记录，错误转为字符串
+func newTaskRecord(r *TaskResult) *TaskRecord {
+	record := &TaskRecord{
+		Name:      r.TaskName,
+		Type:      r.TaskType,
+		Success:   r.Success,
+		Error:     errorString(r.Error),
+		StartTime: r.StartTime,
+		EndTime:   r.EndTime,
+		Duration:  r.Duration,
+		Results:   r.Results,
+	}
+	if record.Results == nil {
+		record.Results = make([]interface{}, 0)
+	}
+	for _, run := range r.Runs {
+		record.Runs = append(record.Runs, &RunRecord{
+			Index:       run.Index,
+			Success:     run.Success,
+			Error:       errorString(run.Error),
+			StartTime:   run.StartTime,
+			Duration:    run.Duration,
+			ResultCount: run.ResultCount,
+		})
+	}
+	return record
+}
+
+func writeJSONFile(f formatter.Formatter, path string, data interface{}) error {
+	output, err := f.Format(data)
+	if err != nil {
+		return fmt.Errorf("序列化 %s 失败: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, []byte(output+"\n"), 0o644); err != nil {
+		return fmt.Errorf("写入结果文件失败: %w", err)
+	}
+	return nil
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// fileNamer 为任务分配不重复的文件名，比较时忽略大小写以兼容大小写不敏感的文件系统
+type fileNamer struct {
+	used map[string]bool
+}
+
+func newFileNamer(reserved ...string) *fileNamer {
+	n := &fileNamer{used: make(map[string]bool)}
+	for _, name := range reserved {
+		n.used[strings.ToLower(name)] = true
+	}
+	return n
+}
+
+// next 返回任务名对应的文件名，重名时追加 -2、-3 等后缀
+func (n *fileNamer) next(taskName string) string {
+	base := sanitizeFileName(taskName)
+	name := base + ".json"
+	for i := 2; n.used[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s-%d.json", base, i)
+	}
+	n.used[strings.ToLower(name)] = true
+	return name
+}
+
+// sanitizeFileName 保留字母、数字、'-'、'_' 与 '.'，其余字符替换为 '_'
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return "task"
+	}
+	return name
+}
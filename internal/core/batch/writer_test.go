@@ -0,0 +1,95 @@
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteResultDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "results")
+	result := &BatchResult{
+		TotalTasks:   3,
+		SuccessTasks: 2,
+		FailedTasks:  1,
+		TaskResults: []*TaskResult{
+			{
+				TaskName: "core/servers",
+				TaskType: TaskTypePing,
+				Success:  true,
+				Duration: time.Second,
+				Results: []interface{}{&types.PingResult{
+					Target:     &types.Host{Hostname: "example.com", IP: "192.0.2.1"},
+					Status:     types.StatusSuccess,
+					Statistics: &types.Statistics{Sent: 4, Received: 3, LossRate: 25},
+				}},
+			},
+			{
+				TaskName: "Core/Servers",
+				TaskType: TaskTypeDNS,
+				Success:  true,
+				Results: []interface{}{&types.DNSResult{
+					Domain:  "example.com",
+					Records: []*types.DNSRecord{{Type: types.DNSTypeA, Value: "192.0.2.1"}},
+				}},
+			},
+			{
+				TaskName: "index",
+				TaskType: TaskTypeHTTP,
+				Error:    errors.New("http 任务部分失败: 1/1 个目标失败"),
+			},
+		},
+	}
+
+	index, err := WriteResultDir(dir, result)
+	require.NoError(t, err)
+	require.Len(t, index.Tasks, 3)
+	require.Equal(t, "core_servers.json", index.Tasks[0].File)
+	require.Equal(t, "Core_Servers-2.json", index.Tasks[1].File)
+	require.Equal(t, "index-2.json", index.Tasks[2].File)
+
+	var ping struct {
+		TaskRecord
+		Results []*types.PingResult `json:"results"`
+	}
+	readJSON(t, filepath.Join(dir, "core_servers.json"), &ping)
+	require.Equal(t, "core/servers", ping.Name)
+	require.Equal(t, TaskTypePing, ping.Type)
+	require.Len(t, ping.Results, 1)
+	require.Equal(t, "192.0.2.1", ping.Results[0].Target.IP)
+	require.Equal(t, 25.0, ping.Results[0].Statistics.LossRate)
+
+	var dns struct {
+		Results []*types.DNSResult `json:"results"`
+	}
+	readJSON(t, filepath.Join(dir, "Core_Servers-2.json"), &dns)
+	require.Len(t, dns.Results, 1)
+	require.Equal(t, "192.0.2.1", dns.Results[0].Records[0].Value)
+
+	var failed TaskRecord
+	readJSON(t, filepath.Join(dir, "index-2.json"), &failed)
+	require.False(t, failed.Success)
+	require.Contains(t, failed.Error, "1/1")
+	require.Empty(t, failed.Results)
+
+	var summary ResultIndex
+	readJSON(t, filepath.Join(dir, IndexFile), &summary)
+	require.Equal(t, 3, summary.TotalTasks)
+	require.Equal(t, 1, summary.FailedTasks)
+	require.Equal(t, "Core_Servers-2.json", summary.Tasks[1].File)
+	require.Equal(t, 0, summary.Tasks[2].SuccessRuns)
+	require.Equal(t, 1, summary.Tasks[2].TotalRuns)
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = NewServer(0).Serve(ctx, ln)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestBenchTCPLoopback(t *testing.T) {
+	port := startServer(t)
+
+	client := NewClient(&types.BenchOptions{
+		Protocol: types.ProtocolTCP,
+		Port:     port,
+		Duration: 200 * time.Millisecond,
+		Parallel: 2,
+	})
+	result, err := client.Run(context.Background(), "127.0.0.1")
+	require.NoError(t, err)
+	require.Len(t, result.Streams, 2)
+	require.Greater(t, result.Bytes, int64(0))
+	require.Greater(t, result.BitsPerSecond, 0.0)
+}
+
+func TestBenchUDPLoopback(t *testing.T) {
+	port := startServer(t)
+
+	client := NewClient(&types.BenchOptions{
+		Protocol:   types.ProtocolUDP,
+		Port:       port,
+		Duration:   300 * time.Millisecond,
+		Parallel:   1,
+		Bandwidth:  8_000_000,
+		PacketSize: 1000,
+	})
+	result, err := client.Run(context.Background(), "127.0.0.1")
+	require.NoError(t, err)
+	require.Greater(t, result.PacketsSent, int64(0))
+	require.Equal(t, result.PacketsSent, result.PacketsLost+result.Streams[0].PacketsReceived)
+	require.Greater(t, result.Streams[0].PacketsReceived, int64(0))
+}
+
+func TestSummarizeRetransmitsUnsupported(t *testing.T) {
+	result := summarize("x", types.ProtocolTCP, []*types.BenchStreamResult{
+		{Bytes: 10, Retransmits: 3},
+		{Bytes: 20, Retransmits: -1},
+	})
+	require.Equal(t, int64(30), result.Bytes)
+	require.Equal(t, int64(-1), result.Retransmits)
+}
+
+func TestReadMessageRejectsOversizedLine(t *testing.T) {
+	line := `{"protocol":"` + strings.Repeat("x", maxMessageSize) + `"}` + "\n"
+	var h hello
+	err := readMessage(bufio.NewReader(strings.NewReader(line)), &h)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "超过")
+
+	require.NoError(t, readMessage(bufio.NewReader(strings.NewReader(`{"protocol":"tcp"}`+"\n")), &h))
+	require.Equal(t, types.ProtocolTCP, h.Protocol)
+}
+
+func TestServerRejectsInvalidDuration(t *testing.T) {
+	port := startServer(t)
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, writeMessage(conn, hello{Protocol: types.ProtocolTCP, Duration: 2 * maxTestDuration}))
+	var r report
+	require.NoError(t, readMessage(bufio.NewReader(conn), &r))
+	require.Contains(t, r.Error, "测试时长")
+}
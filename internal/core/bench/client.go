@@ -0,0 +1,268 @@
+package bench
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+// Client 带宽测试客户端
+type Client struct {
+	opts *types.BenchOptions
+}
+
+// NewClient 创建带宽测试客户端
+func NewClient(opts *types.BenchOptions) *Client {
+	defaults := types.DefaultBenchOptions()
+	if opts == nil {
+		opts = defaults
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = defaults.Protocol
+	}
+	if opts.Port <= 0 {
+		opts.Port = defaults.Port
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = defaults.Duration
+	}
+	if opts.Parallel <= 0 {
+		opts.Parallel = defaults.Parallel
+	}
+	if opts.Bandwidth <= 0 {
+		opts.Bandwidth = defaults.Bandwidth
+	}
+	if opts.PacketSize < 16 {
+		opts.PacketSize = defaults.PacketSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	return &Client{opts: opts}
+}
+
+// Run 对指定服务端执行带宽测试
+func (c *Client) Run(ctx context.Context, host string) (*types.BenchResult, error) {
+	if c.opts.Protocol != types.ProtocolTCP && c.opts.Protocol != types.ProtocolUDP {
+		return nil, fmt.Errorf("不支持的协议: %s", c.opts.Protocol)
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(c.opts.Port))
+	logger.Info("开始带宽测试",
+		zap.String("server", addr),
+		zap.String("protocol", string(c.opts.Protocol)),
+		zap.Int("parallel", c.opts.Parallel),
+		zap.Duration("duration", c.opts.Duration))
+
+	streams := make([]*types.BenchStreamResult, c.opts.Parallel)
+	errs := make([]error, c.opts.Parallel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.opts.Parallel; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if c.opts.Protocol == types.ProtocolUDP {
+				streams[id], errs[id] = c.runUDPStream(ctx, addr, id+1)
+			} else {
+				streams[id], errs[id] = c.runTCPStream(ctx, addr, id+1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return summarize(addr, c.opts.Protocol, streams), nil
+}
+
+func (c *Client) dial(ctx context.Context, addr string) (net.Conn, error) {
+	d := net.Dialer{Timeout: c.opts.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("连接服务端 %s 失败: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// runTCPStream 在单条连接上持续写入数据
+func (c *Client) runTCPStream(ctx context.Context, addr string, id int) (*types.BenchStreamResult, error) {
+	conn, err := c.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := writeMessage(conn, hello{Protocol: types.ProtocolTCP, Duration: c.opts.Duration}); err != nil {
+		return nil, fmt.Errorf("发送握手失败: %w", err)
+	}
+
+	buf := make([]byte, bufferSize)
+	deadline := time.Now().Add(c.opts.Duration)
+	_ = conn.SetWriteDeadline(deadline)
+	for ctx.Err() == nil && time.Now().Before(deadline) {
+		if _, err := conn.Write(buf); err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				break
+			}
+			return nil, fmt.Errorf("发送数据失败: %w", err)
+		}
+	}
+
+	retransmits := int64(-1)
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		retransmits = tcpRetransmits(tcpConn)
+		if err := tcpConn.CloseWrite(); err != nil {
+			return nil, fmt.Errorf("关闭写端失败: %w", err)
+		}
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(c.opts.Timeout))
+	var rep report
+	if err := readMessage(bufio.NewReader(conn), &rep); err != nil {
+		return nil, err
+	}
+	if rep.Error != "" {
+		return nil, fmt.Errorf("服务端错误: %s", rep.Error)
+	}
+
+	return &types.BenchStreamResult{
+		ID:            id,
+		Bytes:         rep.Bytes,
+		Duration:      rep.Duration,
+		BitsPerSecond: bitsPerSecond(rep.Bytes, rep.Duration),
+		Retransmits:   retransmits,
+	}, nil
+}
+
+// runUDPStream 按目标带宽发送带序号的数据报
+func (c *Client) runUDPStream(ctx context.Context, addr string, id int) (*types.BenchStreamResult, error) {
+	conn, err := c.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	if err := writeMessage(conn, hello{Protocol: types.ProtocolUDP, Duration: c.opts.Duration}); err != nil {
+		return nil, fmt.Errorf("发送握手失败: %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(c.opts.Timeout))
+	var setup udpSetup
+	if err := readMessage(reader, &setup); err != nil {
+		return nil, err
+	}
+
+	host, _, _ := net.SplitHostPort(addr)
+	udpConn, err := net.Dial("udp", net.JoinHostPort(host, strconv.Itoa(setup.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("创建 UDP 连接失败: %w", err)
+	}
+	defer udpConn.Close()
+
+	sent := sendPaced(ctx, udpConn, c.opts.PacketSize, c.opts.Bandwidth, c.opts.Duration)
+
+	if err := writeMessage(conn, udpDone{PacketsSent: sent}); err != nil {
+		return nil, fmt.Errorf("发送结束消息失败: %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(c.opts.Timeout + udpGracePeriod))
+	var rep report
+	if err := readMessage(reader, &rep); err != nil {
+		return nil, err
+	}
+	if rep.Error != "" {
+		return nil, fmt.Errorf("服务端错误: %s", rep.Error)
+	}
+
+	duration := rep.Duration
+	if duration <= 0 {
+		duration = c.opts.Duration
+	}
+	result := &types.BenchStreamResult{
+		ID:              id,
+		Bytes:           rep.Bytes,
+		Duration:        duration,
+		BitsPerSecond:   bitsPerSecond(rep.Bytes, duration),
+		PacketsSent:     sent,
+		PacketsReceived: rep.Packets,
+	}
+	if sent > 0 && rep.Packets < sent {
+		result.LossRate = float64(sent-rep.Packets) / float64(sent) * 100
+	}
+	return result, nil
+}
+
+// sendPaced 在 duration 内按 bandwidth (bit/s) 发送数据报，返回发送数量
+func sendPaced(ctx context.Context, conn net.Conn, size int, bandwidth int64, duration time.Duration) int64 {
+	buf := make([]byte, size)
+	packetBits := float64(size * 8)
+
+	var sent int64
+	start := time.Now()
+	for ctx.Err() == nil {
+		elapsed := time.Since(start)
+		if elapsed >= duration {
+			break
+		}
+		due := int64(elapsed.Seconds() * float64(bandwidth) / packetBits)
+		if sent >= due {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		binary.BigEndian.PutUint64(buf[:8], uint64(sent))
+		if _, err := conn.Write(buf); err != nil {
+			// 发送缓冲区满等瞬时错误不终止测试，该数据报计为丢失
+			logger.Debug("UDP 发送失败", zap.Error(err))
+		}
+		sent++
+	}
+	return sent
+}
+
+// summarize 汇总各数据流结果
+func summarize(target string, protocol types.Protocol, streams []*types.BenchStreamResult) *types.BenchResult {
+	result := &types.BenchResult{
+		Target:   target,
+		Protocol: protocol,
+		Streams:  streams,
+	}
+
+	var received int64
+	for _, s := range streams {
+		result.Bytes += s.Bytes
+		result.BitsPerSecond += s.BitsPerSecond
+		if s.Duration > result.Duration {
+			result.Duration = s.Duration
+		}
+		result.PacketsSent += s.PacketsSent
+		received += s.PacketsReceived
+		if protocol == types.ProtocolTCP {
+			if s.Retransmits < 0 || result.Retransmits < 0 {
+				result.Retransmits = -1
+			} else {
+				result.Retransmits += s.Retransmits
+			}
+		}
+	}
+
+	if result.PacketsSent > received {
+		result.PacketsLost = result.PacketsSent - received
+		result.LossRate = float64(result.PacketsLost) / float64(result.PacketsSent) * 100
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+// Package bench 提供 ntx 实例之间的 TCP/UDP 带宽测试
+//
+// 测试由客户端发起，每条数据流使用一条 TCP 连接:
+//   - TCP 模式: 握手后客户端持续写入数据，半关闭后由服务端回报接收字节数
+//   - UDP 模式: 该 TCP 连接作为控制通道，服务端分配 UDP 端口，
+//     客户端按目标带宽发送带序号的数据报，结束后服务端回报接收统计
+//
+// 使用示例:
+//
+//	server := bench.NewServer(5201)
+//	go server.ListenAndServe(ctx)
+//
+//	client := bench.NewClient(opts)
+//	result, err := client.Run(ctx, "192.168.1.10")
+//
+// 作者: Catsayer
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// bufferSize TCP 写入缓冲区大小
+const bufferSize = 128 * 1024
+
+// udpGracePeriod UDP 测试结束后等待在途数据报的时间
+const udpGracePeriod = 500 * time.Millisecond
+
+// maxMessageSize 单条控制消息的最大字节数（含换行）
+const maxMessageSize = 4096
+
+// hello 客户端在每条数据流开始时发送的握手信息
+type hello struct {
+	Protocol types.Protocol `json:"protocol"`
+	Duration time.Duration  `json:"duration"`
+}
+
+// udpSetup 服务端为 UDP 数据流分配的端口
+type udpSetup struct {
+	Port int `json:"udp_port"`
+}
+
+// udpDone 客户端结束 UDP 发送后上报的发送统计
+type udpDone struct {
+	PacketsSent int64 `json:"packets_sent"`
+}
+
+// report 服务端回报的接收统计
+type report struct {
+	Bytes    int64         `json:"bytes"`
+	Packets  int64         `json:"packets,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// writeMessage 以单行 JSON 写入控制消息
+func writeMessage(conn net.Conn, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(append(data, '\n'))
+	return err
+}
+
+// readMessage 读取单行 JSON 控制消息，超过 maxMessageSize 时返回错误而不继续缓冲
+func readMessage(r *bufio.Reader, v interface{}) error {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxMessageSize {
+			return fmt.Errorf("控制消息超过 %d 字节", maxMessageSize)
+		}
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return fmt.Errorf("读取控制消息失败: %w", err)
+		}
+	}
+	if err := json.Unmarshal(line, v); err != nil {
+		return fmt.Errorf("解析控制消息失败: %w", err)
+	}
+	return nil
+}
+
+// bitsPerSecond 计算吞吐量
+func bitsPerSecond(bytes int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / d.Seconds()
+}
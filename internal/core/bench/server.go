@@ -0,0 +1,182 @@
+package bench
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// handshakeTimeout 连接建立后等待握手消息的时间
+	handshakeTimeout = 10 * time.Second
+	// maxTestDuration 客户端可请求的最长测试时长
+	maxTestDuration = time.Hour
+	// deadlineSlack 测试时长之外允许的收尾时间（半关闭、上报统计等）
+	deadlineSlack = 10 * time.Second
+)
+
+// Server 带宽测试服务端
+type Server struct {
+	port int
+}
+
+// NewServer 创建带宽测试服务端
+func NewServer(port int) *Server {
+	if port <= 0 {
+		port = types.DefaultBenchPort
+	}
+	return &Server{port: port}
+}
+
+// ListenAndServe 监听端口并处理测试连接，直到 ctx 取消
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", ":"+strconv.Itoa(s.port))
+	if err != nil {
+		return fmt.Errorf("监听端口 %d 失败: %w", s.port, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve 在已有监听器上处理测试连接，直到 ctx 取消
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("接受连接失败: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			if err := s.handle(ctx, conn); err != nil {
+				logger.Warn("带宽测试连接处理失败", zap.String("peer", conn.RemoteAddr().String()), zap.Error(err))
+			}
+		}()
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) error {
+	reader := bufio.NewReader(conn)
+
+	_ = conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	var h hello
+	if err := readMessage(reader, &h); err != nil {
+		return err
+	}
+	if h.Duration <= 0 || h.Duration > maxTestDuration {
+		_ = writeMessage(conn, report{Error: fmt.Sprintf("无效的测试时长: %s", h.Duration)})
+		return fmt.Errorf("无效的测试时长: %s", h.Duration)
+	}
+	// 按协商的测试时长限制整个连接，防止客户端无限期占用
+	_ = conn.SetReadDeadline(time.Now().Add(h.Duration + deadlineSlack))
+
+	logger.Info("开始带宽测试",
+		zap.String("peer", conn.RemoteAddr().String()),
+		zap.String("protocol", string(h.Protocol)),
+		zap.Duration("duration", h.Duration))
+
+	switch h.Protocol {
+	case types.ProtocolTCP:
+		return s.receiveTCP(conn, reader)
+	case types.ProtocolUDP:
+		return s.receiveUDP(ctx, conn, reader)
+	default:
+		_ = writeMessage(conn, report{Error: fmt.Sprintf("不支持的协议: %s", h.Protocol)})
+		return fmt.Errorf("不支持的协议: %s", h.Protocol)
+	}
+}
+
+// receiveTCP 接收数据直到客户端半关闭，然后回报接收字节数
+func (s *Server) receiveTCP(conn net.Conn, reader *bufio.Reader) error {
+	start := time.Now()
+	n, err := io.Copy(io.Discard, reader)
+	elapsed := time.Since(start)
+	if err != nil {
+		return fmt.Errorf("接收数据失败: %w", err)
+	}
+
+	return writeMessage(conn, report{Bytes: n, Duration: elapsed})
+}
+
+// receiveUDP 分配 UDP 端口接收数据报，客户端上报结束后回报接收统计
+func (s *Server) receiveUDP(ctx context.Context, conn net.Conn, reader *bufio.Reader) error {
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	if err != nil {
+		_ = writeMessage(conn, report{Error: err.Error()})
+		return fmt.Errorf("创建 UDP 监听失败: %w", err)
+	}
+	defer udpConn.Close()
+
+	if err := writeMessage(conn, udpSetup{Port: udpConn.LocalAddr().(*net.UDPAddr).Port}); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var bytes, packets int64
+	var first, last time.Time
+
+	recvDone := make(chan struct{})
+	go func() {
+		defer close(recvDone)
+		buf := make([]byte, 65535)
+		for {
+			n, _, err := udpConn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if n < 8 {
+				continue
+			}
+			now := time.Now()
+			mu.Lock()
+			if packets == 0 {
+				first = now
+			}
+			last = now
+			packets++
+			bytes += int64(n)
+			mu.Unlock()
+		}
+	}()
+
+	var done udpDone
+	doneErr := readMessage(reader, &done)
+
+	// 等待在途数据报后停止接收
+	select {
+	case <-time.After(udpGracePeriod):
+	case <-ctx.Done():
+	}
+	udpConn.Close()
+	<-recvDone
+
+	if doneErr != nil && !errors.Is(doneErr, io.EOF) {
+		return doneErr
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return writeMessage(conn, report{Bytes: bytes, Packets: packets, Duration: last.Sub(first)})
+}
@@ -0,0 +1,27 @@
+//go:build linux
+// +build linux
+
+package bench
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// tcpRetransmits 通过 TCP_INFO 读取连接的累计重传次数
+func tcpRetransmits(conn *net.TCPConn) int64 {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return -1
+	}
+
+	retrans := int64(-1)
+	_ = raw.Control(func(fd uintptr) {
+		info, err := unix.GetsockoptTCPInfo(int(fd), unix.IPPROTO_TCP, unix.TCP_INFO)
+		if err == nil {
+			retrans = int64(info.Total_retrans)
+		}
+	})
+	return retrans
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package bench
+
+import "net"
+
+// tcpRetransmits 当前平台不支持读取重传次数
+func tcpRetransmits(conn *net.TCPConn) int64 {
+	return -1
+}
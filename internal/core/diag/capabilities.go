@@ -0,0 +1,116 @@
+package diag
+
+import (
+	"net"
+	"os"
+	"runtime"
+
+	"github.com/catsayer/ntx/pkg/buildinfo"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"golang.org/x/net/icmp"
+)
+
+// Capabilities 当前二进制在当前环境中可用的功能，供编排系统决定调用方式
+type Capabilities struct {
+	Version string `json:"version" yaml:"version"`
+	OS      string `json:"os" yaml:"os"`
+	Arch    string `json:"arch" yaml:"arch"`
+	// Privileged 是否以 root/管理员身份运行（Windows 上始终为 false）
+	Privileged bool `json:"privileged" yaml:"privileged"`
+	// RawICMPv4 / RawICMPv6 能否打开 ICMP 原始套接字（ICMP ping、TCP/UDP trace 依赖）
+	RawICMPv4 bool `json:"raw_icmp_v4" yaml:"raw_icmp_v4"`
+	RawICMPv6 bool `json:"raw_icmp_v6" yaml:"raw_icmp_v6"`
+	// DatagramICMPv4 能否打开非特权 ICMP 套接字（无原始套接字时 ICMP trace 使用）
+	DatagramICMPv4 bool `json:"datagram_icmp_v4" yaml:"datagram_icmp_v4"`
+	// IPv4 / IPv6 是否存在已启用的非回环地址
+	IPv4 bool `json:"ipv4" yaml:"ipv4"`
+	IPv6 bool `json:"ipv6" yaml:"ipv6"`
+	// CustomOUI 是否已加载外部 OUI 厂商文件
+	CustomOUI bool `json:"custom_oui" yaml:"custom_oui"`
+	// Protocols 各命令当前可用的协议/扫描方式
+	Protocols map[string][]string `json:"protocols" yaml:"protocols"`
+}
+
+// ProbeCapabilities 探测运行时功能，仅打开并立即关闭本地套接字，不发送任何数据包
+func ProbeCapabilities() *Capabilities {
+	caps := &Capabilities{
+		Version:    buildinfo.Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Privileged: os.Geteuid() == 0,
+		RawICMPv4:  canListen("ip4:icmp", "0.0.0.0"),
+		RawICMPv6:  canListen("ip6:ipv6-icmp", "::"),
+		CustomOUI:  netutil.HasCustomVendors(),
+	}
+	caps.IPv4, caps.IPv6 = hostAddressFamilies()
+	caps.DatagramICMPv4 = canListenICMP("udp4", "0.0.0.0")
+
+	// 与 NewService 一致：ICMP 不可用时 ping 自动回退到 TCP
+	ping := []string{"tcp", "http"}
+	if caps.RawICMPv4 || caps.RawICMPv6 {
+		ping = append([]string{"icmp"}, ping...)
+	}
+	// 与 trace.NewTracer 一致：ICMP 追踪可退回非特权套接字，TCP/UDP 追踪需通过原始套接字接收 ICMP 差错
+	trace := []string{}
+	if caps.RawICMPv4 || caps.DatagramICMPv4 {
+		trace = append(trace, "icmp")
+	}
+	if caps.RawICMPv4 {
+		trace = append(trace, "tcp", "udp")
+	}
+	caps.Protocols = map[string][]string{
+		"ping":  ping,
+		"trace": trace,
+		"scan":  {"tcp-connect"},
+	}
+	return caps
+}
+
+// canListen 判断能否打开指定网络类型的套接字
+func canListen(network, address string) bool {
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// canListenICMP 判断能否打开指定类型的 ICMP 套接字（udp4 为非特权 ICMP）
+func canListenICMP(network, address string) bool {
+	conn, err := icmp.ListenPacket(network, address)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// hostAddressFamilies 检查已启用接口上是否存在非回环 IPv4/IPv6 地址
+func hostAddressFamilies() (v4, v6 bool) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return false, false
+	}
+	for _, ifc := range ifaces {
+		if ifc.Flags&net.FlagUp == 0 || ifc.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := ifc.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() {
+				continue
+			}
+			if ipNet.IP.To4() != nil {
+				v4 = true
+			} else if !ipNet.IP.IsLinkLocalUnicast() {
+				v6 = true
+			}
+		}
+	}
+	return v4, v6
+}
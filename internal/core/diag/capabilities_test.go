@@ -0,0 +1,23 @@
+package diag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeCapabilitiesConsistent(t *testing.T) {
+	caps := ProbeCapabilities()
+
+	require.NotEmpty(t, caps.OS)
+	require.Contains(t, caps.Protocols["ping"], "tcp")
+	require.Equal(t, caps.RawICMPv4 || caps.RawICMPv6, contains(caps.Protocols["ping"], "icmp"))
+	require.Equal(t, caps.RawICMPv4 || caps.DatagramICMPv4, contains(caps.Protocols["trace"], "icmp"))
+	require.Equal(t, caps.RawICMPv4, contains(caps.Protocols["trace"], "tcp"))
+	require.Equal(t, caps.RawICMPv4, contains(caps.Protocols["trace"], "udp"))
+
+	data, err := json.Marshal(caps)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"raw_icmp_v4":`)
+}
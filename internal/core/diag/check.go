@@ -0,0 +1,145 @@
+package diag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// Check 可注册的诊断检查项
+//
+// Run 返回 nil 表示检查被跳过（如缺少权限）；结果非健康时，
+// Diagnose 以 Category 为分类生成 Issue。
+type Check interface {
+	// Name 检查项名称，用于 --only/--skip 选择
+	Name() string
+	// Category 问题分类
+	Category() string
+	// Run 执行检查
+	Run(ctx context.Context) *types.CheckResult
+}
+
+// LeveledCheck 仅在指定诊断级别及以上执行的检查项，未实现时在所有级别执行
+type LeveledCheck interface {
+	Check
+	Level() DiagnosticLevel
+}
+
+// funcCheck 将检查函数包装为 Check
+type funcCheck struct {
+	name       string
+	category   string
+	level      DiagnosticLevel
+	suggestion string
+	run        func(ctx context.Context) *types.CheckResult
+}
+
+func (c *funcCheck) Name() string           { return c.name }
+func (c *funcCheck) Category() string       { return c.category }
+func (c *funcCheck) Level() DiagnosticLevel { return c.level }
+
+// Run 执行检查，结果未附带修复建议时使用默认建议
+func (c *funcCheck) Run(ctx context.Context) *types.CheckResult {
+	result := c.run(ctx)
+	if result != nil && result.Status != types.DiagStatusHealthy && result.Suggestion == "" {
+		result.Suggestion = c.suggestion
+	}
+	return result
+}
+
+// Register 按顺序追加检查项，名称重复时返回错误
+func (s *Service) Register(checks ...Check) error {
+	for _, check := range checks {
+		for _, existing := range s.checks {
+			if existing.Name() == check.Name() {
+				return fmt.Errorf("检查项 %q 已注册", check.Name())
+			}
+		}
+		s.checks = append(s.checks, check)
+	}
+	return nil
+}
+
+// Checks 返回已注册的检查项
+func (s *Service) Checks() []Check {
+	return s.checks
+}
+
+// registerBuiltinChecks 注册内置检查项
+//
+//   - 快速: 网络接口、网关与经系统解析器的 DNS 解析，不直接访问公网
+//   - 标准: 增加公网连通性、强制门户、路径 MTU 与解析器比对
+//   - 完整: 增加持续 Ping 的抖动/丢包采样与 HTTP 首字节时间测量
+func (s *Service) registerBuiltinChecks() {
+	_ = s.Register(
+		&funcCheck{name: "interfaces", category: "网络配置", level: DiagLevelFast,
+			suggestion: "检查网络接口配置和状态", run: s.checkNetworkInterfaces},
+		&funcCheck{name: "gateway", category: "本地连通性", level: DiagLevelFast,
+			suggestion: "检查网关配置和本地网络连接", run: s.checkLocalConnectivity},
+		&funcCheck{name: "internet", category: "互联网连通性", level: DiagLevelNormal,
+			suggestion: "检查路由器配置和 ISP 连接", run: s.checkInternetConnectivity},
+		&funcCheck{name: "captive-portal", category: "强制门户", level: DiagLevelNormal,
+			run: func(ctx context.Context) *types.CheckResult { return s.checkCaptivePortal(ctx, s.captiveURL) }},
+		&funcCheck{name: "mtu", category: "路径 MTU", level: DiagLevelNormal, run: s.checkPathMTU},
+		&funcCheck{name: "dns", category: "DNS 解析", level: DiagLevelFast,
+			suggestion: fmt.Sprintf("检查 DNS 服务器配置，尝试使用公共 DNS（如 %s）", types.DefaultDNSServer),
+			run:        s.checkDNSResolution},
+		&funcCheck{name: "dns-consistency", category: "DNS 解析", level: DiagLevelNormal, run: s.checkDNSConsistency},
+		&funcCheck{name: "latency", category: "网络质量", level: DiagLevelFull, run: s.checkLatencyQuality},
+		&funcCheck{name: "http", category: "网络质量", level: DiagLevelFull, run: s.checkHTTPLatency},
+	)
+}
+
+// SelectChecks 按诊断级别与 Only/Skip 返回将要执行的检查项
+//
+// Only 非空时只执行列出的检查项且不受级别限制；未知的名称返回错误。
+func (s *Service) SelectChecks(opts DiagnosticOptions) ([]Check, error) {
+	known := make(map[string]bool, len(s.checks))
+	names := make([]string, 0, len(s.checks))
+	for _, check := range s.checks {
+		known[check.Name()] = true
+		names = append(names, check.Name())
+	}
+	toSet := func(list []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(list))
+		for _, name := range list {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !known[name] {
+				return nil, fmt.Errorf("未知的检查项 %q，可用的检查项: %s", name, strings.Join(names, ","))
+			}
+			set[name] = true
+		}
+		return set, nil
+	}
+	only, err := toSet(opts.Only)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := toSet(opts.Skip)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []Check
+	for _, check := range s.checks {
+		switch {
+		case skip[check.Name()]:
+			continue
+		case len(only) > 0:
+			if !only[check.Name()] {
+				continue
+			}
+		default:
+			if leveled, ok := check.(LeveledCheck); ok && leveled.Level() > opts.Level {
+				continue
+			}
+		}
+		selected = append(selected, check)
+	}
+	return selected, nil
+}
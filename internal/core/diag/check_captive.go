@@ -0,0 +1,84 @@
+package diag
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/http"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// checkCaptivePortal 请求返回 204 的控制地址，检测强制登录门户
+//
+// 门户会劫持 HTTP 请求并重定向到登录页或直接返回 HTML；此时 DNS 与 TCP
+// 看似正常，但实际无法访问互联网。控制地址不可达时无法判断，视为正常。
+func (s *Service) checkCaptivePortal(ctx context.Context, controlURL string) *types.CheckResult {
+	startTime := time.Now()
+	if controlURL == "" {
+		controlURL = types.DefaultCaptivePortalURL
+	}
+
+	// 不跟随重定向，以便记录门户地址
+	client := http.NewClient(&types.HTTPOptions{
+		Timeout:    types.DiagnosticTargetTimeout,
+		Decompress: true,
+	})
+	defer client.Close()
+
+	check := &types.CheckResult{
+		Name:     "强制门户检查",
+		Category: "连通性",
+		Status:   types.DiagStatusHealthy,
+		Details:  map[string]interface{}{"url": controlURL},
+	}
+	result, err := client.Get(ctx, controlURL, nil)
+	if err != nil {
+		check.Message = "无法访问控制地址，跳过强制门户检测"
+		check.Details["error"] = err.Error()
+		check.Duration = time.Since(startTime)
+		return check
+	}
+
+	check.Details["status_code"] = result.StatusCode
+	switch {
+	case result.StatusCode == nethttp.StatusNoContent:
+		check.Message = "未检测到强制门户"
+	case result.StatusCode >= 300 && result.StatusCode < 400:
+		location := headerValue(result.Headers, "Location")
+		check.Details["location"] = location
+		check.Status = types.DiagStatusWarning
+		check.Message = fmt.Sprintf("请求被重定向到 %s，当前网络需要登录", location)
+		check.Suggestion = "在浏览器中打开任意 http:// 网址完成 Wi-Fi 登录或认证"
+	case result.StatusCode == nethttp.StatusOK && isHTML(result):
+		check.Status = types.DiagStatusWarning
+		check.Message = "控制地址返回了 HTML 页面，当前网络可能需要登录"
+		check.Suggestion = "在浏览器中打开任意 http:// 网址完成 Wi-Fi 登录或认证"
+	default:
+		check.Status = types.DiagStatusWarning
+		check.Message = fmt.Sprintf("控制地址返回 %d，预期为 204，可能存在透明代理", result.StatusCode)
+		check.Suggestion = "检查网络是否经过代理或内容过滤设备"
+	}
+	check.Duration = time.Since(startTime)
+	return check
+}
+
+// isHTML 判断响应是否为 HTML 页面
+func isHTML(result *types.HTTPResult) bool {
+	if strings.Contains(strings.ToLower(result.ContentType), "html") {
+		return true
+	}
+	body := bytes.TrimSpace(result.Body)
+	return len(body) > 0 && body[0] == '<'
+}
+
+// headerValue 返回响应头的第一个值
+func headerValue(headers map[string][]string, key string) string {
+	if values := headers[nethttp.CanonicalHeaderKey(key)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package diag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCaptivePortal(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/generate_204", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://login.portal.example/?orig=generate_204", http.StatusFound)
+	})
+	mux.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html><body>Welcome, please sign in</body></html>"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := &Service{}
+	ctx := context.Background()
+
+	check := s.checkCaptivePortal(ctx, srv.URL+"/generate_204")
+	require.Equal(t, types.DiagStatusHealthy, check.Status)
+	require.Empty(t, check.Suggestion)
+	require.Equal(t, http.StatusNoContent, check.Details["status_code"])
+
+	check = s.checkCaptivePortal(ctx, srv.URL+"/redirect")
+	require.Equal(t, types.DiagStatusWarning, check.Status)
+	require.Equal(t, "http://login.portal.example/?orig=generate_204", check.Details["location"])
+	require.Contains(t, check.Suggestion, "浏览器")
+
+	check = s.checkCaptivePortal(ctx, srv.URL+"/html")
+	require.Equal(t, types.DiagStatusWarning, check.Status)
+	require.Equal(t, http.StatusOK, check.Details["status_code"])
+	require.Contains(t, check.Suggestion, "浏览器")
+}
@@ -10,15 +10,15 @@ import (
 )
 
 // checkLocalConnectivity 检查本地连通性（Ping 网关）
-func (s *Service) checkLocalConnectivity(ctx context.Context) *CheckResult {
+func (s *Service) checkLocalConnectivity(ctx context.Context) *types.CheckResult {
 	startTime := time.Now()
 
 	gateway, err := s.getDefaultGateway()
 	if err != nil {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "本地连通性检查",
 			Category: "连通性",
-			Status:   StatusWarning,
+			Status:   types.DiagStatusWarning,
 			Message:  "无法获取默认网关",
 			Duration: time.Since(startTime),
 		}
@@ -30,19 +30,19 @@ func (s *Service) checkLocalConnectivity(ctx context.Context) *CheckResult {
 
 	result, err := s.pinger.Ping(ctx, gateway, pingOpts)
 	if err != nil || result.Statistics.Received == 0 {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "本地连通性检查",
 			Category: "连通性",
-			Status:   StatusCritical,
+			Status:   types.DiagStatusCritical,
 			Message:  fmt.Sprintf("无法连接到网关 %s", gateway),
 			Duration: time.Since(startTime),
 		}
 	}
 
-	return &CheckResult{
+	return &types.CheckResult{
 		Name:     "本地连通性检查",
 		Category: "连通性",
-		Status:   StatusHealthy,
+		Status:   types.DiagStatusHealthy,
 		Message:  fmt.Sprintf("网关 %s 可达，延迟 %.2fms", gateway, float64(result.Statistics.AvgRTT.Microseconds())/1000),
 		Duration: time.Since(startTime),
 		Details: map[string]interface{}{
@@ -53,7 +53,7 @@ func (s *Service) checkLocalConnectivity(ctx context.Context) *CheckResult {
 }
 
 // checkInternetConnectivity 检查互联网连通性
-func (s *Service) checkInternetConnectivity(ctx context.Context) *CheckResult {
+func (s *Service) checkInternetConnectivity(ctx context.Context) *types.CheckResult {
 	startTime := time.Now()
 
 	publicDNS := types.DNSServerList()
@@ -76,31 +76,39 @@ func (s *Service) checkInternetConnectivity(ctx context.Context) *CheckResult {
 		}
 	}
 
+	details := map[string]interface{}{
+		"probed":    len(publicDNS),
+		"reachable": successCount,
+	}
+
 	if successCount == 0 {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "互联网连通性检查",
 			Category: "连通性",
-			Status:   StatusCritical,
+			Status:   types.DiagStatusCritical,
 			Message:  "无法连接到互联网",
 			Duration: time.Since(startTime),
+			Details:  details,
 		}
 	}
 
 	if successCount < len(publicDNS) {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "互联网连通性检查",
 			Category: "连通性",
-			Status:   StatusWarning,
+			Status:   types.DiagStatusWarning,
 			Message:  "互联网连接不稳定",
 			Duration: time.Since(startTime),
+			Details:  details,
 		}
 	}
 
-	return &CheckResult{
+	return &types.CheckResult{
 		Name:     "互联网连通性检查",
 		Category: "连通性",
-		Status:   StatusHealthy,
+		Status:   types.DiagStatusHealthy,
 		Message:  "互联网连接正常",
 		Duration: time.Since(startTime),
+		Details:  details,
 	}
 }
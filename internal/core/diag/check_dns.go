@@ -2,50 +2,166 @@ package diag
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"sort"
 	"time"
 
+	"github.com/catsayer/ntx/internal/core/dns"
 	"github.com/catsayer/ntx/pkg/types"
 )
 
-// checkDNSResolution 检查 DNS 解析
-func (s *Service) checkDNSResolution(ctx context.Context) *CheckResult {
+// checkDNSResolution 通过系统解析器检查 DNS 解析，不直接访问公共 DNS
+func (s *Service) checkDNSResolution(ctx context.Context) *types.CheckResult {
 	startTime := time.Now()
 
 	testDomains := []string{"google.com", "baidu.com"}
 
 	successCount := 0
 	for _, domain := range testDomains {
-		result, err := s.resolver.Query(ctx, domain, types.DNSTypeA)
-		if err == nil && len(result.Records) > 0 {
+		lookupCtx, cancel := context.WithTimeout(ctx, types.DefaultDNSTimeout)
+		addrs, err := s.systemLookup(lookupCtx, domain)
+		cancel()
+		if err == nil && len(addrs) > 0 {
 			successCount++
 		}
 	}
 
+	details := map[string]interface{}{
+		"domains":  testDomains,
+		"resolved": successCount,
+	}
+
 	if successCount == 0 {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "DNS 解析检查",
 			Category: "DNS",
-			Status:   StatusCritical,
+			Status:   types.DiagStatusCritical,
 			Message:  "DNS 解析失败",
 			Duration: time.Since(startTime),
+			Details:  details,
 		}
 	}
 
 	if successCount < len(testDomains) {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "DNS 解析检查",
 			Category: "DNS",
-			Status:   StatusWarning,
+			Status:   types.DiagStatusWarning,
 			Message:  "部分域名解析失败",
 			Duration: time.Since(startTime),
+			Details:  details,
 		}
 	}
 
-	return &CheckResult{
+	return &types.CheckResult{
 		Name:     "DNS 解析检查",
 		Category: "DNS",
-		Status:   StatusHealthy,
+		Status:   types.DiagStatusHealthy,
 		Message:  "DNS 解析正常",
 		Duration: time.Since(startTime),
+		Details:  details,
+	}
+}
+
+// dnsControlDomain 解析器比对使用的控制域名，其 A 记录为固定的任播地址
+const dnsControlDomain = "one.one.one.one"
+
+// dnsLookup 解析域名的 IPv4 地址
+type dnsLookup func(ctx context.Context, domain string) ([]string, error)
+
+// systemDNSLookup 使用操作系统配置的解析器
+func systemDNSLookup(ctx context.Context, domain string) ([]string, error) {
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", domain)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, len(ips))
+	for i, ip := range ips {
+		addrs[i] = ip.String()
+	}
+	return addrs, nil
+}
+
+// resolverLookup 使用指定服务器的解析器
+func resolverLookup(resolver *dns.Resolver) dnsLookup {
+	return func(ctx context.Context, domain string) ([]string, error) {
+		result, err := resolver.Query(ctx, domain, types.DNSTypeA)
+		if err != nil {
+			return nil, err
+		}
+		addrs := make([]string, 0, len(result.Records))
+		for _, record := range result.Records {
+			if record.Type == types.DNSTypeA {
+				addrs = append(addrs, record.Value)
+			}
+		}
+		return addrs, nil
+	}
+}
+
+// checkDNSConsistency 比对系统解析器与公共 DNS 对控制域名的应答
+//
+// 仅系统解析器失败，或两者应答没有交集（疑似 DNS 劫持）时返回 types.DiagStatusWarning
+// 并附带修复建议；公共 DNS 不可达时无法比对，视为正常。
+func (s *Service) checkDNSConsistency(ctx context.Context) *types.CheckResult {
+	startTime := time.Now()
+
+	systemAddrs, systemErr := s.systemLookup(ctx, dnsControlDomain)
+	publicAddrs, publicErr := s.publicLookup(ctx, dnsControlDomain)
+	if systemErr == nil && len(systemAddrs) == 0 {
+		systemErr = fmt.Errorf("没有 A 记录")
+	}
+	if publicErr == nil && len(publicAddrs) == 0 {
+		publicErr = fmt.Errorf("没有 A 记录")
+	}
+	sort.Strings(systemAddrs)
+	sort.Strings(publicAddrs)
+
+	details := map[string]interface{}{
+		"domain":        dnsControlDomain,
+		"public_server": types.DefaultDNSServer,
+		"system":        systemAddrs,
+		"public":        publicAddrs,
+	}
+	if systemErr != nil {
+		details["system_error"] = systemErr.Error()
+	}
+	if publicErr != nil {
+		details["public_error"] = publicErr.Error()
+	}
+
+	check := &types.CheckResult{
+		Name:     "DNS 一致性检查",
+		Category: "DNS",
+		Status:   types.DiagStatusHealthy,
+		Details:  details,
+	}
+	switch {
+	case publicErr != nil:
+		// 公共 DNS 被防火墙拦截时无法比对，系统解析器是否可用由解析检查负责
+		check.Message = fmt.Sprintf("无法访问 %s，跳过解析器比对", types.DefaultDNSServer)
+	case systemErr != nil:
+		check.Status = types.DiagStatusWarning
+		check.Message = fmt.Sprintf("系统解析器无法解析 %s，公共 DNS 正常", dnsControlDomain)
+		check.Suggestion = fmt.Sprintf("本地解析器异常，检查 /etc/resolv.conf 或路由器 DNS 设置，或改用公共 DNS（如 %s）", types.DefaultDNSServer)
+	case !overlaps(systemAddrs, publicAddrs):
+		check.Status = types.DiagStatusWarning
+		check.Message = fmt.Sprintf("系统解析器与 %s 的应答不一致，可能存在 DNS 劫持", types.DefaultDNSServer)
+		check.Suggestion = fmt.Sprintf("确认本地 DNS 服务器可信，必要时改用公共 DNS（如 %s）或启用加密 DNS", types.DefaultDNSServer)
+	default:
+		check.Message = "系统解析器与公共 DNS 应答一致"
+	}
+	check.Duration = time.Since(startTime)
+	return check
+}
+
+// overlaps 判断两个地址列表是否存在相同地址
+func overlaps(a, b []string) bool {
+	for _, addr := range a {
+		if contains(b, addr) {
+			return true
+		}
 	}
+	return false
 }
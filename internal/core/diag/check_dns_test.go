@@ -0,0 +1,64 @@
+package diag
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedLookup 返回固定应答的解析函数
+func fixedLookup(addrs []string, err error) dnsLookup {
+	return func(context.Context, string) ([]string, error) {
+		return addrs, err
+	}
+}
+
+func TestCheckDNSConsistency(t *testing.T) {
+	public := fixedLookup([]string{"1.1.1.1", "1.0.0.1"}, nil)
+
+	t.Run("Match", func(t *testing.T) {
+		s := &Service{systemLookup: fixedLookup([]string{"1.1.1.1"}, nil), publicLookup: public}
+		check := s.checkDNSConsistency(context.Background())
+		require.Equal(t, types.DiagStatusHealthy, check.Status)
+		require.Empty(t, check.Suggestion)
+		require.Equal(t, []string{"1.1.1.1"}, check.Details["system"])
+		require.Equal(t, []string{"1.0.0.1", "1.1.1.1"}, check.Details["public"])
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		s := &Service{systemLookup: fixedLookup([]string{"10.0.0.53"}, nil), publicLookup: public}
+		check := s.checkDNSConsistency(context.Background())
+		require.Equal(t, types.DiagStatusWarning, check.Status)
+		require.Contains(t, check.Message, "劫持")
+		require.NotEmpty(t, check.Suggestion)
+		require.Equal(t, []string{"10.0.0.53"}, check.Details["system"])
+	})
+
+	t.Run("LocalOnlyFailure", func(t *testing.T) {
+		s := &Service{systemLookup: fixedLookup(nil, stderrors.New("server misbehaving")), publicLookup: public}
+		check := s.checkDNSConsistency(context.Background())
+		require.Equal(t, types.DiagStatusWarning, check.Status)
+		require.Contains(t, check.Suggestion, "resolv.conf")
+		require.Equal(t, "server misbehaving", check.Details["system_error"])
+	})
+
+	t.Run("PublicUnreachable", func(t *testing.T) {
+		s := &Service{systemLookup: fixedLookup([]string{"1.1.1.1"}, nil), publicLookup: fixedLookup(nil, stderrors.New("i/o timeout"))}
+		check := s.checkDNSConsistency(context.Background())
+		require.Equal(t, types.DiagStatusHealthy, check.Status)
+		require.Empty(t, check.Suggestion)
+		require.Contains(t, check.Details, "public_error")
+	})
+}
+
+func TestCheckDNSResolutionUsesSystemResolver(t *testing.T) {
+	// publicLookup 未设置，快速诊断的解析检查不得访问公共 DNS
+	s := &Service{systemLookup: fixedLookup([]string{"192.0.2.1"}, nil)}
+	require.Equal(t, types.DiagStatusHealthy, s.checkDNSResolution(context.Background()).Status)
+
+	s.systemLookup = fixedLookup(nil, stderrors.New("no such host"))
+	require.Equal(t, types.DiagStatusCritical, s.checkDNSResolution(context.Background()).Status)
+}
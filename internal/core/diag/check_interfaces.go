@@ -4,18 +4,20 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
 )
 
 // checkNetworkInterfaces 检查网络接口配置
-func (s *Service) checkNetworkInterfaces(ctx context.Context) *CheckResult {
+func (s *Service) checkNetworkInterfaces(ctx context.Context) *types.CheckResult {
 	startTime := time.Now()
 
 	interfaces, err := s.ifReader.GetInterfaces()
 	if err != nil {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "网络接口检查",
 			Category: "网络配置",
-			Status:   StatusCritical,
+			Status:   types.DiagStatusCritical,
 			Message:  fmt.Sprintf("获取网络接口失败: %v", err),
 			Duration: time.Since(startTime),
 		}
@@ -35,29 +37,29 @@ func (s *Service) checkNetworkInterfaces(ctx context.Context) *CheckResult {
 	}
 
 	if !hasActiveInterface {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "网络接口检查",
 			Category: "网络配置",
-			Status:   StatusCritical,
+			Status:   types.DiagStatusCritical,
 			Message:  "没有活动的网络接口",
 			Duration: time.Since(startTime),
 		}
 	}
 
 	if !hasIPv4Address {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     "网络接口检查",
 			Category: "网络配置",
-			Status:   StatusWarning,
+			Status:   types.DiagStatusWarning,
 			Message:  "网络接口没有配置 IPv4 地址",
 			Duration: time.Since(startTime),
 		}
 	}
 
-	return &CheckResult{
+	return &types.CheckResult{
 		Name:     "网络接口检查",
 		Category: "网络配置",
-		Status:   StatusHealthy,
+		Status:   types.DiagStatusHealthy,
 		Message:  "网络接口配置正常",
 		Duration: time.Since(startTime),
 		Details: map[string]interface{}{
@@ -0,0 +1,126 @@
+package diag
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/ping"
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+// mtuDiscoverFunc 探测到目标的路径 MTU
+type mtuDiscoverFunc func(ctx context.Context, target string) (*types.MTUResult, error)
+
+// icmpDiscoverMTU 使用设置 DF 位的 ICMP Pinger 探测路径 MTU，需要原始套接字权限
+func icmpDiscoverMTU(ctx context.Context, target string) (*types.MTUResult, error) {
+	opts := types.DefaultPingOptions()
+	opts.DontFragment = true
+	opts.Timeout = types.DiagnosticMTUTimeout
+
+	pinger, err := ping.NewICMPPinger(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer pinger.Close()
+	return pinger.DiscoverMTU(ctx, target, opts)
+}
+
+// checkPathMTU 探测到默认网关与公网主机的路径 MTU，检测静默丢弃大包的黑洞路由
+//
+// 路径 MTU 低于以太网标准 MTU 时返回 types.DiagStatusWarning 并附带修复建议；
+// 缺少 ICMP 权限或平台不支持 DF 位时跳过，返回 nil。
+func (s *Service) checkPathMTU(ctx context.Context) *types.CheckResult {
+	startTime := time.Now()
+
+	var gatewayResult, publicResult *types.MTUResult
+	details := map[string]interface{}{}
+
+	if gateway, err := s.getDefaultGateway(); err == nil {
+		result, err := s.discoverMTU(ctx, gateway)
+		if mtuUnavailable(err) {
+			logger.Info("跳过路径 MTU 检查", zap.Error(err))
+			return nil
+		}
+		details["gateway"] = gateway
+		if err != nil {
+			details["gateway_error"] = err.Error()
+		} else {
+			gatewayResult = result
+			details["gateway_mtu"] = result.PathMTU
+			details["local_mtu"] = result.LocalMTU
+		}
+	}
+
+	publicHost := types.DNSServerList()[0]
+	if h, _, err := net.SplitHostPort(publicHost); err == nil {
+		publicHost = h
+	}
+	result, err := s.discoverMTU(ctx, publicHost)
+	if mtuUnavailable(err) {
+		logger.Info("跳过路径 MTU 检查", zap.Error(err))
+		return nil
+	}
+	details["public"] = publicHost
+	if err != nil {
+		details["public_error"] = err.Error()
+	} else {
+		publicResult = result
+		details["public_mtu"] = result.PathMTU
+		details["local_mtu"] = result.LocalMTU
+	}
+
+	status, message, suggestion, mtu := classifyPathMTU(gatewayResult, publicResult)
+	if mtu > 0 {
+		details["path_mtu"] = mtu
+	}
+	return &types.CheckResult{
+		Name:       "路径 MTU 检查",
+		Category:   "连通性",
+		Status:     status,
+		Message:    message,
+		Duration:   time.Since(startTime),
+		Details:    details,
+		Suggestion: suggestion,
+	}
+}
+
+// mtuUnavailable 判断探测是否因权限或平台限制无法进行
+func mtuUnavailable(err error) bool {
+	var notSupported *errors.NotSupportedError
+	return errors.IsPermissionDenied(err) || stderrors.As(err, &notSupported)
+}
+
+// classifyPathMTU 根据网关与公网主机的探测结果判断路径 MTU 是否正常
+//
+// 有效路径 MTU 取已确定结果中的最小值；均无法确定（目标不响应 ICMP）时视为正常，
+// 连通性问题由其他检查报告。
+func classifyPathMTU(gateway, public *types.MTUResult) (status types.DiagnosticStatus, message, suggestion string, mtu int) {
+	hop := "公网"
+	for _, r := range []*types.MTUResult{public, gateway} {
+		if r == nil || !r.Determinate() {
+			continue
+		}
+		if mtu == 0 || r.PathMTU < mtu {
+			mtu = r.PathMTU
+			if r == gateway {
+				hop = "网关"
+			}
+		}
+	}
+
+	switch {
+	case mtu == 0:
+		return types.DiagStatusHealthy, "无法确定路径 MTU（目标不响应 ICMP）", "", 0
+	case mtu < types.StandardMTU:
+		message = fmt.Sprintf("到%s的路径 MTU 为 %d，低于 %d，大包可能被静默丢弃", hop, mtu, types.StandardMTU)
+		suggestion = fmt.Sprintf("路径中可能存在 PPPoE 或 VPN 隧道，将接口 MTU 调整为 %d 或在路由器上启用 TCP MSS clamping", mtu)
+		return types.DiagStatusWarning, message, suggestion, mtu
+	}
+	return types.DiagStatusHealthy, fmt.Sprintf("路径 MTU 正常（%d）", mtu), "", mtu
+}
@@ -0,0 +1,45 @@
+package diag
+
+import (
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyPathMTU(t *testing.T) {
+	mtu := func(n int) *types.MTUResult { return &types.MTUResult{PathMTU: n, LocalMTU: 1500} }
+
+	status, _, suggestion, got := classifyPathMTU(mtu(1500), mtu(1500))
+	require.Equal(t, types.DiagStatusHealthy, status)
+	require.Empty(t, suggestion)
+	require.Equal(t, 1500, got)
+
+	// 网关正常但公网路径存在 PPPoE 封装
+	status, message, suggestion, got := classifyPathMTU(mtu(1500), mtu(1492))
+	require.Equal(t, types.DiagStatusWarning, status)
+	require.Contains(t, message, "公网")
+	require.Contains(t, suggestion, "PPPoE")
+	require.Contains(t, suggestion, "1492")
+	require.Equal(t, 1492, got)
+
+	// 本地链路即为隧道
+	status, message, _, got = classifyPathMTU(mtu(1420), nil)
+	require.Equal(t, types.DiagStatusWarning, status)
+	require.Contains(t, message, "网关")
+	require.Equal(t, 1420, got)
+
+	// 目标不响应 ICMP 时无法判断
+	status, _, suggestion, got = classifyPathMTU(mtu(0), nil)
+	require.Equal(t, types.DiagStatusHealthy, status)
+	require.Empty(t, suggestion)
+	require.Zero(t, got)
+}
+
+func TestMTUUnavailable(t *testing.T) {
+	require.True(t, mtuUnavailable(errors.NewPermissionError("icmp ping", "raw socket", "")))
+	require.True(t, mtuUnavailable(errors.NewNotSupportedError("don't fragment", "darwin")))
+	require.False(t, mtuUnavailable(errors.ErrTimeout))
+	require.False(t, mtuUnavailable(nil))
+}
@@ -0,0 +1,140 @@
+package diag
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/http"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+const (
+	// latencySampleCount 完整诊断持续 Ping 的探测次数
+	latencySampleCount = 20
+	// latencySampleInterval 持续 Ping 的发送间隔
+	latencySampleInterval = 200 * time.Millisecond
+	// lossWarnRate 丢包率告警阈值（百分比）
+	lossWarnRate = 5.0
+	// jitterWarn 抖动告警阈值，超过时实时音视频通话质量明显下降
+	jitterWarn = 30 * time.Millisecond
+
+	// httpControlURL 测量 HTTP 首字节时间的控制地址，响应体很小且全球就近接入
+	httpControlURL = "https://www.cloudflare.com/cdn-cgi/trace"
+	// ttfbWarn HTTP 首字节时间告警阈值
+	ttfbWarn = time.Second
+)
+
+// checkLatencyQuality 持续 Ping 公共 DNS 服务器，采样丢包率与抖动
+func (s *Service) checkLatencyQuality(ctx context.Context) *types.CheckResult {
+	startTime := time.Now()
+
+	host := types.DNSServerList()[0]
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	pingOpts := types.DefaultPingOptions()
+	pingOpts.Count = latencySampleCount
+	pingOpts.Interval = latencySampleInterval
+	pingOpts.Timeout = types.DiagnosticGatewayTimeout
+	pingOpts.Protocol = types.ProtocolTCP
+	pingOpts.Port = types.DefaultDNSPort
+
+	check := &types.CheckResult{
+		Name:     "网络质量检查",
+		Category: "性能",
+		Details:  map[string]interface{}{"target": host, "probes": latencySampleCount},
+	}
+	result, err := s.pinger.Ping(ctx, host, pingOpts)
+	if err != nil || result.Statistics == nil {
+		check.Status = types.DiagStatusWarning
+		check.Message = fmt.Sprintf("无法采样到 %s 的延迟", host)
+		if err != nil {
+			check.Details["error"] = err.Error()
+		}
+		check.Duration = time.Since(startTime)
+		check.Suggestion = "检查防火墙是否拦截 ICMP/TCP 探测"
+		return check
+	}
+
+	stats := result.Statistics
+	check.Details["sent"] = stats.Sent
+	check.Details["received"] = stats.Received
+	check.Details["loss_rate"] = fmt.Sprintf("%.1f%%", stats.LossRate)
+	check.Details["min_rtt"] = stats.MinRTT.String()
+	check.Details["avg_rtt"] = stats.AvgRTT.String()
+	check.Details["max_rtt"] = stats.MaxRTT.String()
+	check.Details["p95_rtt"] = stats.P95RTT.String()
+	check.Details["jitter"] = stats.JitterRTT.String()
+
+	check.Status, check.Message, check.Suggestion = classifyLatency(stats)
+	check.Duration = time.Since(startTime)
+	return check
+}
+
+// classifyLatency 根据丢包率与抖动判断网络质量
+func classifyLatency(stats *types.Statistics) (types.DiagnosticStatus, string, string) {
+	switch {
+	case stats.Received == 0:
+		return types.DiagStatusCritical, fmt.Sprintf("%d 次探测全部丢失", stats.Sent),
+			"检查上游链路与 ISP 连接"
+	case stats.LossRate >= lossWarnRate:
+		return types.DiagStatusWarning, fmt.Sprintf("丢包率 %.1f%%，平均延迟 %s", stats.LossRate, stats.AvgRTT),
+			"检查无线信号强度、网线与上游链路拥塞"
+	case stats.JitterRTT >= jitterWarn:
+		return types.DiagStatusWarning, fmt.Sprintf("延迟抖动 %s，平均延迟 %s", stats.JitterRTT, stats.AvgRTT),
+			"抖动较大会影响语音与视频通话，检查是否有大流量下载或无线干扰"
+	}
+	return types.DiagStatusHealthy, fmt.Sprintf("丢包率 %.1f%%，平均延迟 %s，抖动 %s", stats.LossRate, stats.AvgRTT, stats.JitterRTT), ""
+}
+
+// checkHTTPLatency 请求控制地址，测量 DNS、建连、TLS 与首字节时间
+func (s *Service) checkHTTPLatency(ctx context.Context) *types.CheckResult {
+	startTime := time.Now()
+
+	client := http.NewClient(&types.HTTPOptions{
+		Timeout:        types.DiagnosticTargetTimeout * 2,
+		FollowRedirect: true,
+		MaxRedirects:   3,
+	})
+	defer client.Close()
+
+	check := &types.CheckResult{
+		Name:     "HTTP 响应检查",
+		Category: "性能",
+		Details:  map[string]interface{}{"url": httpControlURL},
+	}
+	result, err := client.Get(ctx, httpControlURL, nil)
+	if err != nil {
+		check.Status = types.DiagStatusWarning
+		check.Message = "HTTP 请求失败"
+		check.Details["error"] = err.Error()
+		check.Duration = time.Since(startTime)
+		check.Suggestion = "检查代理设置与 HTTPS 出站访问是否被拦截"
+		return check
+	}
+
+	check.Details["status_code"] = result.StatusCode
+	ttfb := result.Duration
+	if t := result.Timings; t != nil {
+		ttfb = t.TTFB
+		check.Details["dns"] = t.DNS.String()
+		check.Details["connect"] = t.Connect.String()
+		check.Details["tls"] = t.TLS.String()
+		check.Details["ttfb"] = t.TTFB.String()
+		check.Details["total"] = t.Total.String()
+	}
+
+	check.Duration = time.Since(startTime)
+	if ttfb >= ttfbWarn {
+		check.Status = types.DiagStatusWarning
+		check.Message = fmt.Sprintf("HTTP 首字节时间 %s，响应较慢", ttfb.Round(time.Millisecond))
+		check.Suggestion = "检查 DNS 解析耗时与代理设置，或链路是否拥塞"
+		return check
+	}
+	check.Status = types.DiagStatusHealthy
+	check.Message = fmt.Sprintf("HTTP 首字节时间 %s", ttfb.Round(time.Millisecond))
+	return check
+}
@@ -0,0 +1,26 @@
+package diag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyLatency(t *testing.T) {
+	status, _, suggestion := classifyLatency(&types.Statistics{Sent: 20, Received: 20, AvgRTT: 10 * time.Millisecond, JitterRTT: 2 * time.Millisecond})
+	require.Equal(t, types.DiagStatusHealthy, status)
+	require.Empty(t, suggestion)
+
+	status, message, _ := classifyLatency(&types.Statistics{Sent: 20, Received: 18, LossRate: 10})
+	require.Equal(t, types.DiagStatusWarning, status)
+	require.Contains(t, message, "丢包率 10.0%")
+
+	status, message, _ = classifyLatency(&types.Statistics{Sent: 20, Received: 20, JitterRTT: 45 * time.Millisecond})
+	require.Equal(t, types.DiagStatusWarning, status)
+	require.Contains(t, message, "抖动")
+
+	status, _, _ = classifyLatency(&types.Statistics{Sent: 20, LossRate: 100})
+	require.Equal(t, types.DiagStatusCritical, status)
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // checkTargetReachability 检查目标主机可达性
-func (s *Service) checkTargetReachability(ctx context.Context, target string) *CheckResult {
+func (s *Service) checkTargetReachability(ctx context.Context, target string) *types.CheckResult {
 	startTime := time.Now()
 
 	pingOpts := types.DefaultPingOptions()
@@ -18,10 +18,10 @@ func (s *Service) checkTargetReachability(ctx context.Context, target string) *C
 
 	result, err := s.pinger.Ping(ctx, target, pingOpts)
 	if err != nil || result.Statistics.Received == 0 {
-		return &CheckResult{
+		return &types.CheckResult{
 			Name:     fmt.Sprintf("目标主机检查 (%s)", target),
 			Category: "连通性",
-			Status:   StatusCritical,
+			Status:   types.DiagStatusCritical,
 			Message:  fmt.Sprintf("无法连接到 %s", target),
 			Duration: time.Since(startTime),
 		}
@@ -30,15 +30,15 @@ func (s *Service) checkTargetReachability(ctx context.Context, target string) *C
 	lossRate := result.Statistics.LossRate
 	avgRTT := result.Statistics.AvgRTT
 
-	status := StatusHealthy
+	status := types.DiagStatusHealthy
 	message := fmt.Sprintf("目标主机 %s 可达，延迟 %.2fms", target, float64(avgRTT.Microseconds())/1000)
 
 	if lossRate > 20 {
-		status = StatusWarning
+		status = types.DiagStatusWarning
 		message = fmt.Sprintf("目标主机 %s 可达但丢包率较高 (%.1f%%)", target, lossRate)
 	}
 
-	return &CheckResult{
+	return &types.CheckResult{
 		Name:     fmt.Sprintf("目标主机检查 (%s)", target),
 		Category: "连通性",
 		Status:   status,
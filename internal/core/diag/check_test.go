@@ -0,0 +1,91 @@
+package diag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCheck 返回固定结果并记录执行次数
+type fakeCheck struct {
+	name   string
+	status types.DiagnosticStatus
+	runs   int
+}
+
+func (c *fakeCheck) Name() string     { return c.name }
+func (c *fakeCheck) Category() string { return "测试" }
+
+func (c *fakeCheck) Run(context.Context) *types.CheckResult {
+	c.runs++
+	return &types.CheckResult{Name: c.name, Status: c.status, Message: c.name + " failed", Suggestion: "fix " + c.name}
+}
+
+func checkNames(checks []Check) []string {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name())
+	}
+	return names
+}
+
+func TestSelectChecksByLevel(t *testing.T) {
+	s := &Service{}
+	s.registerBuiltinChecks()
+
+	fast, err := s.SelectChecks(DiagnosticOptions{Level: DiagLevelFast})
+	require.NoError(t, err)
+	require.Equal(t, []string{"interfaces", "gateway", "dns"}, checkNames(fast))
+
+	normal, err := s.SelectChecks(DiagnosticOptions{Level: DiagLevelNormal})
+	require.NoError(t, err)
+	require.Equal(t, []string{"interfaces", "gateway", "internet", "captive-portal", "mtu", "dns", "dns-consistency"}, checkNames(normal))
+
+	full, err := s.SelectChecks(DiagnosticOptions{Level: DiagLevelFull})
+	require.NoError(t, err)
+	require.Equal(t, append(checkNames(normal), "latency", "http"), checkNames(full))
+}
+
+func TestSelectChecksOnlySkip(t *testing.T) {
+	s := &Service{}
+	s.registerBuiltinChecks()
+
+	// --only 忽略诊断级别
+	only, err := s.SelectChecks(DiagnosticOptions{Level: DiagLevelFast, Only: []string{"latency", "DNS"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dns", "latency"}, checkNames(only))
+
+	skipped, err := s.SelectChecks(DiagnosticOptions{Level: DiagLevelFast, Skip: []string{"gateway"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"interfaces", "dns"}, checkNames(skipped))
+
+	_, err = s.SelectChecks(DiagnosticOptions{Only: []string{"dnssec"}})
+	require.ErrorContains(t, err, `"dnssec"`)
+
+	require.Error(t, s.Register(&fakeCheck{name: "dns"}))
+}
+
+func TestDiagnoseRunsRegisteredCheck(t *testing.T) {
+	healthy := &fakeCheck{name: "ok", status: types.DiagStatusHealthy}
+	failing := &fakeCheck{name: "broken", status: types.DiagStatusCritical}
+
+	s := &Service{}
+	require.NoError(t, s.Register(healthy, failing))
+
+	result, err := s.Diagnose(context.Background(), DiagnosticOptions{Level: DiagLevelFast})
+	require.NoError(t, err)
+	require.Equal(t, 1, healthy.runs)
+	require.Equal(t, 1, failing.runs)
+	require.Len(t, result.Checks, 2)
+	require.Equal(t, types.DiagStatusCritical, result.Status)
+	require.Len(t, result.Issues, 1)
+	require.Equal(t, "测试", result.Issues[0].Category)
+	require.Equal(t, "fix broken", result.Issues[0].Suggestion)
+
+	result, err = s.Diagnose(context.Background(), DiagnosticOptions{Skip: []string{"broken"}})
+	require.NoError(t, err)
+	require.Equal(t, types.DiagStatusHealthy, result.Status)
+	require.Equal(t, 1, failing.runs)
+}
@@ -22,7 +22,6 @@ package diag
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/catsayer/ntx/internal/core/dns"
@@ -49,63 +48,27 @@ const (
 type DiagnosticOptions struct {
 	Level  DiagnosticLevel
 	Target string // 可选的目标主机
-}
-
-// DiagnosticResult 诊断结果
-type DiagnosticResult struct {
-	Timestamp   time.Time
-	Duration    time.Duration
-	Status      DiagnosticStatus
-	Checks      []*CheckResult
-	Issues      []*Issue
-	Suggestions []string
-}
-
-// DiagnosticStatus 诊断状态
-type DiagnosticStatus int
-
-const (
-	StatusHealthy DiagnosticStatus = iota
-	StatusWarning
-	StatusCritical
-)
-
-func (s DiagnosticStatus) String() string {
-	switch s {
-	case StatusHealthy:
-		return "HEALTHY"
-	case StatusWarning:
-		return "WARNING"
-	case StatusCritical:
-		return "CRITICAL"
-	default:
-		return "UNKNOWN"
-	}
-}
-
-// CheckResult 单项检查结果
-type CheckResult struct {
-	Name     string
-	Category string
-	Status   DiagnosticStatus
-	Message  string
-	Duration time.Duration
-	Details  map[string]interface{}
-}
-
-// Issue 发现的问题
-type Issue struct {
-	Severity    DiagnosticStatus
-	Category    string
-	Description string
-	Suggestion  string
+	// Only 只执行列出的检查项（按名称），忽略诊断级别
+	Only []string
+	// Skip 跳过列出的检查项
+	Skip []string
 }
 
 // Service 诊断服务
 type Service struct {
 	pinger   types.Pinger
-	resolver *dns.Resolver
 	ifReader *iface.InterfaceReader
+
+	// systemLookup 用于解析检查与一致性比对，publicLookup 仅用于一致性比对
+	systemLookup dnsLookup
+	publicLookup dnsLookup
+	// discoverMTU 用于路径 MTU 检查
+	discoverMTU mtuDiscoverFunc
+	// captiveURL 强制门户检测的控制地址
+	captiveURL string
+
+	// checks 按顺序执行的检查项
+	checks []Check
 }
 
 // NewService 创建新的诊断服务
@@ -127,10 +90,23 @@ func NewService() *Service {
 		Timeout: types.DefaultDNSTimeout,
 	}
 
-	return &Service{
-		pinger:   pinger,
-		resolver: dns.NewResolver(dnsOpts),
-		ifReader: iface.NewInterfaceReader(),
+	resolver := dns.NewResolver(dnsOpts)
+	s := &Service{
+		pinger:       pinger,
+		ifReader:     iface.NewInterfaceReader(),
+		systemLookup: systemDNSLookup,
+		publicLookup: resolverLookup(resolver),
+		discoverMTU:  icmpDiscoverMTU,
+		captiveURL:   types.DefaultCaptivePortalURL,
+	}
+	s.registerBuiltinChecks()
+	return s
+}
+
+// SetCaptivePortalURL 设置强制门户检测的控制地址，url 为空时保持默认
+func (s *Service) SetCaptivePortalURL(url string) {
+	if url != "" {
+		s.captiveURL = url
 	}
 }
 
@@ -143,77 +119,48 @@ func NewService() *Service {
 //
 // 返回:
 //
-//	*DiagnosticResult: 诊断结果
+//	*types.DiagnosticResult: 诊断结果
 //	error: 错误信息
-func (s *Service) Diagnose(ctx context.Context, opts DiagnosticOptions) (*DiagnosticResult, error) {
+func (s *Service) Diagnose(ctx context.Context, opts DiagnosticOptions) (*types.DiagnosticResult, error) {
 	startTime := time.Now()
 
 	logger.Info("开始网络诊断", zap.Int("level", int(opts.Level)))
 
-	result := &DiagnosticResult{
-		Timestamp:   startTime,
-		Checks:      make([]*CheckResult, 0),
-		Issues:      make([]*Issue, 0),
-		Suggestions: make([]string, 0),
+	checks, err := s.SelectChecks(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// 1. 检查网络接口配置
-	if check := s.checkNetworkInterfaces(ctx); check != nil {
-		result.Checks = append(result.Checks, check)
-		if check.Status != StatusHealthy {
-			result.Issues = append(result.Issues, &Issue{
-				Severity:    check.Status,
-				Category:    "网络配置",
-				Description: check.Message,
-				Suggestion:  "检查网络接口配置和状态",
-			})
-		}
+	result := &types.DiagnosticResult{
+		Timestamp:   startTime,
+		Checks:      make([]*types.CheckResult, 0),
+		Issues:      make([]*types.Issue, 0),
+		Suggestions: make([]string, 0),
 	}
 
-	// 2. 检查本地连通性（网关）
-	if check := s.checkLocalConnectivity(ctx); check != nil {
-		result.Checks = append(result.Checks, check)
-		if check.Status != StatusHealthy {
-			result.Issues = append(result.Issues, &Issue{
-				Severity:    check.Status,
-				Category:    "本地连通性",
-				Description: check.Message,
-				Suggestion:  "检查网关配置和本地网络连接",
-			})
+	for _, check := range checks {
+		if ctx.Err() != nil {
+			break
 		}
-	}
-
-	// 3. 检查公网连通性
-	if check := s.checkInternetConnectivity(ctx); check != nil {
-		result.Checks = append(result.Checks, check)
-		if check.Status != StatusHealthy {
-			result.Issues = append(result.Issues, &Issue{
-				Severity:    check.Status,
-				Category:    "互联网连通性",
-				Description: check.Message,
-				Suggestion:  "检查路由器配置和 ISP 连接",
-			})
+		logger.Debug("执行诊断检查", zap.String("check", check.Name()))
+		checkResult := check.Run(ctx)
+		if checkResult == nil {
+			continue
 		}
-	}
-
-	// 4. 检查 DNS 解析
-	if check := s.checkDNSResolution(ctx); check != nil {
-		result.Checks = append(result.Checks, check)
-		if check.Status != StatusHealthy {
-			result.Issues = append(result.Issues, &Issue{
-				Severity:    check.Status,
-				Category:    "DNS 解析",
-				Description: check.Message,
-				Suggestion:  fmt.Sprintf("检查 DNS 服务器配置，尝试使用公共 DNS（如 %s）", types.DefaultDNSServer),
+		result.Checks = append(result.Checks, checkResult)
+		if checkResult.Status != types.DiagStatusHealthy {
+			result.Issues = append(result.Issues, &types.Issue{
+				Severity:    checkResult.Status,
+				Category:    check.Category(),
+				Description: checkResult.Message,
+				Suggestion:  checkResult.Suggestion,
 			})
 		}
 	}
 
-	// 5. 如果指定了目标，进行额外测试
-	if opts.Target != "" {
-		if check := s.checkTargetReachability(ctx, opts.Target); check != nil {
-			result.Checks = append(result.Checks, check)
-		}
+	// 目标可达性仅作参考，不生成 Issue
+	if opts.Target != "" && opts.Level >= DiagLevelNormal && ctx.Err() == nil {
+		result.Checks = append(result.Checks, s.checkTargetReachability(ctx, opts.Target))
 	}
 
 	// 计算整体状态
@@ -233,32 +180,32 @@ func (s *Service) Diagnose(ctx context.Context, opts DiagnosticOptions) (*Diagno
 }
 
 // calculateOverallStatus 计算整体状态
-func (s *Service) calculateOverallStatus(checks []*CheckResult) DiagnosticStatus {
+func (s *Service) calculateOverallStatus(checks []*types.CheckResult) types.DiagnosticStatus {
 	hasCritical := false
 	hasWarning := false
 
 	for _, check := range checks {
-		if check.Status == StatusCritical {
+		if check.Status == types.DiagStatusCritical {
 			hasCritical = true
-		} else if check.Status == StatusWarning {
+		} else if check.Status == types.DiagStatusWarning {
 			hasWarning = true
 		}
 	}
 
 	if hasCritical {
-		return StatusCritical
+		return types.DiagStatusCritical
 	}
 	if hasWarning {
-		return StatusWarning
+		return types.DiagStatusWarning
 	}
-	return StatusHealthy
+	return types.DiagStatusHealthy
 }
 
 // generateSuggestions 生成诊断建议
-func (s *Service) generateSuggestions(result *DiagnosticResult) []string {
+func (s *Service) generateSuggestions(result *types.DiagnosticResult) []string {
 	suggestions := make([]string, 0)
 
-	if result.Status == StatusHealthy {
+	if result.Status == types.DiagStatusHealthy {
 		suggestions = append(suggestions, "网络配置正常，所有检查通过")
 		return suggestions
 	}
@@ -0,0 +1,45 @@
+package diag
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticResultJSON(t *testing.T) {
+	result := &types.DiagnosticResult{
+		Timestamp: time.Unix(0, 0).UTC(),
+		Duration:  time.Second,
+		Status:    types.DiagStatusWarning,
+		Checks: []*types.CheckResult{
+			{
+				Name:     "默认网关检查",
+				Category: "连通性",
+				Status:   types.DiagStatusHealthy,
+				Message:  "网关可达",
+				Details:  map[string]interface{}{"gateway": "192.168.1.1"},
+			},
+		},
+		Issues: []*types.Issue{
+			{Severity: types.DiagStatusCritical, Category: "DNS", Description: "DNS 解析失败"},
+		},
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	out := string(data)
+	require.True(t, strings.Contains(out, `"status":"WARNING"`), out)
+	require.True(t, strings.Contains(out, `"status":"HEALTHY"`), out)
+	require.True(t, strings.Contains(out, `"severity":"CRITICAL"`), out)
+	require.True(t, strings.Contains(out, `"details":{"gateway":"192.168.1.1"}`), out)
+
+	var decoded types.DiagnosticResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, types.DiagStatusWarning, decoded.Status)
+	require.Equal(t, types.DiagStatusCritical, decoded.Issues[0].Severity)
+}
@@ -19,9 +19,11 @@ package dns
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/catsayer/ntx/pkg/errors"
@@ -51,6 +53,7 @@ func NewResolver(opts *types.DNSOptions) *Resolver {
 	if opts.Timeout == 0 {
 		opts.Timeout = types.DefaultDNSTimeout
 	}
+	opts.FallbackServers = fallbackServers(opts.Server, opts.FallbackServers)
 
 	return &Resolver{
 		options: opts,
@@ -78,28 +81,46 @@ func (r *Resolver) Query(ctx context.Context, domain string, recordType types.DN
 	msg := new(dns.Msg)
 	msg.SetQuestion(domain, uint16(recordType))
 	msg.RecursionDesired = true
-
-	// 执行查询
-	response, rtt, err := r.client.ExchangeContext(ctx, msg, r.options.Server)
-	if err != nil {
-		return nil, fmt.Errorf("DNS 查询失败: %w", err)
+	if r.options.DNSSEC {
+		msg.SetEdns0(4096, true)
 	}
 
-	// 检查响应状态
-	if response.Rcode != dns.RcodeSuccess {
-		return nil, fmt.Errorf("DNS 查询失败: %s", dns.RcodeToString[response.Rcode])
+	// 执行查询，主服务器失败时依次尝试备用服务器
+	response, rtt, server, err := r.exchange(ctx, msg)
+	bogus := false
+	if err != nil && r.options.DNSSEC && stderrors.Is(err, errServFail) {
+		// 验证型解析器对签名验证失败的数据返回 SERVFAIL，关闭验证 (CD) 后能得到应答即为 BOGUS
+		cd := msg.Copy()
+		cd.CheckingDisabled = true
+		if cdResponse, cdRTT, cdServer, cdErr := r.exchange(ctx, cd); cdErr == nil {
+			response, rtt, server, err = cdResponse, cdRTT, cdServer, nil
+			bogus = true
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	// 解析响应
 	result := &types.DNSResult{
 		Domain:     strings.TrimSuffix(domain, "."),
 		RecordType: recordType,
-		Server:     r.options.Server,
+		Server:     server,
 		QueryTime:  rtt,
 		StartTime:  startTime,
 		EndTime:    time.Now(),
 		Records:    make([]*types.DNSRecord, 0),
 	}
+	if r.options.DNSSEC {
+		result.Authenticated = response.AuthenticatedData && !bogus
+		result.Bogus = bogus
+		for _, rr := range response.Answer {
+			if _, ok := rr.(*dns.RRSIG); ok {
+				result.Signed = true
+				break
+			}
+		}
+	}
 
 	// 解析 Answer 部分
 	for _, rr := range response.Answer {
@@ -128,6 +149,49 @@ func (r *Resolver) Query(ctx context.Context, domain string, recordType types.DN
 	return result, nil
 }
 
+// errServFail 所有服务器均返回 SERVFAIL
+var errServFail = stderrors.New(dns.RcodeToString[dns.RcodeServerFailure])
+
+// exchange 向主服务器发送查询，超时等网络错误或 SERVFAIL 时依次改用备用服务器，
+// 返回应答及实际应答的服务器
+func (r *Resolver) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, string, error) {
+	servers := append([]string{r.options.Server}, r.options.FallbackServers...)
+
+	var lastErr error
+	for _, server := range servers {
+		response, rtt, err := r.client.ExchangeContext(ctx, msg, server)
+		switch {
+		case err != nil:
+			lastErr = fmt.Errorf("DNS 查询失败: %w", err)
+			if ctx.Err() != nil {
+				return nil, 0, "", lastErr
+			}
+		case response.Rcode == dns.RcodeServerFailure:
+			lastErr = fmt.Errorf("DNS 查询失败: %w", errServFail)
+		case response.Rcode != dns.RcodeSuccess:
+			return nil, 0, "", fmt.Errorf("DNS 查询失败: %s", dns.RcodeToString[response.Rcode])
+		default:
+			return response, rtt, server, nil
+		}
+	}
+	return nil, 0, "", lastErr
+}
+
+// fallbackServers 规范化备用服务器列表，去除空项、重复项及与主服务器相同的项
+func fallbackServers(primary string, servers []string) []string {
+	seen := map[string]bool{primary: true}
+	var out []string
+	for _, server := range servers {
+		server = types.FormatDNSServer(strings.TrimSpace(server))
+		if server == "" || seen[server] {
+			continue
+		}
+		seen[server] = true
+		out = append(out, server)
+	}
+	return out
+}
+
 // QueryAll 查询所有常见记录类型
 func (r *Resolver) QueryAll(ctx context.Context, domain string) (map[types.DNSRecordType]*types.DNSResult, error) {
 	recordTypes := []types.DNSRecordType{
@@ -142,10 +206,9 @@ func (r *Resolver) QueryAll(ctx context.Context, domain string) (map[types.DNSRe
 
 	results := make(map[types.DNSRecordType]*types.DNSResult)
 
-	for _, recordType := range recordTypes {
-		result, err := r.Query(ctx, domain, recordType)
-		if err == nil && len(result.Records) > 0 {
-			results[recordType] = result
+	for _, result := range r.QueryTypes(ctx, domain, recordTypes) {
+		if result.Error == nil && len(result.Records) > 0 {
+			results[result.RecordType] = result
 		}
 	}
 
@@ -156,6 +219,33 @@ func (r *Resolver) QueryAll(ctx context.Context, domain string) (map[types.DNSRe
 	return results, nil
 }
 
+// QueryTypes 并发查询同一域名的多种记录类型，结果顺序与 recordTypes 一致，
+// 单个类型失败时记录在对应结果的 Error 中
+func (r *Resolver) QueryTypes(ctx context.Context, domain string, recordTypes []types.DNSRecordType) []*types.DNSResult {
+	results := make([]*types.DNSResult, len(recordTypes))
+
+	var wg sync.WaitGroup
+	for i, recordType := range recordTypes {
+		wg.Add(1)
+		go func(i int, recordType types.DNSRecordType) {
+			defer wg.Done()
+			result, err := r.Query(ctx, domain, recordType)
+			if err != nil {
+				result = &types.DNSResult{
+					Domain:     strings.TrimSuffix(domain, "."),
+					RecordType: recordType,
+					Server:     r.options.Server,
+					Error:      err,
+				}
+			}
+			results[i] = result
+		}(i, recordType)
+	}
+	wg.Wait()
+
+	return results
+}
+
 // QueryBatch 批量查询多个域名
 func (r *Resolver) QueryBatch(ctx context.Context, domains []string, recordType types.DNSRecordType) ([]*types.DNSResult, error) {
 	results := make([]*types.DNSResult, 0, len(domains))
@@ -235,6 +325,19 @@ func (r *Resolver) parseRecord(rr dns.RR) *types.DNSRecord {
 	case *dns.SRV:
 		record.Value = fmt.Sprintf("%d %d %d %s",
 			v.Priority, v.Weight, v.Port, strings.TrimSuffix(v.Target, "."))
+	case *dns.NAPTR:
+		record.Value = fmt.Sprintf("%d %d %q %q %q %s",
+			v.Order, v.Preference, v.Flags, v.Service, v.Regexp, fqdnOrRoot(v.Replacement))
+	case *dns.CAA:
+		record.Value = fmt.Sprintf("%d %s %q", v.Flag, v.Tag, v.Value)
+	case *dns.SVCB:
+		record.Value = formatSVCB(v)
+	case *dns.HTTPS:
+		record.Value = formatSVCB(&v.SVCB)
+	case *dns.DNSKEY:
+		record.Value = fmt.Sprintf("%d %d %d %s", v.Flags, v.Protocol, v.Algorithm, v.PublicKey)
+	case *dns.DS:
+		record.Value = fmt.Sprintf("%d %d %d %s", v.KeyTag, v.Algorithm, v.DigestType, strings.ToUpper(v.Digest))
 	default:
 		record.Value = strings.TrimSpace(strings.TrimPrefix(rr.String(), header.String()))
 	}
@@ -242,6 +345,23 @@ func (r *Resolver) parseRecord(rr dns.RR) *types.DNSRecord {
 	return record
 }
 
+// formatSVCB 格式化 SVCB/HTTPS 记录: 优先级 目标 参数...
+func formatSVCB(v *dns.SVCB) string {
+	parts := []string{fmt.Sprintf("%d", v.Priority), fqdnOrRoot(v.Target)}
+	for _, kv := range v.Value {
+		parts = append(parts, fmt.Sprintf("%s=%s", kv.Key(), kv.String()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fqdnOrRoot 去除末尾的点，根域名保留为 "."
+func fqdnOrRoot(name string) string {
+	if name == "." || name == "" {
+		return "."
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
 // Close 关闭解析器 (当前无需实际操作)
 func (r *Resolver) Close() error {
 	return nil
@@ -0,0 +1,155 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// startDNSServer 启动本地 UDP DNS 服务器并返回其地址
+func startDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: pc, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+func TestParseRecordModernTypes(t *testing.T) {
+	r := NewResolver(nil)
+
+	cases := map[string]string{
+		`example.com. 300 IN CAA 0 issue "letsencrypt.org"`:                         `0 issue "letsencrypt.org"`,
+		`example.com. 300 IN HTTPS 1 . alpn="h2,h3" ipv4hint="192.0.2.1"`:           `1 . alpn=h2,h3 ipv4hint=192.0.2.1`,
+		`_svc.example.com. 300 IN SVCB 0 svc.example.net.`:                          `0 svc.example.net`,
+		`example.com. 300 IN NAPTR 100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`:  `100 10 "S" "SIP+D2U" "" _sip._udp.example.com`,
+		`example.com. 300 IN DNSKEY 257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d`:      `257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d`,
+		`example.com. 300 IN DS 2371 13 2 c988ec423e3880eb8dd8a46fe06ca230ee23f35b`: `2371 13 2 C988EC423E3880EB8DD8A46FE06CA230EE23F35B`,
+	}
+	for text, want := range cases {
+		rr, err := dns.NewRR(text)
+		require.NoError(t, err, text)
+		record := r.parseRecord(rr)
+		require.Equal(t, want, record.Value, text)
+	}
+}
+
+func TestQueryTypesKeepsOrderAndPerTypeErrors(t *testing.T) {
+	addr := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		switch req.Question[0].Qtype {
+		case dns.TypeA:
+			rr, _ := dns.NewRR("example.com. 60 IN A 192.0.2.1")
+			resp.Answer = append(resp.Answer, rr)
+		case dns.TypeMX:
+			resp.Rcode = dns.RcodeRefused
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	r := NewResolver(&types.DNSOptions{Server: addr, Timeout: time.Second})
+	results := r.QueryTypes(context.Background(), "example.com", []types.DNSRecordType{types.DNSTypeMX, types.DNSTypeA, types.DNSTypeAAAA})
+
+	require.Len(t, results, 3)
+	require.Equal(t, types.DNSTypeMX, results[0].RecordType)
+	require.ErrorContains(t, results[0].Error, "REFUSED")
+	require.NoError(t, results[1].Error)
+	require.Len(t, results[1].Records, 1)
+	require.Equal(t, "192.0.2.1", results[1].Records[0].Value)
+	require.NoError(t, results[2].Error)
+	require.Empty(t, results[2].Records)
+}
+
+func TestQueryFallsBackOnDeadPrimary(t *testing.T) {
+	// 只接收不应答的主服务器
+	dead, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer dead.Close()
+
+	servfail := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetRcode(req, dns.RcodeServerFailure)
+		_ = w.WriteMsg(resp)
+	})
+	fallback := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		rr, _ := dns.NewRR("example.com. 60 IN A 192.0.2.7")
+		resp.Answer = append(resp.Answer, rr)
+		_ = w.WriteMsg(resp)
+	})
+
+	r := NewResolver(&types.DNSOptions{
+		Server:          dead.LocalAddr().String(),
+		Timeout:         200 * time.Millisecond,
+		FallbackServers: []string{servfail, fallback},
+	})
+	result, err := r.Query(context.Background(), "example.com", types.DNSTypeA)
+	require.NoError(t, err)
+	require.Equal(t, fallback, result.Server)
+	require.Len(t, result.Records, 1)
+	require.Equal(t, "192.0.2.7", result.Records[0].Value)
+
+	// 所有服务器均失败时返回最后一个错误
+	r = NewResolver(&types.DNSOptions{Server: dead.LocalAddr().String(), Timeout: 200 * time.Millisecond, FallbackServers: []string{servfail}})
+	_, err = r.Query(context.Background(), "example.com", types.DNSTypeA)
+	require.ErrorContains(t, err, "SERVFAIL")
+}
+
+func TestQueryReportsDNSSECStatus(t *testing.T) {
+	addr := startDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		opt := req.IsEdns0()
+		switch {
+		case req.Question[0].Name == "bogus.example.":
+			// 签名验证失败：仅在关闭验证 (CD) 时应答
+			if !req.CheckingDisabled {
+				resp.Rcode = dns.RcodeServerFailure
+				break
+			}
+			rr, _ := dns.NewRR("bogus.example. 60 IN A 192.0.2.9")
+			resp.Answer = append(resp.Answer, rr)
+		case opt != nil && opt.Do():
+			rr, _ := dns.NewRR("example.com. 60 IN A 192.0.2.1")
+			sig, _ := dns.NewRR("example.com. 60 IN RRSIG A 13 2 60 20300101000000 20200101000000 12345 example.com. c2ln")
+			resp.Answer = append(resp.Answer, rr, sig)
+			resp.AuthenticatedData = true
+		default:
+			rr, _ := dns.NewRR("example.com. 60 IN A 192.0.2.1")
+			resp.Answer = append(resp.Answer, rr)
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	plain := NewResolver(&types.DNSOptions{Server: addr, Timeout: time.Second})
+	result, err := plain.Query(context.Background(), "example.com", types.DNSTypeA)
+	require.NoError(t, err)
+	require.False(t, result.Authenticated)
+	require.Equal(t, types.DNSSECUnsigned, result.DNSSECStatus())
+
+	r := NewResolver(&types.DNSOptions{Server: addr, Timeout: time.Second, DNSSEC: true})
+	result, err = r.Query(context.Background(), "example.com", types.DNSTypeA)
+	require.NoError(t, err)
+	require.True(t, result.Authenticated)
+	require.True(t, result.Signed)
+	require.Equal(t, types.DNSSECAuthenticated, result.DNSSECStatus())
+
+	result, err = r.Query(context.Background(), "bogus.example", types.DNSTypeA)
+	require.NoError(t, err)
+	require.True(t, result.Bogus)
+	require.Equal(t, types.DNSSECBogus, result.DNSSECStatus())
+	require.Equal(t, "192.0.2.9", result.Records[0].Value)
+
+	// 未开启 DNSSEC 时 SERVFAIL 照常报错
+	_, err = plain.Query(context.Background(), "bogus.example", types.DNSTypeA)
+	require.ErrorContains(t, err, "SERVFAIL")
+}
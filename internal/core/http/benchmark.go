@@ -0,0 +1,153 @@
+package http
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/stats"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// benchSample 单次性能测试请求的结果
+type benchSample struct {
+	done       bool
+	failed     bool
+	statusCode int
+	duration   time.Duration
+}
+
+// Benchmark 执行性能测试
+//
+// concurrency 个 worker 共同完成 count 次请求。ctx 取消后不再发起新请求，
+// 返回已完成请求的统计并将 Interrupted 置为 true；被取消的在途请求不计入统计。
+func (c *Client) Benchmark(ctx context.Context, method, url string, body []byte, headers map[string]string, count, concurrency int) (*types.HTTPBenchmarkResult, error) {
+	if count <= 0 {
+		count = 1
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > count {
+		concurrency = count
+	}
+
+	tmpl, err := newRequestTemplate(url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	samples := make([]benchSample, count)
+	var (
+		next      atomic.Int64
+		errOnce   sync.Once
+		renderErr error
+		wg        sync.WaitGroup
+	)
+
+	startTime := time.Now()
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1)) - 1
+				if i >= count {
+					return
+				}
+
+				reqURL, reqBody := url, body
+				if tmpl != nil {
+					var err error
+					reqURL, reqBody, err = tmpl.render(i+1, url, body)
+					if err != nil {
+						errOnce.Do(func() {
+							renderErr = err
+							cancel()
+						})
+						return
+					}
+				}
+
+				result, err := c.Request(ctx, method, reqURL, reqBody, headers)
+				if err != nil {
+					if ctx.Err() != nil {
+						// 中断导致的失败不代表服务端表现
+						return
+					}
+					samples[i] = benchSample{done: true, failed: true}
+					continue
+				}
+				samples[i] = benchSample{done: true, statusCode: result.StatusCode, duration: result.Duration}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if renderErr != nil {
+		return nil, renderErr
+	}
+
+	elapsed := time.Since(startTime)
+	benchResult := summarizeBenchmark(samples)
+	benchResult.Method = method
+	benchResult.URL = url
+	benchResult.Concurrency = concurrency
+	benchResult.TotalDuration = elapsed
+	benchResult.RequestsPerSec = float64(benchResult.TotalRequests) / elapsed.Seconds()
+	benchResult.Interrupted = benchResult.TotalRequests < count
+
+	return benchResult, nil
+}
+
+// summarizeBenchmark 汇总已完成请求的计数、耗时分布与状态码分布
+//
+// 耗时统计仅包含收到响应的请求。
+func summarizeBenchmark(samples []benchSample) *types.HTTPBenchmarkResult {
+	result := &types.HTTPBenchmarkResult{
+		StatusCodes: make(map[int]int),
+	}
+
+	durations := make([]time.Duration, 0, len(samples))
+	var totalDuration time.Duration
+	for _, sample := range samples {
+		if !sample.done {
+			continue
+		}
+		result.TotalRequests++
+		if sample.failed {
+			result.FailureCount++
+			continue
+		}
+
+		result.StatusCodes[sample.statusCode]++
+		if sample.statusCode >= 200 && sample.statusCode < 300 {
+			result.SuccessCount++
+		} else {
+			result.FailureCount++
+		}
+		durations = append(durations, sample.duration)
+		totalDuration += sample.duration
+	}
+
+	if len(durations) == 0 {
+		return result
+	}
+
+	result.Histogram = stats.BuildHistogram(durations, stats.DefaultHistogramBuckets)
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	result.MinDuration = durations[0]
+	result.MaxDuration = durations[len(durations)-1]
+	result.AvgDuration = totalDuration / time.Duration(len(durations))
+	result.P50Duration = stats.Percentile(durations, 50)
+	result.P90Duration = stats.Percentile(durations, 90)
+	result.P99Duration = stats.Percentile(durations, 99)
+	return result
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startSlowServer 每个请求延迟 delay 后响应
+func startSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBenchmarkConcurrent(t *testing.T) {
+	server := startSlowServer(t, 50*time.Millisecond)
+	client := NewClient(nil)
+
+	serial, err := client.Benchmark(context.Background(), "GET", server.URL, nil, nil, 8, 1)
+	require.NoError(t, err)
+	concurrent, err := client.Benchmark(context.Background(), "GET", server.URL, nil, nil, 8, 8)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, serial.Concurrency)
+	require.Equal(t, 8, concurrent.Concurrency)
+	require.Less(t, concurrent.TotalDuration, serial.TotalDuration/2)
+
+	require.Equal(t, 8, concurrent.TotalRequests)
+	require.Equal(t, 8, concurrent.SuccessCount)
+	require.False(t, concurrent.Interrupted)
+	require.Equal(t, map[int]int{http.StatusOK: 8}, concurrent.StatusCodes)
+	require.GreaterOrEqual(t, concurrent.P50Duration, 50*time.Millisecond)
+	require.GreaterOrEqual(t, concurrent.P90Duration, concurrent.P50Duration)
+	require.GreaterOrEqual(t, concurrent.P99Duration, concurrent.P90Duration)
+	require.LessOrEqual(t, concurrent.P99Duration, concurrent.MaxDuration)
+	require.NotEmpty(t, concurrent.Histogram)
+}
+
+func TestBenchmarkStatusCodes(t *testing.T) {
+	// 序号为偶数的请求返回 404
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seq, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		if seq%2 == 0 {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	url := server.URL + "/{{.i}}"
+	result, err := NewClient(nil).Benchmark(context.Background(), "GET", url, nil, nil, 10, 3)
+	require.NoError(t, err)
+	require.Equal(t, 10, result.TotalRequests)
+	require.Equal(t, 5, result.SuccessCount)
+	require.Equal(t, 5, result.FailureCount)
+	require.Equal(t, map[int]int{http.StatusOK: 5, http.StatusNotFound: 5}, result.StatusCodes)
+}
+
+func TestBenchmarkCanceledReturnsPartialStats(t *testing.T) {
+	server := startSlowServer(t, 40*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	result, err := NewClient(nil).Benchmark(ctx, "GET", server.URL, nil, nil, 1000, 2)
+	require.NoError(t, err)
+	require.True(t, result.Interrupted)
+	require.Greater(t, result.TotalRequests, 0)
+	require.Less(t, result.TotalRequests, 1000)
+	require.Zero(t, result.FailureCount)
+	require.Equal(t, result.TotalRequests, result.StatusCodes[http.StatusOK])
+}
+
+func TestBenchmarkTemplateError(t *testing.T) {
+	server := startSlowServer(t, 0)
+	_, err := NewClient(nil).Benchmark(context.Background(), "GET", server.URL+"/{{.missing}}", nil, nil, 5, 2)
+	require.Error(t, err)
+}
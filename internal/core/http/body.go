@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// Body 请求体及其 Content-Type
+type Body struct {
+	// Data 请求体内容
+	Data []byte
+	// ContentType 请求体类型，为空时不设置 Content-Type
+	ContentType string
+}
+
+// NewFormBody 创建 application/x-www-form-urlencoded 请求体，字段按名称排序
+func NewFormBody(fields map[string]string) *Body {
+	values := make(url.Values, len(fields))
+	for k, v := range fields {
+		values.Set(k, v)
+	}
+	return &Body{
+		Data:        []byte(values.Encode()),
+		ContentType: "application/x-www-form-urlencoded",
+	}
+}
+
+// NewMultipartBody 创建 multipart/form-data 请求体
+//
+// fields 为普通字段，files 为 字段名 -> 本地文件路径；字段按名称排序写入。
+func NewMultipartBody(fields map[string]string, files map[string]string) (*Body, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, name := range sortedKeys(fields) {
+		if err := w.WriteField(name, fields[name]); err != nil {
+			return nil, fmt.Errorf("写入字段 %s 失败: %w", name, err)
+		}
+	}
+	for _, name := range sortedKeys(files) {
+		if err := writeFilePart(w, name, files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("生成 multipart 请求体失败: %w", err)
+	}
+
+	return &Body{Data: buf.Bytes(), ContentType: w.FormDataContentType()}, nil
+}
+
+// NewFileBody 读取文件作为请求体，Content-Type 按扩展名推断，未知时按内容探测
+func NewFileBody(path string) (*Body, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取请求体文件失败: %w", err)
+	}
+	return &Body{Data: data, ContentType: detectContentType(path, data)}, nil
+}
+
+// ParseBodyArg 解析命令行请求体参数，"@路径" 表示读取文件，其余按原始内容发送
+func ParseBodyArg(arg string) (*Body, error) {
+	if path, ok := strings.CutPrefix(arg, "@"); ok {
+		return NewFileBody(path)
+	}
+	return &Body{Data: []byte(arg)}, nil
+}
+
+// RequestBody 发送带请求体的请求，headers 未指定 Content-Type 时使用 body 的类型
+func (c *Client) RequestBody(ctx context.Context, method, url string, body *Body, headers map[string]string) (*types.HTTPResult, error) {
+	if body == nil {
+		return c.Request(ctx, method, url, nil, headers)
+	}
+
+	merged := make(map[string]string, len(headers)+1)
+	hasContentType := false
+	for k, v := range headers {
+		merged[k] = v
+		if http.CanonicalHeaderKey(k) == "Content-Type" {
+			hasContentType = true
+		}
+	}
+	if !hasContentType && body.ContentType != "" {
+		merged["Content-Type"] = body.ContentType
+	}
+	return c.Request(ctx, method, url, body.Data, merged)
+}
+
+// writeFilePart 将文件写入 multipart 的一个部分
+func writeFilePart(w *multipart.Writer, field, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取上传文件失败: %w", err)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     field,
+		"filename": filepath.Base(path),
+	}))
+	header.Set("Content-Type", detectContentType(path, data))
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("写入文件 %s 失败: %w", field, err)
+	}
+	_, err = io.Copy(part, bytes.NewReader(data))
+	return err
+}
+
+// detectContentType 按扩展名推断 MIME 类型，未知时按内容探测
+func detectContentType(path string, data []byte) string {
+	if ct := mime.TypeByExtension(filepath.Ext(path)); ct != "" {
+		return ct
+	}
+	return http.DetectContentType(data)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// formEcho 解析表单后以 JSON 返回字段、文件与 Content-Type
+type formEcho struct {
+	ContentType string            `json:"content_type"`
+	Fields      map[string]string `json:"fields"`
+	Files       map[string]string `json:"files"`
+	FileNames   map[string]string `json:"file_names"`
+	FileTypes   map[string]string `json:"file_types"`
+}
+
+func startFormEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		echo := formEcho{
+			ContentType: r.Header.Get("Content-Type"),
+			Fields:      map[string]string{},
+			Files:       map[string]string{},
+			FileNames:   map[string]string{},
+			FileTypes:   map[string]string{},
+		}
+		if strings.HasPrefix(echo.ContentType, "multipart/") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			for name, fhs := range r.MultipartForm.File {
+				f, err := fhs[0].Open()
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				data, _ := io.ReadAll(f)
+				f.Close()
+				echo.Files[name] = string(data)
+				echo.FileNames[name] = fhs[0].Filename
+				echo.FileTypes[name] = fhs[0].Header.Get("Content-Type")
+			}
+		} else if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for name := range r.PostForm {
+			echo.Fields[name] = r.PostForm.Get(name)
+		}
+		_ = json.NewEncoder(w).Encode(echo)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func postBody(t *testing.T, server *httptest.Server, body *Body, headers map[string]string) formEcho {
+	t.Helper()
+	result, err := NewClient(nil).RequestBody(context.Background(), "POST", server.URL, body, headers)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, result.StatusCode, string(result.Body))
+
+	var echo formEcho
+	require.NoError(t, json.Unmarshal(result.Body, &echo))
+	return echo
+}
+
+func TestMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.json")
+	require.NoError(t, os.WriteFile(report, []byte(`{"ok":true}`), 0o644))
+
+	body, err := NewMultipartBody(
+		map[string]string{"name": "ntx", "note": "多语言 ✓"},
+		map[string]string{"report": report},
+	)
+	require.NoError(t, err)
+
+	echo := postBody(t, startFormEchoServer(t), body, nil)
+	require.True(t, strings.HasPrefix(echo.ContentType, "multipart/form-data; boundary="))
+	require.Equal(t, map[string]string{"name": "ntx", "note": "多语言 ✓"}, echo.Fields)
+	require.Equal(t, `{"ok":true}`, echo.Files["report"])
+	require.Equal(t, "report.json", echo.FileNames["report"])
+	require.Equal(t, "application/json", echo.FileTypes["report"])
+}
+
+func TestMultipartBodyMissingFile(t *testing.T) {
+	_, err := NewMultipartBody(nil, map[string]string{"f": filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+}
+
+func TestFormBody(t *testing.T) {
+	body := NewFormBody(map[string]string{"b": "2", "a": "x y&z"})
+	require.Equal(t, "a=x+y%26z&b=2", string(body.Data))
+
+	echo := postBody(t, startFormEchoServer(t), body, nil)
+	require.Equal(t, "application/x-www-form-urlencoded", echo.ContentType)
+	require.Equal(t, map[string]string{"a": "x y&z", "b": "2"}, echo.Fields)
+}
+
+func TestParseBodyArg(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "payload.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":1}`), 0o644))
+
+	body, err := ParseBodyArg("@" + path)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1}`, string(body.Data))
+	require.Equal(t, "application/json", body.ContentType)
+
+	body, err = ParseBodyArg("raw text")
+	require.NoError(t, err)
+	require.Equal(t, "raw text", string(body.Data))
+	require.Empty(t, body.ContentType)
+
+	_, err = ParseBodyArg("@" + filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestRequestBodyKeepsExplicitContentType(t *testing.T) {
+	echo := postBody(t, startFormEchoServer(t), NewFormBody(map[string]string{"a": "1"}),
+		map[string]string{"content-type": "application/x-www-form-urlencoded; charset=utf-8"})
+	require.Equal(t, "application/x-www-form-urlencoded; charset=utf-8", echo.ContentType)
+}
@@ -6,6 +6,9 @@
 // - 请求体支持（JSON、Form 等）
 // - 超时控制
 // - 重定向控制
+// - gzip/deflate 响应解码
+// - TLS 证书链信息
+// - DNS/连接/TLS/首字节各阶段耗时
 // - 响应详情显示
 //
 // 依赖:
@@ -22,12 +25,16 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"time"
 
 	"github.com/catsayer/ntx/pkg/buildinfo"
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/types"
 )
 
@@ -44,6 +51,7 @@ func NewClient(opts *types.HTTPOptions) *Client {
 			Timeout:        types.DefaultHTTPTimeout,
 			FollowRedirect: true,
 			MaxRedirects:   10,
+			Decompress:     true,
 		}
 	}
 
@@ -54,8 +62,18 @@ func NewClient(opts *types.HTTPOptions) *Client {
 		opts.UserAgent = buildinfo.UserAgent()
 	}
 
+	// 关闭 Transport 的透明 gzip 解压，由 Request 自行解码，以便同时记录传输与解码后的大小
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableCompression = true
+	if opts.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if len(opts.Resolve) > 0 {
+		transport.DialContext = netutil.OverrideDialContext(opts.Resolve, (&net.Dialer{}).DialContext)
+	}
 	httpClient := &http.Client{
-		Timeout: opts.Timeout,
+		Timeout:   opts.Timeout,
+		Transport: transport,
 	}
 
 	// 配置重定向策略
@@ -82,6 +100,9 @@ func NewClient(opts *types.HTTPOptions) *Client {
 func (c *Client) Request(ctx context.Context, method, url string, body []byte, headers map[string]string) (*types.HTTPResult, error) {
 	startTime := time.Now()
 
+	timer := &netutil.PhaseTimer{}
+	ctx = httptrace.WithClientTrace(ctx, timer.Trace())
+
 	// 创建请求
 	var bodyReader io.Reader
 	if body != nil {
@@ -102,6 +123,9 @@ func (c *Client) Request(ctx context.Context, method, url string, body []byte, h
 	if req.Header.Get("User-Agent") == "" {
 		req.Header.Set("User-Agent", c.options.UserAgent)
 	}
+	if c.options.Decompress && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
 
 	// 发送请求
 	resp, err := c.client.Do(req)
@@ -115,6 +139,16 @@ func (c *Client) Request(ctx context.Context, method, url string, body []byte, h
 	if err != nil {
 		return nil, fmt.Errorf("读取响应失败: %w", err)
 	}
+	transferred := int64(len(respBody))
+
+	// 即使未声明 Accept-Encoding，服务端仍可能返回压缩内容，按 Content-Encoding 解码
+	var uncompressed bool
+	if c.options.Decompress {
+		respBody, uncompressed, err = decodeBody(resp.Header.Get("Content-Encoding"), respBody)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	endTime := time.Now()
 
@@ -131,10 +165,13 @@ func (c *Client) Request(ctx context.Context, method, url string, body []byte, h
 		StartTime:       startTime,
 		EndTime:         endTime,
 		Duration:        endTime.Sub(startTime),
+		Timings:         timer.Timings(startTime, endTime),
 		TLSUsed:         resp.TLS != nil,
-		Uncompressed:    resp.Uncompressed,
+		TLS:             netutil.TLSInfoFromState(resp.TLS, endTime),
+		Uncompressed:    uncompressed,
 		ContentType:     resp.Header.Get("Content-Type"),
-		TransferredSize: int64(len(respBody)),
+		TransferredSize: transferred,
+		DecodedSize:     int64(len(respBody)),
 	}
 
 	// 复制响应头
@@ -180,76 +217,6 @@ func (c *Client) Options(ctx context.Context, url string, headers map[string]str
 	return c.Request(ctx, "OPTIONS", url, nil, headers)
 }
 
-// Benchmark 执行性能测试
-func (c *Client) Benchmark(ctx context.Context, method, url string, body []byte, headers map[string]string, count int) (*types.HTTPBenchmarkResult, error) {
-	if count <= 0 {
-		count = 1
-	}
-
-	results := make([]*types.HTTPResult, 0, count)
-	var totalDuration time.Duration
-	var successCount, failureCount int
-
-	startTime := time.Now()
-
-	for i := 0; i < count; i++ {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		result, err := c.Request(ctx, method, url, body, headers)
-		if err != nil {
-			failureCount++
-			continue
-		}
-
-		results = append(results, result)
-		totalDuration += result.Duration
-
-		if result.StatusCode >= 200 && result.StatusCode < 300 {
-			successCount++
-		} else {
-			failureCount++
-		}
-	}
-
-	endTime := time.Now()
-
-	// 计算统计信息
-	var minDuration, maxDuration, avgDuration time.Duration
-	if len(results) > 0 {
-		minDuration = results[0].Duration
-		maxDuration = results[0].Duration
-		avgDuration = totalDuration / time.Duration(len(results))
-
-		for _, result := range results {
-			if result.Duration < minDuration {
-				minDuration = result.Duration
-			}
-			if result.Duration > maxDuration {
-				maxDuration = result.Duration
-			}
-		}
-	}
-
-	benchResult := &types.HTTPBenchmarkResult{
-		Method:         method,
-		URL:            url,
-		TotalRequests:  count,
-		SuccessCount:   successCount,
-		FailureCount:   failureCount,
-		TotalDuration:  endTime.Sub(startTime),
-		MinDuration:    minDuration,
-		MaxDuration:    maxDuration,
-		AvgDuration:    avgDuration,
-		RequestsPerSec: float64(count) / endTime.Sub(startTime).Seconds(),
-	}
-
-	return benchResult, nil
-}
-
 // Close 关闭客户端 (当前无需实际操作)
 func (c *Client) Close() error {
 	return nil
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// compressibleBody 足够长且重复，压缩后明显变小
+var compressibleBody = strings.Repeat("hello ntx, compressed world! ", 200)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestRequestDecodesGzip(t *testing.T) {
+	compressed := gzipBytes(t, compressibleBody)
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compressed)
+	}))
+	defer server.Close()
+
+	client := NewClient(&types.HTTPOptions{Decompress: true})
+	result, err := client.Get(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "gzip, deflate, br", acceptEncoding)
+	require.Equal(t, compressibleBody, string(result.Body))
+	require.True(t, result.Uncompressed)
+	require.Equal(t, int64(len(compressed)), result.TransferredSize)
+	require.Equal(t, int64(len(compressibleBody)), result.DecodedSize)
+	require.Less(t, result.TransferredSize, result.DecodedSize)
+}
+
+func TestRequestWithoutDecompress(t *testing.T) {
+	compressed := gzipBytes(t, compressibleBody)
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		// 服务端忽略请求头，仍返回压缩内容
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compressed)
+	}))
+	defer server.Close()
+
+	result, err := NewClient(&types.HTTPOptions{}).Get(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+
+	require.Empty(t, acceptEncoding)
+	require.Equal(t, compressed, result.Body)
+	require.False(t, result.Uncompressed)
+	require.Equal(t, result.TransferredSize, result.DecodedSize)
+}
+
+func TestDecodeBody(t *testing.T) {
+	var zbuf bytes.Buffer
+	zw := zlib.NewWriter(&zbuf)
+	_, err := zw.Write([]byte("deflated"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	body, ok, err := decodeBody("deflate", zbuf.Bytes())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "deflated", string(body))
+
+	// 多重编码逆序解码
+	double := gzipBytes(t, string(gzipBytes(t, "twice")))
+	body, ok, err = decodeBody("gzip, gzip", double)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "twice", string(body))
+
+	var bbuf bytes.Buffer
+	bw := brotli.NewWriter(&bbuf)
+	_, err = bw.Write([]byte("brotli"))
+	require.NoError(t, err)
+	require.NoError(t, bw.Close())
+	body, ok, err = decodeBody("br", bbuf.Bytes())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "brotli", string(body))
+
+	// 不支持的编码原样返回
+	body, ok, err = decodeBody("zstd", []byte{1, 2, 3})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, []byte{1, 2, 3}, body)
+
+	_, _, err = decodeBody("gzip", []byte("not gzip"))
+	require.Error(t, err)
+}
+
+func TestRequestCapturesTLSCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(&types.HTTPOptions{InsecureSkipVerify: true})
+	result, err := client.Get(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	require.True(t, result.TLSUsed)
+	require.NotNil(t, result.TLS)
+	require.NotEmpty(t, result.TLS.Version)
+	require.NotEmpty(t, result.TLS.CipherSuite)
+
+	cert := server.Certificate()
+	leaf := result.TLS.Leaf()
+	require.NotNil(t, leaf)
+	require.Equal(t, cert.Subject.String(), leaf.Subject)
+	require.Equal(t, cert.Issuer.String(), leaf.Issuer)
+	require.Equal(t, cert.DNSNames, leaf.DNSNames)
+	require.Contains(t, leaf.IPAddresses, "127.0.0.1")
+	require.True(t, cert.NotBefore.Equal(leaf.NotBefore))
+	require.True(t, cert.NotAfter.Equal(leaf.NotAfter))
+	require.Greater(t, leaf.DaysUntilExpiry, types.DefaultCertExpiryWarnDays)
+	require.False(t, leaf.ExpiresWithin(types.DefaultCertExpiryWarnDays))
+}
+
+func TestRequestPlainHTTPHasNoTLSInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result, err := NewClient(nil).Get(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	require.False(t, result.TLSUsed)
+	require.Nil(t, result.TLS)
+	require.NotNil(t, result.Timings)
+	require.Positive(t, result.Timings.TTFB)
+	require.Zero(t, result.Timings.TLS)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptEncoding 开启解压时发送的 Accept-Encoding，仅包含可解码的编码
+const acceptEncoding = "gzip, deflate, br"
+
+// decodeBody 按 Content-Encoding 解码响应体
+//
+// 多重编码按逆序逐层解码；遇到不支持的编码（如 zstd）时停止并原样返回剩余内容，
+// decoded 仅在全部编码都被解开时为 true。
+func decodeBody(contentEncoding string, body []byte) (decoded []byte, ok bool, err error) {
+	if len(body) == 0 {
+		return body, false, nil
+	}
+
+	var encodings []string
+	for _, enc := range strings.Split(contentEncoding, ",") {
+		enc = strings.ToLower(strings.TrimSpace(enc))
+		if enc != "" && enc != "identity" {
+			encodings = append(encodings, enc)
+		}
+	}
+	if len(encodings) == 0 {
+		return body, false, nil
+	}
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var next []byte
+		switch encodings[i] {
+		case "gzip", "x-gzip":
+			next, err = readAllFrom(gzip.NewReader(bytes.NewReader(body)))
+		case "deflate":
+			next, err = inflate(body)
+		case "br":
+			next, err = io.ReadAll(brotli.NewReader(bytes.NewReader(body)))
+		default:
+			return body, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("解码 %s 响应失败: %w", encodings[i], err)
+		}
+		body = next
+	}
+	return body, true, nil
+}
+
+// inflate 解码 deflate 响应，按 RFC 9110 应为 zlib 格式，兼容部分服务端发送的裸 deflate 流
+func inflate(body []byte) ([]byte, error) {
+	if out, err := readAllFrom(zlib.NewReader(bytes.NewReader(body))); err == nil {
+		return out, nil
+	}
+	return io.ReadAll(flate.NewReader(bytes.NewReader(body)))
+}
+
+func readAllFrom(r io.ReadCloser, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"text/template"
+)
+
+// requestTemplate 性能测试中逐请求渲染的 URL 与请求体模板
+//
+// 支持的变量与函数:
+//   - {{.i}}              请求序号（从 1 开始）
+//   - {{uuid}}            随机 UUID v4
+//   - {{randInt 1 100}}   [min, max] 区间内的随机整数
+type requestTemplate struct {
+	url  *template.Template
+	body *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"uuid":    newUUID,
+	"randInt": randInt,
+}
+
+// hasTemplate 判断文本中是否包含模板占位符
+func hasTemplate(s string) bool {
+	return strings.Contains(s, "{{")
+}
+
+// newRequestTemplate 解析 URL 与请求体模板，二者均不含占位符时返回 nil
+func newRequestTemplate(url string, body []byte) (*requestTemplate, error) {
+	if !hasTemplate(url) && !hasTemplate(string(body)) {
+		return nil, nil
+	}
+
+	t := &requestTemplate{}
+	if hasTemplate(url) {
+		parsed, err := template.New("url").Funcs(templateFuncs).Option("missingkey=error").Parse(url)
+		if err != nil {
+			return nil, fmt.Errorf("解析 URL 模板失败: %w", err)
+		}
+		t.url = parsed
+	}
+	if hasTemplate(string(body)) {
+		parsed, err := template.New("body").Funcs(templateFuncs).Option("missingkey=error").Parse(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("解析请求体模板失败: %w", err)
+		}
+		t.body = parsed
+	}
+	return t, nil
+}
+
+// render 渲染第 seq 个请求的 URL 与请求体
+func (t *requestTemplate) render(seq int, url string, body []byte) (string, []byte, error) {
+	data := map[string]interface{}{"i": seq}
+
+	if t.url != nil {
+		var buf bytes.Buffer
+		if err := t.url.Execute(&buf, data); err != nil {
+			return "", nil, fmt.Errorf("渲染 URL 模板失败: %w", err)
+		}
+		url = buf.String()
+	}
+	if t.body != nil {
+		var buf bytes.Buffer
+		if err := t.body.Execute(&buf, data); err != nil {
+			return "", nil, fmt.Errorf("渲染请求体模板失败: %w", err)
+		}
+		body = buf.Bytes()
+	}
+	return url, body, nil
+}
+
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func randInt(min, max int) (int, error) {
+	if max < min {
+		return 0, fmt.Errorf("randInt 参数无效: %d > %d", min, max)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min)+1))
+	if err != nil {
+		return 0, err
+	}
+	return min + int(n.Int64()), nil
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestTemplateWithoutPlaceholders(t *testing.T) {
+	tmpl, err := newRequestTemplate("https://example.com", []byte(`{"id":1}`))
+	require.NoError(t, err)
+	require.Nil(t, tmpl)
+}
+
+func TestRequestTemplateRender(t *testing.T) {
+	tmpl, err := newRequestTemplate(
+		"https://example.com/items/{{.i}}",
+		[]byte(`{"id":{{.i}},"uuid":"{{uuid}}","n":{{randInt 5 7}}}`),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, tmpl)
+
+	url, body, err := tmpl.render(3, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/items/3", url)
+
+	m := regexp.MustCompile(`^\{"id":3,"uuid":"([0-9a-f-]{36})","n":(\d)\}$`).FindStringSubmatch(string(body))
+	require.NotNil(t, m, string(body))
+	n, _ := strconv.Atoi(m[2])
+	require.GreaterOrEqual(t, n, 5)
+	require.LessOrEqual(t, n, 7)
+}
+
+func TestRequestTemplateParseError(t *testing.T) {
+	_, err := newRequestTemplate("https://example.com/{{.i", nil)
+	require.Error(t, err)
+}
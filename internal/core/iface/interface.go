@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/catsayer/ntx/pkg/netutil"
 	"github.com/catsayer/ntx/pkg/types"
 )
 
@@ -98,6 +99,7 @@ func (r *InterfaceReader) parseInterface(netIface *net.Interface) (*types.Interf
 		MTU:          netIface.MTU,
 		Flags:        flags,
 		HardwareAddr: netIface.HardwareAddr.String(),
+		Vendor:       netutil.LookupVendor(netIface.HardwareAddr.String()),
 		IPv4Addrs:    ipv4Addrs,
 		IPv6Addrs:    ipv6Addrs,
 	}
@@ -0,0 +1,86 @@
+package iface
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// GetNetworkConfig 获取系统 DNS 服务器与各网卡默认网关
+func (r *InterfaceReader) GetNetworkConfig() (*types.NetworkConfig, error) {
+	cfg, err := r.getDNSConfigImpl()
+	if err != nil {
+		return nil, err
+	}
+
+	// 路由表读取失败时仍返回 DNS 信息
+	if routes, err := r.GetRoutes(); err == nil {
+		cfg.Gateways = defaultGateways(routes)
+	}
+
+	return cfg, nil
+}
+
+// parseResolvConf 解析 resolv.conf 格式的 DNS 配置
+func parseResolvConf(rd io.Reader) (*types.NetworkConfig, error) {
+	cfg := &types.NetworkConfig{}
+	scanner := bufio.NewScanner(rd)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			cfg.DNSServers = append(cfg.DNSServers, fields[1])
+		case "search":
+			cfg.SearchDomains = fields[1:]
+		case "domain":
+			if len(cfg.SearchDomains) == 0 {
+				cfg.SearchDomains = fields[1:2]
+			}
+		}
+	}
+
+	return cfg, scanner.Err()
+}
+
+// defaultGateways 从路由表中提取每个网卡的默认网关
+func defaultGateways(routes []*types.Route) []*types.InterfaceGateway {
+	gateways := make([]*types.InterfaceGateway, 0)
+	seen := make(map[string]bool)
+
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		dest := strings.ToLower(strings.TrimSpace(route.Destination))
+		if dest != "default" && dest != "0.0.0.0" && dest != "0.0.0.0/0" && dest != "::/0" {
+			continue
+		}
+		gateway := strings.TrimSpace(route.Gateway)
+		if gateway == "" || gateway == "0.0.0.0" || gateway == "*" || strings.HasPrefix(gateway, "link#") {
+			continue
+		}
+		key := route.Interface + "|" + gateway
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		gateways = append(gateways, &types.InterfaceGateway{
+			Interface: route.Interface,
+			Gateway:   gateway,
+		})
+	}
+
+	return gateways
+}
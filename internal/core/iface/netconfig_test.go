@@ -0,0 +1,38 @@
+package iface
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	content := `# generated by NetworkManager
+search corp.example.com example.com
+nameserver 192.168.1.1
+; comment
+nameserver 2001:4860:4860::8888
+options edns0
+`
+	cfg, err := parseResolvConf(strings.NewReader(content))
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.168.1.1", "2001:4860:4860::8888"}, cfg.DNSServers)
+	require.Equal(t, []string{"corp.example.com", "example.com"}, cfg.SearchDomains)
+}
+
+func TestDefaultGateways(t *testing.T) {
+	routes := []*types.Route{
+		{Interface: "eth0", Destination: "0.0.0.0", Gateway: "192.168.1.1"},
+		{Interface: "eth0", Destination: "192.168.1.0", Gateway: "0.0.0.0"},
+		{Interface: "wlan0", Destination: "default", Gateway: "10.0.0.1"},
+		{Interface: "en0", Destination: "default", Gateway: "link#4"},
+	}
+
+	gateways := defaultGateways(routes)
+	require.Len(t, gateways, 2)
+	require.Equal(t, "eth0", gateways[0].Interface)
+	require.Equal(t, "192.168.1.1", gateways[0].Gateway)
+	require.Equal(t, "wlan0", gateways[1].Interface)
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package iface
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+const resolvConfPath = "/etc/resolv.conf"
+
+// getDNSConfigImpl 读取 /etc/resolv.conf (Linux/macOS)
+func (r *InterfaceReader) getDNSConfigImpl() (*types.NetworkConfig, error) {
+	file, err := os.Open(resolvConfPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 %s 失败: %w", resolvConfPath, err)
+	}
+	defer file.Close()
+
+	cfg, err := parseResolvConf(file)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", resolvConfPath, err)
+	}
+	return cfg, nil
+}
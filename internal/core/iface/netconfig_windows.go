@@ -0,0 +1,54 @@
+//go:build windows
+// +build windows
+
+package iface
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"golang.org/x/sys/windows"
+)
+
+// getDNSConfigImpl 通过 GetAdaptersAddresses 读取各网卡的 DNS 配置 (Windows)
+func (r *InterfaceReader) getDNSConfigImpl() (*types.NetworkConfig, error) {
+	size := uint32(15 * 1024)
+	var buf []byte
+	for {
+		buf = make([]byte, size)
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC, windows.GAA_FLAG_INCLUDE_PREFIX, 0,
+			(*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])), &size)
+		if err == nil {
+			break
+		}
+		if err != windows.ERROR_BUFFER_OVERFLOW {
+			return nil, fmt.Errorf("GetAdaptersAddresses 失败: %w", os.NewSyscallError("GetAdaptersAddresses", err))
+		}
+	}
+
+	cfg := &types.NetworkConfig{}
+	seen := make(map[string]bool)
+	for aa := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])); aa != nil; aa = aa.Next {
+		if aa.OperStatus != windows.IfOperStatusUp {
+			continue
+		}
+		for dns := aa.FirstDnsServerAddress; dns != nil; dns = dns.Next {
+			ip := dns.Address.IP()
+			if ip == nil {
+				continue
+			}
+			addr := ip.String()
+			if !seen[addr] {
+				seen[addr] = true
+				cfg.DNSServers = append(cfg.DNSServers, addr)
+			}
+		}
+		if suffix := windows.UTF16PtrToString(aa.DnsSuffix); suffix != "" {
+			cfg.SearchDomains = append(cfg.SearchDomains, suffix)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,239 @@
+// Package neigh 提供邻居表（ARP）读取与局域网主机发现功能
+//
+// 本模块实现:
+//   - 读取系统 ARP 表（Linux /proc/net/arp，macOS/Windows arp 命令）
+//   - 主动探测子网: 向每个地址发送 UDP 数据报触发内核 ARP 解析，
+//     随后从邻居表中收集已解析的主机，无需 raw socket 权限
+//   - 基于 OUI 的厂商识别
+//
+// 使用示例:
+//
+//	reader := neigh.NewReader()
+//	neighbors, err := reader.Scan(ctx, "eth0", time.Second)
+//
+// 作者: Catsayer
+package neigh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxScanHosts 单次主动探测的最大主机数（/20）
+	maxScanHosts = 4096
+	// probeConcurrency 主动探测并发数
+	probeConcurrency = 256
+	// discardPort 探测数据报目标端口（discard 服务）
+	discardPort = 9
+)
+
+// Reader 邻居表读取器
+type Reader struct{}
+
+// NewReader 创建邻居表读取器
+func NewReader() *Reader {
+	return &Reader{}
+}
+
+// List 读取系统邻居表，ifaceName 为空时返回所有网卡的条目
+func (r *Reader) List(ifaceName string) ([]*types.Neighbor, error) {
+	neighbors, err := readNeighbors()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*types.Neighbor, 0, len(neighbors))
+	for _, n := range neighbors {
+		if ifaceName != "" && n.Interface != ifaceName {
+			continue
+		}
+		n.Vendor = netutil.LookupVendor(n.MAC)
+		filtered = append(filtered, n)
+	}
+	sortNeighbors(filtered)
+	return filtered, nil
+}
+
+// Scan 主动探测网卡所在 IPv4 子网，等待 wait 后返回子网内已解析的邻居
+func (r *Reader) Scan(ctx context.Context, ifaceName string, wait time.Duration) ([]*types.Neighbor, error) {
+	iface, subnet, err := selectSubnet(ifaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, _ := subnet.Mask.Size()
+	if size := 1 << uint(32-ones); size-2 > maxScanHosts {
+		return nil, fmt.Errorf("子网 %s 过大（%d 个地址），最多支持 %d 个", subnet, size-2, maxScanHosts)
+	}
+	hosts := subnetHosts(subnet)
+
+	logger.Info("开始探测子网",
+		zap.String("interface", iface.Name),
+		zap.String("subnet", subnet.String()),
+		zap.Int("hosts", len(hosts)))
+
+	probeHosts(ctx, hosts)
+
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	all, err := r.List(iface.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make([]*types.Neighbor, 0, len(all))
+	for _, n := range all {
+		ip := net.ParseIP(n.IP)
+		if ip == nil || !subnet.Contains(ip) || !isResolved(n) {
+			continue
+		}
+		live = append(live, n)
+	}
+	return live, nil
+}
+
+// selectSubnet 选择探测的网卡与 IPv4 子网，未指定网卡时使用第一个可用的非回环网卡
+func selectSubnet(ifaceName string) (*net.Interface, *net.IPNet, error) {
+	var candidates []net.Interface
+	if ifaceName != "" {
+		iface, err := net.InterfaceByName(ifaceName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取网卡 %s 失败: %w", ifaceName, err)
+		}
+		candidates = []net.Interface{*iface}
+	} else {
+		all, err := net.Interfaces()
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取网卡列表失败: %w", err)
+		}
+		candidates = all
+	}
+
+	for i := range candidates {
+		iface := &candidates[i]
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			return iface, &net.IPNet{IP: ipNet.IP.To4().Mask(ipNet.Mask), Mask: ipNet.Mask}, nil
+		}
+	}
+
+	if ifaceName != "" {
+		return nil, nil, fmt.Errorf("网卡 %s 没有可用的 IPv4 地址", ifaceName)
+	}
+	return nil, nil, fmt.Errorf("未找到可用于探测的网卡")
+}
+
+// subnetHosts 列出子网内除网络地址和广播地址外的所有主机地址
+func subnetHosts(subnet *net.IPNet) []net.IP {
+	ones, bits := subnet.Mask.Size()
+	if bits != 32 || ones >= 31 {
+		return []net.IP{subnet.IP}
+	}
+
+	size := uint32(1) << uint(32-ones)
+	base := ipToUint32(subnet.IP.To4())
+	hosts := make([]net.IP, 0, size-2)
+	for i := uint32(1); i < size-1; i++ {
+		hosts = append(hosts, uint32ToIP(base+i))
+	}
+	return hosts
+}
+
+// probeHosts 向每个地址发送一个 UDP 数据报，促使内核发起 ARP 解析
+func probeHosts(ctx context.Context, hosts []net.IP) {
+	sem := make(chan struct{}, probeConcurrency)
+	var wg sync.WaitGroup
+
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ip net.IP) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: ip, Port: discardPort})
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte{0})
+			conn.Close()
+		}(host)
+	}
+	wg.Wait()
+}
+
+// isResolved 判断条目是否包含有效的硬件地址
+func isResolved(n *types.Neighbor) bool {
+	if n.MAC == "" || n.MAC == "00:00:00:00:00:00" {
+		return false
+	}
+	state := strings.ToLower(n.State)
+	return state != "incomplete" && state != "failed"
+}
+
+func sortNeighbors(neighbors []*types.Neighbor) {
+	sort.Slice(neighbors, func(i, j int) bool {
+		a, b := net.ParseIP(neighbors[i].IP), net.ParseIP(neighbors[j].IP)
+		if a == nil || b == nil {
+			return neighbors[i].IP < neighbors[j].IP
+		}
+		if a4, b4 := a.To4(), b.To4(); a4 != nil && b4 != nil {
+			return ipToUint32(a4) < ipToUint32(b4)
+		}
+		return a.String() < b.String()
+	})
+}
+
+func ipToUint32(ip net.IP) uint32 {
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+}
+
+func uint32ToIP(v uint32) net.IP {
+	return net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// normalizeMAC 统一 MAC 地址格式为小写冒号分隔，macOS 省略的前导零会被补齐
+func normalizeMAC(mac string) string {
+	parts := strings.FieldsFunc(mac, func(r rune) bool { return r == ':' || r == '-' })
+	if len(parts) != 6 {
+		return strings.ToLower(mac)
+	}
+	for i, p := range parts {
+		if len(p) == 1 {
+			parts[i] = "0" + p
+		}
+	}
+	hw, err := net.ParseMAC(strings.Join(parts, ":"))
+	if err != nil {
+		return strings.ToLower(mac)
+	}
+	return hw.String()
+}
@@ -0,0 +1,21 @@
+//go:build darwin
+// +build darwin
+
+package neigh
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// readNeighbors 读取邻居表 (macOS)
+func readNeighbors() ([]*types.Neighbor, error) {
+	output, err := exec.Command("arp", "-an").Output()
+	if err != nil {
+		return nil, fmt.Errorf("执行 arp 命令失败: %w", err)
+	}
+	return parseBSDARP(bytes.NewReader(output)), nil
+}
@@ -0,0 +1,22 @@
+//go:build linux
+// +build linux
+
+package neigh
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// readNeighbors 读取邻居表 (Linux)
+func readNeighbors() ([]*types.Neighbor, error) {
+	file, err := os.Open(types.ProcNetARP)
+	if err != nil {
+		return nil, fmt.Errorf("读取 ARP 表失败: %w", err)
+	}
+	defer file.Close()
+
+	return parseProcARP(file), nil
+}
@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+package neigh
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// readNeighbors 读取邻居表 (Windows)
+func readNeighbors() ([]*types.Neighbor, error) {
+	output, err := exec.Command("arp", "-a").Output()
+	if err != nil {
+		return nil, fmt.Errorf("执行 arp 命令失败: %w", err)
+	}
+	return parseWindowsARP(bytes.NewReader(output), interfaceNameByIP), nil
+}
+
+// interfaceNameByIP 根据网卡地址查找网卡名称
+func interfaceNameByIP(ip string) string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.String() == ip {
+				return iface.Name
+			}
+		}
+	}
+	return ""
+}
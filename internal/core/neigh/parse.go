@@ -0,0 +1,136 @@
+package neigh
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// ARP 表标志位（include/uapi/linux/if_arp.h）
+const (
+	atfComplete = 0x02
+	atfPerm     = 0x04
+)
+
+// parseProcARP 解析 Linux /proc/net/arp
+//
+// 格式: IP address  HW type  Flags  HW address  Mask  Device
+func parseProcARP(r io.Reader) []*types.Neighbor {
+	var neighbors []*types.Neighbor
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		if first {
+			first = false
+			continue
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(strings.TrimPrefix(fields[2], "0x"), 16, 32)
+		if err != nil {
+			continue
+		}
+
+		state := "incomplete"
+		switch {
+		case flags&atfPerm != 0:
+			state = "permanent"
+		case flags&atfComplete != 0:
+			state = "reachable"
+		}
+
+		neighbors = append(neighbors, &types.Neighbor{
+			IP:        fields[0],
+			MAC:       normalizeMAC(fields[3]),
+			Interface: fields[5],
+			State:     state,
+		})
+	}
+	return neighbors
+}
+
+// parseBSDARP 解析 macOS/BSD `arp -an` 输出
+//
+// 格式: ? (192.168.1.1) at 0:11:22:33:44:55 on en0 ifscope [ethernet]
+func parseBSDARP(r io.Reader) []*types.Neighbor {
+	var neighbors []*types.Neighbor
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[2] != "at" || fields[4] != "on" {
+			continue
+		}
+
+		n := &types.Neighbor{
+			IP:        strings.Trim(fields[1], "()"),
+			Interface: fields[5],
+			State:     "reachable",
+		}
+		if fields[3] == "(incomplete)" {
+			n.State = "incomplete"
+		} else {
+			n.MAC = normalizeMAC(fields[3])
+		}
+		for _, f := range fields[6:] {
+			if f == "permanent" {
+				n.State = "permanent"
+			}
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors
+}
+
+// parseWindowsARP 解析 Windows `arp -a` 输出，ifaceName 将网卡地址映射为网卡名称
+//
+// 格式:
+//
+//	Interface: 192.168.1.10 --- 0xb
+//	  Internet Address      Physical Address      Type
+//	  192.168.1.1           00-11-22-33-44-55     dynamic
+func parseWindowsARP(r io.Reader, ifaceName func(ip string) string) []*types.Neighbor {
+	var neighbors []*types.Neighbor
+	current := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasSuffix(fields[0], ":") && len(fields) >= 2 {
+			current = fields[1]
+			if ifaceName != nil {
+				if name := ifaceName(current); name != "" {
+					current = name
+				}
+			}
+			continue
+		}
+		if len(fields) < 3 || net.ParseIP(fields[0]) == nil {
+			continue
+		}
+
+		state := strings.ToLower(fields[2])
+		switch state {
+		case "dynamic", "动态":
+			state = "reachable"
+		case "static", "静态":
+			state = "permanent"
+		}
+
+		neighbors = append(neighbors, &types.Neighbor{
+			IP:        fields[0],
+			MAC:       normalizeMAC(fields[1]),
+			Interface: current,
+			State:     state,
+		})
+	}
+	return neighbors
+}
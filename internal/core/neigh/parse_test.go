@@ -0,0 +1,69 @@
+package neigh
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcARP(t *testing.T) {
+	input := `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.1      0x1         0x2         B8:27:EB:11:22:33     *        eth0
+192.168.1.7      0x1         0x0         00:00:00:00:00:00     *        eth0
+10.0.0.5         0x1         0x6         52:54:00:aa:bb:cc     *        br0
+`
+	neighbors := parseProcARP(strings.NewReader(input))
+	require.Len(t, neighbors, 3)
+	require.Equal(t, "192.168.1.1", neighbors[0].IP)
+	require.Equal(t, "b8:27:eb:11:22:33", neighbors[0].MAC)
+	require.Equal(t, "eth0", neighbors[0].Interface)
+	require.Equal(t, "reachable", neighbors[0].State)
+	require.Equal(t, "incomplete", neighbors[1].State)
+	require.False(t, isResolved(neighbors[1]))
+	require.Equal(t, "permanent", neighbors[2].State)
+}
+
+func TestParseBSDARP(t *testing.T) {
+	input := `? (192.168.1.1) at 0:11:22:33:44:55 on en0 ifscope [ethernet]
+? (192.168.1.9) at (incomplete) on en0 ifscope [ethernet]
+? (224.0.0.251) at 1:0:5e:0:0:fb on en0 ifscope permanent [ethernet]
+`
+	neighbors := parseBSDARP(strings.NewReader(input))
+	require.Len(t, neighbors, 3)
+	require.Equal(t, "00:11:22:33:44:55", neighbors[0].MAC)
+	require.Equal(t, "en0", neighbors[0].Interface)
+	require.Equal(t, "incomplete", neighbors[1].State)
+	require.Equal(t, "permanent", neighbors[2].State)
+}
+
+func TestParseWindowsARP(t *testing.T) {
+	input := `
+Interface: 192.168.1.10 --- 0xb
+  Internet Address      Physical Address      Type
+  192.168.1.1           00-50-56-c0-00-08     dynamic
+  192.168.1.255         ff-ff-ff-ff-ff-ff     static
+`
+	neighbors := parseWindowsARP(strings.NewReader(input), func(ip string) string {
+		if ip == "192.168.1.10" {
+			return "Ethernet"
+		}
+		return ""
+	})
+	require.Len(t, neighbors, 2)
+	require.Equal(t, "00:50:56:c0:00:08", neighbors[0].MAC)
+	require.Equal(t, "Ethernet", neighbors[0].Interface)
+	require.Equal(t, "reachable", neighbors[0].State)
+	require.Equal(t, "permanent", neighbors[1].State)
+}
+
+func TestSubnetHosts(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("192.168.1.0/29")
+	require.NoError(t, err)
+
+	hosts := subnetHosts(subnet)
+	require.Len(t, hosts, 6)
+	require.Equal(t, "192.168.1.1", hosts[0].String())
+	require.Equal(t, "192.168.1.6", hosts[5].String())
+}
@@ -7,7 +7,9 @@ package netstat
 import (
 	"bufio"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -28,10 +30,16 @@ const (
 	tcpStateClosingHex     = 0x0B
 )
 
-type linuxReader struct{}
+// defaultProcRoot procfs 挂载点
+const defaultProcRoot = "/proc"
+
+type linuxReader struct {
+	// procRoot 查找套接字所属进程时遍历的 procfs 根目录
+	procRoot string
+}
 
 func newPlatformReader() platformReader {
-	return &linuxReader{}
+	return &linuxReader{procRoot: defaultProcRoot}
 }
 
 // getConnections 从 /proc/net 读取连接信息
@@ -64,6 +72,11 @@ func (r *linuxReader) getConnections(opts *types.NetStatOptions) ([]*types.Conne
 		}
 	}
 
+	// 遍历 /proc/*/fd 开销较大，仅在需要进程信息时执行
+	if opts.IncludeProcess || opts.ProcessName != "" {
+		attachProcesses(connections, findSocketOwners(r.procRoot))
+	}
+
 	return connections, nil
 }
 
@@ -120,6 +133,7 @@ func (r *linuxReader) getStatistics() (*types.NetStatistics, error) {
 		}
 		stats.TotalConnections++
 	}
+	stats.Counters = readStackCounters()
 
 	return stats, nil
 }
@@ -164,6 +178,7 @@ func (r *linuxReader) readTCPConnections(path string) ([]*types.Connection, erro
 			RemoteAddr: remoteAddr,
 			RemotePort: remotePort,
 			State:      state,
+			Inode:      parseInode(fields[9]),
 		}
 
 		connections = append(connections, conn)
@@ -210,6 +225,7 @@ func (r *linuxReader) readUDPConnections(path string) ([]*types.Connection, erro
 			RemoteAddr: remoteAddr,
 			RemotePort: remotePort,
 			State:      types.StateUnknown, // UDP 没有状态
+			Inode:      parseInode(fields[9]),
 		}
 
 		connections = append(connections, conn)
@@ -218,6 +234,92 @@ func (r *linuxReader) readUDPConnections(path string) ([]*types.Connection, erro
 	return connections, scanner.Err()
 }
 
+// socketOwner 套接字所属进程
+type socketOwner struct {
+	pid  int
+	name string
+}
+
+// findSocketOwners 遍历 procRoot/[pid]/fd，建立套接字 inode 到进程的映射
+//
+// 无权读取的进程（非 root 时的其他用户进程）会被跳过。
+func findSocketOwners(procRoot string) map[uint64]socketOwner {
+	owners := make(map[uint64]socketOwner)
+
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return owners
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		fdDir := filepath.Join(procRoot, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		var name string
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			inode, ok := parseSocketLink(link)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				name = readProcessName(procRoot, entry.Name())
+			}
+			// 多个进程共享同一套接字时保留 PID 最小的（通常为父进程）
+			if _, exists := owners[inode]; !exists {
+				owners[inode] = socketOwner{pid: pid, name: name}
+			}
+		}
+	}
+
+	return owners
+}
+
+// attachProcesses 按 inode 为连接填充 PID 与进程名
+func attachProcesses(connections []*types.Connection, owners map[uint64]socketOwner) {
+	for _, conn := range connections {
+		if owner, ok := owners[conn.Inode]; ok && conn.Inode != 0 {
+			conn.PID = owner.pid
+			conn.ProcessName = owner.name
+		}
+	}
+}
+
+// parseSocketLink 解析 fd 符号链接目标 "socket:[12345]" 中的 inode
+func parseSocketLink(link string) (uint64, bool) {
+	if !strings.HasPrefix(link, "socket:[") || !strings.HasSuffix(link, "]") {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(link[len("socket:["):len(link)-1], 10, 64)
+	return inode, err == nil
+}
+
+// readProcessName 读取 procRoot/[pid]/comm 中的进程名
+func readProcessName(procRoot, pid string) string {
+	data, err := os.ReadFile(filepath.Join(procRoot, pid, "comm"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseInode 解析 /proc/net/* 的 inode 列
+func parseInode(field string) uint64 {
+	inode, _ := strconv.ParseUint(field, 10, 64)
+	return inode
+}
+
 // parseAddress 解析地址字符串 (格式: 0100007F:1F90)
 func parseAddress(addrStr string) (string, int) {
 	parts := strings.Split(addrStr, ":")
@@ -259,20 +361,30 @@ func parseIPv4(hexIP string) string {
 }
 
 // parseIPv6 解析 IPv6 地址
+//
+// /proc/net/*6 按 4 个 32 位字输出地址，每个字为主机字节序（小端）的十六进制。
+// IPv4 映射地址保留 "::ffff:" 前缀，以区分 IPv6 套接字上的 IPv4 连接。
 func parseIPv6(hexIP string) string {
 	if len(hexIP) != 32 {
 		return "::"
 	}
 
-	// 简化版实现,实际应该处理压缩表示
-	var parts []string
-	for i := 0; i < 8; i++ {
-		start := i * 4
-		end := start + 4
-		parts = append(parts, hexIP[start:end])
+	ip := make(net.IP, net.IPv6len)
+	for word := 0; word < 4; word++ {
+		for i := 0; i < 4; i++ {
+			start := word*8 + i*2
+			val, err := strconv.ParseUint(hexIP[start:start+2], 16, 8)
+			if err != nil {
+				return "::"
+			}
+			ip[word*4+3-i] = byte(val)
+		}
 	}
 
-	return strings.Join(parts, ":")
+	if v4 := ip.To4(); v4 != nil && !ip.Equal(net.IPv4zero) {
+		return "::ffff:" + v4.String()
+	}
+	return ip.String()
 }
 
 // parseTCPState 解析 TCP 状态
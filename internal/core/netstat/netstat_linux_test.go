@@ -0,0 +1,109 @@
+//go:build linux
+// +build linux
+
+package netstat
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProcEntry 在伪造的 proc 目录下创建进程的 comm 与 fd 链接
+func writeProcEntry(t *testing.T, root, pid, comm string, links map[string]string) {
+	t.Helper()
+	fdDir := filepath.Join(root, pid, "fd")
+	require.NoError(t, os.MkdirAll(fdDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, pid, "comm"), []byte(comm+"\n"), 0o644))
+	for fd, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(fdDir, fd)))
+	}
+}
+
+func TestFindSocketOwners(t *testing.T) {
+	root := t.TempDir()
+	writeProcEntry(t, root, "101", "nginx", map[string]string{
+		"0": "/dev/null",
+		"6": "socket:[4567]",
+		"7": "socket:[4568]",
+	})
+	writeProcEntry(t, root, "202", "sshd", map[string]string{
+		"3": "socket:[9001]",
+		"4": "pipe:[777]",
+	})
+	// 非数字目录与无 fd 目录的进程应被忽略
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "self", "fd"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "303"), 0o755))
+
+	owners := findSocketOwners(root)
+	require.Equal(t, map[uint64]socketOwner{
+		4567: {pid: 101, name: "nginx"},
+		4568: {pid: 101, name: "nginx"},
+		9001: {pid: 202, name: "sshd"},
+	}, owners)
+
+	conns := []*types.Connection{{Inode: 4568}, {Inode: 9001}, {Inode: 1}, {}}
+	attachProcesses(conns, owners)
+	require.Equal(t, 101, conns[0].PID)
+	require.Equal(t, "nginx", conns[0].ProcessName)
+	require.Equal(t, "sshd", conns[1].ProcessName)
+	require.Zero(t, conns[2].PID)
+	require.Zero(t, conns[3].PID)
+}
+
+func TestReadTCPConnectionsParsesInode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tcp")
+	content := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n" +
+		"   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 4567 1 0000000000000000 100 0 0 10 0\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	conns, err := (&linuxReader{}).readTCPConnections(path)
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	require.Equal(t, 8080, conns[0].LocalPort)
+	require.Equal(t, types.StateListen, conns[0].State)
+	require.Equal(t, uint64(4567), conns[0].Inode)
+}
+
+func TestGetConnectionsIncludesOwnProcess(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	conns, err := NewNetStatReader().GetConnections(&types.NetStatOptions{
+		Protocol:       "tcp",
+		LocalPort:      port,
+		IncludeProcess: true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, conns)
+	require.Equal(t, os.Getpid(), conns[0].PID)
+	require.NotEmpty(t, conns[0].ProcessName)
+}
+
+func TestParseIPv6(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want string
+	}{
+		{"00000000000000000000000000000000", "::"},
+		{"00000000000000000000000001000000", "::1"},
+		{"0000000000000000FFFF00000100007F", "::ffff:127.0.0.1"},
+		{"0000000000000000FFFF00000A01A8C0", "::ffff:192.168.1.10"},
+		{"B80D0120000000000000000001000000", "2001:db8::1"},
+		{"000080FE00000000FF0E4202FE01A8C0", "fe80::242:eff:c0a8:1fe"},
+		{"B80D0120785634120000000000000000", "2001:db8:1234:5678::"},
+		{"short", "::"},
+		{"ZZ0D0120000000000000000001000000", "::"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			require.Equal(t, tt.want, parseIPv6(tt.hex))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+//go:build linux
+// +build linux
+
+package netstat
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// parseSNMP 解析 /proc/net/snmp 与 /proc/net/netstat 格式
+//
+// 文件由成对的行组成: 第一行为 "前缀: 字段名..."，第二行为 "前缀: 数值..."。
+// 返回 前缀 -> 字段名 -> 数值，负数（如 Tcp MaxConn 的 -1）被忽略。
+func parseSNMP(r io.Reader) (map[string]map[string]uint64, error) {
+	result := make(map[string]map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var header []string
+	var headerPrefix string
+	for scanner.Scan() {
+		prefix, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+
+		if header == nil || prefix != headerPrefix {
+			header, headerPrefix = fields, prefix
+			continue
+		}
+
+		values := result[prefix]
+		if values == nil {
+			values = make(map[string]uint64)
+			result[prefix] = values
+		}
+		for i, name := range header {
+			if i >= len(fields) {
+				break
+			}
+			if v, err := strconv.ParseUint(fields[i], 10, 64); err == nil {
+				values[name] = v
+			}
+		}
+		header = nil
+	}
+	return result, scanner.Err()
+}
+
+// stackCountersFrom 从解析结果中提取 TCP/UDP 计数器
+func stackCountersFrom(snmp, ext map[string]map[string]uint64) *types.StackCounters {
+	tcp, udp, tcpExt := snmp["Tcp"], snmp["Udp"], ext["TcpExt"]
+	return &types.StackCounters{
+		TCPActiveOpens:     tcp["ActiveOpens"],
+		TCPPassiveOpens:    tcp["PassiveOpens"],
+		TCPAttemptFails:    tcp["AttemptFails"],
+		TCPEstabResets:     tcp["EstabResets"],
+		TCPInSegs:          tcp["InSegs"],
+		TCPOutSegs:         tcp["OutSegs"],
+		TCPRetransSegs:     tcp["RetransSegs"],
+		TCPInErrs:          tcp["InErrs"],
+		TCPOutRsts:         tcp["OutRsts"],
+		TCPListenOverflows: tcpExt["ListenOverflows"],
+		TCPListenDrops:     tcpExt["ListenDrops"],
+		TCPTimeouts:        tcpExt["TCPTimeouts"],
+		UDPInDatagrams:     udp["InDatagrams"],
+		UDPOutDatagrams:    udp["OutDatagrams"],
+		UDPNoPorts:         udp["NoPorts"],
+		UDPInErrors:        udp["InErrors"],
+		UDPRcvbufErrors:    udp["RcvbufErrors"],
+		UDPSndbufErrors:    udp["SndbufErrors"],
+	}
+}
+
+// readStackCounters 读取协议栈计数器，/proc/net/snmp 不可读时返回 nil
+func readStackCounters() *types.StackCounters {
+	snmp, err := readSNMPFile(types.ProcNetSNMP)
+	if err != nil {
+		return nil
+	}
+	// /proc/net/netstat 仅提供扩展计数器，缺失时对应字段为 0
+	ext, _ := readSNMPFile(types.ProcNetNetstat)
+	return stackCountersFrom(snmp, ext)
+}
+
+func readSNMPFile(path string) (map[string]map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseSNMP(file)
+}
@@ -0,0 +1,69 @@
+//go:build linux
+// +build linux
+
+package netstat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleSNMP = `Ip: Forwarding DefaultTTL InReceives InHdrErrors
+Ip: 2 64 327318 0
+Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens PassiveOpens AttemptFails EstabResets CurrEstab InSegs OutSegs RetransSegs InErrs OutRsts InCsumErrors
+Tcp: 1 200 120000 -1 5939 730 5205 110 2 320316 320340 17 3 5268 0
+Udp: InDatagrams NoPorts InErrors OutDatagrams RcvbufErrors SndbufErrors InCsumErrors IgnoredMulti MemErrors
+Udp: 4433 24 2 4727 1 0 0 0 0
+UdpLite: InDatagrams NoPorts InErrors OutDatagrams RcvbufErrors SndbufErrors InCsumErrors IgnoredMulti MemErrors
+UdpLite: 0 0 0 0 0 0 0 0 0
+`
+
+const sampleNetstat = `TcpExt: SyncookiesSent ListenOverflows ListenDrops TCPTimeouts
+TcpExt: 0 4 5 9
+IpExt: InNoRoutes InTruncatedPkts
+IpExt: 0 0
+`
+
+func TestParseSNMP(t *testing.T) {
+	snmp, err := parseSNMP(strings.NewReader(sampleSNMP))
+	require.NoError(t, err)
+	require.Equal(t, uint64(327318), snmp["Ip"]["InReceives"])
+	// MaxConn 为 -1，不应出现在结果中
+	_, ok := snmp["Tcp"]["MaxConn"]
+	require.False(t, ok)
+
+	ext, err := parseSNMP(strings.NewReader(sampleNetstat))
+	require.NoError(t, err)
+
+	require.Equal(t, &types.StackCounters{
+		TCPActiveOpens:     5939,
+		TCPPassiveOpens:    730,
+		TCPAttemptFails:    5205,
+		TCPEstabResets:     110,
+		TCPInSegs:          320316,
+		TCPOutSegs:         320340,
+		TCPRetransSegs:     17,
+		TCPInErrs:          3,
+		TCPOutRsts:         5268,
+		TCPListenOverflows: 4,
+		TCPListenDrops:     5,
+		TCPTimeouts:        9,
+		UDPInDatagrams:     4433,
+		UDPOutDatagrams:    4727,
+		UDPNoPorts:         24,
+		UDPInErrors:        2,
+		UDPRcvbufErrors:    1,
+	}, stackCountersFrom(snmp, ext))
+}
+
+func TestStackCountersWithoutExtendedFile(t *testing.T) {
+	snmp, err := parseSNMP(strings.NewReader(sampleSNMP))
+	require.NoError(t, err)
+
+	counters := stackCountersFrom(snmp, nil)
+	require.Equal(t, uint64(17), counters.TCPRetransSegs)
+	require.Zero(t, counters.TCPListenOverflows)
+}
@@ -0,0 +1,48 @@
+package ping
+
+import (
+	"context"
+	"errors"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// withDeadline 按 opts.Deadline 限制总运行时间，Deadline <= 0 时只派生可取消的上下文
+func withDeadline(ctx context.Context, opts *types.PingOptions) (context.Context, context.CancelFunc) {
+	if opts.Deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, opts.Deadline)
+}
+
+// deadlineReached 判断 runCtx 是否因 Deadline 到期结束，而非上层取消（如 Ctrl+C）
+func deadlineReached(ctx, runCtx context.Context) bool {
+	return ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded)
+}
+
+// moreProbes 判断是否发送第 i 个探测；Count <= 0 时仅在设置了 Deadline 时持续发送直到到期
+func moreProbes(opts *types.PingOptions, i int) bool {
+	if opts.Count > 0 {
+		return i < opts.Count
+	}
+	return opts.Deadline > 0
+}
+
+// lastProbe 判断第 i 个探测是否为最后一个，最后一个探测之后不再等待发送间隔
+func lastProbe(opts *types.PingOptions, i int) bool {
+	return opts.Count > 0 && i >= opts.Count-1
+}
+
+// markStopped 记录提前结束的原因：Deadline 到期属于正常结束，上层取消记为失败
+func markStopped(ctx, runCtx context.Context, result *types.PingResult) {
+	if deadlineReached(ctx, runCtx) {
+		return
+	}
+	result.Error = runCtx.Err()
+	result.Status = types.StatusFailure
+}
+
+// interruptedByDeadline 判断探测是否被 Deadline 打断，此类探测不计入统计
+func interruptedByDeadline(ctx, runCtx context.Context, reply *types.PingReply) bool {
+	return reply.Status != types.StatusSuccess && deadlineReached(ctx, runCtx)
+}
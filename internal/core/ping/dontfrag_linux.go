@@ -0,0 +1,26 @@
+//go:build linux
+// +build linux
+
+package ping
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// setDontFragment 为 IPv4 原始套接字设置 DF 位。使用 PROBE 模式忽略内核缓存的路径 MTU，
+// 超过出口网卡 MTU 的报文在发送时直接返回 EMSGSIZE
+func setDontFragment(conn *net.IPConn) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_PROBE)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
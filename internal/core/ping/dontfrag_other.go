@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package ping
+
+import (
+	"net"
+	"runtime"
+
+	"github.com/catsayer/ntx/pkg/errors"
+)
+
+// setDontFragment 当前仅 Linux 支持为原始套接字设置 DF 位
+func setDontFragment(conn *net.IPConn) error {
+	return errors.NewNotSupportedError("don't fragment", runtime.GOOS)
+}
@@ -22,6 +22,9 @@ func (Factory) Create(opts *types.PingOptions) (types.Pinger, error) {
 	if opts == nil {
 		opts = types.DefaultPingOptions()
 	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = types.DefaultPingTimeoutFor(opts.Protocol)
+	}
 
 	switch opts.Protocol {
 	case types.ProtocolICMP:
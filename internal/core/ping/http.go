@@ -11,8 +11,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -30,13 +32,17 @@ type HTTPPinger struct {
 
 // NewHTTPPinger 创建 HTTP Pinger
 func NewHTTPPinger(opts *types.PingOptions) *HTTPPinger {
-	return &HTTPPinger{
-		client: &http.Client{
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
 		},
 	}
+	if opts != nil && len(opts.HTTPResolve) > 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = netutil.OverrideDialContext(opts.HTTPResolve, (&net.Dialer{}).DialContext)
+		client.Transport = transport
+	}
+	return &HTTPPinger{client: client}
 }
 
 // Ping 执行 HTTP Ping
@@ -53,12 +59,15 @@ func (p *HTTPPinger) Ping(ctx context.Context, target string, opts *types.PingOp
 		return nil, err
 	}
 
-	hostInfo, err := netutil.ResolveHost(targetURL.Hostname(), opts.IPVersion)
+	check, err := newResponseCheck(opts)
 	if err != nil {
-		return nil, errors.NewNetworkError("resolve", target, err)
+		return nil, err
 	}
 
-	p.client.Timeout = opts.Timeout
+	hostInfo, err := p.resolveTarget(targetURL, opts)
+	if err != nil {
+		return nil, errors.NewNetworkError("resolve", target, err)
+	}
 
 	result := &types.PingResult{
 		Target: &types.Host{
@@ -83,24 +92,28 @@ func (p *HTTPPinger) Ping(ctx context.Context, target string, opts *types.PingOp
 	hostname, _ := os.Hostname()
 	result.Context.Hostname = hostname
 
-	for i := 0; i < opts.Count; i++ {
+	runCtx, cancel := withDeadline(ctx, opts)
+	defer cancel()
+
+	for i := 0; moreProbes(opts, i); i++ {
 		select {
-		case <-ctx.Done():
-			result.Error = ctx.Err()
-			result.Status = types.StatusFailure
+		case <-runCtx.Done():
+			markStopped(ctx, runCtx, result)
 			goto end
 		default:
 		}
 
-		reply := p.pingOnce(ctx, targetURL, i+1, opts)
+		reply := p.pingOnce(runCtx, targetURL, i+1, opts, check)
+		if interruptedByDeadline(ctx, runCtx, reply) {
+			goto end
+		}
 		result.AddReply(reply)
 
-		if i < opts.Count-1 {
+		if !lastProbe(opts, i) {
 			select {
-			case <-time.After(opts.Interval):
-			case <-ctx.Done():
-				result.Error = ctx.Err()
-				result.Status = types.StatusFailure
+			case <-time.After(nextInterval(opts)):
+			case <-runCtx.Done():
+				markStopped(ctx, runCtx, result)
 				goto end
 			}
 		}
@@ -137,27 +150,35 @@ func (p *HTTPPinger) PingStream(ctx context.Context, target string, opts *types.
 		return nil, err
 	}
 
-	p.client.Timeout = opts.Timeout
+	check, err := newResponseCheck(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	replyChan := make(chan *types.PingReply)
 
 	go func() {
 		defer close(replyChan)
+		runCtx, cancel := withDeadline(ctx, opts)
+		defer cancel()
 
 		for i := 0; opts.Count <= 0 || i < opts.Count; i++ {
 			select {
-			case <-ctx.Done():
+			case <-runCtx.Done():
 				return
 			default:
 			}
 
-			reply := p.pingOnce(ctx, targetURL, i+1, opts)
+			reply := p.pingOnce(runCtx, targetURL, i+1, opts, check)
+			if interruptedByDeadline(ctx, runCtx, reply) {
+				return
+			}
 			replyChan <- reply
 
 			if opts.Count <= 0 || i < opts.Count-1 {
 				select {
-				case <-time.After(opts.Interval):
-				case <-ctx.Done():
+				case <-time.After(nextInterval(opts)):
+				case <-runCtx.Done():
 					return
 				}
 			}
@@ -168,7 +189,7 @@ func (p *HTTPPinger) PingStream(ctx context.Context, target string, opts *types.
 }
 
 // pingOnce 执行一次 HTTP Ping
-func (p *HTTPPinger) pingOnce(ctx context.Context, targetURL *url.URL, seq int, opts *types.PingOptions) *types.PingReply {
+func (p *HTTPPinger) pingOnce(ctx context.Context, targetURL *url.URL, seq int, opts *types.PingOptions, check *responseCheck) *types.PingReply {
 	reply := &types.PingReply{
 		Seq:    seq,
 		From:   targetURL.Host,
@@ -181,6 +202,15 @@ func (p *HTTPPinger) pingOnce(ctx context.Context, targetURL *url.URL, seq int,
 		method = "GET"
 	}
 
+	// 超时按请求设置：Pinger 在多个目标间共享，不能修改 http.Client.Timeout
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	timer := &netutil.PhaseTimer{}
+	ctx = httptrace.WithClientTrace(ctx, timer.Trace())
 	req, err := http.NewRequestWithContext(ctx, method, targetURL.String(), nil)
 	if err != nil {
 		reply.Status = types.StatusFailure
@@ -188,7 +218,7 @@ func (p *HTTPPinger) pingOnce(ctx context.Context, targetURL *url.URL, seq int,
 		return reply
 	}
 
-	req.Header.Set("User-Agent", buildinfo.UserAgent())
+	applyRequestHeaders(req, opts)
 
 	start := time.Now()
 	resp, err := p.client.Do(req)
@@ -206,6 +236,10 @@ func (p *HTTPPinger) pingOnce(ctx context.Context, targetURL *url.URL, seq int,
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	resp.Body.Close()
+	reply.HTTPTimings = timer.Timings(start, time.Now())
+	if resp.TLS != nil && !reply.HTTPTimings.Reused {
+		reply.TLS = netutil.TLSInfoFromState(resp.TLS, time.Now())
+	}
 
 	if err != nil {
 		reply.Status = types.StatusFailure
@@ -216,14 +250,81 @@ func (p *HTTPPinger) pingOnce(ctx context.Context, targetURL *url.URL, seq int,
 	reply.Bytes = len(bodyBytes)
 	reply.From = fmt.Sprintf("%s (status: %d)", targetURL.Host, resp.StatusCode)
 
-	if resp.StatusCode >= 400 {
+	if err := check.validate(resp.StatusCode, bodyBytes); err != nil {
 		reply.Status = types.StatusFailure
-		reply.Error = fmt.Sprintf("HTTP %d %s", resp.StatusCode, resp.Status)
+		reply.Error = err.Error()
 	}
 
 	return reply
 }
 
+// applyRequestHeaders 设置附加请求头与 User-Agent，--user-agent 优先于 -H 中的 User-Agent
+func applyRequestHeaders(req *http.Request, opts *types.PingOptions) {
+	req.Header.Set("User-Agent", buildinfo.UserAgent())
+	for key, value := range opts.HTTPHeaders {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+	if opts.HTTPUserAgent != "" {
+		req.Header.Set("User-Agent", opts.HTTPUserAgent)
+	}
+}
+
+// responseCheck HTTP 响应校验规则
+type responseCheck struct {
+	statuses []int
+	body     *regexp.Regexp
+}
+
+// newResponseCheck 根据选项构造校验规则，正则无效时返回错误
+func newResponseCheck(opts *types.PingOptions) (*responseCheck, error) {
+	check := &responseCheck{statuses: opts.HTTPExpectStatus}
+	if opts.HTTPExpectBody != "" {
+		re, err := regexp.Compile(opts.HTTPExpectBody)
+		if err != nil {
+			return nil, fmt.Errorf("无效的响应体正则 %q: %w", opts.HTTPExpectBody, err)
+		}
+		check.body = re
+	}
+	return check, nil
+}
+
+// validate 校验状态码与响应体，未指定期望状态码时 >= 400 视为失败
+func (c *responseCheck) validate(statusCode int, body []byte) error {
+	if len(c.statuses) == 0 {
+		if statusCode >= 400 {
+			return fmt.Errorf("HTTP %d %s", statusCode, http.StatusText(statusCode))
+		}
+	} else if !containsInt(c.statuses, statusCode) {
+		return fmt.Errorf("unexpected status %d (expected %s)", statusCode, joinInts(c.statuses))
+	}
+
+	if c.body != nil && !c.body.Match(body) {
+		return fmt.Errorf("body does not match %q", c.body.String())
+	}
+	return nil
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
+
 // parseURL 解析 URL
 func (p *HTTPPinger) parseURL(target string, opts *types.PingOptions) (*url.URL, error) {
 	if !strings.Contains(target, "://") {
@@ -253,6 +354,18 @@ func (p *HTTPPinger) parseURL(target string, opts *types.PingOptions) (*url.URL,
 	return u, nil
 }
 
+// resolveTarget 解析目标地址，--resolve 覆盖优先于 DNS
+func (p *HTTPPinger) resolveTarget(u *url.URL, opts *types.PingOptions) (*types.Host, error) {
+	if ip, ok := netutil.LookupOverride(opts.HTTPResolve, u.Hostname(), p.getPort(u)); ok {
+		version := types.IPv4
+		if net.ParseIP(ip).To4() == nil {
+			version = types.IPv6
+		}
+		return &types.Host{Hostname: u.Hostname(), IP: ip, IPVersion: version}, nil
+	}
+	return netutil.ResolveHost(u.Hostname(), opts.IPVersion)
+}
+
 // getPort 获取端口号
 func (p *HTTPPinger) getPort(u *url.URL) int {
 	port := u.Port()
@@ -0,0 +1,164 @@
+package ping
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/buildinfo"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCheck(t *testing.T) {
+	defaults, err := newResponseCheck(types.DefaultPingOptions())
+	require.NoError(t, err)
+	require.NoError(t, defaults.validate(301, nil))
+	require.EqualError(t, defaults.validate(503, nil), "HTTP 503 Service Unavailable")
+
+	opts := types.DefaultPingOptions()
+	opts.HTTPExpectStatus = []int{200, 301}
+	opts.HTTPExpectBody = `"status":\s*"ok"`
+	check, err := newResponseCheck(opts)
+	require.NoError(t, err)
+
+	require.NoError(t, check.validate(200, []byte(`{"status": "ok"}`)))
+	require.EqualError(t, check.validate(204, nil), "unexpected status 204 (expected 200,301)")
+	require.ErrorContains(t, check.validate(301, []byte(`{"status": "degraded"}`)), "body does not match")
+
+	opts.HTTPExpectBody = "("
+	_, err = newResponseCheck(opts)
+	require.Error(t, err)
+}
+
+func TestApplyRequestHeaders(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://192.0.2.1/", nil)
+	require.NoError(t, err)
+
+	applyRequestHeaders(req, types.DefaultPingOptions())
+	require.Equal(t, buildinfo.UserAgent(), req.Header.Get("User-Agent"))
+
+	opts := types.DefaultPingOptions()
+	opts.HTTPHeaders = map[string]string{
+		"Authorization": "Bearer x",
+		"User-Agent":    "from-header",
+		"host":          "example.com",
+	}
+	opts.HTTPUserAgent = "custom/1.0"
+	applyRequestHeaders(req, opts)
+	require.Equal(t, "custom/1.0", req.Header.Get("User-Agent"))
+	require.Equal(t, "Bearer x", req.Header.Get("Authorization"))
+	require.Equal(t, "example.com", req.Host)
+}
+
+func TestHTTPPingResolveOverrideKeepsHost(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+	}))
+	defer srv.Close()
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	opts := types.DefaultPingOptions()
+	opts.Count = 1
+	opts.Timeout = time.Second
+	opts.HTTPResolve, err = netutil.ParseResolveOverrides([]string{"backend.invalid:" + port + ":127.0.0.1"})
+	require.NoError(t, err)
+
+	result, err := NewHTTPPinger(opts).Ping(context.Background(), "http://backend.invalid:"+port+"/", opts)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1", result.Target.IP)
+	require.Equal(t, 1, result.Statistics.Received)
+	require.Equal(t, "backend.invalid:"+port, gotHost)
+}
+
+func TestHTTPPingRecordsPhaseTimings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		// 响应头之后延迟写出响应体，使总耗时明显大于首字节时间
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	opts := types.DefaultPingOptions()
+	opts.Count = 1
+	opts.Timeout = time.Second
+
+	result, err := NewHTTPPinger(opts).Ping(context.Background(), srv.URL, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Replies, 1)
+
+	timings := result.Replies[0].HTTPTimings
+	require.NotNil(t, timings)
+	require.False(t, timings.Reused)
+	require.Positive(t, timings.Connect)
+	require.Positive(t, timings.TTFB)
+	require.Less(t, timings.TTFB, timings.Total)
+	require.Zero(t, timings.TLS)
+}
+
+func TestHTTPPingCapturesCertificateOnHandshake(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	pinger := NewHTTPPinger(nil)
+	pinger.client.Transport = srv.Client().Transport
+
+	opts := types.DefaultPingOptions()
+	opts.Count = 2
+	opts.Interval = 10 * time.Millisecond
+	result, err := pinger.Ping(context.Background(), srv.URL, opts)
+	require.NoError(t, err)
+	require.Equal(t, types.ProtocolHTTPS, result.Protocol)
+	require.Len(t, result.Replies, 2)
+
+	leaf := result.Replies[0].TLS.Leaf()
+	require.NotNil(t, leaf)
+	require.Contains(t, leaf.DNSNames, "example.com")
+	// 复用连接时不重复报告证书
+	require.True(t, result.Replies[1].HTTPTimings.Reused)
+	require.Nil(t, result.Replies[1].TLS)
+}
+
+func TestHTTPPingSharedPingerConcurrentTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	pinger := NewHTTPPinger(nil)
+	run := func(path string, timeout time.Duration) <-chan *types.PingResult {
+		ch := make(chan *types.PingResult, 1)
+		go func() {
+			opts := types.DefaultPingOptions()
+			opts.Count = 1
+			opts.Timeout = timeout
+			result, err := pinger.Ping(context.Background(), srv.URL+path, opts)
+			if err != nil {
+				t.Error(err)
+			}
+			ch <- result
+		}()
+		return ch
+	}
+
+	// 同一 Pinger 并发使用不同超时，各自的超时互不影响
+	slow := run("/slow", 50*time.Millisecond)
+	fast := run("/", time.Second)
+
+	require.Equal(t, types.StatusTimeout, (<-slow).Replies[0].Status)
+	require.Equal(t, types.StatusSuccess, (<-fast).Replies[0].Status)
+}
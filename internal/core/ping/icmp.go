@@ -27,6 +27,8 @@ import (
 )
 
 const (
+	// dupBufferSize 每次 Ping 缓存的待处理重复响应数，超出部分丢弃
+	dupBufferSize = 16
 	// ProtocolICMP ICMP 协议号
 	ProtocolICMP = 1
 	// ProtocolIPv6ICMP ICMPv6 协议号
@@ -35,9 +37,16 @@ const (
 
 // ICMPPinger ICMP Ping 实现
 type ICMPPinger struct {
-	conn4 *icmp.PacketConn
+	// conn4 使用原始 IPConn 而非 icmp.PacketConn，以便读取响应的 IPv4 头
+	conn4 *net.IPConn
 	conn6 *icmp.PacketConn
 	id    int
+	// demux4/demux6 各自由单个读取协程分发响应，Pinger 可被多个协程并发使用
+	demux4 *demux
+	demux6 *demux
+	// sourceErr4/sourceErr6 指定的源地址无法用于该地址族时的错误，Ping 对应地址族的目标时返回
+	sourceErr4 error
+	sourceErr6 error
 }
 
 // NewICMPPinger 创建 ICMP Pinger
@@ -45,25 +54,69 @@ func NewICMPPinger(opts *types.PingOptions) (*ICMPPinger, error) {
 	p := &ICMPPinger{
 		id: os.Getpid() & types.ICMPIDMask,
 	}
+	if opts != nil && opts.ICMPID > 0 {
+		p.id = opts.ICMPID & types.ICMPIDMask
+	}
+
+	// 指定源地址时将各地址族的套接字绑定到对应地址
+	var laddr4 *net.IPAddr
+	laddr6 := ""
+	if opts != nil && opts.Source != "" {
+		ip4, err4 := netutil.ResolveSourceAddr(opts.Source, types.IPv4)
+		ip6, err6 := netutil.ResolveSourceAddr(opts.Source, types.IPv6)
+		if err4 != nil && err6 != nil {
+			return nil, err4
+		}
+		p.sourceErr4, p.sourceErr6 = err4, err6
+		if ip4 != nil {
+			laddr4 = &net.IPAddr{IP: ip4}
+		}
+		if ip6 != nil {
+			laddr6 = ip6.String()
+			if ip6.IsLinkLocalUnicast() && netutil.SourceIPVersion(opts.Source) == types.IPvAny {
+				laddr6 += "%" + opts.Source
+			}
+		}
+	}
 
 	// 打开 ICMPv4 连接
-	conn4, err := icmp.ListenPacket("ip4:icmp", "")
+	conn4, err := net.ListenIP("ip4:icmp", laddr4)
 	if err != nil {
+		if laddr4 != nil && !os.IsPermission(err) {
+			return nil, fmt.Errorf("绑定源地址 %s 失败: %w", laddr4.IP, err)
+		}
 		return nil, errors.NewPermissionError("icmp ping", "raw socket", getPermissionHint())
 	}
+	if opts != nil && opts.DontFragment {
+		if err := setDontFragment(conn4); err != nil {
+			conn4.Close()
+			return nil, err
+		}
+	}
 	p.conn4 = conn4
+	p.demux4 = newDemux()
+	// 接收响应的 TTL；Linux 原始套接字保留 IP 头时直接从头部读取，此处为剥离头部的平台兜底
+	_ = ipv4.NewPacketConn(conn4).SetControlMessage(ipv4.FlagTTL, true)
 
 	// 尝试打开 ICMPv6 连接（可选）
-	conn6, err := icmp.ListenPacket("ip6:ipv6-icmp", "")
+	conn6, err := icmp.ListenPacket("ip6:ipv6-icmp", laddr6)
 	if err != nil {
 		p.conn6 = nil
 	} else {
 		p.conn6 = conn6
+		// 接收 Traffic Class 与 Hop Limit 以便回显 DSCP 标记与响应的 TTL，失败时仅不显示
+		_ = conn6.IPv6PacketConn().SetControlMessage(ipv6.FlagTrafficClass|ipv6.FlagHopLimit, true)
+	}
+
+	go p.readLoop(true, p.demux4)
+	if p.conn6 != nil {
+		p.demux6 = newDemux()
+		go p.readLoop(false, p.demux6)
 	}
 
 	// 设置 TOS (仅 IPv4 支持)
 	if opts.TOS > 0 && p.conn4 != nil {
-		if err := p.conn4.IPv4PacketConn().SetTOS(opts.TOS); err != nil {
+		if err := ipv4.NewPacketConn(p.conn4).SetTOS(opts.TOS); err != nil {
 			logger.Warn("无法为 IPv4 设置 TOS", zap.Error(err))
 		}
 	}
@@ -71,6 +124,22 @@ func NewICMPPinger(opts *types.PingOptions) (*ICMPPinger, error) {
 	return p, nil
 }
 
+// sourceErr 返回源地址与目标地址族不匹配的错误
+func (p *ICMPPinger) sourceErr(version types.IPVersion) error {
+	if version == types.IPv6 {
+		return p.sourceErr6
+	}
+	return p.sourceErr4
+}
+
+// startSeq 返回首个探测的序列号，未指定时从 1 开始
+func startSeq(opts *types.PingOptions) int {
+	if opts.StartSeq > 0 {
+		return opts.StartSeq
+	}
+	return 1
+}
+
 // getPermissionHint 根据操作系统返回权限提示
 func getPermissionHint() string {
 	switch runtime.GOOS {
@@ -105,6 +174,9 @@ func (p *ICMPPinger) Ping(ctx context.Context, target string, opts *types.PingOp
 	if hostInfo.IPVersion == types.IPv6 && p.conn6 == nil {
 		return nil, errors.NewPermissionError("icmp ping", "ipv6", "connection not available")
 	}
+	if err := p.sourceErr(hostInfo.IPVersion); err != nil {
+		return nil, err
+	}
 
 	result := &types.PingResult{
 		Target: &types.Host{
@@ -124,30 +196,35 @@ func (p *ICMPPinger) Ping(ctx context.Context, target string, opts *types.PingOp
 	hostname, _ := os.Hostname()
 	result.Context.Hostname = hostname
 
-	for i := 0; i < opts.Count; i++ {
+	dups := make(chan icmpPacket, dupBufferSize)
+	addDup := func(pkt icmpPacket) { result.AddReply(duplicateReply(pkt)) }
+	runCtx, cancel := withDeadline(ctx, opts)
+	defer cancel()
+
+	for i := 0; moreProbes(opts, i); i++ {
 		select {
-		case <-ctx.Done():
-			result.Error = ctx.Err()
-			result.Status = types.StatusFailure
+		case <-runCtx.Done():
+			markStopped(ctx, runCtx, result)
 			goto end
 		default:
 		}
 
-		reply := p.pingOnce(ctx, hostInfo.IP, i+1, opts)
+		reply := p.pingOnce(runCtx, hostInfo.IP, startSeq(opts)+i, opts, dups)
+		if interruptedByDeadline(ctx, runCtx, reply) {
+			goto end
+		}
 		result.AddReply(reply)
 
-		if i < opts.Count-1 {
-			select {
-			case <-time.After(opts.Interval):
-			case <-ctx.Done():
-				result.Error = ctx.Err()
-				result.Status = types.StatusFailure
+		if !lastProbe(opts, i) {
+			if waitNext(runCtx, nextInterval(opts), dups, addDup) != nil {
+				markStopped(ctx, runCtx, result)
 				goto end
 			}
 		}
 	}
 
 end:
+	drainDups(dups, addDup)
 	result.Context.EndTime = time.Now()
 	result.Context.Duration = result.Context.EndTime.Sub(result.Context.StartTime)
 	result.UpdateStatistics()
@@ -184,42 +261,55 @@ func (p *ICMPPinger) PingStream(ctx context.Context, target string, opts *types.
 	if hostInfo.IPVersion == types.IPv6 && p.conn6 == nil {
 		return nil, errors.NewPermissionError("icmp ping", "ipv6", "connection not available")
 	}
+	if err := p.sourceErr(hostInfo.IPVersion); err != nil {
+		return nil, err
+	}
 
 	replyChan := make(chan *types.PingReply)
 
 	go func() {
 		defer close(replyChan)
+		runCtx, cancel := withDeadline(ctx, opts)
+		defer cancel()
 
-		for i := 0; opts.Count <= 0 || i < opts.Count; i++ {
+		dups := make(chan icmpPacket, dupBufferSize)
+		sendDup := func(pkt icmpPacket) {
 			select {
+			case replyChan <- duplicateReply(pkt):
 			case <-ctx.Done():
+			}
+		}
+		for i := 0; opts.Count <= 0 || i < opts.Count; i++ {
+			select {
+			case <-runCtx.Done():
 				return
 			default:
 			}
 
-			reply := p.pingOnce(ctx, hostInfo.IP, i+1, opts)
+			reply := p.pingOnce(runCtx, hostInfo.IP, startSeq(opts)+i, opts, dups)
+			if interruptedByDeadline(ctx, runCtx, reply) {
+				return
+			}
 			replyChan <- reply
 
 			if opts.Count <= 0 || i < opts.Count-1 {
-				select {
-				case <-time.After(opts.Interval):
-				case <-ctx.Done():
+				if waitNext(runCtx, nextInterval(opts), dups, sendDup) != nil {
 					return
 				}
 			}
 		}
+		drainDups(dups, sendDup)
 	}()
 
 	return replyChan, nil
 }
 
-// pingOnce 执行一次 ICMP Ping
-func (p *ICMPPinger) pingOnce(ctx context.Context, ip string, seq int, opts *types.PingOptions) *types.PingReply {
+// pingOnce 执行一次 ICMP Ping，首个响应之后超时前到达的同序号响应发送到 dups
+func (p *ICMPPinger) pingOnce(ctx context.Context, ip string, seq int, opts *types.PingOptions, dups chan<- icmpPacket) *types.PingReply {
 	reply := &types.PingReply{
 		Seq:    seq,
 		From:   ip,
 		Bytes:  opts.Size,
-		TTL:    opts.TTL,
 		Time:   time.Now(),
 		Status: types.StatusSuccess,
 	}
@@ -231,10 +321,11 @@ func (p *ICMPPinger) pingOnce(ctx context.Context, ip string, seq int, opts *typ
 		return reply
 	}
 
-	var conn *icmp.PacketConn
+	var conn net.PacketConn
 	var msgType icmp.Type
 
-	if dst.IP.To4() != nil {
+	isIPv4 := dst.IP.To4() != nil
+	if isIPv4 {
 		conn = p.conn4
 		msgType = ipv4.ICMPTypeEcho
 	} else {
@@ -263,11 +354,16 @@ func (p *ICMPPinger) pingOnce(ctx context.Context, ip string, seq int, opts *typ
 		return reply
 	}
 
-	deadline := time.Now().Add(opts.Timeout)
-	conn.SetReadDeadline(deadline)
-	conn.SetWriteDeadline(deadline)
-
+	d := p.demux6
+	if isIPv4 {
+		d = p.demux4
+	}
+	// 先登记再发送，避免响应早于登记到达而被丢弃
 	start := time.Now()
+	key := probeKey{dst: dst.IP.String(), seq: seq & 0xffff}
+	w := &waiter{dups: dups, seq: seq, sent: start, expires: start.Add(opts.Timeout)}
+	d.register(key, w)
+	defer d.unregister(key, w)
 
 	if err := ctx.Err(); err != nil {
 		reply.Status = types.StatusFailure
@@ -282,84 +378,119 @@ func (p *ICMPPinger) pingOnce(ctx context.Context, ip string, seq int, opts *typ
 		return reply
 	}
 
-	recvBuf := make([]byte, types.StandardMTU)
-
-	cancelRead := make(chan struct{})
-	defer close(cancelRead)
-	go func() {
-		select {
-		case <-ctx.Done():
-			// 提前唤醒阻塞的 ReadFrom
-			_ = conn.SetReadDeadline(time.Now())
-		case <-cancelRead:
-		}
-	}()
+	timer := time.NewTimer(opts.Timeout)
+	defer timer.Stop()
 
-	for {
-		if err := ctx.Err(); err != nil {
+	select {
+	case pkt := <-w.ch:
+		if icmpErr := parseICMPError(pkt.msg, pkt.raw); icmpErr != nil {
 			reply.Status = types.StatusFailure
-			reply.Error = err.Error()
+			reply.From = pkt.peer
+			reply.Error = icmpErr.message
+			reply.MTU = icmpErr.mtu
 			return reply
 		}
+		reply.RTT = pkt.at.Sub(start)
+		reply.From = pkt.peer
+		reply.Bytes = len(msgBytes)
+		reply.TTL = pkt.ttl
+		reply.ReceivedTOS = pkt.tos
+	case <-timer.C:
+		reply.Status = types.StatusTimeout
+	case <-d.done:
+		reply.Status = types.StatusFailure
+		reply.Error = d.err.Error()
+	case <-ctx.Done():
+		reply.Status = types.StatusFailure
+		reply.Error = ctx.Err().Error()
+	}
+	return reply
+}
 
-		n, peer, err := conn.ReadFrom(recvBuf)
-		if err != nil {
-			if ctx.Err() != nil {
-				reply.Status = types.StatusFailure
-				reply.Error = ctx.Err().Error()
-				return reply
-			}
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				reply.Status = types.StatusTimeout
-			} else {
-				reply.Status = types.StatusFailure
-				reply.Error = err.Error()
-			}
-			return reply
+// waitNext 等待发送间隔，期间到达的重复响应交给 onDup；ctx 取消时返回其错误
+func waitNext(ctx context.Context, interval time.Duration, dups <-chan icmpPacket, onDup func(icmpPacket)) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return nil
+		case pkt := <-dups:
+			onDup(pkt)
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
+}
 
-		rtt := time.Since(start)
-
-		var proto int
-		if dst.IP.To4() != nil {
-			proto = ProtocolICMP
-		} else {
-			proto = ProtocolIPv6ICMP
+// drainDups 处理已到达但尚未处理的重复响应
+func drainDups(dups <-chan icmpPacket, onDup func(icmpPacket)) {
+	for {
+		select {
+		case pkt := <-dups:
+			onDup(pkt)
+		default:
+			return
 		}
+	}
+}
+
+// duplicateReply 将重复响应转换为带 DUP! 标记的 PingReply
+func duplicateReply(pkt icmpPacket) *types.PingReply {
+	return &types.PingReply{
+		Seq:         pkt.seq,
+		From:        pkt.peer,
+		Bytes:       len(pkt.raw),
+		TTL:         pkt.ttl,
+		ReceivedTOS: pkt.tos,
+		RTT:         pkt.at.Sub(pkt.sent),
+		Time:        pkt.at,
+		Status:      types.StatusSuccess,
+		Duplicate:   true,
+	}
+}
 
-		rm, err := icmp.ParseMessage(proto, recvBuf[:n])
+// readReply 读取一个 ICMP 报文，返回 ICMP 负载、来源地址、响应的 TOS/Traffic Class
+// 与 TTL/Hop Limit（0 表示不可用）
+func (p *ICMPPinger) readReply(isIPv4 bool, buf, oob []byte) ([]byte, net.Addr, int, int, error) {
+	if !isIPv4 {
+		n, cm, peer, err := p.conn6.IPv6PacketConn().ReadFrom(buf)
 		if err != nil {
-			continue
+			return nil, nil, 0, 0, err
 		}
-
-		switch rm.Type {
-		case ipv4.ICMPTypeEchoReply, ipv6.ICMPTypeEchoReply:
-			if echo, ok := rm.Body.(*icmp.Echo); ok {
-				if echo.ID == p.id && echo.Seq == seq {
-					reply.RTT = rtt
-					reply.From = peer.String()
-					reply.Bytes = len(msgBytes)
-					// TTL 从 IP 包头获取,默认设置为配置的 TTL
-					reply.TTL = opts.TTL
-
-					return reply
-				}
-			}
-		case ipv4.ICMPTypeDestinationUnreachable, ipv6.ICMPTypeDestinationUnreachable:
-			reply.Status = types.StatusFailure
-			reply.Error = "destination unreachable"
-			return reply
-		case ipv4.ICMPTypeTimeExceeded, ipv6.ICMPTypeTimeExceeded:
-			reply.Status = types.StatusFailure
-			reply.Error = "time exceeded"
-			return reply
+		tos, ttl := 0, 0
+		if cm != nil {
+			tos, ttl = cm.TrafficClass, cm.HopLimit
 		}
+		return buf[:n], peer, tos, ttl, nil
+	}
 
-		if time.Now().After(deadline) {
-			reply.Status = types.StatusTimeout
-			return reply
+	// 原始套接字的 ReadMsgIP 不剥离 IPv4 头，可直接读取 TOS 与 TTL
+	n, oobn, _, peer, err := p.conn4.ReadMsgIP(buf, oob)
+	if err != nil {
+		return nil, nil, 0, 0, err
+	}
+	payload, tos, ttl := splitIPv4Header(buf[:n])
+	if ttl == 0 && oobn > 0 {
+		var cm ipv4.ControlMessage
+		if cm.Parse(oob[:oobn]) == nil {
+			ttl = cm.TTL
 		}
 	}
+	return payload, peer, tos, ttl, nil
+}
+
+// splitIPv4Header 拆分 IPv4 头与负载，返回负载、TOS 与 TTL；
+// 数据不含 IP 头（如 Darwin 剥离了头部）时原样返回，TOS 与 TTL 为 0
+func splitIPv4Header(data []byte) ([]byte, int, int) {
+	if len(data) == 0 || data[0]>>4 != ipv4.Version {
+		return data, 0, 0
+	}
+	h, err := ipv4.ParseHeader(data)
+	if err != nil || h.Len > len(data) {
+		return data, 0, 0
+	}
+	return data[h.Len:], h.TOS, h.TTL
 }
 
 // Close 关闭资源
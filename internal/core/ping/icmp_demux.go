@@ -0,0 +1,241 @@
+package ping
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// icmpPacket 读取协程分发给探测的报文
+type icmpPacket struct {
+	msg *icmp.Message
+	// raw 完整的 ICMP 报文（已剥离 IP 头）
+	raw  []byte
+	peer string
+	tos  int
+	// ttl 响应的 TTL/Hop Limit，0 表示不可用
+	ttl int
+	// at 报文被读取的时间，用于计算 RTT
+	at time.Time
+	// seq/sent 仅重复响应填写：所属探测的逻辑序号与发送时间
+	seq  int
+	sent time.Time
+	// echo 为 Echo Reply；其来源可能不同于目的地址（广播或多地址主机），允许仅按序号匹配
+	echo bool
+}
+
+// probeKey 标识一个在途探测：目的地址 + 16 位序号（标识符由 Pinger 固定）
+type probeKey struct {
+	dst string
+	seq int
+}
+
+// waiter 一个在途探测
+type waiter struct {
+	ch chan icmpPacket
+	// dups 接收首个响应之后的重复响应，nil 表示忽略重复
+	dups chan<- icmpPacket
+	seq  int
+	sent time.Time
+	// expires 之后到达的同序号报文不再视为重复
+	expires time.Time
+}
+
+// demux 每个地址族一个读取协程，按 probeKey 将报文分发给等待的探测，
+// 使并发 Ping 不再争用 ReadDeadline 或读走彼此的响应
+type demux struct {
+	mu      sync.Mutex
+	waiters map[probeKey][]*waiter
+	// answered 已收到首个响应的探测，保留到超时以识别重复响应（DUP!）；
+	// Echo Reply 来自其他地址时按序号查找
+	answered map[probeKey]*waiter
+	// done 读取协程退出（连接关闭）时关闭
+	done chan struct{}
+	err  error
+}
+
+func newDemux() *demux {
+	return &demux{
+		waiters:  make(map[probeKey][]*waiter),
+		answered: make(map[probeKey]*waiter),
+		done:     make(chan struct{}),
+	}
+}
+
+// register 登记等待者；同一 key 有多个等待者时按登记顺序分发
+func (d *demux) register(key probeKey, w *waiter) {
+	w.ch = make(chan icmpPacket, 1)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	for k, a := range d.answered {
+		if now.After(a.expires) {
+			delete(d.answered, k)
+		}
+	}
+	d.waiters[key] = append(d.waiters[key], w)
+}
+
+// unregister 移除尚未收到报文的等待者
+func (d *demux) unregister(key probeKey, w *waiter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ws := d.waiters[key]
+	for i, c := range ws {
+		if c == w {
+			ws = append(ws[:i], ws[i+1:]...)
+			break
+		}
+	}
+	if len(ws) == 0 {
+		delete(d.waiters, key)
+	} else {
+		d.waiters[key] = ws
+	}
+}
+
+// dispatch 将报文交给 key 上最早登记的等待者；无等待者时视为已应答探测的重复响应。
+// Echo Reply 的来源与目的地址不一致时，退回到同序号的等待者；都不匹配则丢弃
+func (d *demux) dispatch(key probeKey, pkt icmpPacket) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ws := d.waiters[key]
+	if len(ws) == 0 {
+		if a, ok := d.answered[key]; ok {
+			d.deliverDup(a, pkt)
+			return
+		}
+		if !pkt.echo {
+			return
+		}
+		seq := key.seq
+		if key, ws = d.waitersBySeq(seq); len(ws) == 0 {
+			// 广播时其他主机对同一序号的应答同样是重复响应
+			if a := d.answeredBySeq(seq, pkt.at); a != nil {
+				d.deliverDup(a, pkt)
+			}
+			return
+		}
+	}
+
+	// 每个等待者只接收一个报文，缓冲为 1，发送不会阻塞
+	w := ws[0]
+	w.ch <- pkt
+	if len(ws) == 1 {
+		delete(d.waiters, key)
+	} else {
+		d.waiters[key] = ws[1:]
+	}
+	if w.dups != nil {
+		d.answered[key] = w
+	}
+}
+
+// deliverDup 将报文作为已应答探测的重复响应交出，超时之后到达的丢弃
+func (d *demux) deliverDup(a *waiter, pkt icmpPacket) {
+	if pkt.at.After(a.expires) {
+		return
+	}
+	pkt.seq, pkt.sent = a.seq, a.sent
+	select {
+	case a.dups <- pkt:
+	default:
+	}
+}
+
+// waitersBySeq 返回同序号、最早发送的在途探测；不同目的地址共用序号时无法区分，按发送顺序分配
+func (d *demux) waitersBySeq(seq int) (probeKey, []*waiter) {
+	var found probeKey
+	var best []*waiter
+	for k, ws := range d.waiters {
+		if k.seq != seq || len(ws) == 0 {
+			continue
+		}
+		if best == nil || ws[0].sent.Before(best[0].sent) {
+			found, best = k, ws
+		}
+	}
+	return found, best
+}
+
+// answeredBySeq 返回同序号、尚未超时且最近发送的已应答探测
+func (d *demux) answeredBySeq(seq int, at time.Time) *waiter {
+	var best *waiter
+	for k, a := range d.answered {
+		if k.seq != seq || at.After(a.expires) {
+			continue
+		}
+		if best == nil || a.sent.After(best.sent) {
+			best = a
+		}
+	}
+	return best
+}
+
+// readLoop 持续读取连接上的 ICMP 报文并分发，直到连接关闭
+func (p *ICMPPinger) readLoop(isIPv4 bool, d *demux) {
+	defer close(d.done)
+
+	proto := ProtocolIPv6ICMP
+	if isIPv4 {
+		proto = ProtocolICMP
+	}
+
+	buf := make([]byte, types.StandardMTU)
+	oob := ipv4.NewControlMessage(ipv4.FlagTTL)
+	for {
+		payload, peer, tos, ttl, err := p.readReply(isIPv4, buf, oob)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			d.err = err
+			return
+		}
+		at := time.Now()
+
+		// 解析结果引用底层缓冲区，需复制后再交给其他协程
+		raw := append([]byte(nil), payload...)
+		rm, err := icmp.ParseMessage(proto, raw)
+		if err != nil {
+			continue
+		}
+		key, ok := p.probeKeyOf(rm, peer)
+		if !ok {
+			continue
+		}
+		echo := rm.Type == ipv4.ICMPTypeEchoReply || rm.Type == ipv6.ICMPTypeEchoReply
+		d.dispatch(key, icmpPacket{msg: rm, raw: raw, peer: peer.String(), tos: tos, ttl: ttl, at: at, echo: echo})
+	}
+}
+
+// probeKeyOf 确定报文所属的探测：Echo Reply 优先按来源地址匹配（dispatch 中可退回仅按序号），
+// 差错报文按引用的原始请求匹配；无法识别或属于其他进程的报文返回 false
+func (p *ICMPPinger) probeKeyOf(rm *icmp.Message, peer net.Addr) (probeKey, bool) {
+	if rm.Type == ipv4.ICMPTypeEchoReply || rm.Type == ipv6.ICMPTypeEchoReply {
+		echo, ok := rm.Body.(*icmp.Echo)
+		if !ok || echo.ID != p.id {
+			return probeKey{}, false
+		}
+		return probeKey{dst: addrIP(peer), seq: echo.Seq}, true
+	}
+
+	id, seq, dst, ok := quotedEcho(rm)
+	if !ok || id != p.id {
+		return probeKey{}, false
+	}
+	return probeKey{dst: dst.String(), seq: seq}, true
+}
+
+// addrIP 返回地址中的 IP 字符串（不含 IPv6 zone），用于与目的地址比较
+func addrIP(addr net.Addr) string {
+	if ipAddr, ok := addr.(*net.IPAddr); ok {
+		return ipAddr.IP.String()
+	}
+	return addr.String()
+}
@@ -0,0 +1,128 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemuxDispatchInRegistrationOrder(t *testing.T) {
+	d := newDemux()
+	key := probeKey{dst: "192.0.2.1", seq: 1}
+	otherKey := probeKey{dst: "192.0.2.2", seq: 1}
+	first, second, other := &waiter{}, &waiter{}, &waiter{}
+	d.register(key, first)
+	d.register(key, second)
+	d.register(otherKey, other)
+
+	d.dispatch(key, icmpPacket{peer: "a"})
+	d.dispatch(key, icmpPacket{peer: "b"})
+	// 无等待者且未要求识别重复时丢弃
+	d.dispatch(key, icmpPacket{peer: "c"})
+
+	require.Equal(t, "a", (<-first.ch).peer)
+	require.Equal(t, "b", (<-second.ch).peer)
+	require.Empty(t, other.ch)
+
+	d.unregister(otherKey, other)
+	require.Empty(t, d.waiters)
+}
+
+func TestDemuxEchoFromOtherSource(t *testing.T) {
+	d := newDemux()
+	key := probeKey{dst: "192.0.2.255", seq: 3}
+	w := &waiter{sent: time.Now()}
+	d.register(key, w)
+
+	// 差错报文必须与引用的目的地址一致
+	d.dispatch(probeKey{dst: "192.0.2.7", seq: 3}, icmpPacket{peer: "192.0.2.1"})
+	require.Empty(t, w.ch)
+
+	// 广播或多地址主机从其他地址应答 Echo 时按序号匹配
+	d.dispatch(probeKey{dst: "192.0.2.7", seq: 4}, icmpPacket{peer: "192.0.2.7", echo: true})
+	require.Empty(t, w.ch)
+	d.dispatch(probeKey{dst: "192.0.2.7", seq: 3}, icmpPacket{peer: "192.0.2.7", echo: true})
+	require.Equal(t, "192.0.2.7", (<-w.ch).peer)
+	require.Empty(t, d.waiters)
+}
+
+func TestDemuxDuplicates(t *testing.T) {
+	d := newDemux()
+	key := probeKey{dst: "192.0.2.1", seq: 7}
+	dups := make(chan icmpPacket, 4)
+	sent := time.Now()
+	w := &waiter{dups: dups, seq: 65543, sent: sent, expires: sent.Add(time.Second)}
+	d.register(key, w)
+
+	d.dispatch(key, icmpPacket{peer: "192.0.2.1", at: sent.Add(time.Millisecond)})
+	d.dispatch(key, icmpPacket{peer: "192.0.2.1", at: sent.Add(2 * time.Millisecond)})
+	// 超时之后到达的不再视为重复
+	d.dispatch(key, icmpPacket{peer: "192.0.2.1", at: sent.Add(2 * time.Second)})
+
+	require.Len(t, w.ch, 1)
+	require.Len(t, dups, 1)
+	dup := <-dups
+	require.Equal(t, 65543, dup.seq)
+	require.Equal(t, 2*time.Millisecond, dup.at.Sub(dup.sent))
+}
+
+func TestICMPPingerConcurrentTargets(t *testing.T) {
+	opts := types.DefaultPingOptions()
+	opts.Count = 5
+	opts.Interval = time.Millisecond
+	opts.Timeout = time.Second
+
+	p, err := NewICMPPinger(opts)
+	if err != nil {
+		t.Skipf("需要原始套接字权限: %v", err)
+	}
+	defer p.Close()
+
+	// 多个目标共享同一 Pinger 和套接字，序号相同，只能靠目的地址区分
+	targets := []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4", "127.0.0.1"}
+	results := make([]*types.PingResult, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			result, err := p.Ping(context.Background(), target, opts)
+			require.NoError(t, err)
+			results[i] = result
+		}(i, target)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		require.Len(t, result.Replies, opts.Count, targets[i])
+		for _, reply := range result.Replies {
+			require.Equal(t, types.StatusSuccess, reply.Status, fmt.Sprintf("%s seq=%d", targets[i], reply.Seq))
+			require.Equal(t, targets[i], reply.From)
+		}
+	}
+}
+
+func TestDemuxDuplicateFromOtherResponder(t *testing.T) {
+	d := newDemux()
+	key := probeKey{dst: "192.0.2.255", seq: 9}
+	dups := make(chan icmpPacket, 4)
+	sent := time.Now()
+	w := &waiter{dups: dups, seq: 9, sent: sent, expires: sent.Add(time.Second)}
+	d.register(key, w)
+
+	d.dispatch(probeKey{dst: "192.0.2.1", seq: 9}, icmpPacket{peer: "192.0.2.1", echo: true, at: sent.Add(time.Millisecond)})
+	d.dispatch(probeKey{dst: "192.0.2.2", seq: 9}, icmpPacket{peer: "192.0.2.2", echo: true, at: sent.Add(2 * time.Millisecond)})
+	// 其他序号的应答不属于该探测
+	d.dispatch(probeKey{dst: "192.0.2.3", seq: 10}, icmpPacket{peer: "192.0.2.3", echo: true, at: sent.Add(3 * time.Millisecond)})
+
+	require.Equal(t, "192.0.2.1", (<-w.ch).peer)
+	require.Len(t, dups, 1)
+	dup := <-dups
+	require.Equal(t, "192.0.2.2", dup.peer)
+	require.Equal(t, 9, dup.seq)
+}
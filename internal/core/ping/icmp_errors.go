@@ -0,0 +1,99 @@
+package ping
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	// icmpCodeFragNeeded ICMPv4 目标不可达中的 "需要分片但设置了 DF"
+	icmpCodeFragNeeded = 4
+	// ipv6HeaderLen IPv6 固定头长度
+	ipv6HeaderLen = 40
+)
+
+// icmpError 从 ICMP 差错报文中解析出的信息
+type icmpError struct {
+	// message 面向用户的错误描述
+	message string
+	// mtu 下一跳 MTU，仅 "需要分片"/"Packet Too Big" 时有效
+	mtu int
+}
+
+// parseICMPError 解析差错报文；非差错报文返回 nil。
+// raw 为完整的 ICMP 报文，用于读取 x/net 未解析的 ICMPv4 Next-Hop MTU 字段。
+func parseICMPError(rm *icmp.Message, raw []byte) *icmpError {
+	switch rm.Type {
+	case ipv4.ICMPTypeDestinationUnreachable:
+		if rm.Code == icmpCodeFragNeeded {
+			// RFC 1191: 第 6-7 字节为 Next-Hop MTU，旧路由器可能填 0
+			mtu := 0
+			if len(raw) >= 8 {
+				mtu = int(binary.BigEndian.Uint16(raw[6:8]))
+			}
+			return &icmpError{message: formatPMTUError("fragmentation needed", mtu), mtu: mtu}
+		}
+		return &icmpError{message: "destination unreachable"}
+	case ipv6.ICMPTypeDestinationUnreachable:
+		return &icmpError{message: "destination unreachable"}
+	case ipv6.ICMPTypePacketTooBig:
+		mtu := 0
+		if body, ok := rm.Body.(*icmp.PacketTooBig); ok {
+			mtu = body.MTU
+		}
+		return &icmpError{message: formatPMTUError("packet too big", mtu), mtu: mtu}
+	case ipv4.ICMPTypeTimeExceeded, ipv6.ICMPTypeTimeExceeded:
+		return &icmpError{message: "time exceeded"}
+	}
+	return nil
+}
+
+func formatPMTUError(reason string, mtu int) string {
+	if mtu == 0 {
+		return reason + " (next-hop MTU unknown)"
+	}
+	return fmt.Sprintf("%s (next-hop MTU %d)", reason, mtu)
+}
+
+// quotedEcho 从差错报文引用的原始数据包中提取 Echo 请求的 ID、序号与目的地址
+func quotedEcho(rm *icmp.Message) (id, seq int, dst net.IP, ok bool) {
+	var data []byte
+	switch body := rm.Body.(type) {
+	case *icmp.DstUnreach:
+		data = body.Data
+	case *icmp.TimeExceeded:
+		data = body.Data
+	case *icmp.PacketTooBig:
+		data = body.Data
+	default:
+		return 0, 0, nil, false
+	}
+	if len(data) == 0 {
+		return 0, 0, nil, false
+	}
+
+	var echo []byte
+	switch data[0] >> 4 {
+	case ipv4.Version:
+		hl := int(data[0]&0x0f) << 2
+		if hl < ipv4.HeaderLen || len(data) < hl+8 || data[hl] != byte(ipv4.ICMPTypeEcho) {
+			return 0, 0, nil, false
+		}
+		echo = data[hl:]
+		dst = net.IP(data[16:20])
+	case ipv6.Version:
+		if len(data) < ipv6HeaderLen+8 || data[ipv6HeaderLen] != byte(ipv6.ICMPTypeEchoRequest) {
+			return 0, 0, nil, false
+		}
+		echo = data[ipv6HeaderLen:]
+		dst = net.IP(data[24:40])
+	default:
+		return 0, 0, nil, false
+	}
+	return int(binary.BigEndian.Uint16(echo[4:6])), int(binary.BigEndian.Uint16(echo[6:8])), dst, true
+}
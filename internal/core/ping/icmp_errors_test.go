@@ -0,0 +1,110 @@
+package ping
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// quotedIPv4Echo 构造差错报文中引用的 IPv4 头 + Echo 请求前 8 字节
+func quotedIPv4Echo(t *testing.T, id, seq int) []byte {
+	h := &ipv4.Header{
+		Version:  ipv4.Version,
+		Len:      ipv4.HeaderLen,
+		TotalLen: ipv4.HeaderLen + 8,
+		TTL:      64,
+		Protocol: ProtocolICMP,
+		Src:      net.IPv4(192, 0, 2, 1),
+		Dst:      net.IPv4(198, 51, 100, 1),
+	}
+	raw, err := h.Marshal()
+	require.NoError(t, err)
+	echo := []byte{byte(ipv4.ICMPTypeEcho), 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(echo[4:], uint16(id))
+	binary.BigEndian.PutUint16(echo[6:], uint16(seq))
+	return append(raw, echo...)
+}
+
+func TestParseICMPErrorFragmentationNeeded(t *testing.T) {
+	msg := &icmp.Message{
+		Type: ipv4.ICMPTypeDestinationUnreachable,
+		Code: icmpCodeFragNeeded,
+		Body: &icmp.DstUnreach{Data: quotedIPv4Echo(t, 0x1234, 7)},
+	}
+	raw, err := msg.Marshal(nil)
+	require.NoError(t, err)
+	binary.BigEndian.PutUint16(raw[6:8], 1400)
+
+	rm, err := icmp.ParseMessage(ProtocolICMP, raw)
+	require.NoError(t, err)
+
+	icmpErr := parseICMPError(rm, raw)
+	require.NotNil(t, icmpErr)
+	require.Equal(t, 1400, icmpErr.mtu)
+	require.Equal(t, "fragmentation needed (next-hop MTU 1400)", icmpErr.message)
+
+	id, seq, dst, ok := quotedEcho(rm)
+	require.True(t, ok)
+	require.Equal(t, 0x1234, id)
+	require.Equal(t, 7, seq)
+	require.Equal(t, "198.51.100.1", dst.String())
+}
+
+func TestParseICMPErrorPacketTooBig(t *testing.T) {
+	quoted := make([]byte, ipv6HeaderLen+8)
+	quoted[0] = ipv6.Version << 4
+	quoted[ipv6HeaderLen] = byte(ipv6.ICMPTypeEchoRequest)
+	binary.BigEndian.PutUint16(quoted[ipv6HeaderLen+4:], 42)
+	binary.BigEndian.PutUint16(quoted[ipv6HeaderLen+6:], 3)
+	copy(quoted[24:40], net.ParseIP("2001:db8::1"))
+
+	msg := &icmp.Message{
+		Type: ipv6.ICMPTypePacketTooBig,
+		Body: &icmp.PacketTooBig{MTU: 1280, Data: quoted},
+	}
+	raw, err := msg.Marshal(nil)
+	require.NoError(t, err)
+
+	rm, err := icmp.ParseMessage(ProtocolIPv6ICMP, raw)
+	require.NoError(t, err)
+
+	icmpErr := parseICMPError(rm, raw)
+	require.NotNil(t, icmpErr)
+	require.Equal(t, 1280, icmpErr.mtu)
+	require.Equal(t, "packet too big (next-hop MTU 1280)", icmpErr.message)
+
+	id, seq, dst, ok := quotedEcho(rm)
+	require.True(t, ok)
+	require.Equal(t, 42, id)
+	require.Equal(t, 3, seq)
+	require.Equal(t, "2001:db8::1", dst.String())
+}
+
+func TestParseICMPErrorGeneric(t *testing.T) {
+	msg := &icmp.Message{
+		Type: ipv4.ICMPTypeDestinationUnreachable,
+		Code: 1,
+		Body: &icmp.DstUnreach{Data: quotedIPv4Echo(t, 1, 1)},
+	}
+	raw, err := msg.Marshal(nil)
+	require.NoError(t, err)
+	rm, err := icmp.ParseMessage(ProtocolICMP, raw)
+	require.NoError(t, err)
+
+	icmpErr := parseICMPError(rm, raw)
+	require.NotNil(t, icmpErr)
+	require.Zero(t, icmpErr.mtu)
+	require.Equal(t, "destination unreachable", icmpErr.message)
+
+	echo := &icmp.Message{Type: ipv4.ICMPTypeEchoReply, Body: &icmp.Echo{ID: 1, Seq: 1}}
+	raw, err = echo.Marshal(nil)
+	require.NoError(t, err)
+	rm, err = icmp.ParseMessage(ProtocolICMP, raw)
+	require.NoError(t, err)
+	require.Nil(t, parseICMPError(rm, raw))
+}
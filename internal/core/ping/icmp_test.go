@@ -0,0 +1,104 @@
+package ping
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestSplitIPv4Header(t *testing.T) {
+	h := &ipv4.Header{
+		Version:  ipv4.Version,
+		Len:      ipv4.HeaderLen,
+		TOS:      0xb8,
+		TotalLen: ipv4.HeaderLen + 4,
+		TTL:      57,
+		Protocol: ProtocolICMP,
+		Src:      net.IPv4(192, 0, 2, 1),
+		Dst:      net.IPv4(192, 0, 2, 2),
+	}
+	raw, err := h.Marshal()
+	require.NoError(t, err)
+	payload := []byte{0, 0, 0xff, 0xff}
+
+	got, tos, ttl := splitIPv4Header(append(raw, payload...))
+	require.Equal(t, payload, got)
+	require.Equal(t, 0xb8, tos)
+	require.Equal(t, 57, ttl)
+
+	// 已剥离 IP 头的 ICMP 报文原样返回
+	got, tos, ttl = splitIPv4Header(payload)
+	require.Equal(t, payload, got)
+	require.Zero(t, tos)
+	require.Zero(t, ttl)
+}
+
+func TestICMPPingerReportsReceivedTTL(t *testing.T) {
+	opts := types.DefaultPingOptions()
+	opts.Count = 1
+	opts.Timeout = time.Second
+	// 发送 TTL 与常见的初始 TTL 不同，响应中的 TTL 必须来自收到的报文
+	opts.TTL = 7
+
+	p, err := NewICMPPinger(opts)
+	if err != nil {
+		t.Skipf("需要原始套接字权限: %v", err)
+	}
+	defer p.Close()
+
+	result, err := p.Ping(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Len(t, result.Replies, 1)
+	require.Equal(t, types.StatusSuccess, result.Replies[0].Status)
+	require.Contains(t, []int{64, 128, 255}, result.Replies[0].TTL)
+}
+
+func TestICMPPingerFixedIDAndStartSeq(t *testing.T) {
+	opts := types.DefaultPingOptions()
+	opts.Count = 2
+	opts.Interval = 10 * time.Millisecond
+	opts.Timeout = time.Second
+	opts.ICMPID = 0x1234
+	opts.StartSeq = 65535
+
+	p, err := NewICMPPinger(opts)
+	if err != nil {
+		t.Skipf("需要原始套接字权限: %v", err)
+	}
+	defer p.Close()
+	require.Equal(t, 0x1234, p.id)
+
+	result, err := p.Ping(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Len(t, result.Replies, 2)
+	require.Equal(t, 65535, result.Replies[0].Seq)
+	// 序列号超过 16 位后在报文中回绕，仍能匹配响应
+	require.Equal(t, 65536, result.Replies[1].Seq)
+	require.Equal(t, types.StatusSuccess, result.Replies[1].Status)
+}
+
+func TestICMPPingerFloodSkipsInterval(t *testing.T) {
+	opts := types.DefaultPingOptions()
+	opts.Count = 20
+	opts.Interval = time.Second
+	opts.Timeout = time.Second
+	opts.Flood = true
+
+	p, err := NewICMPPinger(opts)
+	if err != nil {
+		t.Skipf("需要原始套接字权限: %v", err)
+	}
+	defer p.Close()
+
+	start := time.Now()
+	result, err := p.Ping(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Equal(t, 20, result.Statistics.Received)
+	// 按 1 秒间隔需要约 19 秒，洪泛模式下应几乎没有发送间隔
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
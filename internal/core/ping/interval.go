@@ -0,0 +1,24 @@
+package ping
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// nextInterval 返回下一次发送前的等待时间，启用 Jitter 时在 Interval±Jitter 内均匀随机，
+// 避免与周期性网络事件同步；洪泛模式下不等待
+func nextInterval(opts *types.PingOptions) time.Duration {
+	if opts.Flood {
+		return 0
+	}
+	if opts.Jitter <= 0 {
+		return opts.Interval
+	}
+	d := opts.Interval - opts.Jitter + rand.N(2*opts.Jitter+1)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
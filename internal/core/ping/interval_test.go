@@ -0,0 +1,31 @@
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextIntervalJitterBounds(t *testing.T) {
+	opts := &types.PingOptions{Interval: time.Second}
+	require.Equal(t, time.Second, nextInterval(opts))
+
+	opts.Jitter = 100 * time.Millisecond
+	varied := false
+	for i := 0; i < 200; i++ {
+		d := nextInterval(opts)
+		require.GreaterOrEqual(t, d, 900*time.Millisecond)
+		require.LessOrEqual(t, d, 1100*time.Millisecond)
+		if d != time.Second {
+			varied = true
+		}
+	}
+	require.True(t, varied)
+}
+
+func TestNextIntervalFlood(t *testing.T) {
+	opts := &types.PingOptions{Interval: time.Second, Jitter: 100 * time.Millisecond, Flood: true}
+	require.Zero(t, nextInterval(opts))
+}
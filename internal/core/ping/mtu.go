@@ -0,0 +1,172 @@
+package ping
+
+import (
+	"context"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+const (
+	// ipv4HeaderLen 不含选项的 IPv4 头长度
+	ipv4HeaderLen = 20
+	// icmpEchoHeaderLen ICMP Echo 头长度
+	icmpEchoHeaderLen = 8
+	// minIPv4MTU RFC 791 规定所有主机必须能处理的最小 MTU
+	minIPv4MTU = 68
+	// maxIPv4PacketSize IPv4 总长度字段上限
+	maxIPv4PacketSize = 65535
+	// mtuProbeAttempts 无响应时的重试次数，用于区分丢包与静默丢弃大包的黑洞路由
+	mtuProbeAttempts = 2
+)
+
+// mtuOutcome 单次不分片探测的结果
+type mtuOutcome int
+
+const (
+	// mtuPassed 收到回显，该大小可以通过
+	mtuPassed mtuOutcome = iota
+	// mtuTooBig 收到 "需要分片" 或本地因超过出口 MTU 拒绝发送
+	mtuTooBig
+	// mtuLost 超时或其他失败
+	mtuLost
+)
+
+// mtuProber 以指定 IP 包大小发送一次不分片探测，mtuTooBig 时返回报告的下一跳 MTU（未知为 0）
+type mtuProber func(ctx context.Context, packetSize int) (mtuOutcome, int)
+
+// searchPathMTU 在 [low, high] 内二分查找可以通过的最大 IP 包大小，返回结果与探测次数。
+// 先探测上界以便常见路径一次确定；收到下一跳 MTU 时以其为新上界并直接尝试该值。
+// low 也无法通过时返回 0，表示无法确定。
+func searchPathMTU(ctx context.Context, low, high int, probe mtuProber) (int, int) {
+	probes := 0
+	try := func(size int) (mtuOutcome, int) {
+		probes++
+		return probe(ctx, size)
+	}
+
+	if out, _ := try(low); out != mtuPassed {
+		return 0, probes
+	}
+
+	good, bad := low, high+1
+	size := high
+	for bad-good > 1 && ctx.Err() == nil {
+		out, hop := try(size)
+		switch {
+		case out == mtuPassed:
+			good = size
+		case out == mtuTooBig && hop > good && hop < size:
+			// 超过下一跳 MTU 的包必然无法通过，直接验证该值
+			bad = hop + 1
+			size = hop
+			continue
+		default:
+			bad = size
+		}
+		size = (good + bad) / 2
+	}
+	return good, probes
+}
+
+// classifyMTUReply 将 pingOnce 的结果归类为 MTU 探测结果
+func classifyMTUReply(reply *types.PingReply) (mtuOutcome, int) {
+	switch {
+	case reply.Status == types.StatusSuccess:
+		return mtuPassed, 0
+	case reply.MTU > 0:
+		return mtuTooBig, reply.MTU
+	case strings.HasPrefix(reply.Error, "fragmentation needed"),
+		strings.Contains(reply.Error, syscall.EMSGSIZE.Error()):
+		return mtuTooBig, 0
+	}
+	return mtuLost, 0
+}
+
+// DiscoverMTU 通过设置 DF 位的 ICMP Echo 二分查找到目标的路径 MTU（仅 IPv4）。
+// Pinger 需以 opts.DontFragment 创建；目标始终无响应时 PathMTU 为 0。
+func (p *ICMPPinger) DiscoverMTU(ctx context.Context, target string, opts *types.PingOptions) (*types.MTUResult, error) {
+	if target == "" {
+		return nil, errors.ErrInvalidHost
+	}
+	if opts == nil {
+		opts = types.DefaultPingOptions()
+	}
+	if !opts.DontFragment {
+		return nil, errors.NewValidationError("dont_fragment", false, "MTU 探测需要设置不分片标志")
+	}
+
+	hostInfo, err := netutil.ResolveHost(target, types.IPv4)
+	if err != nil {
+		return nil, errors.NewNetworkError("resolve", target, err)
+	}
+	if err := p.sourceErr(types.IPv4); err != nil {
+		return nil, err
+	}
+
+	result := &types.MTUResult{
+		Target:   &types.Host{Hostname: target, IP: hostInfo.IP, IPVersion: types.IPv4},
+		LocalMTU: outgoingMTU(hostInfo.IP),
+	}
+	start := time.Now()
+
+	seq := startSeq(opts)
+	probe := func(ctx context.Context, packetSize int) (mtuOutcome, int) {
+		probeOpts := *opts
+		probeOpts.Size = packetSize - ipv4HeaderLen - icmpEchoHeaderLen
+		var out mtuOutcome
+		var hop int
+		for i := 0; i < mtuProbeAttempts; i++ {
+			out, hop = classifyMTUReply(p.pingOnce(ctx, hostInfo.IP, seq, &probeOpts, nil))
+			seq++
+			if out != mtuLost || ctx.Err() != nil {
+				break
+			}
+		}
+		return out, hop
+	}
+
+	high := result.LocalMTU
+	if high > maxIPv4PacketSize {
+		high = maxIPv4PacketSize
+	}
+	result.PathMTU, result.Probes = searchPathMTU(ctx, minIPv4MTU, high, probe)
+	if result.PathMTU > 0 {
+		result.MaxPayload = result.PathMTU - ipv4HeaderLen - icmpEchoHeaderLen
+	}
+	result.Duration = time.Since(start)
+	return result, ctx.Err()
+}
+
+// outgoingMTU 返回到达 ip 的出口网卡 MTU，无法确定时返回以太网标准 MTU
+func outgoingMTU(ip string) int {
+	// UDP "连接" 只做路由查询，不发送数据
+	conn, err := net.Dial("udp4", net.JoinHostPort(ip, "9"))
+	if err != nil {
+		return types.StandardMTU
+	}
+	local := conn.LocalAddr().(*net.UDPAddr).IP
+	conn.Close()
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return types.StandardMTU
+	}
+	for _, ifc := range ifaces {
+		addrs, err := ifc.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(local) && ifc.MTU > 0 {
+				return ifc.MTU
+			}
+		}
+	}
+	return types.StandardMTU
+}
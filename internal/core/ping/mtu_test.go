@@ -0,0 +1,101 @@
+package ping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePath 模拟一条路径：超过 mtu 的探测被拒绝，reportMTU 为 true 时在拒绝中携带下一跳 MTU
+func fakePath(mtu int, reportMTU bool) (mtuProber, *[]int) {
+	var sizes []int
+	return func(_ context.Context, size int) (mtuOutcome, int) {
+		sizes = append(sizes, size)
+		if size <= mtu {
+			return mtuPassed, 0
+		}
+		if reportMTU {
+			return mtuTooBig, mtu
+		}
+		return mtuTooBig, 0
+	}, &sizes
+}
+
+func TestSearchPathMTU(t *testing.T) {
+	ctx := context.Background()
+
+	for _, mtu := range []int{68, 576, 1400, 1492, 1499, 1500} {
+		probe, _ := fakePath(mtu, false)
+		got, probes := searchPathMTU(ctx, minIPv4MTU, 1500, probe)
+		require.Equal(t, mtu, got)
+		require.LessOrEqual(t, probes, 13, "二分查找的探测次数应为对数级")
+	}
+
+	// 路由器报告下一跳 MTU 时直接命中
+	probe, sizes := fakePath(1400, true)
+	got, probes := searchPathMTU(ctx, minIPv4MTU, 1500, probe)
+	require.Equal(t, 1400, got)
+	require.Equal(t, []int{68, 1500, 1400}, *sizes)
+	require.Equal(t, 3, probes)
+}
+
+func TestSearchPathMTUIndeterminate(t *testing.T) {
+	probes := 0
+	got, n := searchPathMTU(context.Background(), minIPv4MTU, 1500, func(context.Context, int) (mtuOutcome, int) {
+		probes++
+		return mtuLost, 0
+	})
+	require.Zero(t, got)
+	require.Equal(t, 1, n)
+	require.Equal(t, 1, probes)
+}
+
+func TestSearchPathMTUBlackHole(t *testing.T) {
+	// 大包被静默丢弃（不回 "需要分片"）时按无法通过处理
+	got, _ := searchPathMTU(context.Background(), minIPv4MTU, 1500, func(_ context.Context, size int) (mtuOutcome, int) {
+		if size > 1280 {
+			return mtuLost, 0
+		}
+		return mtuPassed, 0
+	})
+	require.Equal(t, 1280, got)
+}
+
+func TestClassifyMTUReply(t *testing.T) {
+	out, _ := classifyMTUReply(&types.PingReply{Status: types.StatusSuccess})
+	require.Equal(t, mtuPassed, out)
+
+	out, hop := classifyMTUReply(&types.PingReply{Status: types.StatusFailure, MTU: 1400, Error: "fragmentation needed (next-hop MTU 1400)"})
+	require.Equal(t, mtuTooBig, out)
+	require.Equal(t, 1400, hop)
+
+	out, _ = classifyMTUReply(&types.PingReply{Status: types.StatusFailure, Error: "fragmentation needed (next-hop MTU unknown)"})
+	require.Equal(t, mtuTooBig, out)
+
+	out, _ = classifyMTUReply(&types.PingReply{Status: types.StatusFailure, Error: "write ip4 0.0.0.0->192.0.2.1: sendto: message too long"})
+	require.Equal(t, mtuTooBig, out)
+
+	out, _ = classifyMTUReply(&types.PingReply{Status: types.StatusTimeout})
+	require.Equal(t, mtuLost, out)
+}
+
+func TestICMPPingerDiscoverMTULoopback(t *testing.T) {
+	opts := types.DefaultPingOptions()
+	opts.Timeout = 500 * time.Millisecond
+	opts.DontFragment = true
+
+	p, err := NewICMPPinger(opts)
+	if err != nil {
+		t.Skipf("需要原始套接字权限且支持 DF: %v", err)
+	}
+	defer p.Close()
+
+	result, err := p.DiscoverMTU(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.True(t, result.Determinate())
+	require.Equal(t, min(result.LocalMTU, maxIPv4PacketSize), result.PathMTU)
+	require.Equal(t, result.PathMTU-28, result.MaxPayload)
+}
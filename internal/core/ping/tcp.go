@@ -8,6 +8,7 @@ package ping
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"syscall"
@@ -20,7 +21,9 @@ import (
 
 // TCPPinger TCP Ping 实现
 type TCPPinger struct {
-	dialer *net.Dialer
+	dialer     *net.Dialer
+	source     string
+	sourcePort int
 }
 
 // NewTCPPinger 创建 TCP Pinger
@@ -49,9 +52,27 @@ func NewTCPPinger(opts ...*types.PingOptions) *TCPPinger {
 		}
 	}
 
+	netutil.BindSourcePort(dialer, cfg.SourcePort)
+
 	return &TCPPinger{
-		dialer: dialer,
+		dialer:     dialer,
+		source:     cfg.Source,
+		sourcePort: cfg.SourcePort,
+	}
+}
+
+// dialerFor 返回绑定源地址的 Dialer，源地址按目标地址族解析；未设置源地址时复用共享 Dialer
+func (p *TCPPinger) dialerFor(version types.IPVersion) (*net.Dialer, error) {
+	if p.source == "" {
+		return p.dialer, nil
 	}
+	ip, err := netutil.ResolveSourceAddr(p.source, version)
+	if err != nil {
+		return nil, err
+	}
+	d := *p.dialer
+	d.LocalAddr = &net.TCPAddr{IP: ip, Port: p.sourcePort}
+	return &d, nil
 }
 
 // Ping 执行 TCP Ping
@@ -75,6 +96,10 @@ func (p *TCPPinger) Ping(ctx context.Context, target string, opts *types.PingOpt
 	if err != nil {
 		return nil, errors.NewNetworkError("resolve", target, err)
 	}
+	dialer, err := p.dialerFor(hostInfo.IPVersion)
+	if err != nil {
+		return nil, err
+	}
 
 	result := &types.PingResult{
 		Target: &types.Host{
@@ -95,24 +120,28 @@ func (p *TCPPinger) Ping(ctx context.Context, target string, opts *types.PingOpt
 	hostname, _ := os.Hostname()
 	result.Context.Hostname = hostname
 
-	for i := 0; i < opts.Count; i++ {
+	runCtx, cancel := withDeadline(ctx, opts)
+	defer cancel()
+
+	for i := 0; moreProbes(opts, i); i++ {
 		select {
-		case <-ctx.Done():
-			result.Error = ctx.Err()
-			result.Status = types.StatusFailure
+		case <-runCtx.Done():
+			markStopped(ctx, runCtx, result)
 			goto end
 		default:
 		}
 
-		reply := p.pingOnce(ctx, hostInfo.IP, port, i+1, opts)
+		reply := p.pingOnce(runCtx, dialer, hostInfo.IP, port, i+1, opts)
+		if interruptedByDeadline(ctx, runCtx, reply) {
+			goto end
+		}
 		result.AddReply(reply)
 
-		if i < opts.Count-1 {
+		if !lastProbe(opts, i) {
 			select {
-			case <-time.After(opts.Interval):
-			case <-ctx.Done():
-				result.Error = ctx.Err()
-				result.Status = types.StatusFailure
+			case <-time.After(nextInterval(opts)):
+			case <-runCtx.Done():
+				markStopped(ctx, runCtx, result)
 				goto end
 			}
 		}
@@ -156,26 +185,35 @@ func (p *TCPPinger) PingStream(ctx context.Context, target string, opts *types.P
 	if err != nil {
 		return nil, errors.NewNetworkError("resolve", target, err)
 	}
+	dialer, err := p.dialerFor(hostInfo.IPVersion)
+	if err != nil {
+		return nil, err
+	}
 
 	replyChan := make(chan *types.PingReply)
 
 	go func() {
 		defer close(replyChan)
+		runCtx, cancel := withDeadline(ctx, opts)
+		defer cancel()
 
 		for i := 0; opts.Count <= 0 || i < opts.Count; i++ {
 			select {
-			case <-ctx.Done():
+			case <-runCtx.Done():
 				return
 			default:
 			}
 
-			reply := p.pingOnce(ctx, hostInfo.IP, port, i+1, opts)
+			reply := p.pingOnce(runCtx, dialer, hostInfo.IP, port, i+1, opts)
+			if interruptedByDeadline(ctx, runCtx, reply) {
+				return
+			}
 			replyChan <- reply
 
 			if opts.Count <= 0 || i < opts.Count-1 {
 				select {
-				case <-time.After(opts.Interval):
-				case <-ctx.Done():
+				case <-time.After(nextInterval(opts)):
+				case <-runCtx.Done():
 					return
 				}
 			}
@@ -186,7 +224,7 @@ func (p *TCPPinger) PingStream(ctx context.Context, target string, opts *types.P
 }
 
 // pingOnce 执行一次 TCP Ping
-func (p *TCPPinger) pingOnce(ctx context.Context, ip string, port, seq int, opts *types.PingOptions) *types.PingReply {
+func (p *TCPPinger) pingOnce(ctx context.Context, dialer *net.Dialer, ip string, port, seq int, opts *types.PingOptions) *types.PingReply {
 	reply := &types.PingReply{
 		Seq:    seq,
 		From:   ip,
@@ -199,7 +237,7 @@ func (p *TCPPinger) pingOnce(ctx context.Context, ip string, port, seq int, opts
 
 	start := time.Now()
 	addr := net.JoinHostPort(ip, fmt.Sprintf("%d", port))
-	conn, err := p.dialer.DialContext(dialCtx, "tcp", addr)
+	conn, err := dialer.DialContext(dialCtx, "tcp", addr)
 	reply.RTT = time.Since(start)
 
 	if err != nil {
@@ -207,16 +245,55 @@ func (p *TCPPinger) pingOnce(ctx context.Context, ip string, port, seq int, opts
 			reply.Status = types.StatusTimeout
 		} else {
 			reply.Status = types.StatusFailure
-			reply.Error = err.Error()
+			reply.Error = netutil.SourcePortError(err, p.sourcePort).Error()
 		}
 		return reply
 	}
-	conn.Close()
+	defer conn.Close()
+	if p.sourcePort > 0 {
+		// 以 RST 关闭，避免固定源端口进入 TIME_WAIT 导致下一次连接失败
+		_ = conn.(*net.TCPConn).SetLinger(0)
+	}
+
+	if opts.TCPReadProbe {
+		if err := readProbe(dialCtx, conn, opts.TCPSendData); err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				reply.Status = types.StatusTimeout
+			} else {
+				reply.Status = types.StatusFailure
+				reply.Error = err.Error()
+			}
+			return reply
+		}
+		reply.RTT = time.Since(start)
+	}
 
 	reply.Bytes = types.TCPHandshakeBytes
 	return reply
 }
 
+// readProbe 发送可选的探测数据并等待一个响应字节，截止时间与建连共用同一超时，ctx 取消时立即中断
+func readProbe(ctx context.Context, conn net.Conn, data []byte) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+	if len(data) > 0 {
+		if _, err := conn.Write(data); err != nil {
+			return err
+		}
+	}
+	buf := make([]byte, 1)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("connection closed before response")
+		}
+		return err
+	}
+	return nil
+}
+
 // parseTarget 解析目标地址和端口
 func (p *TCPPinger) parseTarget(target string, defaultPort int) (string, int, error) {
 	host, portStr, err := net.SplitHostPort(target)
@@ -37,6 +37,32 @@ func setupTCPServer(t *testing.T) (*net.TCPListener, string) {
 	return listener, listener.Addr().String()
 }
 
+// setupTCPEchoServer starts a TCP server that reads one byte and echoes it back after delay.
+func setupTCPEchoServer(t *testing.T, delay time.Duration) (*net.TCPListener, string) {
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err, "Failed to listen on TCP address")
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // Listener was closed
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 1)
+				if _, err := conn.Read(buf); err != nil {
+					return
+				}
+				time.Sleep(delay)
+				_, _ = conn.Write(buf)
+			}()
+		}
+	}()
+
+	return listener, listener.Addr().String()
+}
+
 func TestTCPPinger_Ping(t *testing.T) {
 	// Case 1: Successful ping to a live server
 	t.Run("Success", func(t *testing.T) {
@@ -102,6 +128,87 @@ func TestTCPPinger_Ping(t *testing.T) {
 		require.Error(t, err)
 		assert.ErrorIs(t, err, errors.ErrInvalidHost)
 	})
+
+	// Case 4: Source address binds the local end and must match the target family
+	t.Run("Source", func(t *testing.T) {
+		server, addr := setupTCPServer(t)
+		defer server.Close()
+
+		pinger := NewTCPPinger(&types.PingOptions{Source: "127.0.0.1"})
+		defer pinger.Close()
+
+		opts := &types.PingOptions{Count: 1, Timeout: time.Second}
+		result, err := pinger.Ping(context.Background(), addr, opts)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Statistics.Received)
+
+		mismatched := NewTCPPinger(&types.PingOptions{Source: "::1"})
+		defer mismatched.Close()
+		_, err = mismatched.Ping(context.Background(), addr, opts)
+		require.ErrorContains(t, err, "不一致")
+	})
+
+	// Case 5: Read probe times the application round trip, not just the handshake
+	t.Run("ReadProbe", func(t *testing.T) {
+		const delay = 100 * time.Millisecond
+		server, addr := setupTCPEchoServer(t, delay)
+		defer server.Close()
+
+		pinger := NewTCPPinger()
+		defer pinger.Close()
+
+		connectOnly, err := pinger.Ping(context.Background(), addr, &types.PingOptions{Count: 1, Timeout: time.Second})
+		require.NoError(t, err)
+		assert.Less(t, connectOnly.Replies[0].RTT, delay)
+
+		opts := &types.PingOptions{
+			Count:        1,
+			Timeout:      time.Second,
+			TCPReadProbe: true,
+			TCPSendData:  []byte("x"),
+		}
+		result, err := pinger.Ping(context.Background(), addr, opts)
+		require.NoError(t, err)
+		require.Equal(t, types.StatusSuccess, result.Replies[0].Status)
+		assert.GreaterOrEqual(t, result.Replies[0].RTT, delay)
+
+		// Server never answers without data: the probe times out
+		opts.TCPSendData = nil
+		opts.Timeout = 200 * time.Millisecond
+		result, err = pinger.Ping(context.Background(), addr, opts)
+		require.NoError(t, err)
+		assert.Equal(t, types.StatusTimeout, result.Replies[0].Status)
+	})
+
+	// Case 6: Deadline bounds total run time while statistics stay valid
+	t.Run("Deadline", func(t *testing.T) {
+		server, addr := setupTCPServer(t)
+		defer server.Close()
+
+		pinger := NewTCPPinger()
+		defer pinger.Close()
+
+		opts := &types.PingOptions{
+			Count:    0, // unlimited, only the deadline stops the run
+			Interval: 50 * time.Millisecond,
+			Timeout:  time.Second,
+			Deadline: 300 * time.Millisecond,
+		}
+
+		start := time.Now()
+		result, err := pinger.Ping(context.Background(), addr, opts)
+		elapsed := time.Since(start)
+
+		require.NoError(t, err)
+		require.NotNil(t, result.Statistics)
+		assert.GreaterOrEqual(t, elapsed, opts.Deadline)
+		assert.Less(t, elapsed, opts.Deadline+250*time.Millisecond)
+		assert.NoError(t, result.Error)
+		assert.Equal(t, types.StatusSuccess, result.Status)
+		assert.Greater(t, result.Statistics.Sent, 1)
+		assert.Equal(t, result.Statistics.Sent, result.Statistics.Received)
+		assert.Equal(t, 0.0, result.Statistics.LossRate)
+	})
 }
 
 func TestTCPPinger_PingStream(t *testing.T) {
@@ -0,0 +1,117 @@
+package scan
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// maxBannerLength Banner 保留的最大字符数
+const maxBannerLength = 256
+
+// httpProbe HTTP 端口的探测请求
+var httpProbe = []byte("GET / HTTP/1.0\r\n\r\n")
+
+// bannerPayloads 需要主动发送请求才会响应的端口，其余端口只读取服务端问候语
+var bannerPayloads = map[int][]byte{
+	80:   httpProbe,
+	8000: httpProbe,
+	8008: httpProbe,
+	8080: httpProbe,
+	8888: httpProbe,
+}
+
+var (
+	sshBanner     = regexp.MustCompile(`^SSH-[\d.]+-([^_\s]+)(?:_(\S+))?`)
+	httpStatus    = regexp.MustCompile(`^HTTP/\d(?:\.\d)? \d{3}`)
+	httpServer    = regexp.MustCompile(`(?im)^Server:[ \t]*(.+?)\r?$`)
+	productVerRe  = regexp.MustCompile(`([A-Za-z][\w.-]*?)[ /_-]v?(\d+(?:\.\d+)+[\w.-]*)`)
+	greetingCodes = regexp.MustCompile(`^(220|\+OK|\* OK)[ -]`)
+)
+
+// grabBanner 连接开放端口并读取首行响应，填充 Banner 与 Version
+//
+// 返回从 Banner 中识别出的服务名，无法识别时返回空字符串。
+func grabBanner(ctx context.Context, scanPort *types.ScanPort, timeout time.Duration) string {
+	addr := net.JoinHostPort(scanPort.IP.String(), strconv.Itoa(scanPort.Port))
+	response, err := sendProbe(ctx, addr, bannerPayloads[scanPort.Port], timeout)
+	if err != nil && len(response) == 0 {
+		return ""
+	}
+
+	service, banner, version := parseBanner(string(response))
+	if banner == "" {
+		return ""
+	}
+	scanPort.Banner = banner
+	if version != "" {
+		scanPort.Version = version
+	}
+	return service
+}
+
+// parseBanner 从响应中提取服务名、Banner 首行与版本
+//
+// HTTP 响应优先使用 Server 头作为 Banner。
+func parseBanner(response string) (service, banner, version string) {
+	banner = firstLine(response)
+	if banner == "" {
+		return "", "", ""
+	}
+
+	switch {
+	case sshBanner.MatchString(banner):
+		m := sshBanner.FindStringSubmatch(banner)
+		return "ssh", banner, strings.TrimSpace(m[1] + " " + m[2])
+	case httpStatus.MatchString(banner):
+		if m := httpServer.FindStringSubmatch(response); m != nil {
+			server := strings.TrimSpace(m[1])
+			return "http", server, productVersion(server)
+		}
+		return "http", banner, ""
+	case greetingCodes.MatchString(banner):
+		lower := strings.ToLower(banner)
+		switch {
+		case strings.Contains(lower, "smtp"):
+			service = "smtp"
+		case strings.Contains(lower, "ftp"):
+			service = "ftp"
+		case strings.Contains(lower, "imap"):
+			service = "imap"
+		case strings.Contains(lower, "pop"):
+			service = "pop3"
+		}
+		return service, banner, productVersion(banner)
+	}
+	return "", banner, productVersion(banner)
+}
+
+// productVersion 提取 "产品 版本" 形式的版本描述，如 "nginx/1.25.3" -> "nginx 1.25.3"
+func productVersion(s string) string {
+	m := productVerRe.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return m[1] + " " + m[2]
+}
+
+// firstLine 返回响应首行，去除不可打印字符并限制长度
+func firstLine(response string) string {
+	line, _, _ := strings.Cut(response, "\n")
+	line = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, line)
+	line = strings.TrimSpace(line)
+	if len(line) > maxBannerLength {
+		line = strings.ToValidUTF8(line[:maxBannerLength], "")
+	}
+	return line
+}
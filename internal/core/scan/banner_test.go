@@ -0,0 +1,54 @@
+package scan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCPScannerBannerGrab(t *testing.T) {
+	port := setupTCPServer(t, "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13\r\n")
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{port}
+	opts.Timeout = time.Second
+	opts.BannerGrab = true
+
+	result, err := NewTCPScanner().Scan(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Len(t, result.Ports, 1)
+
+	p := result.Ports[0]
+	require.Equal(t, types.PortOpen, p.State)
+	require.Equal(t, "ssh", p.Service)
+	require.Equal(t, "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13", p.Banner)
+	require.Equal(t, "OpenSSH 9.6p1", p.Version)
+}
+
+func TestParseBanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		service  string
+		banner   string
+		version  string
+	}{
+		{"ftp", "220 (vsFTPd 3.0.5)\r\n", "ftp", "220 (vsFTPd 3.0.5)", "vsFTPd 3.0.5"},
+		{"smtp", "220 mail.example.com ESMTP Postfix\r\n", "smtp", "220 mail.example.com ESMTP Postfix", ""},
+		{"http", "HTTP/1.1 200 OK\r\nDate: Mon, 01 Jan 2024 00:00:00 GMT\r\nServer: nginx/1.25.3\r\n\r\n", "http", "nginx/1.25.3", "nginx 1.25.3"},
+		{"http 无 Server 头", "HTTP/1.0 404 Not Found\r\n\r\n", "http", "HTTP/1.0 404 Not Found", ""},
+		{"redis", "-ERR unknown command\r\n", "", "-ERR unknown command", ""},
+		{"空响应", "\r\n", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, banner, version := parseBanner(tt.response)
+			require.Equal(t, tt.service, service)
+			require.Equal(t, tt.banner, banner)
+			require.Equal(t, tt.version, version)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package scan
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ExcludePorts 返回去除 excluded 中端口后的列表，保持原有顺序
+func ExcludePorts(ports, excluded []int) []int {
+	if len(excluded) == 0 {
+		return ports
+	}
+	skip := make(map[int]struct{}, len(excluded))
+	for _, p := range excluded {
+		skip[p] = struct{}{}
+	}
+	kept := make([]int, 0, len(ports))
+	for _, p := range ports {
+		if _, ok := skip[p]; !ok {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// HostExclusions 排除的主机集合，由单个 IP 与 CIDR 组成
+type HostExclusions []*net.IPNet
+
+// ParseHostExclusions 解析逗号分隔的 IP/CIDR 列表，如 "10.0.0.1,10.0.0.0/30"
+func ParseHostExclusions(spec string) (HostExclusions, error) {
+	var nets HostExclusions
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.Contains(part, "/") {
+			_, ipNet, err := net.ParseCIDR(part)
+			if err != nil {
+				return nil, fmt.Errorf("无效的 CIDR: %s", part)
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		ip := net.ParseIP(part)
+		if ip == nil {
+			return nil, fmt.Errorf("无效的 IP 地址: %s", part)
+		}
+		bits := 128
+		if v4 := ip.To4(); v4 != nil {
+			ip, bits = v4, 32
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
+
+// Contains 判断 ip 是否被排除
+func (h HostExclusions) Contains(ip net.IP) bool {
+	for _, n := range h {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package scan
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludePorts(t *testing.T) {
+	require.Equal(t, []int{80, 443}, ExcludePorts([]int{22, 80, 443, 3389}, []int{22, 3389, 8080}))
+	require.Equal(t, []int{22}, ExcludePorts([]int{22}, nil))
+}
+
+func TestParseHostExclusions(t *testing.T) {
+	ex, err := ParseHostExclusions("10.0.0.1, 10.0.0.4/30,2001:db8::1")
+	require.NoError(t, err)
+	require.Len(t, ex, 3)
+
+	require.True(t, ex.Contains(net.ParseIP("10.0.0.1")))
+	require.False(t, ex.Contains(net.ParseIP("10.0.0.2")))
+	require.True(t, ex.Contains(net.ParseIP("10.0.0.6")))
+	require.True(t, ex.Contains(net.ParseIP("2001:db8::1")))
+	require.False(t, ex.Contains(net.ParseIP("2001:db8::2")))
+
+	_, err = ParseHostExclusions("10.0.0.1,bogus")
+	require.Error(t, err)
+	_, err = ParseHostExclusions("10.0.0.0/33")
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package scan
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// Plan 扫描计划，用于 --dry-run 时展示扫描范围而不发送任何数据包
+type Plan struct {
+	Target        string        `json:"target" yaml:"target"`
+	IP            net.IP        `json:"ip" yaml:"ip"`
+	Ports         string        `json:"ports" yaml:"ports"`
+	PortCount     int           `json:"port_count" yaml:"port_count"`
+	Concurrency   int           `json:"concurrency" yaml:"concurrency"`
+	Timeout       time.Duration `json:"timeout" yaml:"timeout"`
+	ServiceDetect bool          `json:"service_detect" yaml:"service_detect"`
+	Technique     string        `json:"technique" yaml:"technique"`
+	Timing        string        `json:"timing" yaml:"timing"`
+	// EstimatedPackets 预计发送的探测包数（每端口一个 SYN 或 UDP 数据报，不含重传与服务识别流量）
+	EstimatedPackets int `json:"estimated_packets" yaml:"estimated_packets"`
+}
+
+// NewPlan 解析目标并生成扫描计划
+func NewPlan(target string, opts types.ScanOptions) (*Plan, error) {
+	ip, err := ResolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Target:           target,
+		IP:               ip,
+		Ports:            FormatPortRanges(opts.Ports),
+		PortCount:        len(opts.Ports),
+		Concurrency:      opts.Concurrency,
+		Timeout:          opts.Timeout,
+		ServiceDetect:    opts.ServiceDetect,
+		Technique:        opts.ScanMode.String(),
+		Timing:           opts.Timing,
+		EstimatedPackets: len(opts.Ports),
+	}, nil
+}
+
+// FormatPortRanges 将端口列表压缩为区间表示，如 "22,80-90,443"
+func FormatPortRanges(ports []int) string {
+	if len(ports) == 0 {
+		return ""
+	}
+
+	sorted := append([]int(nil), ports...)
+	sort.Ints(sorted)
+
+	var parts []string
+	start, prev := sorted[0], sorted[0]
+	flush := func() {
+		if start == prev {
+			parts = append(parts, strconv.Itoa(start))
+		} else {
+			parts = append(parts, strconv.Itoa(start)+"-"+strconv.Itoa(prev))
+		}
+	}
+	for _, p := range sorted[1:] {
+		if p == prev {
+			continue
+		}
+		if p == prev+1 {
+			prev = p
+			continue
+		}
+		flush()
+		start, prev = p, p
+	}
+	flush()
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,39 @@
+package scan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPortRanges(t *testing.T) {
+	require.Equal(t, "", FormatPortRanges(nil))
+	require.Equal(t, "22,80-82,443", FormatPortRanges([]int{443, 80, 81, 22, 82, 81}))
+}
+
+func TestNewPlan(t *testing.T) {
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{1, 2, 3, 10}
+
+	plan, err := NewPlan("127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1", plan.IP.String())
+	require.Equal(t, "1-3,10", plan.Ports)
+	require.Equal(t, 4, plan.PortCount)
+	require.Equal(t, 4, plan.EstimatedPackets)
+}
+
+func TestScanResultRecordsTechniqueAndTiming(t *testing.T) {
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{1}
+	opts.Timeout = 200 * time.Millisecond
+	opts.Timing = types.ScanTimingFast
+
+	result, err := NewTCPScanner().Scan(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Equal(t, "tcp-connect", result.Technique)
+	require.Equal(t, types.ScanTimingFast, result.Timing)
+}
@@ -0,0 +1,340 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// maxProbeResponse 单次探测读取的最大响应字节数
+const maxProbeResponse = 4096
+
+// Probe 服务探测定义（nmap-service-probes 格式子集）
+type Probe struct {
+	// Name 探测名称
+	Name string
+	// Payload 连接建立后发送的数据，为空时仅等待服务端 Banner
+	Payload []byte
+	// Ports 适用端口，为空表示适用于所有端口
+	Ports map[int]bool
+	// Matches 响应匹配规则，按顺序尝试
+	Matches []*ProbeMatch
+}
+
+// ProbeMatch 响应匹配规则
+type ProbeMatch struct {
+	// Service 匹配成功时的服务名称
+	Service string
+	// Pattern 响应正则
+	Pattern *regexp.Regexp
+	// Product 产品名模板，可引用 $1 等捕获组
+	Product string
+	// Version 版本模板，可引用 $1 等捕获组
+	Version string
+}
+
+// LoadProbeFile 从文件加载服务探测定义
+func LoadProbeFile(path string) ([]*Probe, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开探测文件失败: %w", err)
+	}
+	defer file.Close()
+
+	return ParseProbes(file)
+}
+
+// ParseProbes 解析 nmap-service-probes 格式子集
+//
+// 支持的指令:
+//
+//	Probe TCP <name> q|<payload>|
+//	ports 80,443,8000-8100
+//	match <service> m|<regex>|[i][s] [p/<product>/] [v/<version>/]
+func ParseProbes(r io.Reader) ([]*Probe, error) {
+	var probes []*Probe
+	var current *Probe
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		directive, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		switch directive {
+		case "Probe":
+			probe, err := parseProbeLine(rest)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+			}
+			if probe != nil {
+				probes = append(probes, probe)
+			}
+			current = probe
+		case "ports":
+			if current == nil {
+				continue
+			}
+			ports, err := parseProbePorts(rest)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+			}
+			current.Ports = ports
+		case "match", "softmatch":
+			if current == nil {
+				continue
+			}
+			match, err := parseMatchLine(rest)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+			}
+			current.Matches = append(current.Matches, match)
+		default:
+			// 忽略 rarity、totalwaitms 等暂不支持的指令
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取探测文件失败: %w", err)
+	}
+
+	return probes, nil
+}
+
+// parseProbeLine 解析 "TCP <name> q|<payload>|"，非 TCP 探测返回 nil
+func parseProbeLine(rest string) (*Probe, error) {
+	fields := strings.SplitN(rest, " ", 3)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("无效的 Probe 指令: %s", rest)
+	}
+	if !strings.EqualFold(fields[0], "TCP") {
+		return nil, nil
+	}
+
+	payloadSpec := strings.TrimSpace(fields[2])
+	if len(payloadSpec) < 3 || payloadSpec[0] != 'q' {
+		return nil, fmt.Errorf("无效的探测数据: %s", payloadSpec)
+	}
+	raw, _, err := readDelimited(payloadSpec[1:])
+	if err != nil {
+		return nil, err
+	}
+	payload, err := unescapeProbeString(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Probe{Name: fields[1], Payload: payload}, nil
+}
+
+// parseProbePorts 解析 ports 指令的端口列表，拒绝越界或起止颠倒的范围
+func parseProbePorts(spec string) (map[int]bool, error) {
+	ports := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		start, end, isRange := strings.Cut(part, "-")
+		lo, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("无效的端口: %s", part)
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.Atoi(end); err != nil {
+				return nil, fmt.Errorf("无效的端口: %s", part)
+			}
+		}
+		if lo < types.MinPort || hi > types.MaxPort || lo > hi {
+			return nil, fmt.Errorf("端口超出范围: %s", part)
+		}
+		for p := lo; p <= hi; p++ {
+			ports[p] = true
+		}
+	}
+	return ports, nil
+}
+
+// parseMatchLine 解析 "<service> m|<regex>|[flags] [p/../] [v/../]"
+func parseMatchLine(rest string) (*ProbeMatch, error) {
+	service, spec, ok := strings.Cut(rest, " ")
+	if !ok {
+		return nil, fmt.Errorf("无效的 match 指令: %s", rest)
+	}
+	spec = strings.TrimSpace(spec)
+	if len(spec) < 3 || spec[0] != 'm' {
+		return nil, fmt.Errorf("无效的匹配正则: %s", spec)
+	}
+
+	pattern, remain, err := readDelimited(spec[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := ""
+	for len(remain) > 0 && (remain[0] == 'i' || remain[0] == 's') {
+		prefix += string(remain[0])
+		remain = remain[1:]
+	}
+	if prefix != "" {
+		pattern = "(?" + prefix + ")" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("编译正则失败: %w", err)
+	}
+
+	match := &ProbeMatch{Service: service, Pattern: re}
+
+	remain = strings.TrimSpace(remain)
+	for len(remain) > 1 {
+		key := remain[0]
+		value, next, err := readDelimited(remain[1:])
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case 'p':
+			match.Product = value
+		case 'v':
+			match.Version = value
+		}
+		remain = strings.TrimSpace(next)
+	}
+
+	return match, nil
+}
+
+// readDelimited 读取以首字符为分隔符的字段，返回字段内容和剩余部分
+func readDelimited(s string) (string, string, error) {
+	if s == "" {
+		return "", "", fmt.Errorf("缺少分隔符")
+	}
+	delim := s[0]
+	end := strings.IndexByte(s[1:], delim)
+	if end < 0 {
+		return "", "", fmt.Errorf("未闭合的字段: %s", s)
+	}
+	return s[1 : end+1], s[end+2:], nil
+}
+
+// unescapeProbeString 处理 \r \n \t \0 \\ \xHH 转义
+func unescapeProbeString(s string) ([]byte, error) {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			out = append(out, s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'r':
+			out = append(out, '\r')
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case '0':
+			out = append(out, 0)
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, fmt.Errorf("无效的十六进制转义: %s", s)
+			}
+			v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("无效的十六进制转义: %s", s[i-1:i+3])
+			}
+			out = append(out, byte(v))
+			i += 2
+		default:
+			out = append(out, s[i])
+		}
+	}
+	return out, nil
+}
+
+// appliesTo 判断探测是否适用于指定端口
+func (p *Probe) appliesTo(port int) bool {
+	return len(p.Ports) == 0 || p.Ports[port]
+}
+
+// match 在响应中查找第一条匹配规则，返回服务名与版本描述
+func (p *Probe) match(response []byte) (service, version string, ok bool) {
+	for _, m := range p.Matches {
+		sub := m.Pattern.FindSubmatchIndex(response)
+		if sub == nil {
+			continue
+		}
+		product := string(m.Pattern.Expand(nil, []byte(m.Product), response, sub))
+		ver := string(m.Pattern.Expand(nil, []byte(m.Version), response, sub))
+		return m.Service, strings.TrimSpace(product + " " + ver), true
+	}
+	return "", "", false
+}
+
+// runProbes 对开放端口依次执行适用的探测，匹配成功时填充服务和版本
+func runProbes(ctx context.Context, probes []*Probe, scanPort *types.ScanPort, timeout time.Duration) bool {
+	addr := net.JoinHostPort(scanPort.IP.String(), strconv.Itoa(scanPort.Port))
+
+	for _, probe := range probes {
+		if !probe.appliesTo(scanPort.Port) {
+			continue
+		}
+
+		response, err := sendProbe(ctx, addr, probe.Payload, timeout)
+		if err != nil || len(response) == 0 {
+			continue
+		}
+
+		if service, version, ok := probe.match(response); ok {
+			scanPort.Service = service
+			scanPort.Version = version
+			if scanPort.Banner == "" {
+				scanPort.Banner = strings.TrimSpace(string(response))
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func sendProbe(ctx context.Context, addr string, payload []byte, timeout time.Duration) ([]byte, error) {
+	d := net.Dialer{Timeout: timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	if len(payload) > 0 {
+		if _, err := conn.Write(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := make([]byte, maxProbeResponse)
+	n, err := conn.Read(buf)
+	if n > 0 {
+		return buf[:n], nil
+	}
+	return nil, err
+}
@@ -0,0 +1,71 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sampleProbes = `# 示例探测
+Probe TCP NULL q||
+match ssh m|^SSH-([\d.]+)-OpenSSH_([\w.]+)| p/OpenSSH/ v/$2/
+
+Probe TCP GetRequest q|GET / HTTP/1.0\r\n\r\n|
+ports 80,8000-8002
+match http m|^HTTP/1\.[01] \d\d\d.*\r\nServer: nginx/([\d.]+)|s p/nginx/ v/$1/
+match http m|^HTTP/1\.[01] \d\d\d|
+
+Probe UDP DNSStatus q|\0\0\x10\0|
+`
+
+func TestParseProbes(t *testing.T) {
+	probes, err := ParseProbes(strings.NewReader(sampleProbes))
+	require.NoError(t, err)
+	require.Len(t, probes, 2)
+
+	require.Equal(t, "NULL", probes[0].Name)
+	require.Empty(t, probes[0].Payload)
+	require.True(t, probes[0].appliesTo(22))
+
+	require.Equal(t, "GetRequest", probes[1].Name)
+	require.Equal(t, []byte("GET / HTTP/1.0\r\n\r\n"), probes[1].Payload)
+	require.True(t, probes[1].appliesTo(8001))
+	require.False(t, probes[1].appliesTo(22))
+	require.Len(t, probes[1].Matches, 2)
+}
+
+func TestProbeMatch(t *testing.T) {
+	probes, err := ParseProbes(strings.NewReader(sampleProbes))
+	require.NoError(t, err)
+
+	service, version, ok := probes[0].match([]byte("SSH-2.0-OpenSSH_8.9p1 Ubuntu-3\r\n"))
+	require.True(t, ok)
+	require.Equal(t, "ssh", service)
+	require.Equal(t, "OpenSSH 8.9p1", version)
+
+	service, version, ok = probes[1].match([]byte("HTTP/1.1 200 OK\r\nServer: nginx/1.24.0\r\n\r\n"))
+	require.True(t, ok)
+	require.Equal(t, "http", service)
+	require.Equal(t, "nginx 1.24.0", version)
+
+	_, _, ok = probes[1].match([]byte("garbage"))
+	require.False(t, ok)
+}
+
+func TestParseProbesInvalidRegex(t *testing.T) {
+	_, err := ParseProbes(strings.NewReader("Probe TCP NULL q||\nmatch x m|([|\n"))
+	require.Error(t, err)
+}
+
+func TestParseProbePortsRange(t *testing.T) {
+	ports, err := parseProbePorts("80, 8000-8002")
+	require.NoError(t, err)
+	require.Len(t, ports, 4)
+
+	for _, spec := range []string{"0", "65536", "-1", "90-80", "1-70000"} {
+		_, err := parseProbePorts(spec)
+		require.Error(t, err, spec)
+		require.Contains(t, err.Error(), spec)
+	}
+}
@@ -23,13 +23,15 @@ import (
 	"fmt"
 	"go.uber.org/zap"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/catsayer/ntx/internal/logger"
 	"github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/pool"
 	"github.com/catsayer/ntx/pkg/types"
-	"golang.org/x/sync/semaphore"
 )
 
 // Scanner 定义端口扫描器接口
@@ -44,6 +46,9 @@ type Scanner interface {
 // TCPScanner TCP Connect 扫描器实现
 type TCPScanner struct {
 	timeout time.Duration
+	probes  []*Probe
+	// dial 建立 TCP 连接，测试中可替换
+	dial func(ctx context.Context, d *net.Dialer, address string) (net.Conn, error)
 }
 
 // NewTCPScanner 创建新的 TCP 扫描器
@@ -53,6 +58,11 @@ func NewTCPScanner() *TCPScanner {
 	}
 }
 
+// SetProbes 设置服务探测定义，开放端口会优先按探测结果识别服务
+func (s *TCPScanner) SetProbes(probes []*Probe) {
+	s.probes = probes
+}
+
 // Scan 执行 TCP Connect 扫描
 //
 // 参数:
@@ -79,7 +89,7 @@ func (s *TCPScanner) Scan(ctx context.Context, target string, opts types.ScanOpt
 	startTime := time.Now()
 
 	// 解析目标主机
-	ip, err := resolveTarget(target)
+	ip, err := ResolveTarget(target)
 	if err != nil {
 		return nil, fmt.Errorf("解析目标失败: %w", err)
 	}
@@ -89,53 +99,33 @@ func (s *TCPScanner) Scan(ctx context.Context, target string, opts types.ScanOpt
 		IP:        ip,
 		Ports:     make([]*types.ScanPort, 0),
 		StartTime: startTime,
+		Technique: types.ScanTCPConnect.String(),
+		Timing:    opts.Timing,
 	}
 
-	// 创建扫描结果 channel
-	portCh := make(chan *types.ScanPort, len(opts.Ports))
-
-	// 使用信号量控制并发
-	sem := semaphore.NewWeighted(int64(opts.Concurrency))
-	var wg sync.WaitGroup
-
-	// 并发扫描所有端口
-	for _, port := range opts.Ports {
-		wg.Add(1)
-		go func(p int) {
-			defer wg.Done()
-
-			// 获取信号量
-			if err := sem.Acquire(ctx, 1); err != nil {
-				logger.Error("获取信号量失败", zap.Error(err))
-				return
-			}
-			defer sem.Release(1)
-
-			// 扫描单个端口
-			scanPort := s.scanPort(ctx, ip, p, opts.Timeout)
-
-			// 服务识别
-			if opts.ServiceDetect && scanPort.State == types.PortOpen {
-				scanPort.Service = identifyService(p)
-			}
-
-			portCh <- scanPort
-		}(port)
-	}
-
-	// 等待所有扫描完成
-	go func() {
-		wg.Wait()
-		close(portCh)
-	}()
+	// 并发扫描所有端口，ctx 取消后未扫描的端口不计入结果
+	th := newThrottle(opts.Concurrency)
+	ports, err := pool.Run(ctx, opts.Ports, opts.Concurrency, func(ctx context.Context, p int) (*types.ScanPort, error) {
+		// 扫描单个端口
+		scanPort, err := s.scanPortThrottled(ctx, th, ip, p, opts)
+		if err != nil {
+			return nil, err
+		}
 
-	// 收集结果
-	for scanPort := range portCh {
-		result.Ports = append(result.Ports, scanPort)
+		// 服务识别与 Banner 抓取
+		if (opts.ServiceDetect || opts.BannerGrab) && scanPort.State == types.PortOpen {
+			s.detectService(ctx, scanPort, opts)
+		}
+		return scanPort, nil
+	})
+	if err != nil {
+		logger.Warn("扫描提前结束", zap.String("target", target), zap.Error(err))
 	}
+	result.Ports = append(result.Ports, ports...)
+	sortPorts(result.Ports)
 
 	result.EndTime = time.Now()
-	result.Summary = calculateSummary(result)
+	result.Summary = CalculateSummary(result)
 
 	logger.Info("TCP 扫描完成",
 		zap.String("target", target),
@@ -150,7 +140,7 @@ func (s *TCPScanner) Scan(ctx context.Context, target string, opts types.ScanOpt
 // ScanStream 返回实时扫描结果的 Channel
 func (s *TCPScanner) ScanStream(ctx context.Context, target string, opts types.ScanOptions) (<-chan *types.ScanPort, error) {
 	// 解析目标主机
-	ip, err := resolveTarget(target)
+	ip, err := ResolveTarget(target)
 	if err != nil {
 		return nil, fmt.Errorf("解析目标失败: %w", err)
 	}
@@ -161,7 +151,7 @@ func (s *TCPScanner) ScanStream(ctx context.Context, target string, opts types.S
 	go func() {
 		defer close(portCh)
 
-		sem := semaphore.NewWeighted(int64(opts.Concurrency))
+		th := newThrottle(opts.Concurrency)
 		var wg sync.WaitGroup
 
 		for _, port := range opts.Ports {
@@ -169,15 +159,13 @@ func (s *TCPScanner) ScanStream(ctx context.Context, target string, opts types.S
 			go func(p int) {
 				defer wg.Done()
 
-				if err := sem.Acquire(ctx, 1); err != nil {
+				scanPort, err := s.scanPortThrottled(ctx, th, ip, p, opts)
+				if err != nil {
 					return
 				}
-				defer sem.Release(1)
 
-				scanPort := s.scanPort(ctx, ip, p, opts.Timeout)
-
-				if opts.ServiceDetect && scanPort.State == types.PortOpen {
-					scanPort.Service = identifyService(p)
+				if (opts.ServiceDetect || opts.BannerGrab) && scanPort.State == types.PortOpen {
+					s.detectService(ctx, scanPort, opts)
 				}
 
 				select {
@@ -194,8 +182,36 @@ func (s *TCPScanner) ScanStream(ctx context.Context, target string, opts types.S
 	return portCh, nil
 }
 
+// scanPortThrottled 在并发控制下扫描单个端口
+//
+// 因本地资源不足失败时收缩并发并重试，重试过的端口标记 Retried。
+// 仅在 ctx 取消导致无法获取信号量时返回错误。
+func (s *TCPScanner) scanPortThrottled(ctx context.Context, th *throttle, ip net.IP, port int, opts types.ScanOptions) (*types.ScanPort, error) {
+	for attempt := 0; ; attempt++ {
+		if err := th.acquire(ctx); err != nil {
+			return nil, err
+		}
+		scanPort := s.scanPort(ctx, ip, port, opts.Timeout, opts.SourcePort)
+		th.release()
+
+		scanPort.Retried = attempt > 0
+		// 固定源端口冲突由 --source-port 引起，重试无意义
+		sourceConflict := opts.SourcePort > 0 && netutil.IsSourcePortConflict(scanPort.Error)
+		if !isLocalResourceError(scanPort.Error) || sourceConflict || attempt == maxLocalRetries {
+			return scanPort, nil
+		}
+
+		th.shrink(ctx)
+		select {
+		case <-time.After(time.Duration(attempt+1) * localRetryBackoff):
+		case <-ctx.Done():
+			return scanPort, nil
+		}
+	}
+}
+
 // scanPort 扫描单个端口
-func (s *TCPScanner) scanPort(ctx context.Context, ip net.IP, port int, timeout time.Duration) *types.ScanPort {
+func (s *TCPScanner) scanPort(ctx context.Context, ip net.IP, port int, timeout time.Duration, sourcePort int) *types.ScanPort {
 	startTime := time.Now()
 
 	scanPort := &types.ScanPort{
@@ -207,9 +223,16 @@ func (s *TCPScanner) scanPort(ctx context.Context, ip net.IP, port int, timeout
 
 	// 设置超时
 	d := net.Dialer{Timeout: timeout}
+	netutil.BindSourcePort(&d, sourcePort)
 
 	// 尝试连接
-	conn, err := d.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", ip.String(), port))
+	dial := s.dial
+	if dial == nil {
+		dial = func(ctx context.Context, d *net.Dialer, address string) (net.Conn, error) {
+			return d.DialContext(ctx, "tcp", address)
+		}
+	}
+	conn, err := dial(ctx, &d, fmt.Sprintf("%s:%d", ip.String(), port))
 
 	scanPort.ResponseTime = time.Since(startTime)
 
@@ -217,22 +240,46 @@ func (s *TCPScanner) scanPort(ctx context.Context, ip net.IP, port int, timeout
 		// 判断错误类型
 		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 			scanPort.State = types.PortFiltered
+		} else if sourcePort > 0 && netutil.IsSourcePortConflict(err) {
+			// 本地绑定失败，无法判断目标端口状态
+			scanPort.State = types.PortUnknown
+		} else if isLocalResourceError(err) {
+			// 本机资源耗尽，与目标端口状态无关
+			scanPort.State = types.PortUnknown
 		} else {
 			scanPort.State = types.PortClosed
 		}
-		scanPort.Error = err
+		scanPort.Error = netutil.SourcePortError(err, sourcePort)
 		return scanPort
 	}
 
 	// 连接成功，端口开放
+	if sourcePort > 0 {
+		// 以 RST 关闭，避免固定源端口进入 TIME_WAIT
+		_ = conn.(*net.TCPConn).SetLinger(0)
+	}
 	defer conn.Close()
 	scanPort.State = types.PortOpen
 
 	return scanPort
 }
 
-// resolveTarget 解析目标主机名到 IP 地址
-func resolveTarget(target string) (net.IP, error) {
+// detectService 依次尝试探测文件匹配与 Banner 抓取（BannerGrab），均未识别时按端口号推断服务
+func (s *TCPScanner) detectService(ctx context.Context, scanPort *types.ScanPort, opts types.ScanOptions) {
+	if len(s.probes) > 0 && runProbes(ctx, s.probes, scanPort, opts.Timeout) {
+		return
+	}
+	if opts.BannerGrab {
+		if service := grabBanner(ctx, scanPort, opts.Timeout); service != "" {
+			scanPort.Service = service
+			return
+		}
+	}
+	scanPort.Service = identifyService(scanPort.Port)
+}
+
+// ResolveTarget 解析目标主机名到 IP 地址（优先 IPv4）
+func ResolveTarget(target string) (net.IP, error) {
 	// 尝试直接解析为 IP
 	if ip := net.ParseIP(target); ip != nil {
 		return ip, nil
@@ -269,7 +316,9 @@ func identifyService(port int) string {
 		53:    "dns",
 		80:    "http",
 		110:   "pop3",
+		123:   "ntp",
 		143:   "imap",
+		161:   "snmp",
 		443:   "https",
 		445:   "smb",
 		3306:  "mysql",
@@ -289,8 +338,13 @@ func identifyService(port int) string {
 	return "unknown"
 }
 
-// calculateSummary 计算扫描统计信息
-func calculateSummary(result *types.ScanResult) *types.ScanSummary {
+// sortPorts 按端口号升序排列扫描结果，保证输出稳定
+func sortPorts(ports []*types.ScanPort) {
+	sort.SliceStable(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+}
+
+// CalculateSummary 根据端口结果与起止时间计算扫描统计信息
+func CalculateSummary(result *types.ScanResult) *types.ScanSummary {
 	summary := &types.ScanSummary{
 		TotalPorts: len(result.Ports),
 		Duration:   result.EndTime.Sub(result.StartTime),
@@ -304,6 +358,8 @@ func calculateSummary(result *types.ScanResult) *types.ScanSummary {
 			summary.ClosedPorts++
 		case types.PortFiltered:
 			summary.FilteredPorts++
+		case types.PortOpenFiltered:
+			summary.OpenFilteredPorts++
 		}
 	}
 
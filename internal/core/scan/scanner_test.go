@@ -0,0 +1,125 @@
+package scan
+
+import (
+	"context"
+	"net"
+	"os"
+	"sort"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTCPServer 启动本地 TCP 服务，连接建立后发送固定 Banner
+func setupTCPServer(t *testing.T, banner string) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte(banner))
+			conn.Close()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestTCPScannerSortsPorts(t *testing.T) {
+	ports := []int{setupTCPServer(t, ""), setupTCPServer(t, ""), setupTCPServer(t, "")}
+	// 倒序输入，结果仍按端口号升序
+	sort.Sort(sort.Reverse(sort.IntSlice(ports)))
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = ports
+	opts.Timeout = time.Second
+
+	result, err := NewTCPScanner().Scan(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Len(t, result.Ports, 3)
+	require.True(t, sort.SliceIsSorted(result.Ports, func(i, j int) bool {
+		return result.Ports[i].Port < result.Ports[j].Port
+	}))
+	require.Equal(t, ports[2], result.Ports[0].Port)
+}
+
+func TestTCPScannerRetriesLocalResourceErrors(t *testing.T) {
+	open := setupTCPServer(t, "")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	oldBackoff := localRetryBackoff
+	localRetryBackoff = time.Millisecond
+	t.Cleanup(func() { localRetryBackoff = oldBackoff })
+
+	// 前 2 次拨号模拟文件描述符耗尽
+	var calls atomic.Int32
+	scanner := NewTCPScanner()
+	scanner.dial = func(ctx context.Context, d *net.Dialer, address string) (net.Conn, error) {
+		if calls.Add(1) <= 2 {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("socket", syscall.EMFILE)}
+		}
+		return d.DialContext(ctx, "tcp", address)
+	}
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{open, closed}
+	opts.Concurrency = 4
+	opts.Timeout = time.Second
+
+	result, err := scanner.Scan(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Len(t, result.Ports, 2)
+	require.EqualValues(t, 4, calls.Load())
+
+	states := map[int]*types.ScanPort{}
+	for _, p := range result.Ports {
+		states[p.Port] = p
+	}
+	require.Equal(t, types.PortOpen, states[open].State)
+	require.Equal(t, types.PortClosed, states[closed].State)
+	require.True(t, states[open].Retried)
+	require.True(t, states[closed].Retried)
+}
+
+func TestTCPScannerGivesUpAfterLocalRetries(t *testing.T) {
+	oldBackoff := localRetryBackoff
+	localRetryBackoff = time.Millisecond
+	t.Cleanup(func() { localRetryBackoff = oldBackoff })
+
+	scanner := NewTCPScanner()
+	scanner.dial = func(context.Context, *net.Dialer, string) (net.Conn, error) {
+		return nil, os.NewSyscallError("socket", syscall.ENOBUFS)
+	}
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{80}
+	opts.Timeout = time.Second
+
+	result, err := scanner.Scan(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Equal(t, types.PortUnknown, result.Ports[0].State)
+	require.True(t, result.Ports[0].Retried)
+	require.Error(t, result.Ports[0].Error)
+}
+
+func TestThrottleShrink(t *testing.T) {
+	th := newThrottle(8)
+	th.shrink(context.Background())
+	th.shrink(context.Background())
+	require.Equal(t, 2, th.limit)
+
+	require.True(t, th.sem.TryAcquire(2))
+	require.False(t, th.sem.TryAcquire(1))
+}
@@ -0,0 +1,71 @@
+package scan
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
+)
+
+// maxLocalRetries 端口因本地资源不足失败时的最大重试次数
+const maxLocalRetries = 3
+
+// localRetryBackoff 每次重试前的等待时间基数，第 n 次重试等待 n 倍
+var localRetryBackoff = 50 * time.Millisecond
+
+// throttle 基于信号量的自适应并发控制
+//
+// 出现本地资源错误（文件描述符、缓冲区或临时端口耗尽）时，永久占用一半
+// 当前可用的信号量权重，使本次扫描的有效并发数减半，最低为 1。
+type throttle struct {
+	sem   *semaphore.Weighted
+	mu    sync.Mutex
+	limit int
+}
+
+func newThrottle(concurrency int) *throttle {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &throttle{sem: semaphore.NewWeighted(int64(concurrency)), limit: concurrency}
+}
+
+func (t *throttle) acquire(ctx context.Context) error {
+	return t.sem.Acquire(ctx, 1)
+}
+
+func (t *throttle) release() {
+	t.sem.Release(1)
+}
+
+// shrink 将有效并发数减半，调用方不应持有信号量
+func (t *throttle) shrink(ctx context.Context) {
+	t.mu.Lock()
+	reduce := t.limit / 2
+	if reduce == 0 {
+		t.mu.Unlock()
+		return
+	}
+	t.limit -= reduce
+	limit := t.limit
+	t.mu.Unlock()
+
+	// 占用的权重在扫描结束前不再释放
+	if err := t.sem.Acquire(ctx, int64(reduce)); err != nil {
+		return
+	}
+	logger.Warn("本地资源不足，降低扫描并发", zap.Int("concurrency", limit))
+}
+
+// isLocalResourceError 判断错误是否由本机资源耗尽导致，而非目标主机的响应
+func isLocalResourceError(err error) bool {
+	return stderrors.Is(err, syscall.EMFILE) ||
+		stderrors.Is(err, syscall.ENFILE) ||
+		stderrors.Is(err, syscall.ENOBUFS) ||
+		stderrors.Is(err, syscall.EADDRNOTAVAIL)
+}
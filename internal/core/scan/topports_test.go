@@ -0,0 +1,25 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopPorts(t *testing.T) {
+	require.Equal(t, []int{80, 23, 443, 21, 22, 25, 3389, 110, 445, 139}, TopPorts(10))
+	require.Empty(t, TopPorts(0))
+	require.Len(t, TopPorts(MaxTopPorts+50), MaxTopPorts)
+
+	// 列表中不应有重复端口
+	seen := make(map[int]bool)
+	for _, p := range TopPorts(MaxTopPorts) {
+		require.False(t, seen[p], "重复端口 %d", p)
+		seen[p] = true
+	}
+
+	// 返回副本，修改不影响内置列表
+	ports := TopPorts(1)
+	ports[0] = 1
+	require.Equal(t, 80, TopPorts(1)[0])
+}
@@ -0,0 +1,205 @@
+package scan
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/catsayer/ntx/internal/logger"
+	"github.com/catsayer/ntx/pkg/pool"
+	"github.com/catsayer/ntx/pkg/types"
+	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
+)
+
+// udpPayloads 常见 UDP 服务的探测载荷，空载荷通常得不到应用层响应
+var udpPayloads = map[int][]byte{
+	// DNS: 查询根域 NS 记录
+	53: {0x4e, 0x54, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01},
+	// NTP: v3 客户端请求
+	123: append([]byte{0x1b}, make([]byte, 47)...),
+}
+
+// UDPScanner UDP 扫描器实现
+//
+// 每个端口使用一个已连接的 UDP 套接字发送探测数据报:
+//   - 收到应答: open
+//   - 收到 ICMP 端口不可达（内核以 ECONNREFUSED 回报给已连接套接字）: closed
+//   - 超时无响应: open|filtered
+//
+// 无需 raw socket；平台不回报 ICMP 差错时所有无应答端口均为 open|filtered。
+type UDPScanner struct {
+	timeout time.Duration
+}
+
+// NewUDPScanner 创建新的 UDP 扫描器
+func NewUDPScanner() *UDPScanner {
+	return &UDPScanner{
+		timeout: types.DefaultScanTimeout,
+	}
+}
+
+// Scan 执行 UDP 扫描
+func (s *UDPScanner) Scan(ctx context.Context, target string, opts types.ScanOptions) (*types.ScanResult, error) {
+	logger.Info("开始 UDP 扫描",
+		zap.String("target", target),
+		zap.Int("ports", len(opts.Ports)),
+		zap.Int("concurrency", opts.Concurrency),
+	)
+
+	startTime := time.Now()
+
+	ip, err := ResolveTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("解析目标失败: %w", err)
+	}
+
+	result := &types.ScanResult{
+		Target:    target,
+		IP:        ip,
+		Ports:     make([]*types.ScanPort, 0),
+		StartTime: startTime,
+		Technique: types.ScanUDP.String(),
+		Timing:    opts.Timing,
+	}
+
+	ports, err := pool.Run(ctx, opts.Ports, opts.Concurrency, func(ctx context.Context, p int) (*types.ScanPort, error) {
+		scanPort := s.scanPort(ctx, ip, p, s.probeTimeout(opts))
+		if opts.ServiceDetect && scanPort.State == types.PortOpen {
+			scanPort.Service = identifyService(scanPort.Port)
+		}
+		return scanPort, nil
+	})
+	if err != nil {
+		logger.Warn("扫描提前结束", zap.String("target", target), zap.Error(err))
+	}
+	result.Ports = append(result.Ports, ports...)
+	sortPorts(result.Ports)
+
+	result.EndTime = time.Now()
+	result.Summary = CalculateSummary(result)
+
+	logger.Info("UDP 扫描完成",
+		zap.String("target", target),
+		zap.Int("total", result.Summary.TotalPorts),
+		zap.Int("open", result.Summary.OpenPorts),
+		zap.Duration("duration", result.Summary.Duration),
+	)
+
+	return result, nil
+}
+
+// ScanStream 返回实时扫描结果的 Channel
+func (s *UDPScanner) ScanStream(ctx context.Context, target string, opts types.ScanOptions) (<-chan *types.ScanPort, error) {
+	ip, err := ResolveTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("解析目标失败: %w", err)
+	}
+
+	portCh := make(chan *types.ScanPort, opts.Concurrency)
+
+	go func() {
+		defer close(portCh)
+
+		sem := semaphore.NewWeighted(int64(opts.Concurrency))
+		var wg sync.WaitGroup
+
+		for _, port := range opts.Ports {
+			wg.Add(1)
+			go func(p int) {
+				defer wg.Done()
+
+				if err := sem.Acquire(ctx, 1); err != nil {
+					return
+				}
+				defer sem.Release(1)
+
+				scanPort := s.scanPort(ctx, ip, p, s.probeTimeout(opts))
+				if opts.ServiceDetect && scanPort.State == types.PortOpen {
+					scanPort.Service = identifyService(scanPort.Port)
+				}
+
+				select {
+				case portCh <- scanPort:
+				case <-ctx.Done():
+					return
+				}
+			}(port)
+		}
+
+		wg.Wait()
+	}()
+
+	return portCh, nil
+}
+
+// probeTimeout 返回单个端口的等待时间
+func (s *UDPScanner) probeTimeout(opts types.ScanOptions) time.Duration {
+	if opts.Timeout > 0 {
+		return opts.Timeout
+	}
+	return s.timeout
+}
+
+// scanPort 向单个端口发送探测数据报并根据响应判断状态
+func (s *UDPScanner) scanPort(ctx context.Context, ip net.IP, port int, timeout time.Duration) *types.ScanPort {
+	startTime := time.Now()
+
+	scanPort := &types.ScanPort{
+		IP:    ip,
+		Port:  port,
+		Proto: "udp",
+		State: types.PortUnknown,
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(ip.String(), fmt.Sprint(port)))
+	if err != nil {
+		scanPort.Error = err
+		return scanPort
+	}
+	defer conn.Close()
+
+	deadline := startTime.Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(udpPayloads[port]); err != nil {
+		scanPort.State = classifyUDPError(err)
+		scanPort.Error = err
+		return scanPort
+	}
+
+	buf := make([]byte, 1024)
+	_, err = conn.Read(buf)
+	scanPort.ResponseTime = time.Since(startTime)
+	if err != nil {
+		scanPort.State = classifyUDPError(err)
+		if scanPort.State == types.PortUnknown {
+			scanPort.Error = err
+		}
+		return scanPort
+	}
+
+	// 应答多为二进制协议数据，不作为 Banner 展示
+	scanPort.State = types.PortOpen
+	return scanPort
+}
+
+// classifyUDPError 将探测过程中的错误映射为端口状态
+func classifyUDPError(err error) types.PortState {
+	if stderrors.Is(err, syscall.ECONNREFUSED) {
+		return types.PortClosed
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return types.PortOpenFiltered
+	}
+	return types.PortUnknown
+}
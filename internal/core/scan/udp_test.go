@@ -0,0 +1,89 @@
+package scan
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// startUDPServer 启动本地 UDP 服务，echo 为 false 时只接收不应答
+func startUDPServer(t *testing.T, echo bool) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if echo {
+				// 空探测也需要应答，否则无法与无响应区分
+				_, _ = conn.WriteTo(append([]byte("echo:"), buf[:n]...), addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// closedUDPPort 返回一个当前无人监听的本地 UDP 端口
+func closedUDPPort(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, conn.Close())
+	return port
+}
+
+func TestUDPScannerLoopback(t *testing.T) {
+	open := startUDPServer(t, true)
+	silent := startUDPServer(t, false)
+	closed := closedUDPPort(t)
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{open, silent, closed}
+	opts.Timeout = 300 * time.Millisecond
+	opts.ScanMode = types.ScanUDP
+
+	result, err := NewUDPScanner().Scan(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.Equal(t, "udp", result.Technique)
+
+	states := make(map[int]types.PortState)
+	for _, p := range result.Ports {
+		require.Equal(t, "udp", p.Proto)
+		states[p.Port] = p.State
+	}
+	require.Equal(t, types.PortOpen, states[open])
+	require.Equal(t, types.PortOpenFiltered, states[silent])
+	require.Equal(t, types.PortClosed, states[closed])
+
+	require.Equal(t, 1, result.Summary.OpenPorts)
+	require.Equal(t, 1, result.Summary.ClosedPorts)
+	require.Equal(t, 1, result.Summary.OpenFilteredPorts)
+}
+
+func TestUDPScannerStream(t *testing.T) {
+	open := startUDPServer(t, true)
+
+	opts := types.DefaultScanOptions()
+	opts.Ports = []int{open}
+	opts.Timeout = 300 * time.Millisecond
+
+	ch, err := NewUDPScanner().ScanStream(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	var ports []*types.ScanPort
+	for p := range ch {
+		ports = append(ports, p)
+	}
+	require.Len(t, ports, 1)
+	require.Equal(t, types.PortOpen, ports[0].State)
+}
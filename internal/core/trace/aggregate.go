@@ -0,0 +1,98 @@
+package trace
+
+import (
+	"sort"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/stats"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// Aggregate 汇总多轮 Traceroute 结果，统计各跳出现率、RTT 与地址变化，
+// 用于发现负载均衡或抖动的路由。results 中的 nil 会被忽略。
+func Aggregate(results []*types.TraceResult) *types.TraceAggregate {
+	agg := &types.TraceAggregate{}
+	hopsByTTL := make(map[int]*types.TraceHopStats)
+	rttsByTTL := make(map[int][]time.Duration)
+	var order []int
+	prevPath := map[int]string{}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		agg.Runs++
+		if agg.Target == nil {
+			agg.Target = result.Target
+			agg.Protocol = result.Protocol
+		}
+		if result.ReachedDestination {
+			agg.Reached++
+		}
+		agg.Results = append(agg.Results, result)
+
+		path := make(map[int]string, len(result.Hops))
+		for _, hop := range result.Hops {
+			hs, ok := hopsByTTL[hop.TTL]
+			if !ok {
+				hs = &types.TraceHopStats{TTL: hop.TTL}
+				hopsByTTL[hop.TTL] = hs
+				order = append(order, hop.TTL)
+			}
+			hs.Runs++
+			if hop.IP != "" {
+				hs.Responded++
+				path[hop.TTL] = hop.IP
+			}
+			for _, probe := range hop.Probes {
+				if probe.Status != types.StatusSuccess || probe.IP == "" {
+					continue
+				}
+				rttsByTTL[hop.TTL] = append(rttsByTTL[hop.TTL], probe.RTT)
+				addr := findHopAddress(hs, probe.IP)
+				if addr == nil {
+					addr = &types.TraceHopAddress{IP: probe.IP, Hostname: probe.IP}
+					hs.Addresses = append(hs.Addresses, addr)
+				}
+				if probe.IP == hop.IP && hop.Hostname != "" {
+					addr.Hostname = hop.Hostname
+				}
+				addr.Responses++
+			}
+		}
+
+		// 仅比较两轮都有响应的跳，超时不算路径变化
+		if agg.Runs > 1 {
+			for _, ttl := range order {
+				from, to := prevPath[ttl], path[ttl]
+				if from != "" && to != "" && from != to {
+					agg.Changes = append(agg.Changes, &types.TracePathChange{Run: agg.Runs, TTL: ttl, From: from, To: to})
+				}
+			}
+		}
+		prevPath = path
+	}
+
+	sort.Ints(order)
+	for _, ttl := range order {
+		hs := hopsByTTL[ttl]
+		if hs.Runs > 0 {
+			hs.AppearanceRate = float64(hs.Responded) / float64(hs.Runs) * 100
+		}
+		if rtts := rttsByTTL[ttl]; len(rtts) > 0 {
+			hs.MinRTT, hs.MaxRTT, hs.AvgRTT, _ = stats.ComputeRTTStats(rtts)
+		}
+		hs.Stable = len(hs.Addresses) <= 1
+		agg.Hops = append(agg.Hops, hs)
+	}
+	return agg
+}
+
+func findHopAddress(hs *types.TraceHopStats, ip string) *types.TraceHopAddress {
+	for _, addr := range hs.Addresses {
+		if addr.IP == ip {
+			return addr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func traceRun(reached bool, hops ...string) *types.TraceResult {
+	result := &types.TraceResult{
+		Target:             &types.Host{Hostname: "example.com", IP: "192.0.2.9"},
+		Protocol:           types.ProtocolICMP,
+		ReachedDestination: reached,
+	}
+	for i, ip := range hops {
+		hop := &types.TraceHop{TTL: i + 1, IP: ip, Hostname: ip}
+		if ip == "" {
+			hop.Probes = []*types.TraceProbe{{Seq: 1, Status: types.StatusTimeout}}
+		} else {
+			hop.Probes = []*types.TraceProbe{{Seq: 1, IP: ip, RTT: time.Duration(i+1) * time.Millisecond, Status: types.StatusSuccess}}
+		}
+		result.AddHop(hop)
+	}
+	return result
+}
+
+func TestAggregate(t *testing.T) {
+	agg := Aggregate([]*types.TraceResult{
+		traceRun(true, "10.0.0.1", "198.51.100.1", "192.0.2.9"),
+		traceRun(true, "10.0.0.1", "198.51.100.2", "192.0.2.9"),
+		traceRun(false, "10.0.0.1", "", "192.0.2.9"),
+		nil,
+	})
+
+	require.Equal(t, 3, agg.Runs)
+	require.Equal(t, 2, agg.Reached)
+	require.Equal(t, "example.com", agg.Target.Hostname)
+	require.Len(t, agg.Hops, 3)
+
+	first := agg.Hops[0]
+	require.True(t, first.Stable)
+	require.Equal(t, 100.0, first.AppearanceRate)
+	require.Equal(t, 3, first.Addresses[0].Responses)
+	require.Equal(t, time.Millisecond, first.AvgRTT)
+
+	second := agg.Hops[1]
+	require.False(t, second.Stable)
+	require.Equal(t, 2, second.Responded)
+	require.InDelta(t, 66.67, second.AppearanceRate, 0.01)
+	require.Len(t, second.Addresses, 2)
+
+	// 超时不算路径变化
+	require.Len(t, agg.Changes, 1)
+	require.Equal(t, &types.TracePathChange{Run: 2, TTL: 2, From: "198.51.100.1", To: "198.51.100.2"}, agg.Changes[0])
+}
@@ -8,6 +8,7 @@ package trace
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"net"
 	"os"
@@ -28,6 +29,10 @@ const (
 	ProtocolICMP = 1
 	// ProtocolIPv6ICMP ICMPv6 协议号
 	ProtocolIPv6ICMP = 58
+	// ProtocolTCP TCP 协议号
+	ProtocolTCP = 6
+	// ProtocolUDP UDP 协议号
+	ProtocolUDP = 17
 )
 
 // ICMPTracer ICMP Traceroute 实现
@@ -35,6 +40,8 @@ type ICMPTracer struct {
 	conn4 *icmp.PacketConn
 	conn6 *icmp.PacketConn
 	id    int
+	// datagram 为 true 时使用非特权 ICMP 套接字，内核会改写 Echo ID
+	datagram bool
 }
 
 // NewICMPTracer 创建 ICMP Tracer
@@ -43,29 +50,23 @@ func NewICMPTracer() (*ICMPTracer, error) {
 		id: os.Getpid() & 0xffff,
 	}
 
-	// 尝试打开 ICMPv4 连接
-	network := "ip4:icmp"
-	if runtime.GOOS == "darwin" || runtime.GOOS == "linux" {
-		network = "udp4"
+	// 优先使用原始套接字，TTL/HopLimit 可逐跳设置且 Echo ID 不会被内核改写；
+	// 无权限时在 Linux/macOS 上退回非特权 ICMP 套接字
+	conn4, err := t.listen(4)
+	if err != nil && datagramICMPSupported() {
+		t.datagram = true
+		if conn4, err = t.listen(4); err != nil {
+			t.datagram = false
+		}
 	}
-
-	conn4, err := icmp.ListenPacket(network, "0.0.0.0")
 	if err != nil {
 		return nil, errors.NewPermissionError("icmp traceroute", "raw socket",
 			"需要 root 权限或 CAP_NET_RAW 能力")
 	}
 	t.conn4 = conn4
 
-	// 尝试打开 ICMPv6 连接
-	network6 := "ip6:ipv6-icmp"
-	if runtime.GOOS == "darwin" || runtime.GOOS == "linux" {
-		network6 = "udp6"
-	}
-
-	conn6, err := icmp.ListenPacket(network6, "::")
-	if err != nil {
-		t.conn6 = nil
-	} else {
+	// ICMPv6 与 IPv4 使用同一类套接字，保证 Echo ID 的匹配方式一致
+	if conn6, err := t.listen(6); err == nil {
 		t.conn6 = conn6
 	}
 
@@ -99,106 +100,7 @@ func (t *ICMPTracer) Trace(ctx context.Context, target string, opts *types.Trace
 		return nil, errors.NewPermissionError("icmp traceroute", "ipv6", "connection not available")
 	}
 
-	// 创建结果对象
-	result := &types.TraceResult{
-		Target: &types.Host{
-			Hostname:  target,
-			IP:        hostInfo.IP,
-			IPVersion: hostInfo.IPVersion,
-		},
-		Protocol:           types.ProtocolICMP,
-		Hops:               make([]*types.TraceHop, 0, opts.MaxHops),
-		ReachedDestination: false,
-		Context: &types.ExecutionContext{
-			StartTime: time.Now(),
-		},
-		Status: types.StatusSuccess,
-	}
-
-	// 获取主机名
-	hostname, _ := os.Hostname()
-	result.Context.Hostname = hostname
-
-	// 执行 Traceroute
-	for ttl := opts.FirstTTL; ttl <= opts.MaxHops; ttl++ {
-		if err := ctx.Err(); err != nil {
-			result.Error = err
-			result.Status = types.StatusFailure
-			break
-		}
-
-		hop := t.traceHop(ctx, hostInfo.IP, ttl, opts)
-		result.AddHop(hop)
-
-		// 检查是否到达目标
-		if hop.IsDestination {
-			result.ReachedDestination = true
-			break
-		}
-
-		// 如果所有探测都失败，继续但记录
-		if hop.GetSuccessCount() == 0 {
-			// 连续多跳失败可能表示路径阻塞
-			if ttl > opts.FirstTTL+5 {
-				lastFiveAllFailed := true
-				for i := len(result.Hops) - 1; i >= 0 && i >= len(result.Hops)-5; i-- {
-					if result.Hops[i].GetSuccessCount() > 0 {
-						lastFiveAllFailed = false
-						break
-					}
-				}
-				if lastFiveAllFailed {
-					break
-				}
-			}
-		}
-	}
-
-	// 更新上下文
-	result.Context.EndTime = time.Now()
-	result.Context.Duration = result.Context.EndTime.Sub(result.Context.StartTime)
-
-	// 判断整体状态
-	if !result.ReachedDestination && result.HopCount == 0 {
-		result.Status = types.StatusFailure
-	} else if !result.ReachedDestination {
-		result.Status = types.StatusTimeout
-	}
-
-	return result, nil
-}
-
-// traceHop 追踪单个跳
-func (t *ICMPTracer) traceHop(ctx context.Context, targetIP string, ttl int, opts *types.TraceOptions) *types.TraceHop {
-	hop := &types.TraceHop{
-		TTL:    ttl,
-		Probes: make([]*types.TraceProbe, 0, opts.Queries),
-	}
-
-	// 执行多次探测
-	for i := 0; i < opts.Queries; i++ {
-		probe := t.probeOnce(ctx, targetIP, ttl, i+1, opts)
-		hop.Probes = append(hop.Probes, probe)
-
-		// 记录 IP 和主机名（使用第一个成功的响应）
-		if probe.Status == types.StatusSuccess && hop.IP == "" {
-			hop.IP = probe.IP
-
-			// 尝试反向 DNS 解析
-			if names, err := net.LookupAddr(probe.IP); err == nil && len(names) > 0 {
-				hop.Hostname = names[0]
-			} else {
-				hop.Hostname = probe.IP
-			}
-
-			// 检查是否为目标
-			if probe.IP == targetIP {
-				hop.IsDestination = true
-			}
-		}
-	}
-
-	return hop
+	return runTrace(ctx, target, hostInfo, types.ProtocolICMP, opts, t.probeOnce), nil
 }
 
 // probeOnce 执行单次探测
@@ -216,6 +118,9 @@ func (t *ICMPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq in
 		return probe
 	}
 
+	// 每个探测使用唯一序列号，避免将上一跳迟到的响应计入本跳
+	wireSeq := probeSeq(ttl, seq)
+
 	// 选择连接和消息类型
 	var conn *icmp.PacketConn
 	var msgType icmp.Type
@@ -231,22 +136,37 @@ func (t *ICMPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq in
 		ipVersion = 6
 	}
 
-	// 创建 ICMP 消息
+	// 并行模式下每个探测使用独立的套接字，避免争抢响应与 TTL 设置：
+	// 原始套接字各自收到全部 ICMP 报文后按序列号过滤，非特权套接字由内核按 ID 分发
+	if opts.Parallel {
+		if conn, err = t.listen(ipVersion); err != nil {
+			probe.Status = types.StatusFailure
+			probe.Error = err.Error()
+			return probe
+		}
+		defer conn.Close()
+	}
+
+	// 创建 ICMP 消息；Paris 模式使用固定数据以保持校验和不变，否则填充随机数据
+	var data []byte
+	if opts.Paris {
+		data = parisPayload(opts.PacketSize, opts.FlowID, wireSeq)
+	} else {
+		data = make([]byte, opts.PacketSize)
+		for i := range data {
+			data[i] = byte(rand.Intn(256))
+		}
+	}
 	msg := &icmp.Message{
 		Type: msgType,
 		Code: 0,
 		Body: &icmp.Echo{
 			ID:   t.id,
-			Seq:  seq,
-			Data: make([]byte, opts.PacketSize),
+			Seq:  wireSeq,
+			Data: data,
 		},
 	}
 
-	// 填充随机数据
-	for i := range msg.Body.(*icmp.Echo).Data {
-		msg.Body.(*icmp.Echo).Data[i] = byte(rand.Intn(256))
-	}
-
 	// 序列化消息
 	msgBytes, err := msg.Marshal(nil)
 	if err != nil {
@@ -255,15 +175,11 @@ func (t *ICMPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq in
 		return probe
 	}
 
-	// 尽可能设置 TTL/HopLimit，若内核不支持则继续执行探测
-	if ipVersion == 4 {
-		if err := conn.IPv4PacketConn().SetTTL(ttl); err != nil {
-			// 保持兼容：无法设置 TTL 时继续探测，最终由超时/响应决定结果
-		}
-	} else {
-		if err := conn.IPv6PacketConn().SetHopLimit(ttl); err != nil {
-			// 保持兼容：无法设置 HopLimit 时继续探测，最终由超时/响应决定结果
-		}
+	// 发送前设置本跳的 TTL/HopLimit；未生效时所有探测都会直达目标，必须视为失败
+	if err := setProbeTTL(conn, ipVersion, ttl); err != nil {
+		probe.Status = types.StatusFailure
+		probe.Error = err.Error()
+		return probe
 	}
 
 	// 设置超时
@@ -291,8 +207,12 @@ func (t *ICMPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq in
 		return probe
 	}
 
-	// 发送 ICMP 请求
-	_, err = conn.WriteTo(msgBytes, dst)
+	// 发送 ICMP 请求，非特权套接字需要 UDPAddr 形式的目标地址
+	var dstAddr net.Addr = dst
+	if t.datagram {
+		dstAddr = &net.UDPAddr{IP: dst.IP, Zone: dst.Zone}
+	}
+	_, err = conn.WriteTo(msgBytes, dstAddr)
 	if err != nil {
 		probe.Status = types.StatusFailure
 		probe.Error = err.Error()
@@ -344,23 +264,33 @@ func (t *ICMPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq in
 		switch rm.Type {
 		case ipv4.ICMPTypeEchoReply, ipv6.ICMPTypeEchoReply:
 			// 到达目标
-			if echo, ok := rm.Body.(*icmp.Echo); ok {
-				if echo.ID == t.id && echo.Seq == seq {
-					probe.RTT = rtt
-					probe.IP = peer.String()
-					return probe
-				}
+			if echo, ok := rm.Body.(*icmp.Echo); ok && t.ownsEcho(echo.ID, echo.Seq, wireSeq) {
+				probe.RTT = rtt
+				probe.IP = addrIP(peer)
+				return probe
 			}
 		case ipv4.ICMPTypeTimeExceeded, ipv6.ICMPTypeTimeExceeded:
 			// 中间路由器响应
+			body, ok := rm.Body.(*icmp.TimeExceeded)
+			if ok && !t.ownsQuoted(ipVersion, body.Data, wireSeq) {
+				continue
+			}
 			probe.RTT = rtt
-			probe.IP = peer.String()
+			probe.IP = addrIP(peer)
+			if rm.Code != 0 {
+				probe.Status = types.StatusFailure
+				probe.Error = timeExceededReason(ipVersion, rm.Code)
+			}
 			return probe
 		case ipv4.ICMPTypeDestinationUnreachable, ipv6.ICMPTypeDestinationUnreachable:
 			// 目标不可达
+			body, ok := rm.Body.(*icmp.DstUnreach)
+			if ok && !t.ownsQuoted(ipVersion, body.Data, wireSeq) {
+				continue
+			}
 			probe.Status = types.StatusFailure
-			probe.Error = "destination unreachable"
-			probe.IP = peer.String()
+			probe.Error = unreachableReason(ipVersion, rm.Code)
+			probe.IP = addrIP(peer)
 			return probe
 		}
 
@@ -372,6 +302,53 @@ func (t *ICMPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq in
 	}
 }
 
+// listen 按当前套接字类型打开指定 IP 版本的 ICMP 连接
+func (t *ICMPTracer) listen(ipVersion int) (*icmp.PacketConn, error) {
+	if ipVersion == 6 {
+		if t.datagram {
+			return icmp.ListenPacket("udp6", "::")
+		}
+		return icmp.ListenPacket("ip6:ipv6-icmp", "::")
+	}
+	if t.datagram {
+		return icmp.ListenPacket("udp4", "0.0.0.0")
+	}
+	return icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+}
+
+// datagramICMPSupported 当前平台是否支持非特权 ICMP 套接字
+func datagramICMPSupported() bool {
+	return runtime.GOOS == "darwin" || runtime.GOOS == "linux"
+}
+
+// setProbeTTL 设置后续发出报文的 TTL (IPv4) 或 HopLimit (IPv6)
+func setProbeTTL(conn *icmp.PacketConn, ipVersion, ttl int) error {
+	if ipVersion == 4 {
+		if err := conn.IPv4PacketConn().SetTTL(ttl); err != nil {
+			return fmt.Errorf("设置 TTL 失败: %w", err)
+		}
+		return nil
+	}
+	if err := conn.IPv6PacketConn().SetHopLimit(ttl); err != nil {
+		return fmt.Errorf("设置 HopLimit 失败: %w", err)
+	}
+	return nil
+}
+
+// ownsEcho 判断 Echo 报文是否属于本次探测
+func (t *ICMPTracer) ownsEcho(id, seq, wireSeq int) bool {
+	return seq == wireSeq && (t.datagram || id == t.id)
+}
+
+// ownsQuoted 判断差错报文引用的原始请求是否属于本次探测，无法解析时宽松接受
+func (t *ICMPTracer) ownsQuoted(ipVersion int, data []byte, wireSeq int) bool {
+	id, seq, ok := quotedEcho(ipVersion, data)
+	if !ok {
+		return true
+	}
+	return t.ownsEcho(id, seq, wireSeq)
+}
+
 // Close 关闭资源
 func (t *ICMPTracer) Close() error {
 	var err error
@@ -0,0 +1,179 @@
+package trace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// IPv6 扩展头的 Next Header 值
+const (
+	ipv6HopByHop     = 0
+	ipv6Routing      = 43
+	ipv6Fragment     = 44
+	ipv6AuthHeader   = 51
+	ipv6DestOptions  = 60
+	ipv6HeaderLength = 40
+)
+
+// probeSeq 为每个探测生成唯一的 ICMP 序列号，便于区分迟到的响应
+func probeSeq(ttl, query int) int {
+	return (ttl&0xfff)<<4 | (query & 0xf)
+}
+
+// addrIP 提取对端地址中的 IP 文本，兼容 ip 与 udp 两类 ICMP 套接字
+func addrIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.IPAddr:
+		return a.IP.String()
+	case *net.UDPAddr:
+		return a.IP.String()
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return addr.String()
+		}
+		return host
+	}
+}
+
+// quotedPacket 解析 ICMP 差错报文中引用的原始 IP 报文，返回上层协议号、目的地址与上层报文数据
+//
+// ipVersion 为 6 时会跳过 Hop-by-Hop、Routing、Fragment、Destination Options
+// 和 AH 等扩展头，找到内层上层报文。
+func quotedPacket(ipVersion int, data []byte) (proto int, dst net.IP, payload []byte, ok bool) {
+	var offset int
+
+	if ipVersion == 4 {
+		if len(data) < 20 || data[0]>>4 != 4 {
+			return 0, nil, nil, false
+		}
+		offset = int(data[0]&0x0f) * 4
+		proto = int(data[9])
+		dst = net.IP(data[16:20])
+	} else {
+		if len(data) < ipv6HeaderLength || data[0]>>4 != 6 {
+			return 0, nil, nil, false
+		}
+		proto = int(data[6])
+		dst = net.IP(data[24:40])
+		offset = ipv6HeaderLength
+	extLoop:
+		for {
+			if len(data) < offset+8 {
+				return 0, nil, nil, false
+			}
+			switch proto {
+			case ipv6HopByHop, ipv6Routing, ipv6DestOptions:
+				proto = int(data[offset])
+				offset += (int(data[offset+1]) + 1) * 8
+			case ipv6Fragment:
+				proto = int(data[offset])
+				offset += 8
+			case ipv6AuthHeader:
+				proto = int(data[offset])
+				offset += (int(data[offset+1]) + 2) * 4
+			default:
+				break extLoop
+			}
+		}
+	}
+
+	if len(data) < offset {
+		return 0, nil, nil, false
+	}
+	return proto, dst, data[offset:], true
+}
+
+// quotedEcho 解析 ICMP 差错报文中引用的原始 Echo 请求，返回其 ID 和序列号
+func quotedEcho(ipVersion int, data []byte) (id, seq int, ok bool) {
+	proto, _, payload, ok := quotedPacket(ipVersion, data)
+	if !ok {
+		return 0, 0, false
+	}
+	if (ipVersion == 4 && proto != ProtocolICMP) || (ipVersion == 6 && proto != ProtocolIPv6ICMP) {
+		return 0, 0, false
+	}
+
+	// ICMP Echo: type(1) code(1) checksum(2) id(2) seq(2)
+	if len(payload) < 8 {
+		return 0, 0, false
+	}
+	id = int(binary.BigEndian.Uint16(payload[4:6]))
+	seq = int(binary.BigEndian.Uint16(payload[6:8]))
+	return id, seq, true
+}
+
+// quotedTCP 解析 ICMP 差错报文中引用的原始 TCP 报文，返回目的地址和端口
+func quotedTCP(ipVersion int, data []byte) (dst net.IP, srcPort, dstPort int, ok bool) {
+	return quotedPorts(ipVersion, data, ProtocolTCP)
+}
+
+// quotedUDP 解析 ICMP 差错报文中引用的原始 UDP 报文，返回目的地址和端口
+func quotedUDP(ipVersion int, data []byte) (dst net.IP, srcPort, dstPort int, ok bool) {
+	return quotedPorts(ipVersion, data, ProtocolUDP)
+}
+
+// quotedPorts 读取引用报文的目的地址与传输层端口（TCP/UDP 头前 4 字节布局相同）
+func quotedPorts(ipVersion int, data []byte, want int) (dst net.IP, srcPort, dstPort int, ok bool) {
+	proto, dst, payload, ok := quotedPacket(ipVersion, data)
+	if !ok || proto != want || len(payload) < 4 {
+		return nil, 0, 0, false
+	}
+	srcPort = int(binary.BigEndian.Uint16(payload[0:2]))
+	dstPort = int(binary.BigEndian.Uint16(payload[2:4]))
+	return dst, srcPort, dstPort, true
+}
+
+// unreachableReason 按 IP 版本解释 Destination Unreachable 的代码
+func unreachableReason(ipVersion, code int) string {
+	if ipVersion == 6 {
+		switch code {
+		case 0:
+			return "no route to destination"
+		case 1:
+			return "communication administratively prohibited"
+		case 2:
+			return "beyond scope of source address"
+		case 3:
+			return "address unreachable"
+		case 4:
+			return "port unreachable"
+		case 5:
+			return "source address failed ingress/egress policy"
+		case 6:
+			return "reject route to destination"
+		}
+		return fmt.Sprintf("destination unreachable (code %d)", code)
+	}
+
+	switch code {
+	case 0:
+		return "network unreachable"
+	case 1:
+		return "host unreachable"
+	case 2:
+		return "protocol unreachable"
+	case 3:
+		return "port unreachable"
+	case 4:
+		return "fragmentation needed"
+	case 9, 10, 13:
+		return "communication administratively prohibited"
+	}
+	return fmt.Sprintf("destination unreachable (code %d)", code)
+}
+
+// timeExceededReason 解释 Time Exceeded 的代码，两种协议含义一致
+func timeExceededReason(ipVersion, code int) string {
+	switch code {
+	case 0:
+		if ipVersion == 6 {
+			return "hop limit exceeded in transit"
+		}
+		return "ttl exceeded in transit"
+	case 1:
+		return "fragment reassembly time exceeded"
+	}
+	return fmt.Sprintf("time exceeded (code %d)", code)
+}
@@ -0,0 +1,126 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv6"
+)
+
+func echoBytes(t *testing.T, id, seq int) []byte {
+	t.Helper()
+	msg := &icmp.Message{
+		Type: ipv6.ICMPTypeEchoRequest,
+		Body: &icmp.Echo{ID: id, Seq: seq},
+	}
+	b, err := msg.Marshal(nil)
+	require.NoError(t, err)
+	return b
+}
+
+func ipv6Header(nextHeader byte) []byte {
+	h := make([]byte, ipv6HeaderLength)
+	h[0] = 6 << 4
+	h[6] = nextHeader
+	h[7] = 1
+	return h
+}
+
+func TestQuotedEchoIPv4(t *testing.T) {
+	header := make([]byte, 20)
+	header[0] = 0x45
+	header[9] = ProtocolICMP
+	data := append(header, echoBytes(t, 0x1234, 42)...)
+
+	id, seq, ok := quotedEcho(4, data)
+	require.True(t, ok)
+	require.Equal(t, 0x1234, id)
+	require.Equal(t, 42, seq)
+}
+
+func TestQuotedTCP(t *testing.T) {
+	header := make([]byte, 20)
+	header[0] = 0x45
+	header[9] = ProtocolTCP
+	copy(header[16:20], net.IPv4(93, 184, 216, 34).To4())
+	// TCP 头前 8 字节: 源端口 40000，目的端口 443，序列号
+	data := append(header, 0x9c, 0x40, 0x01, 0xbb, 0, 0, 0, 1)
+
+	dst, srcPort, dstPort, ok := quotedTCP(4, data)
+	require.True(t, ok)
+	require.Equal(t, "93.184.216.34", dst.String())
+	require.Equal(t, 40000, srcPort)
+	require.Equal(t, 443, dstPort)
+
+	_, _, ok = quotedEcho(4, data)
+	require.False(t, ok)
+}
+
+func TestQuotedEchoIPv6(t *testing.T) {
+	data := append(ipv6Header(ProtocolIPv6ICMP), echoBytes(t, 7, 99)...)
+
+	id, seq, ok := quotedEcho(6, data)
+	require.True(t, ok)
+	require.Equal(t, 7, id)
+	require.Equal(t, 99, seq)
+}
+
+func TestQuotedEchoIPv6ExtensionHeaders(t *testing.T) {
+	data := ipv6Header(ipv6HopByHop)
+	// Hop-by-Hop: next=Fragment, len=0 (8 字节)
+	data = append(data, ipv6Fragment, 0, 0, 0, 0, 0, 0, 0)
+	// Fragment: next=ICMPv6，固定 8 字节
+	data = append(data, ProtocolIPv6ICMP, 0, 0, 0, 0, 0, 0, 1)
+	data = append(data, echoBytes(t, 5, 17)...)
+
+	id, seq, ok := quotedEcho(6, data)
+	require.True(t, ok)
+	require.Equal(t, 5, id)
+	require.Equal(t, 17, seq)
+
+	_, _, ok = quotedEcho(6, data[:ipv6HeaderLength+4])
+	require.False(t, ok)
+}
+
+func TestUnreachableReason(t *testing.T) {
+	require.Equal(t, "port unreachable", unreachableReason(6, 4))
+	require.Equal(t, "port unreachable", unreachableReason(4, 3))
+	require.Equal(t, "address unreachable", unreachableReason(6, 3))
+	require.Equal(t, "hop limit exceeded in transit", timeExceededReason(6, 0))
+}
+
+func TestAddrIP(t *testing.T) {
+	require.Equal(t, "2001:db8::1", addrIP(&net.UDPAddr{IP: net.ParseIP("2001:db8::1")}))
+	require.Equal(t, "192.0.2.1", addrIP(&net.IPAddr{IP: net.ParseIP("192.0.2.1")}))
+}
+
+func TestTraceIPv6Loopback(t *testing.T) {
+	tracer, err := NewICMPTracer()
+	if err != nil {
+		t.Skipf("ICMP 套接字不可用: %v", err)
+	}
+	defer tracer.Close()
+	if tracer.conn6 == nil {
+		t.Skip("ICMPv6 套接字不可用")
+	}
+
+	opts := types.DefaultTraceOptions()
+	opts.IPVersion = types.IPv6
+	opts.MaxHops = 3
+	opts.Queries = 1
+	opts.Timeout = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := tracer.Trace(ctx, "::1", opts)
+	require.NoError(t, err)
+	require.True(t, result.ReachedDestination)
+	require.Len(t, result.Hops, 1)
+	require.Equal(t, "::1", result.Hops[0].IP)
+}
@@ -0,0 +1,33 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICMPTracerSetsTTLPerHop(t *testing.T) {
+	tracer, err := NewICMPTracer()
+	if errors.IsPermissionDenied(err) {
+		t.Skip("需要 root 权限或 CAP_NET_RAW 能力")
+	}
+	require.NoError(t, err)
+	defer tracer.Close()
+
+	opts := types.DefaultTraceOptions()
+	opts.Timeout = time.Second
+
+	for ttl := 1; ttl <= 3; ttl++ {
+		probe := tracer.probeOnce(context.Background(), "127.0.0.1", ttl, 1, opts)
+		require.Equal(t, types.StatusSuccess, probe.Status, probe.Error)
+		require.Equal(t, "127.0.0.1", probe.IP)
+
+		got, err := tracer.conn4.IPv4PacketConn().TTL()
+		require.NoError(t, err)
+		require.Equal(t, ttl, got, "第 %d 跳的探测应使用 TTL %d", ttl, ttl)
+	}
+}
@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/whois"
+	"github.com/catsayer/ntx/pkg/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// originLookupTimeout 单个地址的 AS 查询超时
+const originLookupTimeout = 5 * time.Second
+
+// originLookup 查询 IP 所属的 AS 与国家，测试中可替换
+var originLookup = func(ctx context.Context, ip string) (*types.WhoisData, error) {
+	return whois.NewClient().LookupOrigin(ctx, ip, originLookupTimeout)
+}
+
+var defaultOriginCache = &originCache{entries: make(map[string]*types.WhoisData)}
+
+// originCache 进程内的 AS 查询缓存
+//
+// 查询失败或无 AS 信息的地址也会缓存为 nil，避免重复查询；
+// 私有、回环、链路本地等地址不会发起查询。
+type originCache struct {
+	mu      sync.Mutex
+	entries map[string]*types.WhoisData
+	group   singleflight.Group
+}
+
+// lookup 返回 ip 的 AS 信息，无可用信息时返回 false
+func (c *originCache) lookup(ctx context.Context, ip string) (*types.WhoisData, bool) {
+	if !routableIP(ip) {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	data, cached := c.entries[ip]
+	c.mu.Unlock()
+	if cached {
+		return data, data != nil
+	}
+
+	v, _, _ := c.group.Do(ip, func() (interface{}, error) {
+		data, err := originLookup(ctx, ip)
+		if err != nil {
+			data = nil
+			if ctx.Err() != nil {
+				// 被取消的查询不缓存，下次仍可重试
+				return data, nil
+			}
+		}
+		c.mu.Lock()
+		c.entries[ip] = data
+		c.mu.Unlock()
+		return data, nil
+	})
+	data = v.(*types.WhoisData)
+	return data, data != nil
+}
+
+// routableIP 判断地址是否可能出现在公网路由表中
+func routableIP(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	return !(addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsUnspecified() || addr.IsMulticast())
+}
@@ -0,0 +1,22 @@
+package trace
+
+import "encoding/binary"
+
+// parisPayloadMin Paris 模式 Echo 数据的最小长度：流标识 2 字节 + 序号补偿 2 字节
+const parisPayloadMin = 4
+
+// parisPayload 生成 Paris 模式的 Echo 数据。
+//
+// 负载均衡器对 ICMP 常按报文前 4 字节（类型、代码、校验和）计算流哈希，
+// 普通 traceroute 每次改变序号会改变校验和，使探测分散到不同的等价路径上。
+// 这里前两个字节写入流标识，随后两个字节写入序号的反码，序号与其反码在反码和中
+// 相互抵消，同一流内所有探测的校验和保持不变；不同的 flowID 对应不同的路径。
+func parisPayload(size, flowID, seq int) []byte {
+	if size < parisPayloadMin {
+		size = parisPayloadMin
+	}
+	data := make([]byte, size)
+	binary.BigEndian.PutUint16(data[0:2], uint16(flowID))
+	binary.BigEndian.PutUint16(data[2:4], ^uint16(seq))
+	return data
+}
@@ -0,0 +1,30 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+func echoChecksum(t *testing.T, flowID, seq int) []byte {
+	msg := &icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: 0x1234, Seq: seq, Data: parisPayload(32, flowID, seq)},
+	}
+	b, err := msg.Marshal(nil)
+	require.NoError(t, err)
+	return b[2:4]
+}
+
+func TestParisPayloadKeepsChecksumPerFlow(t *testing.T) {
+	base := echoChecksum(t, 0, probeSeq(1, 1))
+	for ttl := 1; ttl <= 30; ttl++ {
+		for q := 1; q <= 3; q++ {
+			require.Equal(t, base, echoChecksum(t, 0, probeSeq(ttl, q)))
+		}
+	}
+	require.NotEqual(t, base, echoChecksum(t, 1, probeSeq(1, 1)))
+	require.Len(t, parisPayload(0, 0, 1), parisPayloadMin)
+}
@@ -0,0 +1,336 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	ntxerrors "github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// DefaultTCPTracePort TCP Traceroute 默认目标端口
+const DefaultTCPTracePort = 80
+
+// TCPTracer TCP Traceroute 实现
+//
+// 通过内核 connect() 发送指定 TTL 的 SYN，中间路由器返回的 ICMP Time Exceeded
+// 经原始 ICMP 套接字接收，并按引用的 TCP 头（源/目的端口）与本次探测匹配；
+// 收到 SYN/ACK（端口开放）或 RST（端口关闭）即表示到达目标，端口状态记录在探测结果中。
+// 接收 ICMP 差错需要 root 权限或 CAP_NET_RAW 能力。
+type TCPTracer struct {
+	conn4 *icmp.PacketConn
+	conn6 *icmp.PacketConn
+}
+
+// NewTCPTracer 创建 TCP Tracer
+func NewTCPTracer() (*TCPTracer, error) {
+	t := &TCPTracer{}
+
+	conn4, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil, ntxerrors.NewPermissionError("tcp traceroute", "raw socket",
+			"需要 root 权限或 CAP_NET_RAW 能力")
+	}
+	t.conn4 = conn4
+
+	if conn6, err := icmp.ListenPacket("ip6:ipv6-icmp", "::"); err == nil {
+		t.conn6 = conn6
+	}
+
+	return t, nil
+}
+
+// Trace 执行 TCP Traceroute
+func (t *TCPTracer) Trace(ctx context.Context, target string, opts *types.TraceOptions) (*types.TraceResult, error) {
+	if target == "" {
+		return nil, ntxerrors.ErrInvalidHost
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts == nil {
+		opts = types.DefaultTraceOptions()
+	}
+	if opts.Parallel {
+		return nil, ntxerrors.NewNotSupportedError("parallel tcp traceroute", "")
+	}
+	if opts.Port <= 0 {
+		opts.Port = DefaultTCPTracePort
+	}
+
+	hostInfo, err := netutil.ResolveHost(target, opts.IPVersion)
+	if err != nil {
+		return nil, ntxerrors.NewNetworkError("resolve", target, err)
+	}
+	if hostInfo.IPVersion == types.IPv6 && t.conn6 == nil {
+		return nil, ntxerrors.NewPermissionError("tcp traceroute", "ipv6", "connection not available")
+	}
+
+	// Paris 模式下所有探测复用首个探测分配到的源端口，使五元组（流标识）保持不变
+	flow := &tcpFlow{}
+	probe := func(ctx context.Context, targetIP string, ttl, seq int, opts *types.TraceOptions) *types.TraceProbe {
+		return t.probeOnce(ctx, targetIP, ttl, seq, opts, flow)
+	}
+	return runTrace(ctx, target, hostInfo, types.ProtocolTCP, opts, probe), nil
+}
+
+// tcpFlow 一次追踪内共享的流状态
+type tcpFlow struct {
+	// srcPort Paris 模式下固定的源端口，0 表示尚未分配
+	srcPort int
+}
+
+// tcpReply 探测期间收到的 ICMP 差错
+type tcpReply struct {
+	ip     string
+	status types.Status
+	reason string
+}
+
+// probeOnce 执行单次 TCP 探测
+func (t *TCPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq int, opts *types.TraceOptions, flow *tcpFlow) *types.TraceProbe {
+	probe := &types.TraceProbe{
+		Seq:    seq,
+		Status: types.StatusSuccess,
+	}
+
+	dstIP := net.ParseIP(targetIP)
+	if dstIP == nil {
+		probe.Status = types.StatusFailure
+		probe.Error = "无效的目标地址: " + targetIP
+		return probe
+	}
+
+	ipVersion := 4
+	conn := t.conn4
+	if dstIP.To4() == nil {
+		ipVersion = 6
+		conn = t.conn6
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	// 预先绑定本地端口，以便从 ICMP 差错引用的 TCP 头中识别本次探测
+	srcPort := 0
+	if opts.Paris {
+		srcPort = flow.srcPort
+	}
+	localPort := make(chan int, 1)
+	dialer := net.Dialer{
+		Control: func(network, address string, c syscall.RawConn) error {
+			var sockErr error
+			err := c.Control(func(fd uintptr) {
+				var port int
+				if port, sockErr = prepareTCPProbeSocket(fd, ipVersion, ttl, srcPort, opts.Paris); sockErr == nil {
+					localPort <- port
+				}
+			})
+			if err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+
+	start := time.Now()
+	dialDone := make(chan error, 1)
+	dialExited := make(chan struct{})
+	go func() {
+		defer close(dialExited)
+		c, err := dialer.DialContext(probeCtx, "tcp", net.JoinHostPort(targetIP, strconv.Itoa(opts.Port)))
+		if err == nil {
+			if opts.Paris {
+				// 以 RST 关闭，避免固定源端口进入 TIME_WAIT 导致下一次探测无法连接
+				_ = c.(*net.TCPConn).SetLinger(0)
+			}
+			c.Close()
+		}
+		dialDone <- err
+	}()
+
+	var port int
+	select {
+	case port = <-localPort:
+	case err := <-dialDone:
+		// 套接字准备失败时 Control 不会发送端口；否则端口已写入缓冲通道
+		select {
+		case port = <-localPort:
+			dialDone <- err
+		default:
+			probe.Status = types.StatusFailure
+			probe.Error = err.Error()
+			return probe
+		}
+	}
+
+	if opts.Paris {
+		flow.srcPort = port
+	}
+
+	replies := make(chan tcpReply, 1)
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		t.readICMP(probeCtx, conn, ipVersion, dstIP, port, opts.Port, replies)
+	}()
+	defer func() {
+		cancel()
+		if opts.Paris {
+			// 下一次探测复用同一源端口，需等待本次连接套接字关闭
+			<-dialExited
+		}
+		// 唤醒阻塞的读取并等待读取协程退出，避免与下一次探测争用套接字
+		for {
+			_ = conn.SetReadDeadline(time.Now())
+			select {
+			case <-readerDone:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	for {
+		select {
+		case reply := <-replies:
+			probe.RTT = time.Since(start)
+			probe.IP = reply.ip
+			probe.Status = reply.status
+			probe.Error = reply.reason
+			return probe
+		case err := <-dialDone:
+			rtt := time.Since(start)
+			if state, reached := classifyTCPDial(err); reached {
+				// SYN/ACK 或 RST: 到达目标
+				probe.RTT = rtt
+				probe.IP = targetIP
+				probe.PortState = state.String()
+				return probe
+			}
+			switch {
+			case probeCtx.Err() != nil:
+				if ctx.Err() != nil {
+					probe.Status = types.StatusFailure
+					probe.Error = ctx.Err().Error()
+				} else {
+					probe.Status = types.StatusTimeout
+				}
+				return probe
+			}
+			// 其余错误（如内核收到 ICMP 不可达）等待 ICMP 读取结果或超时
+			dialDone = nil
+		case <-probeCtx.Done():
+			if ctx.Err() != nil {
+				probe.Status = types.StatusFailure
+				probe.Error = ctx.Err().Error()
+			} else {
+				probe.Status = types.StatusTimeout
+			}
+			return probe
+		}
+	}
+}
+
+// readICMP 读取 ICMP 差错，匹配到本次探测后写入 replies
+func (t *TCPTracer) readICMP(ctx context.Context, conn *icmp.PacketConn, ipVersion int, dst net.IP, srcPort, dstPort int, replies chan<- tcpReply) {
+	buf := make([]byte, 1500)
+	for ctx.Err() == nil {
+		deadline, _ := ctx.Deadline()
+		_ = conn.SetReadDeadline(deadline)
+
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		status, reason, ok := matchTCPReply(ipVersion, buf[:n], dst, srcPort, dstPort)
+		if !ok {
+			continue
+		}
+		reply := tcpReply{ip: addrIP(peer), status: status, reason: reason}
+
+		select {
+		case replies <- reply:
+		default:
+		}
+		return
+	}
+}
+
+// classifyTCPDial 按 connect() 的结果判断是否到达目标：成功为 SYN/ACK（开放），ECONNREFUSED 为 RST（关闭）
+func classifyTCPDial(err error) (types.PortState, bool) {
+	switch {
+	case err == nil:
+		return types.PortOpen, true
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return types.PortClosed, true
+	}
+	return types.PortUnknown, false
+}
+
+// matchTCPReply 解析 ICMP 报文，引用的 TCP 头与本次探测一致时返回状态与失败原因
+func matchTCPReply(ipVersion int, msg []byte, dst net.IP, srcPort, dstPort int) (types.Status, string, bool) {
+	proto := ProtocolICMP
+	if ipVersion == 6 {
+		proto = ProtocolIPv6ICMP
+	}
+	rm, err := icmp.ParseMessage(proto, msg)
+	if err != nil {
+		return "", "", false
+	}
+
+	status, reason := types.StatusSuccess, ""
+	var data []byte
+	switch rm.Type {
+	case ipv4.ICMPTypeTimeExceeded, ipv6.ICMPTypeTimeExceeded:
+		body, ok := rm.Body.(*icmp.TimeExceeded)
+		if !ok {
+			return "", "", false
+		}
+		data = body.Data
+		if rm.Code != 0 {
+			status, reason = types.StatusFailure, timeExceededReason(ipVersion, rm.Code)
+		}
+	case ipv4.ICMPTypeDestinationUnreachable, ipv6.ICMPTypeDestinationUnreachable:
+		body, ok := rm.Body.(*icmp.DstUnreach)
+		if !ok {
+			return "", "", false
+		}
+		data = body.Data
+		status, reason = types.StatusFailure, unreachableReason(ipVersion, rm.Code)
+	default:
+		return "", "", false
+	}
+
+	qDst, qSrcPort, qDstPort, ok := quotedTCP(ipVersion, data)
+	if !ok || !qDst.Equal(dst) || qSrcPort != srcPort || qDstPort != dstPort {
+		return "", "", false
+	}
+	return status, reason, true
+}
+
+// Close 关闭资源
+func (t *TCPTracer) Close() error {
+	var err error
+	if t.conn4 != nil {
+		if e := t.conn4.Close(); e != nil {
+			err = e
+		}
+	}
+	if t.conn6 != nil {
+		if e := t.conn6.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+// +build !windows
+
+package trace
+
+import "golang.org/x/sys/unix"
+
+// prepareTCPProbeSocket 设置探测套接字的 TTL/HopLimit 并绑定本地端口（srcPort 为 0 时由系统分配），返回本地端口。
+// reuse 为 true 时设置 SO_REUSEADDR，以便后续探测复用同一端口
+func prepareTCPProbeSocket(fd uintptr, ipVersion, ttl, srcPort int, reuse bool) (int, error) {
+	s := int(fd)
+	if reuse {
+		if err := unix.SetsockoptInt(s, unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); err != nil {
+			return 0, err
+		}
+	}
+	if ipVersion == 4 {
+		if err := unix.SetsockoptInt(s, unix.IPPROTO_IP, unix.IP_TTL, ttl); err != nil {
+			return 0, err
+		}
+		if err := unix.Bind(s, &unix.SockaddrInet4{Port: srcPort}); err != nil {
+			return 0, err
+		}
+	} else {
+		if err := unix.SetsockoptInt(s, unix.IPPROTO_IPV6, unix.IPV6_UNICAST_HOPS, ttl); err != nil {
+			return 0, err
+		}
+		if err := unix.Bind(s, &unix.SockaddrInet6{Port: srcPort}); err != nil {
+			return 0, err
+		}
+	}
+
+	sa, err := unix.Getsockname(s)
+	if err != nil {
+		return 0, err
+	}
+	switch a := sa.(type) {
+	case *unix.SockaddrInet4:
+		return a.Port, nil
+	case *unix.SockaddrInet6:
+		return a.Port, nil
+	}
+	return 0, unix.EAFNOSUPPORT
+}
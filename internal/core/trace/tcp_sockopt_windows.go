@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+package trace
+
+import "golang.org/x/sys/windows"
+
+// prepareTCPProbeSocket 设置探测套接字的 TTL/HopLimit 并绑定本地端口（srcPort 为 0 时由系统分配），返回本地端口。
+// reuse 为 true 时设置 SO_REUSEADDR，以便后续探测复用同一端口
+func prepareTCPProbeSocket(fd uintptr, ipVersion, ttl, srcPort int, reuse bool) (int, error) {
+	s := windows.Handle(fd)
+	if reuse {
+		if err := windows.SetsockoptInt(s, windows.SOL_SOCKET, windows.SO_REUSEADDR, 1); err != nil {
+			return 0, err
+		}
+	}
+	if ipVersion == 4 {
+		if err := windows.SetsockoptInt(s, windows.IPPROTO_IP, windows.IP_TTL, ttl); err != nil {
+			return 0, err
+		}
+		if err := windows.Bind(s, &windows.SockaddrInet4{Port: srcPort}); err != nil {
+			return 0, err
+		}
+	} else {
+		if err := windows.SetsockoptInt(s, windows.IPPROTO_IPV6, windows.IPV6_UNICAST_HOPS, ttl); err != nil {
+			return 0, err
+		}
+		if err := windows.Bind(s, &windows.SockaddrInet6{Port: srcPort}); err != nil {
+			return 0, err
+		}
+	}
+
+	sa, err := windows.Getsockname(s)
+	if err != nil {
+		return 0, err
+	}
+	switch a := sa.(type) {
+	case *windows.SockaddrInet4:
+		return a.Port, nil
+	case *windows.SockaddrInet6:
+		return a.Port, nil
+	}
+	return 0, windows.WSAEAFNOSUPPORT
+}
@@ -0,0 +1,61 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestMatchTCPReply(t *testing.T) {
+	dst := net.IPv4(192, 0, 2, 10)
+
+	msg := quotedErrorMessage(t, ipv4.ICMPTypeTimeExceeded, 0, ProtocolTCP, dst, 40000, 443)
+	status, reason, ok := matchTCPReply(4, msg, dst, 40000, 443)
+	require.True(t, ok)
+	require.Equal(t, types.StatusSuccess, status)
+	require.Empty(t, reason)
+
+	msg = quotedErrorMessage(t, ipv4.ICMPTypeDestinationUnreachable, 13, ProtocolTCP, dst, 40000, 443)
+	status, reason, ok = matchTCPReply(4, msg, dst, 40000, 443)
+	require.True(t, ok)
+	require.Equal(t, types.StatusFailure, status)
+	require.Equal(t, "communication administratively prohibited", reason)
+
+	// 源端口不同或引用的是 UDP 报文时不属于本次探测
+	_, _, ok = matchTCPReply(4, msg, dst, 40001, 443)
+	require.False(t, ok)
+	msg = quotedErrorMessage(t, ipv4.ICMPTypeTimeExceeded, 0, ProtocolUDP, dst, 40000, 443)
+	_, _, ok = matchTCPReply(4, msg, dst, 40000, 443)
+	require.False(t, ok)
+}
+
+func TestClassifyTCPDial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	// SYN/ACK: 端口开放
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	require.NoError(t, err)
+	conn.Close()
+	state, reached := classifyTCPDial(err)
+	require.True(t, reached)
+	require.Equal(t, types.PortOpen, state)
+
+	// RST: 端口关闭
+	listener.Close()
+	_, err = net.DialTimeout("tcp", addr, time.Second)
+	require.Error(t, err)
+	state, reached = classifyTCPDial(err)
+	require.True(t, reached)
+	require.Equal(t, types.PortClosed, state)
+
+	// 其余错误（如超时）需继续等待 ICMP 差错
+	_, reached = classifyTCPDial(context.DeadlineExceeded)
+	require.False(t, reached)
+}
@@ -0,0 +1,243 @@
+package trace
+
+import (
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// probeFunc 向目标发送一次指定 TTL 的探测
+type probeFunc func(ctx context.Context, targetIP string, ttl, seq int, opts *types.TraceOptions) *types.TraceProbe
+
+// runTrace 按 TTL 递增逐跳探测，各协议 Tracer 共用
+func runTrace(ctx context.Context, target string, hostInfo *types.Host, protocol types.Protocol, opts *types.TraceOptions, probe probeFunc) *types.TraceResult {
+	// 创建结果对象
+	result := &types.TraceResult{
+		Target: &types.Host{
+			Hostname:  target,
+			IP:        hostInfo.IP,
+			IPVersion: hostInfo.IPVersion,
+		},
+		Protocol:           protocol,
+		Hops:               make([]*types.TraceHop, 0, opts.MaxHops),
+		ReachedDestination: false,
+		Context: &types.ExecutionContext{
+			StartTime: time.Now(),
+		},
+		Status: types.StatusSuccess,
+	}
+
+	// 获取主机名
+	hostname, _ := os.Hostname()
+	result.Context.Hostname = hostname
+
+	// 反向解析、AS 查询与后续探测并发进行，返回前等待全部完成
+	var resolver *hopResolver
+	if !opts.NoResolve || opts.ResolveASN {
+		resolver = &hopResolver{reverse: !opts.NoResolve, origin: opts.ResolveASN}
+	}
+
+	// 执行 Traceroute
+	if opts.Parallel {
+		for _, hop := range traceHopsParallel(ctx, hostInfo.IP, opts, probe, resolver) {
+			if hop == nil {
+				// 上下文取消后未执行的跳
+				break
+			}
+			result.AddHop(hop)
+			if traceFinished(result, opts) {
+				break
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			result.Error = err
+			result.Status = types.StatusFailure
+		}
+	} else {
+		for ttl := opts.FirstTTL; ttl <= opts.MaxHops; ttl++ {
+			if err := ctx.Err(); err != nil {
+				result.Error = err
+				result.Status = types.StatusFailure
+				break
+			}
+
+			hop := traceHop(ctx, hostInfo.IP, ttl, opts, probe, resolver)
+			result.AddHop(hop)
+			if traceFinished(result, opts) {
+				break
+			}
+		}
+	}
+
+	if resolver != nil {
+		resolver.wait()
+	}
+
+	// 更新上下文
+	result.Context.EndTime = time.Now()
+	result.Context.Duration = result.Context.EndTime.Sub(result.Context.StartTime)
+
+	// 判断整体状态
+	if !result.ReachedDestination && result.HopCount == 0 {
+		result.Status = types.StatusFailure
+	} else if !result.ReachedDestination {
+		result.Status = types.StatusTimeout
+	}
+
+	return result
+}
+
+// traceFinished 根据最新加入的一跳判断追踪是否结束：到达目标，
+// 或超过起始 TTL 5 跳后连续 5 跳全部无响应（路径可能被阻断）
+func traceFinished(result *types.TraceResult, opts *types.TraceOptions) bool {
+	hop := result.Hops[len(result.Hops)-1]
+	if hop.IsDestination {
+		result.ReachedDestination = true
+		return true
+	}
+	if hop.GetSuccessCount() > 0 || hop.TTL <= opts.FirstTTL+5 {
+		return false
+	}
+	for i := len(result.Hops) - 1; i >= 0 && i >= len(result.Hops)-5; i-- {
+		if result.Hops[i].GetSuccessCount() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// parallelTraceConcurrency 并行模式下同时探测的跳数上限
+const parallelTraceConcurrency = 16
+
+// traceHopsParallel 并发探测 FirstTTL..MaxHops 的所有跳，按 TTL 顺序返回；
+// 已知目标所在 TTL 后不再发起更远的探测，未执行的跳为 nil
+func traceHopsParallel(ctx context.Context, targetIP string, opts *types.TraceOptions, probe probeFunc, resolver *hopResolver) []*types.TraceHop {
+	hops := make([]*types.TraceHop, opts.MaxHops-opts.FirstTTL+1)
+	sem := make(chan struct{}, parallelTraceConcurrency)
+	var destTTL atomic.Int64
+	destTTL.Store(math.MaxInt64)
+
+	var wg sync.WaitGroup
+	for ttl := opts.FirstTTL; ttl <= opts.MaxHops; ttl++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if int64(ttl) > destTTL.Load() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(ttl int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			hop := traceHop(ctx, targetIP, ttl, opts, probe, resolver)
+			hops[ttl-opts.FirstTTL] = hop
+			if hop.IsDestination {
+				for {
+					cur := destTTL.Load()
+					if int64(ttl) >= cur || destTTL.CompareAndSwap(cur, int64(ttl)) {
+						break
+					}
+				}
+			}
+		}(ttl)
+	}
+	wg.Wait()
+	return hops
+}
+
+// traceHop 追踪单个跳
+func traceHop(ctx context.Context, targetIP string, ttl int, opts *types.TraceOptions, probeOnce probeFunc, resolver *hopResolver) *types.TraceHop {
+	hop := &types.TraceHop{
+		TTL:    ttl,
+		Probes: make([]*types.TraceProbe, 0, opts.Queries),
+	}
+
+	// 执行多次探测
+	for i := 0; i < opts.Queries; i++ {
+		probe := probeOnce(ctx, targetIP, ttl, i+1, opts)
+		hop.Probes = append(hop.Probes, probe)
+
+		// 记录 IP 和主机名（使用第一个成功的响应）
+		if probe.Status == types.StatusSuccess && hop.IP == "" {
+			hop.IP = probe.IP
+			hop.Hostname = probe.IP
+
+			// 反向 DNS 解析与 AS 查询（异步，resolver 为 nil 时跳过）
+			if resolver != nil {
+				resolver.resolve(ctx, hop)
+			}
+
+			// 检查是否为目标
+			if probe.IP == targetIP {
+				hop.IsDestination = true
+			}
+		}
+	}
+
+	return hop
+}
+
+// hopResolver 异步解析各跳主机名与 AS 信息，慢速 PTR/whois 服务器不会阻塞后续探测
+type hopResolver struct {
+	wg sync.WaitGroup
+	// reverse 反向解析主机名
+	reverse bool
+	// origin 查询 AS 号、AS 名称与国家
+	origin bool
+}
+
+// resolve 后台解析 hop.IP（经进程内缓存），成功时填充 hop 的对应字段
+func (r *hopResolver) resolve(ctx context.Context, hop *types.TraceHop) {
+	ip := hop.IP
+	if r.reverse {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if name, ok := netutil.ReverseLookup(ctx, ip); ok {
+				hop.Hostname = name
+			}
+		}()
+	}
+	if r.origin {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if data, ok := defaultOriginCache.lookup(ctx, ip); ok {
+				if data.ASN > 0 {
+					hop.ASN = strconv.Itoa(data.ASN)
+				}
+				hop.ASName = data.ASName
+				hop.Country = data.Country
+			}
+		}()
+	}
+}
+
+// wait 等待所有解析完成
+func (r *hopResolver) wait() {
+	r.wg.Wait()
+}
+
+// NewTracer 按协议创建 Tracer，未知协议使用 ICMP
+func NewTracer(protocol types.Protocol) (types.Tracer, error) {
+	switch protocol {
+	case types.ProtocolTCP:
+		return NewTCPTracer()
+	case types.ProtocolUDP:
+		return NewUDPTracer()
+	}
+	return NewICMPTracer()
+}
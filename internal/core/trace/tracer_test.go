@@ -0,0 +1,116 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// loopbackProbe 模拟一跳即到达 127.0.0.1 的路径
+func loopbackProbe(_ context.Context, targetIP string, _, seq int, _ *types.TraceOptions) *types.TraceProbe {
+	return &types.TraceProbe{Seq: seq, IP: targetIP, RTT: time.Millisecond, Status: types.StatusSuccess}
+}
+
+func TestRunTraceNoResolve(t *testing.T) {
+	host := &types.Host{IP: "127.0.0.1", IPVersion: types.IPv4}
+	opts := types.DefaultTraceOptions()
+	opts.NoResolve = true
+
+	result := runTrace(context.Background(), "127.0.0.1", host, types.ProtocolICMP, opts, loopbackProbe)
+	require.True(t, result.ReachedDestination)
+	require.Len(t, result.Hops, 1)
+	require.Equal(t, "127.0.0.1", result.Hops[0].Hostname)
+	require.Len(t, result.Hops[0].Probes, opts.Queries)
+}
+
+func TestRunTraceResolvesOrigin(t *testing.T) {
+	var mu sync.Mutex
+	queried := make(map[string]int)
+	oldLookup, oldCache := originLookup, defaultOriginCache
+	originLookup = func(_ context.Context, ip string) (*types.WhoisData, error) {
+		mu.Lock()
+		queried[ip]++
+		mu.Unlock()
+		return &types.WhoisData{ASN: 64500, ASName: "EXAMPLE-NET", Country: "NL"}, nil
+	}
+	defaultOriginCache = &originCache{entries: make(map[string]*types.WhoisData)}
+	t.Cleanup(func() { originLookup, defaultOriginCache = oldLookup, oldCache })
+
+	// 私有地址第一跳，随后同一公网地址出现两跳，最后到达目标
+	path := []string{"10.0.0.1", "198.51.100.1", "198.51.100.1", "203.0.113.9"}
+	probe := func(_ context.Context, _ string, ttl, seq int, _ *types.TraceOptions) *types.TraceProbe {
+		return &types.TraceProbe{Seq: seq, IP: path[ttl-1], RTT: time.Millisecond, Status: types.StatusSuccess}
+	}
+
+	host := &types.Host{IP: "203.0.113.9", IPVersion: types.IPv4}
+	opts := types.DefaultTraceOptions()
+	opts.NoResolve = true
+	opts.ResolveASN = true
+
+	result := runTrace(context.Background(), "203.0.113.9", host, types.ProtocolICMP, opts, probe)
+	require.True(t, result.ReachedDestination)
+	require.Len(t, result.Hops, 4)
+
+	require.Empty(t, result.Hops[0].ASN)
+	for _, hop := range result.Hops[1:] {
+		require.Equal(t, "64500", hop.ASN)
+		require.Equal(t, "EXAMPLE-NET", hop.ASName)
+		require.Equal(t, "NL", hop.Country)
+	}
+	require.Equal(t, map[string]int{"198.51.100.1": 1, "203.0.113.9": 1}, queried)
+}
+
+// pathProbe 按 TTL 返回固定路径上的地址，空字符串表示该跳无响应；
+// 探测耗时随 TTL 递减，使并行模式下远端跳先完成
+func pathProbe(path []string) probeFunc {
+	return func(_ context.Context, _ string, ttl, seq int, _ *types.TraceOptions) *types.TraceProbe {
+		time.Sleep(time.Duration(len(path)-ttl) * time.Millisecond)
+		if ttl > len(path) {
+			ttl = len(path)
+		}
+		if path[ttl-1] == "" {
+			return &types.TraceProbe{Seq: seq, Status: types.StatusTimeout}
+		}
+		return &types.TraceProbe{Seq: seq, IP: path[ttl-1], RTT: time.Millisecond, Status: types.StatusSuccess}
+	}
+}
+
+func TestRunTraceParallelMatchesSequential(t *testing.T) {
+	target := "203.0.113.9"
+	paths := map[string][]string{
+		"Reached":  {"10.0.0.1", "", "198.51.100.1", "198.51.100.2", "", target},
+		"Blackout": {"10.0.0.1", "198.51.100.1", "", "", "", "", "", "", "", "", "", "", "198.51.100.9"},
+	}
+
+	hopsOf := func(result *types.TraceResult) []string {
+		var out []string
+		for _, hop := range result.Hops {
+			out = append(out, fmt.Sprintf("%d %s %v %d", hop.TTL, hop.IP, hop.IsDestination, hop.GetSuccessCount()))
+		}
+		return out
+	}
+
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			host := &types.Host{IP: target, IPVersion: types.IPv4}
+			opts := types.DefaultTraceOptions()
+			opts.NoResolve = true
+			opts.MaxHops = 20
+			opts.Queries = 2
+
+			sequential := runTrace(context.Background(), target, host, types.ProtocolICMP, opts, pathProbe(path))
+			opts.Parallel = true
+			parallel := runTrace(context.Background(), target, host, types.ProtocolICMP, opts, pathProbe(path))
+
+			require.Equal(t, hopsOf(sequential), hopsOf(parallel))
+			require.Equal(t, sequential.ReachedDestination, parallel.ReachedDestination)
+			require.Equal(t, sequential.HopCount, parallel.HopCount)
+			require.Equal(t, sequential.Status, parallel.Status)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	ntxerrors "github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/netutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// UDPTracer UDP Traceroute 实现
+//
+// 经典 traceroute 方式：向目标的高位端口发送指定 TTL 的 UDP 数据报，每个探测使用递增的目的端口；
+// 中间路由器返回 ICMP Time Exceeded，目标返回 ICMP Port Unreachable 即表示到达。
+// 差错报文经原始 ICMP 套接字接收，并按引用的 UDP 头（源/目的端口）与本次探测匹配，
+// 需要 root 权限或 CAP_NET_RAW 能力。
+type UDPTracer struct {
+	conn4 *icmp.PacketConn
+	conn6 *icmp.PacketConn
+}
+
+// NewUDPTracer 创建 UDP Tracer
+func NewUDPTracer() (*UDPTracer, error) {
+	t := &UDPTracer{}
+
+	conn4, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil, ntxerrors.NewPermissionError("udp traceroute", "raw socket",
+			"需要 root 权限或 CAP_NET_RAW 能力")
+	}
+	t.conn4 = conn4
+
+	if conn6, err := icmp.ListenPacket("ip6:ipv6-icmp", "::"); err == nil {
+		t.conn6 = conn6
+	}
+
+	return t, nil
+}
+
+// Trace 执行 UDP Traceroute
+func (t *UDPTracer) Trace(ctx context.Context, target string, opts *types.TraceOptions) (*types.TraceResult, error) {
+	if target == "" {
+		return nil, ntxerrors.ErrInvalidHost
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts == nil {
+		opts = types.DefaultTraceOptions()
+	}
+	if opts.Parallel {
+		return nil, ntxerrors.NewNotSupportedError("parallel udp traceroute", "")
+	}
+	if opts.Port <= 0 {
+		opts.Port = types.DefaultTraceroutePort
+	}
+
+	hostInfo, err := netutil.ResolveHost(target, opts.IPVersion)
+	if err != nil {
+		return nil, ntxerrors.NewNetworkError("resolve", target, err)
+	}
+	if hostInfo.IPVersion == types.IPv6 && t.conn6 == nil {
+		return nil, ntxerrors.NewPermissionError("udp traceroute", "ipv6", "connection not available")
+	}
+
+	return runTrace(ctx, target, hostInfo, types.ProtocolUDP, opts, t.probeOnce), nil
+}
+
+// udpProbePort 返回探测的目的端口：起始端口按跳数与查询次数递增，超出 65535 时从起始端口回绕
+func udpProbePort(base, ttl, seq, queries int) int {
+	offset := (ttl-1)*queries + seq - 1
+	return base + offset%(types.MaxPort-base+1)
+}
+
+// probeOnce 执行单次 UDP 探测
+func (t *UDPTracer) probeOnce(ctx context.Context, targetIP string, ttl, seq int, opts *types.TraceOptions) *types.TraceProbe {
+	probe := &types.TraceProbe{
+		Seq:    seq,
+		Status: types.StatusSuccess,
+	}
+
+	dstIP := net.ParseIP(targetIP)
+	if dstIP == nil {
+		probe.Status = types.StatusFailure
+		probe.Error = "无效的目标地址: " + targetIP
+		return probe
+	}
+
+	ipVersion, conn, network := 4, t.conn4, "udp4"
+	if dstIP.To4() == nil {
+		ipVersion, conn, network = 6, t.conn6, "udp6"
+	}
+
+	// 每个探测使用独立的套接字，源端口与递增的目的端口共同标识本次探测
+	sock, err := net.ListenPacket(network, "")
+	if err != nil {
+		probe.Status = types.StatusFailure
+		probe.Error = err.Error()
+		return probe
+	}
+	defer sock.Close()
+
+	if ipVersion == 4 {
+		err = ipv4.NewPacketConn(sock).SetTTL(ttl)
+	} else {
+		err = ipv6.NewPacketConn(sock).SetHopLimit(ttl)
+	}
+	if err != nil {
+		// TTL 未生效时所有探测都会直达目标，必须视为失败
+		probe.Status = types.StatusFailure
+		probe.Error = "设置 TTL 失败: " + err.Error()
+		return probe
+	}
+	srcPort := sock.LocalAddr().(*net.UDPAddr).Port
+	dstPort := udpProbePort(opts.Port, ttl, seq, opts.Queries)
+
+	deadline := time.Now().Add(opts.Timeout)
+	_ = conn.SetReadDeadline(deadline)
+
+	cancelRead := make(chan struct{})
+	defer close(cancelRead)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// 提前唤醒阻塞的 ReadFrom
+			_ = conn.SetReadDeadline(time.Now())
+		case <-cancelRead:
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		probe.Status = types.StatusFailure
+		probe.Error = err.Error()
+		return probe
+	}
+
+	start := time.Now()
+	if _, err := sock.WriteTo(make([]byte, opts.PacketSize), &net.UDPAddr{IP: dstIP, Port: dstPort}); err != nil {
+		probe.Status = types.StatusFailure
+		probe.Error = err.Error()
+		return probe
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				probe.Status = types.StatusFailure
+				probe.Error = ctx.Err().Error()
+			} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				probe.Status = types.StatusTimeout
+			} else {
+				probe.Status = types.StatusFailure
+				probe.Error = err.Error()
+			}
+			return probe
+		}
+
+		status, reason, ok := matchUDPReply(ipVersion, buf[:n], dstIP, srcPort, dstPort)
+		if !ok {
+			continue
+		}
+		probe.RTT = time.Since(start)
+		probe.IP = addrIP(peer)
+		probe.Status = status
+		probe.Error = reason
+		return probe
+	}
+}
+
+// matchUDPReply 解析 ICMP 报文，引用的 UDP 头与本次探测一致时返回探测结果。
+// Time Exceeded 表示中间跳，来自目标的 Port Unreachable 表示已到达，二者均视为成功。
+func matchUDPReply(ipVersion int, msg []byte, dst net.IP, srcPort, dstPort int) (types.Status, string, bool) {
+	proto := ProtocolICMP
+	if ipVersion == 6 {
+		proto = ProtocolIPv6ICMP
+	}
+	rm, err := icmp.ParseMessage(proto, msg)
+	if err != nil {
+		return "", "", false
+	}
+
+	status, reason := types.StatusSuccess, ""
+	var data []byte
+	switch rm.Type {
+	case ipv4.ICMPTypeTimeExceeded, ipv6.ICMPTypeTimeExceeded:
+		body, ok := rm.Body.(*icmp.TimeExceeded)
+		if !ok {
+			return "", "", false
+		}
+		data = body.Data
+		if rm.Code != 0 {
+			status, reason = types.StatusFailure, timeExceededReason(ipVersion, rm.Code)
+		}
+	case ipv4.ICMPTypeDestinationUnreachable, ipv6.ICMPTypeDestinationUnreachable:
+		body, ok := rm.Body.(*icmp.DstUnreach)
+		if !ok {
+			return "", "", false
+		}
+		data = body.Data
+		if !isPortUnreachable(ipVersion, rm.Code) {
+			status, reason = types.StatusFailure, unreachableReason(ipVersion, rm.Code)
+		}
+	default:
+		return "", "", false
+	}
+
+	qDst, qSrcPort, qDstPort, ok := quotedUDP(ipVersion, data)
+	if !ok || !qDst.Equal(dst) || qSrcPort != srcPort || qDstPort != dstPort {
+		return "", "", false
+	}
+	return status, reason, true
+}
+
+// isPortUnreachable 判断 Destination Unreachable 代码是否为端口不可达
+func isPortUnreachable(ipVersion, code int) bool {
+	if ipVersion == 6 {
+		return code == 4
+	}
+	return code == 3
+}
+
+// Close 关闭资源
+func (t *UDPTracer) Close() error {
+	var err error
+	if t.conn4 != nil {
+		if e := t.conn4.Close(); e != nil {
+			err = e
+		}
+	}
+	if t.conn6 != nil {
+		if e := t.conn6.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/errors"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// quotedErrorMessage 构造引用 UDP/TCP 探测的 ICMPv4 差错报文
+func quotedErrorMessage(t *testing.T, typ icmp.Type, code int, proto byte, dst net.IP, srcPort, dstPort int) []byte {
+	t.Helper()
+	header := make([]byte, 20)
+	header[0] = 0x45
+	header[9] = proto
+	copy(header[16:20], dst.To4())
+	// 传输层头前 8 字节: 源端口、目的端口，其余字段不参与匹配
+	quoted := append(header, byte(srcPort>>8), byte(srcPort), byte(dstPort>>8), byte(dstPort), 0, 8, 0, 0)
+
+	var body icmp.MessageBody = &icmp.DstUnreach{Data: quoted}
+	if typ == ipv4.ICMPTypeTimeExceeded {
+		body = &icmp.TimeExceeded{Data: quoted}
+	}
+	b, err := (&icmp.Message{Type: typ, Code: code, Body: body}).Marshal(nil)
+	require.NoError(t, err)
+	return b
+}
+
+func TestMatchUDPReply(t *testing.T) {
+	dst := net.IPv4(192, 0, 2, 10)
+
+	t.Run("TimeExceeded", func(t *testing.T) {
+		msg := quotedErrorMessage(t, ipv4.ICMPTypeTimeExceeded, 0, ProtocolUDP, dst, 40000, 33435)
+		status, reason, ok := matchUDPReply(4, msg, dst, 40000, 33435)
+		require.True(t, ok)
+		require.Equal(t, types.StatusSuccess, status)
+		require.Empty(t, reason)
+	})
+
+	t.Run("PortUnreachable", func(t *testing.T) {
+		msg := quotedErrorMessage(t, ipv4.ICMPTypeDestinationUnreachable, 3, ProtocolUDP, dst, 40000, 33440)
+		status, reason, ok := matchUDPReply(4, msg, dst, 40000, 33440)
+		require.True(t, ok)
+		require.Equal(t, types.StatusSuccess, status)
+		require.Empty(t, reason)
+	})
+
+	t.Run("HostUnreachable", func(t *testing.T) {
+		msg := quotedErrorMessage(t, ipv4.ICMPTypeDestinationUnreachable, 1, ProtocolUDP, dst, 40000, 33440)
+		status, reason, ok := matchUDPReply(4, msg, dst, 40000, 33440)
+		require.True(t, ok)
+		require.Equal(t, types.StatusFailure, status)
+		require.Equal(t, "host unreachable", reason)
+	})
+
+	t.Run("OtherProbe", func(t *testing.T) {
+		msg := quotedErrorMessage(t, ipv4.ICMPTypeTimeExceeded, 0, ProtocolUDP, dst, 40000, 33435)
+		_, _, ok := matchUDPReply(4, msg, dst, 40001, 33435)
+		require.False(t, ok)
+		_, _, ok = matchUDPReply(4, msg, dst, 40000, 33436)
+		require.False(t, ok)
+		_, _, ok = matchUDPReply(4, msg, net.IPv4(192, 0, 2, 11), 40000, 33435)
+		require.False(t, ok)
+	})
+}
+
+func TestUDPProbePort(t *testing.T) {
+	require.Equal(t, 33434, udpProbePort(33434, 1, 1, 3))
+	require.Equal(t, 33436, udpProbePort(33434, 1, 3, 3))
+	require.Equal(t, 33437, udpProbePort(33434, 2, 1, 3))
+	// 超出 65535 时回绕到起始端口
+	require.Equal(t, 65535, udpProbePort(65534, 1, 2, 3))
+	require.Equal(t, 65534, udpProbePort(65534, 1, 3, 3))
+}
+
+func TestUDPTracerLoopback(t *testing.T) {
+	tracer, err := NewUDPTracer()
+	if errors.IsPermissionDenied(err) {
+		t.Skip("需要 root 权限或 CAP_NET_RAW 能力")
+	}
+	require.NoError(t, err)
+	defer tracer.Close()
+
+	opts := types.DefaultTraceOptions()
+	opts.MaxHops = 3
+	opts.Queries = 1
+	opts.Timeout = time.Second
+	opts.NoResolve = true
+
+	result, err := tracer.Trace(context.Background(), "127.0.0.1", opts)
+	require.NoError(t, err)
+	require.True(t, result.ReachedDestination)
+	require.Len(t, result.Hops, 1)
+	require.Equal(t, "127.0.0.1", result.Hops[0].IP)
+}
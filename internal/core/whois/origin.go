@@ -0,0 +1,71 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// originServer Team Cymru IP→ASN 映射服务
+var originServer = "whois.cymru.com"
+
+// LookupOrigin 查询 IP 所属的 AS 号、AS 名称与国家代码（Team Cymru whois）
+//
+// 返回的 WhoisData 仅填充 IP、IPRange、ASN、ASName 与 Country；
+// 地址未被任何 AS 宣告时 ASN 为 0。
+func (c *Client) LookupOrigin(ctx context.Context, ip string, timeout time.Duration) (*types.WhoisData, error) {
+	if net.ParseIP(ip) == nil {
+		return nil, fmt.Errorf("无效的 IP 地址: %s", ip)
+	}
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+
+	// -v 输出完整字段: AS | IP | BGP Prefix | CC | Registry | Allocated | AS Name
+	response, err := c.queryServer(ctx, originServer, " -v "+ip, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("查询 AS 信息失败: %w", err)
+	}
+	return parseOriginResponse(response, ip)
+}
+
+// parseOriginResponse 解析 Team Cymru 的竖线分隔响应
+func parseOriginResponse(response, ip string) (*types.WhoisData, error) {
+	for _, line := range strings.Split(response, "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) < 7 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if fields[1] != ip {
+			// 跳过表头或其他地址的行
+			continue
+		}
+
+		data := &types.WhoisData{
+			IP:      net.ParseIP(ip),
+			IPRange: fields[2],
+			Country: fields[3],
+			ASName:  fields[6],
+		}
+		// 未宣告的地址 AS 字段为 NA
+		if asn, err := strconv.Atoi(fields[0]); err == nil {
+			data.ASN = asn
+		}
+		if data.IPRange == "NA" {
+			data.IPRange = ""
+		}
+		if data.ASName == "NA" {
+			data.ASName = ""
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("响应中没有 %s 的 AS 信息", ip)
+}
@@ -25,6 +25,7 @@ import (
 	"net"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/catsayer/ntx/internal/logger"
@@ -61,14 +62,8 @@ func (c *Client) Query(ctx context.Context, query string, opts types.WhoisOption
 
 	logger.Info("开始 Whois 查询", zap.String("query", query))
 
-	// 检测查询类型
-	queryType := detectQueryType(query)
-
-	// 选择 Whois 服务器
-	server := opts.Server
-	if server == "" {
-		server = selectWhoisServer(query, queryType)
-	}
+	// 检测查询类型并选择 Whois 服务器
+	queryType, server := resolveServer(query, opts)
 
 	// 执行查询
 	response, err := c.queryServer(ctx, server, query, opts.Timeout)
@@ -81,13 +76,18 @@ func (c *Client) Query(ctx context.Context, query string, opts types.WhoisOption
 		Type:        queryType,
 		Server:      server,
 		RawResponse: response,
-		QueryTime:   time.Since(startTime),
 		Timestamp:   time.Now(),
 	}
 
 	// 解析响应
 	result.ParsedData = parseWhoisResponse(response, queryType)
 
+	// 瘦注册局（如 .com/.net）只返回注册商的 Whois 服务器，继续查询以获取完整的注册信息
+	if opts.FollowReferrals && queryType == types.WhoisDomain {
+		c.followReferral(ctx, result, opts.Timeout)
+	}
+	result.QueryTime = time.Since(startTime)
+
 	logger.Info("Whois 查询完成",
 		zap.String("query", query),
 		zap.String("server", server),
@@ -97,25 +97,163 @@ func (c *Client) Query(ctx context.Context, query string, opts types.WhoisOption
 	return result, nil
 }
 
+// followReferral 向响应中的注册商 Whois 服务器发起第二次查询，并将更完整的数据合并到 ParsedData；
+// 第二次查询失败时保留首次查询结果
+func (c *Client) followReferral(ctx context.Context, result *types.WhoisResult, timeout time.Duration) {
+	referral := parseReferralServer(result.RawResponse)
+	if referral == "" || strings.EqualFold(referral, result.Server) {
+		return
+	}
+
+	response, err := c.queryServer(ctx, referral, result.Query, timeout)
+	if err != nil {
+		logger.Warn("查询注册商 Whois 服务器失败",
+			zap.String("query", result.Query),
+			zap.String("server", referral),
+			zap.Error(err),
+		)
+		return
+	}
+
+	result.ReferralServer = referral
+	result.ReferralResponse = response
+	mergeWhoisData(result.ParsedData, parseWhoisResponse(response, result.Type))
+}
+
+// parseReferralServer 提取 "Registrar WHOIS Server:" 指向的服务器地址
+func parseReferralServer(response string) string {
+	for _, line := range strings.Split(response, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), "registrar whois server") {
+			continue
+		}
+		server := strings.TrimSpace(parts[1])
+		server = strings.TrimPrefix(server, "whois://")
+		return strings.TrimSuffix(server, "/")
+	}
+	return ""
+}
+
+// mergeWhoisData 用 src 中的非空字段覆盖 dst
+func mergeWhoisData(dst, src *types.WhoisData) {
+	mergeString := func(d *string, s string) {
+		if s != "" {
+			*d = s
+		}
+	}
+	mergeTime := func(d *time.Time, s time.Time) {
+		if !s.IsZero() {
+			*d = s
+		}
+	}
+	mergeList := func(d *[]string, s []string) {
+		if len(s) > 0 {
+			*d = s
+		}
+	}
+
+	mergeString(&dst.Domain, src.Domain)
+	mergeString(&dst.Registrar, src.Registrar)
+	mergeString(&dst.RegistrantName, src.RegistrantName)
+	mergeString(&dst.RegistrantOrg, src.RegistrantOrg)
+	mergeString(&dst.RegistrantEmail, src.RegistrantEmail)
+	mergeTime(&dst.CreationDate, src.CreationDate)
+	mergeTime(&dst.ExpirationDate, src.ExpirationDate)
+	mergeTime(&dst.UpdatedDate, src.UpdatedDate)
+	mergeList(&dst.NameServers, src.NameServers)
+	mergeList(&dst.Status, src.Status)
+	mergeString(&dst.AdminContact, src.AdminContact)
+	mergeString(&dst.TechContact, src.TechContact)
+}
+
 // QueryBatch 批量查询
+//
+// 不同 Whois 服务器之间并发查询（最多 opts.Concurrency 个），同一服务器的查询串行，
+// 且两次查询之间至少间隔 opts.RateLimit，避免被限流。
+// 返回结果与 queries 一一对应，失败项的 Error 字段记录错误信息。
 func (c *Client) QueryBatch(ctx context.Context, queries []string, opts types.WhoisOptions) ([]*types.WhoisResult, error) {
-	results := make([]*types.WhoisResult, 0, len(queries))
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
 
-	for _, query := range queries {
-		result, err := c.Query(ctx, query, opts)
-		if err != nil {
-			logger.Error("查询失败", zap.String("query", query), zap.Error(err))
-			continue
+	results := make([]*types.WhoisResult, len(queries))
+	sem := make(chan struct{}, concurrency)
+	serverLocks := make(map[string]*sync.Mutex)
+	var locksMu sync.Mutex
+	var wg sync.WaitGroup
+
+	lockFor := func(server string) *sync.Mutex {
+		locksMu.Lock()
+		defer locksMu.Unlock()
+		mu, ok := serverLocks[server]
+		if !ok {
+			mu = &sync.Mutex{}
+			serverLocks[server] = mu
 		}
-		results = append(results, result)
+		return mu
+	}
 
-		// 避免频繁查询被限流
-		time.Sleep(1 * time.Second)
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+
+			queryType, server := resolveServer(query, opts)
+			mu := lockFor(server)
+			mu.Lock()
+			defer mu.Unlock()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = failedResult(query, queryType, server, ctx.Err())
+				return
+			}
+			result, err := c.Query(ctx, query, opts)
+			<-sem
+
+			if err != nil {
+				logger.Error("查询失败", zap.String("query", query), zap.Error(err))
+				results[i] = failedResult(query, queryType, server, err)
+			} else {
+				results[i] = result
+			}
+
+			// 持有服务器锁等待间隔，保证对同一服务器的请求不过于频繁
+			if opts.RateLimit > 0 {
+				select {
+				case <-time.After(opts.RateLimit):
+				case <-ctx.Done():
+				}
+			}
+		}(i, query)
 	}
 
+	wg.Wait()
 	return results, nil
 }
 
+// resolveServer 检测查询类型并确定使用的 Whois 服务器
+func resolveServer(query string, opts types.WhoisOptions) (types.WhoisType, string) {
+	queryType := detectQueryType(query)
+	server := opts.Server
+	if server == "" {
+		server = selectWhoisServer(query, queryType)
+	}
+	return queryType, server
+}
+
+func failedResult(query string, queryType types.WhoisType, server string, err error) *types.WhoisResult {
+	return &types.WhoisResult{
+		Query:     query,
+		Type:      queryType,
+		Server:    server,
+		Timestamp: time.Now(),
+		Error:     err.Error(),
+	}
+}
+
 // queryServer 向 Whois 服务器发送查询
 func (c *Client) queryServer(ctx context.Context, server, query string, timeout time.Duration) (string, error) {
 	// 确保服务器地址包含端口
@@ -131,12 +269,30 @@ func (c *Client) queryServer(ctx context.Context, server, query string, timeout
 	}
 	defer conn.Close()
 
-	// 设置读写超时
-	conn.SetDeadline(time.Now().Add(timeout))
+	// 设置读写超时，上下文截止时间更早时以其为准
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	// 上下文取消时提前唤醒阻塞的读写
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
 
 	// 发送查询
 	_, err = fmt.Fprintf(conn, "%s\r\n", query)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		return "", fmt.Errorf("发送查询失败: %w", err)
 	}
 
@@ -149,6 +305,9 @@ func (c *Client) queryServer(ctx context.Context, server, query string, timeout
 	}
 
 	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		return "", fmt.Errorf("读取响应失败: %w", err)
 	}
 
@@ -236,6 +395,7 @@ func parseWhoisResponse(response string, queryType types.WhoisType) *types.Whois
 // parseDomainWhois 解析域名 Whois 响应
 func parseDomainWhois(response string, data *types.WhoisData) {
 	lines := strings.Split(response, "\n")
+	var created, expires, updated int
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -263,12 +423,13 @@ func parseDomainWhois(response string, data *types.WhoisData) {
 			data.RegistrantOrg = value
 		case "registrant email":
 			data.RegistrantEmail = value
-		case "creation date", "created":
-			data.CreationDate = parseDate(value)
-		case "expiration date", "registry expiry date", "expires":
-			data.ExpirationDate = parseDate(value)
-		case "updated date", "last updated", "modified":
-			data.UpdatedDate = parseDate(value)
+		case "creation date", "created", "created on", "registered on", "registration time", "registered":
+			setDate(&data.CreationDate, &created, value)
+		case "expiration date", "registry expiry date", "registrar registration expiration date",
+			"expires", "expires on", "expiry date", "expiration time", "paid-till", "renewal date":
+			setDate(&data.ExpirationDate, &expires, value)
+		case "updated date", "last updated", "last modified", "modified", "changed":
+			setDate(&data.UpdatedDate, &updated, value)
 		case "name server", "nserver":
 			data.NameServers = append(data.NameServers, value)
 		case "domain status", "status":
@@ -337,28 +498,82 @@ func parseASWhois(response string, data *types.WhoisData) {
 	}
 }
 
-// parseDate 解析日期字符串
+// 日期精度，同一响应中存在多个同类日期字段时保留精度最高的一个
+const (
+	datePrecisionNone = iota
+	datePrecisionMonth
+	datePrecisionDay
+	datePrecisionTime
+	datePrecisionZone
+)
+
+// dateLayout 日期格式及其精度
+type dateLayout struct {
+	layout    string
+	precision int
+}
+
+// dateLayouts 各注册局常见的日期格式
+var dateLayouts = []dateLayout{
+	{time.RFC3339, datePrecisionZone},                // 2006-01-02T15:04:05Z / -07:00，可带小数秒
+	{"2006-01-02T15:04:05Z0700", datePrecisionZone},  // 2006-01-02T15:04:05+0800
+	{"2006-01-02 15:04:05Z07:00", datePrecisionZone}, // 2006-01-02 15:04:05+08:00
+	{"2006-01-02 15:04:05 -0700", datePrecisionZone}, // 2006-01-02 15:04:05 +0800
+	{"2006-01-02 15:04:05 MST", datePrecisionZone},   // 2006-01-02 15:04:05 UTC
+	{time.UnixDate, datePrecisionZone},               // Mon Jan 2 15:04:05 MST 2006
+	{"2006-01-02T15:04:05", datePrecisionTime},       // 无时区
+	{"2006-01-02 15:04:05", datePrecisionTime},       // .cn 等
+	{"2006/01/02 15:04:05", datePrecisionTime},       // .jp
+	{"2006.01.02 15:04:05", datePrecisionTime},       // .pl
+	{"02.01.2006 15:04:05", datePrecisionTime},       // .cz / .ru 部分注册商
+	{"02-Jan-2006 15:04:05", datePrecisionTime},
+	{"2006-01-02", datePrecisionDay},
+	{"2006/01/02", datePrecisionDay},
+	{"2006.01.02", datePrecisionDay},    // .ru paid-till
+	{"2006. 01. 02.", datePrecisionDay}, // .kr
+	{"02.01.2006", datePrecisionDay},
+	{"02-Jan-2006", datePrecisionDay},
+	{"2-Jan-2006", datePrecisionDay},
+	{"02 Jan 2006", datePrecisionDay},
+	{"January 2 2006", datePrecisionDay},
+	{"Jan-2006", datePrecisionMonth}, // .uk "before Aug-1996"
+	{"January 2006", datePrecisionMonth},
+}
+
+var (
+	// dateZoneSuffix 末尾括号中的时区说明，如 "(JST)"
+	dateZoneSuffix = regexp.MustCompile(`\s+\([^)]+\)$`)
+	// datePrefix 日期前的修饰词，如 .uk 的 "before Aug-1996"
+	datePrefix = regexp.MustCompile(`(?i)^(before|after)\s+`)
+)
+
+// parseDate 解析日期字符串，无法识别时返回零值
 func parseDate(dateStr string) time.Time {
-	// 常见日期格式
-	formats := []string{
-		"2006-01-02T15:04:05Z",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
-		"02-Jan-2006",
-		"2006/01/02",
-	}
+	t, _ := parseDatePrecision(dateStr)
+	return t
+}
 
-	// 清理日期字符串
+// parseDatePrecision 解析日期字符串并返回其精度，无法识别时精度为 datePrecisionNone
+func parseDatePrecision(dateStr string) (time.Time, int) {
 	dateStr = strings.TrimSpace(dateStr)
-	// 移除时区信息
-	re := regexp.MustCompile(`\s+\([^)]+\)$`)
-	dateStr = re.ReplaceAllString(dateStr, "")
+	dateStr = dateZoneSuffix.ReplaceAllString(dateStr, "")
+	dateStr = datePrefix.ReplaceAllString(dateStr, "")
+	dateStr = strings.Join(strings.Fields(dateStr), " ")
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t
+	for _, f := range dateLayouts {
+		if t, err := time.Parse(f.layout, dateStr); err == nil {
+			return t, f.precision
 		}
 	}
 
-	return time.Time{}
+	return time.Time{}, datePrecisionNone
+}
+
+// setDate 解析 value 并在其精度高于已有值时写入 dst
+func setDate(dst *time.Time, precision *int, value string) {
+	t, p := parseDatePrecision(value)
+	if p > *precision {
+		*dst = t
+		*precision = p
+	}
 }
@@ -0,0 +1,257 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeWhois 启动本地 Whois 服务器，查询 "slow." 开头的域名时不响应
+func startFakeWhois(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				query := strings.TrimSpace(line)
+				if strings.HasPrefix(query, "slow.") {
+					time.Sleep(time.Second)
+					return
+				}
+				fmt.Fprintf(conn, "Domain Name: %s\r\n", strings.ToUpper(query))
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestQueryBatchPreservesOrderAndErrors(t *testing.T) {
+	opts := types.DefaultWhoisOptions()
+	opts.Server = startFakeWhois(t)
+	opts.Timeout = 200 * time.Millisecond
+	opts.RateLimit = 10 * time.Millisecond
+
+	queries := []string{"a.example", "slow.example", "b.example"}
+	results, err := NewClient().QueryBatch(context.Background(), queries, opts)
+	require.NoError(t, err)
+	require.Len(t, results, len(queries))
+
+	for i, query := range queries {
+		require.Equal(t, query, results[i].Query)
+	}
+	require.Empty(t, results[0].Error)
+	require.Equal(t, "A.EXAMPLE", results[0].ParsedData.Domain)
+	require.NotEmpty(t, results[1].Error)
+	require.Empty(t, results[2].Error)
+}
+
+func TestQueryAbortsOnContextCancel(t *testing.T) {
+	opts := types.DefaultWhoisOptions()
+	opts.Server = startFakeWhois(t)
+	opts.Timeout = 10 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := NewClient().Query(ctx, "slow.example", opts)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestParseOriginResponse(t *testing.T) {
+	response := "AS      | IP               | BGP Prefix          | CC | Registry | Allocated  | AS Name\n" +
+		"15169   | 8.8.8.8          | 8.8.8.0/24          | US | arin     | 2023-12-28 | GOOGLE, US\n"
+
+	data, err := parseOriginResponse(response, "8.8.8.8")
+	require.NoError(t, err)
+	require.Equal(t, 15169, data.ASN)
+	require.Equal(t, "GOOGLE, US", data.ASName)
+	require.Equal(t, "US", data.Country)
+	require.Equal(t, "8.8.8.0/24", data.IPRange)
+
+	data, err = parseOriginResponse("NA      | 192.0.2.1        | NA                  |    | other    |            | NA\n", "192.0.2.1")
+	require.NoError(t, err)
+	require.Zero(t, data.ASN)
+	require.Empty(t, data.ASName)
+
+	_, err = parseOriginResponse("Error: no ASN or IP match on line 1.\n", "8.8.8.8")
+	require.Error(t, err)
+}
+
+// startStubWhois 启动对任意查询都返回固定响应的本地 Whois 服务器
+func startStubWhois(t *testing.T, response string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+					return
+				}
+				fmt.Fprint(conn, response)
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestQueryFollowsRegistrarReferral(t *testing.T) {
+	registrar := startStubWhois(t, "Domain Name: EXAMPLE.COM\r\n"+
+		"Registrar: Example Registrar, Inc.\r\n"+
+		"Registrant Organization: Example Org\r\n"+
+		"Registrant Email: admin@example.com\r\n"+
+		"Name Server: ns1.example.com\r\n")
+	registry := startStubWhois(t, "   Domain Name: EXAMPLE.COM\r\n"+
+		"   Registrar WHOIS Server: "+registrar+"\r\n"+
+		"   Registrar: EXAMPLE REGISTRAR\r\n"+
+		"   Creation Date: 1995-08-14T04:00:00Z\r\n"+
+		"   Name Server: NS1.EXAMPLE.COM\r\n"+
+		"   Name Server: NS2.EXAMPLE.COM\r\n")
+
+	opts := types.DefaultWhoisOptions()
+	opts.Server = registry
+	opts.Timeout = time.Second
+
+	result, err := NewClient().Query(context.Background(), "example.com", opts)
+	require.NoError(t, err)
+	require.Equal(t, registrar, result.ReferralServer)
+	require.Contains(t, result.RawResponse, "Registrar WHOIS Server")
+	require.Contains(t, result.ReferralResponse, "Registrant Organization")
+
+	data := result.ParsedData
+	require.Equal(t, "Example Registrar, Inc.", data.Registrar)
+	require.Equal(t, "Example Org", data.RegistrantOrg)
+	require.Equal(t, "admin@example.com", data.RegistrantEmail)
+	require.Equal(t, 1995, data.CreationDate.Year())
+	require.Equal(t, []string{"ns1.example.com"}, data.NameServers)
+
+	// 关闭跟随时只使用注册局的响应
+	opts.FollowReferrals = false
+	result, err = NewClient().Query(context.Background(), "example.com", opts)
+	require.NoError(t, err)
+	require.Empty(t, result.ReferralServer)
+	require.Empty(t, result.ParsedData.RegistrantOrg)
+	require.Equal(t, "EXAMPLE REGISTRAR", result.ParsedData.Registrar)
+}
+
+func TestQueryBatchSpacesSameServerQueries(t *testing.T) {
+	// 记录每次查询到达的时间
+	var mu sync.Mutex
+	var arrivals []time.Time
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			arrivals = append(arrivals, time.Now())
+			mu.Unlock()
+			go func(conn net.Conn) {
+				defer conn.Close()
+				line, _ := bufio.NewReader(conn).ReadString('\n')
+				fmt.Fprintf(conn, "Domain Name: %s\r\n", strings.TrimSpace(line))
+			}(conn)
+		}
+	}()
+
+	opts := types.DefaultWhoisOptions()
+	opts.Server = ln.Addr().String()
+	opts.Concurrency = 4
+	opts.RateLimit = 100 * time.Millisecond
+
+	queries := []string{"a.example", "b.example", "c.example", "d.example"}
+	results, err := NewClient().QueryBatch(context.Background(), queries, opts)
+	require.NoError(t, err)
+	for i, query := range queries {
+		require.Equal(t, query, results[i].Query)
+		require.Equal(t, query, results[i].ParsedData.Domain)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, arrivals, len(queries))
+	for i := 1; i < len(arrivals); i++ {
+		require.GreaterOrEqual(t, arrivals[i].Sub(arrivals[i-1]), 90*time.Millisecond)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	cst := time.FixedZone("", 8*3600)
+	tests := []struct {
+		input     string
+		want      time.Time
+		precision int
+	}{
+		{"2025-09-14T04:00:00Z", time.Date(2025, 9, 14, 4, 0, 0, 0, time.UTC), datePrecisionZone},
+		{"2028-09-13T07:00:00.000Z", time.Date(2028, 9, 13, 7, 0, 0, 0, time.UTC), datePrecisionZone},
+		{"2018-03-12T21:44:25-07:00", time.Date(2018, 3, 12, 21, 44, 25, 0, time.FixedZone("", -7*3600)), datePrecisionZone},
+		{"2020-06-04T10:37:45+0800", time.Date(2020, 6, 4, 10, 37, 45, 0, cst), datePrecisionZone},
+		{"2003-03-17 12:20:05", time.Date(2003, 3, 17, 12, 20, 5, 0, time.UTC), datePrecisionTime},
+		{"2001/01/17 12:00:00 (JST)", time.Date(2001, 1, 17, 12, 0, 0, 0, time.UTC), datePrecisionTime},
+		{"2000.05.19 13:00:00", time.Date(2000, 5, 19, 13, 0, 0, 0, time.UTC), datePrecisionTime},
+		{"2026.07.01", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), datePrecisionDay},
+		{"2007. 03. 02.", time.Date(2007, 3, 2, 0, 0, 0, 0, time.UTC), datePrecisionDay},
+		{"15-Sep-1997", time.Date(1997, 9, 15, 0, 0, 0, 0, time.UTC), datePrecisionDay},
+		{"29.02.2024", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), datePrecisionDay},
+		{"  2024/11/30  ", time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC), datePrecisionDay},
+		{"before Aug-1996", time.Date(1996, 8, 1, 0, 0, 0, 0, time.UTC), datePrecisionMonth},
+		{"not a date", time.Time{}, datePrecisionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, precision := parseDatePrecision(tt.input)
+			require.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+			require.Equal(t, tt.precision, precision)
+		})
+	}
+}
+
+func TestParseDomainWhoisPrefersMostSpecificDate(t *testing.T) {
+	data := &types.WhoisData{}
+	parseDomainWhois(strings.Join([]string{
+		"Expiry date: 2026-07-01",
+		"Registry Expiry Date: 2026-07-01T08:15:00Z",
+		"Expires: Jul-2026",
+		"Created: before Aug-1996",
+	}, "\n"), data)
+
+	require.Equal(t, time.Date(2026, 7, 1, 8, 15, 0, 0, time.UTC), data.ExpirationDate)
+	require.Equal(t, time.Date(1996, 8, 1, 0, 0, 0, 0, time.UTC), data.CreationDate)
+}
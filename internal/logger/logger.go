@@ -21,12 +21,15 @@ package logger
 
 import (
 	"fmt"
+	"sync"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 var (
+	// mu 保护下列全局状态，任务协程可能在 Init 之前并发触发惰性初始化
+	mu sync.RWMutex
 	// globalLogger 是全局日志实例
 	globalLogger *zap.Logger
 	// globalSugar 是全局 sugar logger 实例（提供更简洁的 API）
@@ -65,6 +68,13 @@ func DefaultConfig() Config {
 
 // Init 初始化全局日志系统
 func Init(cfg Config) error {
+	mu.Lock()
+	defer mu.Unlock()
+	return initLocked(cfg)
+}
+
+// initLocked 初始化全局日志系统，调用方需持有 mu 写锁
+func initLocked(cfg Config) error {
 	if closeFn != nil {
 		closeFn()
 		closeFn = nil
@@ -155,24 +165,47 @@ func Init(cfg Config) error {
 	return nil
 }
 
-// L 返回全局 logger 实例
+// L 返回全局 logger 实例，未初始化时使用默认配置
 func L() *zap.Logger {
-	if globalLogger == nil {
-		// 如果未初始化，使用默认配置
-		_ = Init(DefaultConfig())
+	mu.RLock()
+	l := globalLogger
+	mu.RUnlock()
+	if l != nil {
+		return l
 	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	ensureLocked()
 	return globalLogger
 }
 
-// S 返回全局 sugar logger 实例
+// S 返回全局 sugar logger 实例，未初始化时使用默认配置
 func S() *zap.SugaredLogger {
-	if globalSugar == nil {
-		// 如果未初始化，使用默认配置
-		_ = Init(DefaultConfig())
+	mu.RLock()
+	s := globalSugar
+	mu.RUnlock()
+	if s != nil {
+		return s
 	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	ensureLocked()
 	return globalSugar
 }
 
+// ensureLocked 按默认配置完成惰性初始化，失败时退化为不输出的 logger，调用方需持有 mu 写锁
+func ensureLocked() {
+	if globalLogger != nil {
+		return
+	}
+	if err := initLocked(DefaultConfig()); err != nil {
+		globalLogger = zap.NewNop()
+		globalSugar = globalLogger.Sugar()
+	}
+}
+
 // Debug 记录 debug 级别日志
 func Debug(msg string, fields ...zap.Field) {
 	L().Debug(msg, fields...)
@@ -200,6 +233,9 @@ func Fatal(msg string, fields ...zap.Field) {
 
 // Sync 刷新缓冲的日志
 func Sync() error {
+	mu.Lock()
+	defer mu.Unlock()
+
 	var syncErr error
 	if globalLogger != nil {
 		syncErr = globalLogger.Sync()
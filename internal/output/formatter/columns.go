@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+)
+
+// Column 表格列定义：列名用于 --columns 选择，Value 从条目中提取单元格内容
+type Column[T any] struct {
+	Name   string
+	Header string
+	Value  func(item T, printer *termutil.ColorPrinter) string
+}
+
+// ColumnNames 返回注册表中的全部列名
+func ColumnNames[T any](registry []Column[T]) []string {
+	names := make([]string, len(registry))
+	for i, col := range registry {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// SelectColumns 按逗号分隔的列名从注册表中选择列，保持给定顺序；
+// 列名不区分大小写，未知或重复的列名返回错误
+func SelectColumns[T any](registry []Column[T], spec string) ([]Column[T], error) {
+	index := make(map[string]Column[T], len(registry))
+	for _, col := range registry {
+		index[col.Name] = col
+	}
+
+	seen := make(map[string]bool)
+	var cols []Column[T]
+	for _, part := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		col, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("未知的列 %q，可用的列: %s", name, strings.Join(ColumnNames(registry), ","))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("列 %q 重复", name)
+		}
+		seen[name] = true
+		cols = append(cols, col)
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("未指定任何列，可用的列: %s", strings.Join(ColumnNames(registry), ","))
+	}
+	return cols, nil
+}
+
+// RenderColumns 按列定义将条目渲染为表格，列宽随内容自动调整
+func RenderColumns[T any](items []T, cols []Column[T], noColor bool) string {
+	printer := termutil.NewColorPrinter(noColor)
+
+	headers := make([]string, len(cols))
+	for i, col := range cols {
+		headers[i] = printer.Bold(col.Header)
+	}
+	table := NewAutoTable(headers)
+
+	row := make([]string, len(cols))
+	for _, item := range items {
+		for i, col := range cols {
+			row[i] = col.Value(item, printer)
+		}
+		table.AddRow(row...)
+	}
+
+	var sb strings.Builder
+	table.Render(&sb)
+	return sb.String()
+}
+
+// pickColumns 按列名从注册表中取出列，供默认布局使用
+func pickColumns[T any](registry []Column[T], names ...string) []Column[T] {
+	cols, err := SelectColumns(registry, strings.Join(names, ","))
+	if err != nil {
+		panic(err)
+	}
+	return cols
+}
+
+// OmittedNotice 返回列表被 --limit 截断时的提示（以换行结尾），omitted 为 0 时返回空字符串
+func OmittedNotice(omitted int) string {
+	if omitted <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("... and %d more (use --limit 0 to show all)\n", omitted)
+}
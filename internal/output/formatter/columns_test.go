@@ -0,0 +1,32 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectColumnsKeepsOrder(t *testing.T) {
+	cols, err := SelectColumns(ConnectionColumns, "State, REMOTE,proto")
+	require.NoError(t, err)
+	require.Equal(t, []string{"state", "remote", "proto"}, ColumnNames(cols))
+
+	conns := []*types.Connection{{Protocol: "tcp", RemoteAddr: "192.0.2.1", RemotePort: 443, State: "ESTABLISHED"}}
+	lines := strings.Split(RenderColumns(conns, cols, true), "\n")
+	require.Equal(t, []string{"State", "Remote", "Address", "Proto"}, strings.Fields(lines[1]))
+	require.Equal(t, []string{"ESTABLISHED", "192.0.2.1:443", "tcp"}, strings.Fields(lines[3]))
+}
+
+func TestSelectColumnsRejectsUnknown(t *testing.T) {
+	_, err := SelectColumns(ListenerColumns, "proto,state")
+	require.ErrorContains(t, err, `"state"`)
+	require.ErrorContains(t, err, "proto,local,pid,process")
+
+	_, err = SelectColumns(ConnectionColumns, "pid,pid")
+	require.Error(t, err)
+
+	_, err = SelectColumns(ConnectionColumns, " , ")
+	require.Error(t, err)
+}
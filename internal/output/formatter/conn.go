@@ -0,0 +1,127 @@
+// Package formatter 提供网络连接与监听端口结果格式化
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"fmt"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// FormatConnectionsText 格式化连接列表为文本（表格 + 汇总）
+func FormatConnectionsText(connections []*types.Connection, showProcess, noColor bool) string {
+	if len(connections) == 0 {
+		return "无网络连接\n"
+	}
+	return FormatConnectionsTable(connections, showProcess, noColor) +
+		fmt.Sprintf("\nTotal: %d connections\n", len(connections))
+}
+
+// ConnectionColumns 连接表格可选列，--columns 按名称选择
+var ConnectionColumns = []Column[*types.Connection]{
+	{Name: "proto", Header: "Proto", Value: func(c *types.Connection, _ *termutil.ColorPrinter) string {
+		return c.Protocol
+	}},
+	{Name: "local", Header: "Local Address", Value: func(c *types.Connection, _ *termutil.ColorPrinter) string {
+		return fmt.Sprintf("%s:%d", c.LocalAddr, c.LocalPort)
+	}},
+	{Name: "remote", Header: "Remote Address", Value: func(c *types.Connection, _ *termutil.ColorPrinter) string {
+		remote := c.RemoteAddr
+		if c.RemoteHost != "" {
+			remote = c.RemoteHost
+		}
+		return fmt.Sprintf("%s:%d", remote, c.RemotePort)
+	}},
+	{Name: "state", Header: "State", Value: func(c *types.Connection, _ *termutil.ColorPrinter) string {
+		return string(c.State)
+	}},
+	{Name: "pid", Header: "PID", Value: func(c *types.Connection, _ *termutil.ColorPrinter) string {
+		return fmt.Sprintf("%d", c.PID)
+	}},
+	{Name: "process", Header: "Process", Value: func(c *types.Connection, _ *termutil.ColorPrinter) string {
+		return processInfo(c.PID, c.ProcessName)
+	}},
+}
+
+// ListenerColumns 监听端口表格可选列，--columns 按名称选择
+var ListenerColumns = []Column[*types.Listener]{
+	{Name: "proto", Header: "Proto", Value: func(l *types.Listener, _ *termutil.ColorPrinter) string {
+		return l.Protocol
+	}},
+	{Name: "local", Header: "Local Address", Value: func(l *types.Listener, _ *termutil.ColorPrinter) string {
+		return fmt.Sprintf("%s:%d", l.Addr, l.Port)
+	}},
+	{Name: "pid", Header: "PID", Value: func(l *types.Listener, _ *termutil.ColorPrinter) string {
+		return fmt.Sprintf("%d", l.PID)
+	}},
+	{Name: "process", Header: "Process", Value: func(l *types.Listener, _ *termutil.ColorPrinter) string {
+		return processInfo(l.PID, l.ProcessName)
+	}},
+}
+
+// DefaultConnectionColumns 返回连接表格的默认列，showProcess 控制是否包含进程列
+func DefaultConnectionColumns(showProcess bool) []Column[*types.Connection] {
+	names := []string{"proto", "local", "remote", "state"}
+	if showProcess {
+		names = append(names, "pid", "process")
+	}
+	return pickColumns(ConnectionColumns, names...)
+}
+
+// DefaultListenerColumns 返回监听端口表格的默认列，showProcess 控制是否包含进程列
+func DefaultListenerColumns(showProcess bool) []Column[*types.Listener] {
+	names := []string{"proto", "local"}
+	if showProcess {
+		names = append(names, "pid", "process")
+	}
+	return pickColumns(ListenerColumns, names...)
+}
+
+// FormatConnectionsTable 格式化连接列表为表格，showProcess 控制是否显示进程列
+func FormatConnectionsTable(connections []*types.Connection, showProcess, noColor bool) string {
+	return RenderColumns(connections, DefaultConnectionColumns(showProcess), noColor)
+}
+
+// FormatListenersText 格式化监听端口列表为文本（表格 + 汇总）
+func FormatListenersText(listeners []*types.Listener, showProcess, noColor bool) string {
+	if len(listeners) == 0 {
+		return "无监听端口\n"
+	}
+	return FormatListenersTable(listeners, showProcess, noColor) +
+		fmt.Sprintf("\nTotal: %d listeners\n", len(listeners))
+}
+
+// FormatListenersTable 格式化监听端口列表为表格，showProcess 控制是否显示进程列
+func FormatListenersTable(listeners []*types.Listener, showProcess, noColor bool) string {
+	return RenderColumns(listeners, DefaultListenerColumns(showProcess), noColor)
+}
+
+// processInfo 返回 "pid/name"，未知进程显示 "-"
+func processInfo(pid int, name string) string {
+	if pid > 0 {
+		return fmt.Sprintf("%d/%s", pid, name)
+	}
+	return "-"
+}
+
+// connectionsHaveProcess 判断连接列表中是否包含进程信息
+func connectionsHaveProcess(connections []*types.Connection) bool {
+	for _, conn := range connections {
+		if conn.PID > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// listenersHaveProcess 判断监听列表中是否包含进程信息
+func listenersHaveProcess(listeners []*types.Listener) bool {
+	for _, listener := range listeners {
+		if listener.PID > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Package formatter 提供网络诊断结果格式化
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// FormatDiagText 格式化诊断结果为文本，verbose 时列出各检查项的详细信息
+func FormatDiagText(result *types.DiagnosticResult, verbose, noColor bool) string {
+	var sb strings.Builder
+	printer := termutil.NewColorPrinter(noColor)
+	tf := NewTextFormatter(!noColor)
+
+	sb.WriteString(tf.SubHeader("检查结果") + "\n")
+	for _, check := range result.Checks {
+		fmt.Fprintf(&sb, "%s %-30s %s\n",
+			diagStatusSymbol(check.Status, printer),
+			check.Name,
+			diagStatusColor(check.Status, printer)(check.Message),
+		)
+
+		if verbose && len(check.Details) > 0 {
+			keys := make([]string, 0, len(check.Details))
+			for key := range check.Details {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Fprintf(&sb, "    %s: %v\n", key, check.Details[key])
+			}
+		}
+	}
+	sb.WriteString("\n")
+
+	if len(result.Issues) > 0 {
+		sb.WriteString(tf.SubHeader("发现的问题") + "\n")
+		for i, issue := range result.Issues {
+			fmt.Fprintf(&sb, "%d. [%s] %s\n",
+				i+1,
+				diagStatusColor(issue.Severity, printer)(issue.Severity.String()),
+				issue.Description,
+			)
+			if issue.Suggestion != "" {
+				fmt.Fprintf(&sb, "   建议: %s\n", printer.Warning(issue.Suggestion))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(result.Suggestions) > 0 {
+		sb.WriteString(tf.SubHeader("修复建议") + "\n")
+		for i, suggestion := range result.Suggestions {
+			fmt.Fprintf(&sb, "%d. %s\n", i+1, suggestion)
+		}
+		sb.WriteString("\n")
+	}
+
+	divider := strings.Repeat("=", 70)
+	sb.WriteString(divider + "\n")
+	fmt.Fprintf(&sb, "整体状态: %s\n", printer.Bold(diagStatusColor(result.Status, printer)(result.Status.String())))
+	fmt.Fprintf(&sb, "诊断耗时: %s\n", result.Duration.Round(time.Millisecond))
+	fmt.Fprintf(&sb, "检查项目: %d 项\n", len(result.Checks))
+	fmt.Fprintf(&sb, "发现问题: %d 个\n", len(result.Issues))
+	sb.WriteString(divider + "\n")
+
+	return sb.String()
+}
+
+// diagStatusSymbol 返回诊断状态对应的符号
+func diagStatusSymbol(status types.DiagnosticStatus, printer *termutil.ColorPrinter) string {
+	switch status {
+	case types.DiagStatusHealthy:
+		return printer.Success("✓")
+	case types.DiagStatusWarning:
+		return printer.Warning("⚠")
+	case types.DiagStatusCritical:
+		return printer.Error("✗")
+	default:
+		return "?"
+	}
+}
+
+// diagStatusColor 返回诊断状态对应的颜色函数
+func diagStatusColor(status types.DiagnosticStatus, printer *termutil.ColorPrinter) func(...interface{}) string {
+	switch status {
+	case types.DiagStatusHealthy:
+		return printer.Success
+	case types.DiagStatusWarning:
+		return printer.Warning
+	case types.DiagStatusCritical:
+		return printer.Error
+	default:
+		return fmt.Sprint
+	}
+}
@@ -0,0 +1,48 @@
+package formatter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleDiagResult() *types.DiagnosticResult {
+	return &types.DiagnosticResult{
+		Duration: 1234 * time.Millisecond,
+		Status:   types.DiagStatusWarning,
+		Checks: []*types.CheckResult{
+			{Name: "网络接口", Status: types.DiagStatusHealthy, Message: "2 个接口正常", Details: map[string]interface{}{"up": 2, "down": 0}},
+			{Name: "DNS 解析", Status: types.DiagStatusWarning, Message: "解析较慢"},
+		},
+		Issues: []*types.Issue{
+			{Severity: types.DiagStatusWarning, Description: "DNS 响应慢", Suggestion: "更换 DNS 服务器"},
+		},
+		Suggestions: []string{"检查 /etc/resolv.conf"},
+	}
+}
+
+func TestFormatDiagText(t *testing.T) {
+	out := FormatDiagText(sampleDiagResult(), false, true)
+	for _, want := range []string{
+		"✓ 网络接口",
+		"⚠ DNS 解析",
+		"1. [" + types.DiagStatusWarning.String() + "] DNS 响应慢",
+		"   建议: 更换 DNS 服务器",
+		"1. 检查 /etc/resolv.conf",
+		"整体状态: " + types.DiagStatusWarning.String(),
+		"诊断耗时: 1.234s",
+		"检查项目: 2 项",
+		"发现问题: 1 个",
+	} {
+		require.Contains(t, out, want)
+	}
+	require.NotContains(t, out, "    up: 2")
+	require.NotContains(t, out, "\x1b[")
+}
+
+func TestFormatDiagTextVerboseDetailsSorted(t *testing.T) {
+	out := FormatDiagText(sampleDiagResult(), true, true)
+	require.Contains(t, out, "    down: 0\n    up: 2\n")
+}
@@ -0,0 +1,89 @@
+// Package formatter 提供 DNS 解析链格式化
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// DNSChain 从应答记录中还原的解析链: 查询名 → CNAME → ... → 最终记录
+type DNSChain struct {
+	// Domain 查询的域名
+	Domain string `json:"domain" yaml:"domain"`
+	// RecordType 查询的记录类型
+	RecordType types.DNSRecordType `json:"record_type" yaml:"record_type"`
+	// CNAMEs 按解析顺序排列的 CNAME 记录
+	CNAMEs []*types.DNSRecord `json:"cnames,omitempty" yaml:"cnames,omitempty"`
+	// Answers 链末端名称上的最终记录
+	Answers []*types.DNSRecord `json:"answers,omitempty" yaml:"answers,omitempty"`
+	// Loop CNAME 形成环路时为 true
+	Loop bool `json:"loop,omitempty" yaml:"loop,omitempty"`
+}
+
+// BuildDNSChain 按名称跟随应答中的 CNAME 记录，不依赖记录在应答中的顺序
+func BuildDNSChain(result *types.DNSResult) *DNSChain {
+	chain := &DNSChain{Domain: result.Domain, RecordType: result.RecordType}
+
+	cnames := make(map[string]*types.DNSRecord)
+	for _, record := range result.Records {
+		if record.Type == types.DNSTypeCNAME {
+			cnames[dnsNameKey(record.Name)] = record
+		}
+	}
+
+	current := dnsNameKey(result.Domain)
+	visited := map[string]bool{current: true}
+	for {
+		record, ok := cnames[current]
+		if !ok {
+			break
+		}
+		chain.CNAMEs = append(chain.CNAMEs, record)
+		current = dnsNameKey(record.Value)
+		if visited[current] {
+			chain.Loop = true
+			return chain
+		}
+		visited[current] = true
+	}
+
+	for _, record := range result.Records {
+		if record.Type != types.DNSTypeCNAME && dnsNameKey(record.Name) == current {
+			chain.Answers = append(chain.Answers, record)
+		}
+	}
+	return chain
+}
+
+// FormatDNSChainText 以逐跳形式显示解析链
+func FormatDNSChainText(chain *DNSChain, noColor bool) string {
+	printer := termutil.NewColorPrinter(noColor)
+
+	var sb strings.Builder
+	sb.WriteString(printer.Bold(chain.Domain) + "\n")
+	for _, record := range chain.CNAMEs {
+		sb.WriteString(fmt.Sprintf("  → %s %s %s\n",
+			printer.Info("CNAME"), record.Value, printer.Muted(fmt.Sprintf("(TTL %d)", record.TTL))))
+	}
+	switch {
+	case chain.Loop:
+		sb.WriteString(printer.Error("  ✗ CNAME 环路") + "\n")
+	case len(chain.Answers) == 0:
+		sb.WriteString(printer.Warning(fmt.Sprintf("  ✗ 链末端无 %s 记录", chain.RecordType)) + "\n")
+	default:
+		for _, record := range chain.Answers {
+			sb.WriteString(fmt.Sprintf("  → %s %s %s\n",
+				printer.Success(record.Type.String()), record.Value, printer.Muted(fmt.Sprintf("(TTL %d)", record.TTL))))
+		}
+	}
+	return sb.String()
+}
+
+func dnsNameKey(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,48 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDNSChainFollowsCNAMEsOutOfOrder(t *testing.T) {
+	result := &types.DNSResult{
+		Domain:     "www.example.com",
+		RecordType: types.DNSTypeA,
+		Records: []*types.DNSRecord{
+			{Name: "edge.cdn.net", Type: types.DNSTypeA, TTL: 20, Value: "192.0.2.1"},
+			{Name: "example.net", Type: types.DNSTypeCNAME, TTL: 60, Value: "edge.cdn.net"},
+			{Name: "WWW.example.com", Type: types.DNSTypeCNAME, TTL: 300, Value: "example.net"},
+			{Name: "edge.cdn.net", Type: types.DNSTypeA, TTL: 20, Value: "192.0.2.2"},
+		},
+	}
+
+	chain := BuildDNSChain(result)
+	require.False(t, chain.Loop)
+	require.Len(t, chain.CNAMEs, 2)
+	require.Equal(t, "example.net", chain.CNAMEs[0].Value)
+	require.Equal(t, "edge.cdn.net", chain.CNAMEs[1].Value)
+	require.Len(t, chain.Answers, 2)
+
+	text := FormatDNSChainText(chain, true)
+	require.True(t, strings.Index(text, "example.net") < strings.Index(text, "edge.cdn.net"), text)
+	require.Contains(t, text, "→ A 192.0.2.2 (TTL 20)")
+}
+
+func TestBuildDNSChainDetectsLoop(t *testing.T) {
+	result := &types.DNSResult{
+		Domain: "a.example",
+		Records: []*types.DNSRecord{
+			{Name: "a.example", Type: types.DNSTypeCNAME, Value: "b.example"},
+			{Name: "b.example", Type: types.DNSTypeCNAME, Value: "a.example"},
+		},
+	}
+
+	chain := BuildDNSChain(result)
+	require.True(t, chain.Loop)
+	require.Len(t, chain.CNAMEs, 2)
+	require.Empty(t, chain.Answers)
+}
@@ -5,6 +5,7 @@
 // - JSON: JSON 格式
 // - YAML: YAML 格式
 // - Table: 表格格式
+// - NDJSON: 换行分隔的 JSON（流式模式见 NDJSONWriter）
 //
 // 依赖：
 // - encoding/json: JSON 编码
@@ -43,6 +44,10 @@ type Config struct {
 	NoColor bool
 	// Indent 是否缩进（JSON/YAML）
 	Indent bool
+	// Verbose 文本/表格输出附加详细统计（如 RTT 百分位与抖动）
+	Verbose bool
+	// ShowLossMap Ping 文本输出附加按序列号排列的丢包图
+	ShowLossMap bool
 }
 
 // formatter 格式化器实现
@@ -79,6 +84,8 @@ func (f *formatter) Format(data interface{}) (string, error) {
 		return f.formatText(data)
 	case types.OutputTable:
 		return f.formatTable(data)
+	case types.OutputNDJSON:
+		return f.formatNDJSON(data)
 	default:
 		return "", fmt.Errorf("unsupported output format: %s", f.config.Format)
 	}
@@ -112,6 +119,15 @@ func (f *formatter) formatJSON(data interface{}) (string, error) {
 	return string(b), nil
 }
 
+// formatNDJSON 非流式数据输出为单行 JSON，以换行结尾
+func (f *formatter) formatNDJSON(data interface{}) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("json marshal failed: %w", err)
+	}
+	return string(b) + "\n", nil
+}
+
 // formatYAML 格式化为 YAML
 func (f *formatter) formatYAML(data interface{}) (string, error) {
 	b, err := yaml.Marshal(data)
@@ -126,9 +142,21 @@ func (f *formatter) formatText(data interface{}) (string, error) {
 	// 根据数据类型选择合适的文本格式化器
 	switch v := data.(type) {
 	case *types.PingResult:
-		return FormatPingText(v, f.config.NoColor), nil
+		return FormatPingText(v, f.config.NoColor, f.config.Verbose, f.config.ShowLossMap), nil
 	case *types.TraceResult:
 		return FormatTraceText(v, f.config.NoColor), nil
+	case *types.TraceAggregate:
+		return FormatTraceAggregateText(v, f.config.NoColor), nil
+	case []*types.Connection:
+		return FormatConnectionsText(v, connectionsHaveProcess(v), f.config.NoColor), nil
+	case []*types.Listener:
+		return FormatListenersText(v, listenersHaveProcess(v), f.config.NoColor), nil
+	case *types.ScanResult:
+		return FormatScanText(v, nil, 0, f.config.NoColor), nil
+	case *types.WhoisResult:
+		return FormatWhoisText(v, WhoisTextOptions{}, f.config.NoColor), nil
+	case *types.DiagnosticResult:
+		return FormatDiagText(v, f.config.Verbose, f.config.NoColor), nil
 	default:
 		// 默认使用 JSON 格式
 		return f.formatJSON(data)
@@ -140,9 +168,16 @@ func (f *formatter) formatTable(data interface{}) (string, error) {
 	// 根据数据类型选择合适的表格格式化器
 	switch v := data.(type) {
 	case *types.PingResult:
-		return FormatPingTable(v, f.config.NoColor), nil
+		return FormatPingTable(v, f.config.NoColor, f.config.Verbose), nil
 	case *types.TraceResult:
 		return FormatTraceTable(v, f.config.NoColor), nil
+	case []*types.Connection:
+		return FormatConnectionsTable(v, connectionsHaveProcess(v), f.config.NoColor), nil
+	case []*types.Listener:
+		return FormatListenersTable(v, listenersHaveProcess(v), f.config.NoColor), nil
+	case *types.ScanResult, *types.WhoisResult, *types.DiagnosticResult:
+		// 文本输出已包含表格布局
+		return f.formatText(data)
 	default:
 		// 默认使用文本格式
 		return f.formatText(data)
@@ -0,0 +1,59 @@
+// Package formatter 提供 NDJSON 流式输出
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// NDJSON 记录类型
+const (
+	// NDJSONReply 单次 Ping 响应
+	NDJSONReply = "reply"
+	// NDJSONPort 单个端口的扫描结果
+	NDJSONPort = "port"
+	// NDJSONSummary 流结束时的统计汇总
+	NDJSONSummary = "summary"
+)
+
+// NDJSONRecord NDJSON 流中的一条记录，Type 区分事件与汇总
+type NDJSONRecord struct {
+	Type   string      `json:"type"`
+	Target string      `json:"target,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
+// NDJSONWriter 逐条写出换行分隔的 JSON 记录，可并发调用
+//
+// 每条记录写出后立即刷新（底层 Writer 实现 Flush 时），便于管道下游实时处理。
+type NDJSONWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONWriter 创建 NDJSON 写出器
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{w: w}
+}
+
+// Write 写出一条记录
+func (n *NDJSONWriter) Write(recordType, target string, data interface{}) error {
+	b, err := json.Marshal(NDJSONRecord{Type: recordType, Target: target, Data: data})
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := n.w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if f, ok := n.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
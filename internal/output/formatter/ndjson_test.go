@@ -0,0 +1,49 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// flushBuffer 记录 Flush 调用次数
+type flushBuffer struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (b *flushBuffer) Flush() error {
+	b.flushes++
+	return nil
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf flushBuffer
+	w := NewNDJSONWriter(&buf)
+	require.NoError(t, w.Write(NDJSONPort, "example.com", &types.ScanPort{Port: 22, State: types.PortOpen}))
+	require.NoError(t, w.Write(NDJSONSummary, "", &types.ScanSummary{TotalPorts: 1, OpenPorts: 1}))
+	require.Equal(t, 2, buf.flushes)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var first NDJSONRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.Equal(t, NDJSONPort, first.Type)
+	require.Equal(t, "example.com", first.Target)
+
+	var second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	require.Equal(t, NDJSONSummary, second["type"])
+	require.NotContains(t, second, "target")
+}
+
+func TestFormatNDJSONSingleLine(t *testing.T) {
+	out, err := NewFormatter(types.OutputNDJSON, true).Format(map[string]any{"a": []int{1, 2}})
+	require.NoError(t, err)
+	require.Equal(t, "{\"a\":[1,2]}\n", out)
+}
@@ -5,6 +5,7 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,8 +13,11 @@ import (
 	"github.com/catsayer/ntx/pkg/types"
 )
 
-// FormatPingText 格式化 Ping 结果为文本
-func FormatPingText(result *types.PingResult, noColor bool) string {
+// lossMapWidth 丢包图每行的探测数
+const lossMapWidth = 30
+
+// FormatPingText 格式化 Ping 结果为文本，lossMap 为 true 时在统计信息后附加丢包图
+func FormatPingText(result *types.PingResult, noColor, verbose, lossMap bool) string {
 	var sb strings.Builder
 
 	// 设置颜色函数
@@ -23,6 +27,7 @@ func FormatPingText(result *types.PingResult, noColor bool) string {
 	yellow := printer.Warning
 	cyan := printer.Info
 	bold := printer.Bold
+	muted := printer.Muted
 
 	// 标题
 	sb.WriteString(bold(fmt.Sprintf("PING %s (%s) %s protocol\n",
@@ -35,12 +40,24 @@ func FormatPingText(result *types.PingResult, noColor bool) string {
 	for _, reply := range result.Replies {
 		switch reply.Status {
 		case types.StatusSuccess:
+			if reply.Duplicate {
+				sb.WriteString(yellow(fmt.Sprintf("Reply from %s: bytes=%d time=%v ttl=%d seq=%d (DUP!)\n",
+					reply.From,
+					reply.Bytes,
+					formatDuration(reply.RTT),
+					reply.TTL,
+					reply.Seq)))
+				break
+			}
 			sb.WriteString(green(fmt.Sprintf("Reply from %s: bytes=%d time=%v ttl=%d seq=%d\n",
 				reply.From,
 				reply.Bytes,
 				formatDuration(reply.RTT),
 				reply.TTL,
 				reply.Seq)))
+			if verbose && reply.HTTPTimings != nil {
+				sb.WriteString("  " + muted(FormatHTTPTimings(reply.HTTPTimings)) + "\n")
+			}
 		case types.StatusTimeout:
 			sb.WriteString(red(fmt.Sprintf("Request timeout for seq=%d\n", reply.Seq)))
 		case types.StatusFailure:
@@ -56,9 +73,14 @@ func FormatPingText(result *types.PingResult, noColor bool) string {
 		sb.WriteString(bold(fmt.Sprintf("--- %s ping statistics ---\n", result.Target.Hostname)))
 
 		stats := result.Statistics
-		sb.WriteString(fmt.Sprintf("%d packets transmitted, %d packets received, %.1f%% packet loss\n",
+		dups := ""
+		if stats.Duplicates > 0 {
+			dups = fmt.Sprintf(", +%d duplicates", stats.Duplicates)
+		}
+		sb.WriteString(fmt.Sprintf("%d packets transmitted, %d packets received%s, %.1f%% packet loss\n",
 			stats.Sent,
 			stats.Received,
+			dups,
 			stats.LossRate))
 
 		if stats.Received > 0 {
@@ -67,6 +89,13 @@ func FormatPingText(result *types.PingResult, noColor bool) string {
 				formatDuration(stats.AvgRTT),
 				formatDuration(stats.MaxRTT),
 				formatDuration(stats.StdDevRTT))))
+			if verbose {
+				sb.WriteString(cyan(fmt.Sprintf("round-trip p50/p95/p99 = %v/%v/%v, jitter %v\n",
+					formatDuration(stats.P50RTT),
+					formatDuration(stats.P95RTT),
+					formatDuration(stats.P99RTT),
+					formatDuration(stats.JitterRTT))))
+			}
 		}
 
 		if result.Context != nil {
@@ -74,6 +103,10 @@ func FormatPingText(result *types.PingResult, noColor bool) string {
 		}
 	}
 
+	if lossMap && len(result.Replies) > 0 {
+		sb.WriteString("loss map:\n" + LossMap(result.Replies, printer))
+	}
+
 	// 错误信息
 	if result.Error != nil {
 		sb.WriteString("\n" + red(fmt.Sprintf("Error: %v\n", result.Error)))
@@ -83,7 +116,7 @@ func FormatPingText(result *types.PingResult, noColor bool) string {
 }
 
 // FormatPingTable 格式化 Ping 结果为表格
-func FormatPingTable(result *types.PingResult, noColor bool) string {
+func FormatPingTable(result *types.PingResult, noColor, verbose bool) string {
 	var sb strings.Builder
 
 	// 设置颜色函数
@@ -127,6 +160,9 @@ func FormatPingTable(result *types.PingResult, noColor bool) string {
 		switch reply.Status {
 		case types.StatusSuccess:
 			statusStr = green("OK")
+			if reply.Duplicate {
+				statusStr = yellow("DUP!")
+			}
 		case types.StatusTimeout:
 			statusStr = red("TIMEOUT")
 		case types.StatusFailure:
@@ -154,6 +190,9 @@ func FormatPingTable(result *types.PingResult, noColor bool) string {
 		stats := result.Statistics
 		sb.WriteString(fmt.Sprintf("  Sent:     %d\n", stats.Sent))
 		sb.WriteString(fmt.Sprintf("  Received: %d\n", stats.Received))
+		if stats.Duplicates > 0 {
+			sb.WriteString(fmt.Sprintf("  Dups:     %d\n", stats.Duplicates))
+		}
 		sb.WriteString(fmt.Sprintf("  Loss:     %d (%.1f%%)\n", stats.Loss, stats.LossRate))
 
 		if stats.Received > 0 {
@@ -161,6 +200,12 @@ func FormatPingTable(result *types.PingResult, noColor bool) string {
 			sb.WriteString(fmt.Sprintf("  Avg RTT:  %v\n", formatDuration(stats.AvgRTT)))
 			sb.WriteString(fmt.Sprintf("  Max RTT:  %v\n", formatDuration(stats.MaxRTT)))
 			sb.WriteString(fmt.Sprintf("  Std Dev:  %v\n", formatDuration(stats.StdDevRTT)))
+			if verbose {
+				sb.WriteString(fmt.Sprintf("  P50 RTT:  %v\n", formatDuration(stats.P50RTT)))
+				sb.WriteString(fmt.Sprintf("  P95 RTT:  %v\n", formatDuration(stats.P95RTT)))
+				sb.WriteString(fmt.Sprintf("  P99 RTT:  %v\n", formatDuration(stats.P99RTT)))
+				sb.WriteString(fmt.Sprintf("  Jitter:   %v\n", formatDuration(stats.JitterRTT)))
+			}
 		}
 
 		if result.Context != nil {
@@ -171,6 +216,59 @@ func FormatPingTable(result *types.PingResult, noColor bool) string {
 	return sb.String()
 }
 
+// FormatHTTPTimings 格式化 HTTP 请求各阶段耗时
+func FormatHTTPTimings(t *types.HTTPTimings) string {
+	s := fmt.Sprintf("dns=%v connect=%v tls=%v ttfb=%v total=%v",
+		formatDuration(t.DNS),
+		formatDuration(t.Connect),
+		formatDuration(t.TLS),
+		formatDuration(t.TTFB),
+		formatDuration(t.Total))
+	if t.Reused {
+		s += " (reused)"
+	}
+	return s
+}
+
+// LossMap 按序列号顺序渲染丢包图，"." 表示收到响应，"X" 表示丢失，每行 lossMapWidth 个；
+// 重复响应不计入，乱序到达的响应按 Seq 归位
+func LossMap(replies []*types.PingReply, printer *termutil.ColorPrinter) string {
+	ok := make(map[int]bool)
+	for _, reply := range replies {
+		if reply.Duplicate {
+			continue
+		}
+		ok[reply.Seq] = ok[reply.Seq] || reply.Status == types.StatusSuccess
+	}
+	if len(ok) == 0 {
+		return ""
+	}
+
+	seqs := make([]int, 0, len(ok))
+	for seq := range ok {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+
+	var sb strings.Builder
+	for i, seq := range seqs {
+		if i > 0 {
+			if i%lossMapWidth == 0 {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(" ")
+			}
+		}
+		if ok[seq] {
+			sb.WriteString(".")
+		} else {
+			sb.WriteString(printer.Error("X"))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 // formatDuration 格式化时间间隔
 func formatDuration(d time.Duration) string {
 	if d == 0 {
@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPingTextLossMap(t *testing.T) {
+	result := &types.PingResult{
+		Target:   &types.Host{Hostname: "example.com", IP: "192.0.2.1"},
+		Protocol: types.ProtocolICMP,
+		Replies: []*types.PingReply{
+			{Seq: 1, Status: types.StatusSuccess},
+			{Seq: 3, Status: types.StatusTimeout},
+			{Seq: 2, Status: types.StatusSuccess}, // 乱序到达
+			{Seq: 2, Status: types.StatusSuccess, Duplicate: true},
+			{Seq: 5, Status: types.StatusFailure, Error: "network unreachable"},
+			{Seq: 4, Status: types.StatusSuccess},
+			{Seq: 6, Status: types.StatusTimeout},
+			{Seq: 7, Status: types.StatusSuccess},
+		},
+	}
+
+	out := FormatPingText(result, true, false, true)
+	require.True(t, strings.HasSuffix(out, "loss map:\n. . X . X X .\n"), out)
+
+	require.NotContains(t, FormatPingText(result, true, false, false), "loss map")
+}
+
+func TestLossMapWraps(t *testing.T) {
+	replies := make([]*types.PingReply, lossMapWidth+2)
+	for i := range replies {
+		replies[i] = &types.PingReply{Seq: i + 1, Status: types.StatusSuccess}
+	}
+	replies[lossMapWidth].Status = types.StatusTimeout
+
+	lines := strings.Split(strings.TrimSuffix(LossMap(replies, termutil.NewColorPrinter(true)), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, strings.TrimSpace(strings.Repeat(". ", lossMapWidth)), lines[0])
+	require.Equal(t, "X .", lines[1])
+}
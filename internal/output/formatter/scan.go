@@ -0,0 +1,119 @@
+// Package formatter 提供端口扫描结果格式化
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// ScanPortColumns 扫描端口表格可选列，--columns 按名称选择
+var ScanPortColumns = []Column[*types.ScanPort]{
+	{Name: "port", Header: "端口", Value: func(p *types.ScanPort, _ *termutil.ColorPrinter) string {
+		return fmt.Sprintf("%d", p.Port)
+	}},
+	{Name: "proto", Header: "协议", Value: func(p *types.ScanPort, _ *termutil.ColorPrinter) string {
+		return p.Proto
+	}},
+	{Name: "state", Header: "状态", Value: func(p *types.ScanPort, printer *termutil.ColorPrinter) string {
+		if p.State == types.PortOpen {
+			return printer.Success(p.State.String())
+		}
+		return p.State.String()
+	}},
+	{Name: "service", Header: "服务", Value: func(p *types.ScanPort, _ *termutil.ColorPrinter) string {
+		if p.Service == "" || p.Service == "unknown" {
+			return "-"
+		}
+		if p.Version != "" {
+			return p.Service + " " + p.Version
+		}
+		return p.Service
+	}},
+	{Name: "banner", Header: "Banner", Value: func(p *types.ScanPort, _ *termutil.ColorPrinter) string {
+		if p.Banner == "" {
+			return "-"
+		}
+		// Banner 可能包含换行，压缩为单行
+		return strings.Join(strings.Fields(p.Banner), " ")
+	}},
+	{Name: "rtt", Header: "响应时间", Value: func(p *types.ScanPort, _ *termutil.ColorPrinter) string {
+		return p.ResponseTime.Round(time.Millisecond).String()
+	}},
+}
+
+// DefaultScanPortColumns 返回扫描端口表格的默认列
+func DefaultScanPortColumns() []Column[*types.ScanPort] {
+	return pickColumns(ScanPortColumns, "port", "state", "service", "rtt")
+}
+
+// BannerScanPortColumns 返回启用 Banner 抓取时的默认列
+func BannerScanPortColumns() []Column[*types.ScanPort] {
+	return pickColumns(ScanPortColumns, "port", "state", "service", "banner", "rtt")
+}
+
+// FormatScanText 格式化扫描结果为文本：标题、开放端口表格与统计信息
+//
+// cols 为空时使用默认列，存在 Banner 时附加 Banner 列；limit > 0 时最多列出
+// limit 个开放端口，统计信息不受影响。
+func FormatScanText(result *types.ScanResult, cols []Column[*types.ScanPort], limit int, noColor bool) string {
+	var sb strings.Builder
+	printer := termutil.NewColorPrinter(noColor)
+
+	divider := strings.Repeat("=", 80)
+	sb.WriteString("\n" + divider + "\n")
+	if result.Hostname != "" && result.Hostname != result.Target {
+		fmt.Fprintf(&sb, "  扫描报告: %s (%s, %s)\n", result.Target, result.IP, result.Hostname)
+	} else {
+		fmt.Fprintf(&sb, "  扫描报告: %s (%s)\n", result.Target, result.IP)
+	}
+	sb.WriteString(divider + "\n\n")
+
+	open := make([]*types.ScanPort, 0)
+	hasBanner := false
+	for _, port := range result.Ports {
+		if port.State == types.PortOpen {
+			open = append(open, port)
+			hasBanner = hasBanner || port.Banner != ""
+		}
+	}
+	if len(cols) == 0 {
+		cols = DefaultScanPortColumns()
+		if hasBanner {
+			cols = BannerScanPortColumns()
+		}
+	}
+
+	if len(open) > 0 {
+		omitted := 0
+		if limit > 0 && len(open) > limit {
+			open, omitted = open[:limit], len(open)-limit
+		}
+		sb.WriteString(printer.Success("开放端口:") + "\n")
+		sb.WriteString(RenderColumns(open, cols, noColor))
+		sb.WriteString(OmittedNotice(omitted))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(printer.Warning("未发现开放端口") + "\n\n")
+	}
+
+	if summary := result.Summary; summary != nil {
+		sb.WriteString(">>> 统计信息\n")
+		fmt.Fprintf(&sb, "总端口数:   %d\n", summary.TotalPorts)
+		fmt.Fprintf(&sb, "开放端口:   %s\n", printer.Success(summary.OpenPorts))
+		fmt.Fprintf(&sb, "关闭端口:   %d\n", summary.ClosedPorts)
+		fmt.Fprintf(&sb, "过滤端口:   %d\n", summary.FilteredPorts)
+		if summary.OpenFilteredPorts > 0 {
+			fmt.Fprintf(&sb, "开放|过滤:  %d\n", summary.OpenFilteredPorts)
+		}
+		fmt.Fprintf(&sb, "扫描耗时:   %s\n", summary.Duration.Round(time.Millisecond))
+		fmt.Fprintf(&sb, "扫描方式:   %s (时序: %s)\n\n", result.Technique, result.Timing)
+	}
+
+	return sb.String()
+}
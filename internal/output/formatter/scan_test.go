@@ -0,0 +1,79 @@
+package formatter
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleScanResult() *types.ScanResult {
+	ip := net.ParseIP("192.0.2.10")
+	return &types.ScanResult{
+		Target:    "example.com",
+		Hostname:  "host.example.com",
+		IP:        ip,
+		Technique: "tcp-connect",
+		Timing:    types.ScanTimingNormal,
+		Ports: []*types.ScanPort{
+			{IP: ip, Port: 22, Proto: "tcp", State: types.PortOpen, Service: "ssh", Version: "OpenSSH 9.6", Banner: "SSH-2.0-OpenSSH_9.6", ResponseTime: 2 * time.Millisecond},
+			{IP: ip, Port: 23, Proto: "tcp", State: types.PortClosed},
+			{IP: ip, Port: 80, Proto: "tcp", State: types.PortOpen, Service: "http", ResponseTime: 3 * time.Millisecond},
+		},
+		Summary: &types.ScanSummary{TotalPorts: 3, OpenPorts: 2, ClosedPorts: 1, Duration: 1500 * time.Millisecond},
+	}
+}
+
+func TestFormatScanText(t *testing.T) {
+	out := FormatScanText(sampleScanResult(), nil, 0, true)
+	for _, want := range []string{
+		"扫描报告: example.com (192.0.2.10, host.example.com)",
+		"开放端口:",
+		"SSH-2.0-OpenSSH_9.6",
+		"ssh OpenSSH 9.6",
+		"总端口数:   3",
+		"开放端口:   2",
+		"关闭端口:   1",
+		"扫描耗时:   1.5s",
+		"扫描方式:   tcp-connect",
+	} {
+		require.Contains(t, out, want)
+	}
+	require.NotContains(t, out, "\x1b[")
+	// 关闭的端口不列入表格
+	require.NotContains(t, out, "23 ")
+}
+
+func TestFormatScanTextLimitAndColumns(t *testing.T) {
+	cols, err := SelectColumns(ScanPortColumns, "port,state")
+	require.NoError(t, err)
+
+	out := FormatScanText(sampleScanResult(), cols, 1, true)
+	require.Contains(t, out, "22")
+	require.NotContains(t, out, "SSH-2.0")
+	require.Contains(t, out, OmittedNotice(1))
+	// 统计信息不受 limit 影响
+	require.Contains(t, out, "开放端口:   2")
+}
+
+func TestFormatScanTextNoOpenPorts(t *testing.T) {
+	result := sampleScanResult()
+	result.Ports = result.Ports[1:2]
+	result.Summary = nil
+
+	out := FormatScanText(result, nil, 0, true)
+	require.Contains(t, out, "未发现开放端口")
+	require.NotContains(t, out, "统计信息")
+}
+
+func TestFormatterTextDispatchesScanResult(t *testing.T) {
+	out, err := NewFormatter(types.OutputText, true).Format(sampleScanResult())
+	require.NoError(t, err)
+	require.Contains(t, out, "扫描报告")
+
+	out, err = NewFormatter(types.OutputTable, true).Format(sampleScanResult())
+	require.NoError(t, err)
+	require.Contains(t, out, "扫描报告")
+}
@@ -5,8 +5,36 @@ import (
 	"io"
 	"os"
 	"strings"
+
+	"github.com/catsayer/ntx/pkg/termutil"
 )
 
+// Ellipsis 单元格被截断时的结尾标记
+const Ellipsis = "…"
+
+// wideTables 宽模式：不截断单元格，列宽随内容扩展（--wide，便于管道处理）
+var wideTables bool
+
+// SetWideTables 设置表格是否使用宽模式
+func SetWideTables(wide bool) {
+	wideTables = wide
+}
+
+// FitCell 按显示宽度将单元格填充到 width 列；超出时截断并以 "…" 结尾，
+// 宽模式下或 width <= 0 时不截断
+func FitCell(cell string, width int) string {
+	if width <= 0 {
+		return cell
+	}
+	if !wideTables {
+		cell = termutil.Truncate(cell, width, Ellipsis)
+	}
+	if pad := width - termutil.DisplayWidth(cell); pad > 0 {
+		cell += strings.Repeat(" ", pad)
+	}
+	return cell
+}
+
 // Table 提供简单的等宽表格渲染功能
 type Table struct {
 	headers   []string
@@ -14,7 +42,11 @@ type Table struct {
 	rows      [][]string
 	separator rune
 
-	format     string
+	// auto 为 true 时列宽在渲染时按内容计算
+	auto bool
+	// maxWidth 自动列宽的总宽度上限，0 表示使用终端宽度
+	maxWidth int
+
 	lineLength int
 }
 
@@ -27,6 +59,24 @@ func NewTable(headers []string, widths []int) *Table {
 	}).init()
 }
 
+// NewAutoTable 创建按内容自动计算列宽的表格渲染器
+//
+// 每列宽度取表头与单元格的最大显示宽度；总宽度超过终端宽度时，
+// 从最宽的列开始收窄（不窄于表头），超出部分以 "…" 截断。宽模式下不限制总宽度。
+func NewAutoTable(headers []string) *Table {
+	return &Table{
+		headers:   headers,
+		widths:    make([]int, len(headers)),
+		separator: '-',
+		auto:      true,
+	}
+}
+
+// SetMaxWidth 设置自动列宽的总宽度上限，n <= 0 时使用终端宽度
+func (t *Table) SetMaxWidth(n int) {
+	t.maxWidth = n
+}
+
 // SetSeparator 设置表格分隔符
 func (t *Table) SetSeparator(sep rune) {
 	t.separator = sep
@@ -52,6 +102,13 @@ func (t *Table) Render(w io.Writer) {
 		w = os.Stdout
 	}
 
+	switch {
+	case t.auto:
+		t.fitWidths()
+	case wideTables:
+		t.expandWidths()
+	}
+
 	if len(t.headers) > 0 {
 		fmt.Fprintln(w, t.separatorLine())
 		t.printRow(w, t.headers)
@@ -66,17 +123,65 @@ func (t *Table) Render(w io.Writer) {
 }
 
 func (t *Table) init() *Table {
-	t.format = buildFormat(t.widths)
 	t.lineLength = calcLineLength(t.widths)
 	return t
 }
 
+// expandWidths 宽模式下将固定宽度的列扩展到最宽单元格
+func (t *Table) expandWidths() {
+	for _, row := range append([][]string{t.headers}, t.rows...) {
+		for i, cell := range row {
+			if i < len(t.widths) && t.widths[i] > 0 {
+				t.widths[i] = max(t.widths[i], termutil.DisplayWidth(cell))
+			}
+		}
+	}
+	t.lineLength = calcLineLength(t.widths)
+}
+
+// fitWidths 按内容计算自动列宽，并收窄到总宽度上限以内
+func (t *Table) fitWidths() {
+	mins := make([]int, len(t.widths))
+	for i := range t.widths {
+		t.widths[i] = 1
+		if i < len(t.headers) {
+			t.widths[i] = max(1, termutil.DisplayWidth(t.headers[i]))
+		}
+		mins[i] = t.widths[i]
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			t.widths[i] = max(t.widths[i], termutil.DisplayWidth(cell))
+		}
+	}
+
+	limit := t.maxWidth
+	if limit <= 0 {
+		limit = termutil.Width()
+	}
+	for !wideTables && calcLineLength(t.widths) > limit {
+		widest := -1
+		for i, width := range t.widths {
+			if width > mins[i] && (widest < 0 || width > t.widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		t.widths[widest]--
+	}
+	t.lineLength = calcLineLength(t.widths)
+}
+
 func (t *Table) printRow(w io.Writer, row []string) {
-	args := make([]any, len(t.widths))
-	for i, col := range row {
-		args[i] = col
+	cells := make([]string, len(t.widths))
+	for i, width := range t.widths {
+		if i < len(row) {
+			cells[i] = FitCell(row[i], width)
+		}
 	}
-	fmt.Fprintf(w, t.format, args...)
+	fmt.Fprintln(w, strings.Join(cells, " "))
 }
 
 func (t *Table) separatorLine() string {
@@ -89,26 +194,6 @@ func (t *Table) separatorLine() string {
 	return strings.Repeat(string(t.separator), t.lineLength)
 }
 
-func buildFormat(widths []int) string {
-	if len(widths) == 0 {
-		return "\n"
-	}
-
-	var b strings.Builder
-	for i, width := range widths {
-		if i > 0 {
-			b.WriteByte(' ')
-		}
-		if width > 0 {
-			b.WriteString(fmt.Sprintf("%%-%ds", width))
-		} else {
-			b.WriteString("%s")
-		}
-	}
-	b.WriteByte('\n')
-	return b.String()
-}
-
 func calcLineLength(widths []int) int {
 	if len(widths) == 0 {
 		return 0
@@ -0,0 +1,78 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFitCellTruncatesWithEllipsis(t *testing.T) {
+	require.Equal(t, "tcp  ", FitCell("tcp", 5))
+	require.Equal(t, "2001:db8:…", FitCell("2001:db8::1234:5678", 10))
+
+	// ANSI 转义序列不计宽度，截断后追加重置序列
+	colored := "\x1b[32m2001:db8::1234:5678\x1b[0m"
+	fitted := FitCell(colored, 10)
+	require.Equal(t, 10, termutil.DisplayWidth(fitted))
+	require.True(t, strings.HasSuffix(fitted, Ellipsis+"\x1b[0m"))
+	require.Equal(t, 12, termutil.DisplayWidth(FitCell("\x1b[1m端口\x1b[22m", 12)))
+
+	SetWideTables(true)
+	defer SetWideTables(false)
+	require.Equal(t, "2001:db8::1234:5678", FitCell("2001:db8::1234:5678", 10))
+}
+
+func TestTableWideExpandsColumns(t *testing.T) {
+	render := func() []string {
+		table := NewTable([]string{"Addr", "State"}, []int{8, 5})
+		table.AddRow("2001:db8::1", "LISTEN")
+		var sb strings.Builder
+		table.Render(&sb)
+		return strings.Split(sb.String(), "\n")
+	}
+
+	lines := render()
+	require.Equal(t, "2001:db… LIST…", lines[3])
+
+	SetWideTables(true)
+	defer SetWideTables(false)
+	lines = render()
+	require.Equal(t, "2001:db8::1 LISTEN", lines[3])
+	require.Equal(t, len("2001:db8::1 LISTEN"), len(lines[0]))
+}
+
+func TestAutoTableSizesColumnsToContent(t *testing.T) {
+	render := func(maxWidth int, rows ...[]string) []string {
+		table := NewAutoTable([]string{"Host", "Service"})
+		table.SetMaxWidth(maxWidth)
+		for _, row := range rows {
+			table.AddRow(row...)
+		}
+		var sb strings.Builder
+		table.Render(&sb)
+		return strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+	}
+
+	short := render(80, []string{"a", "ssh"})
+	require.Equal(t, len("Host Service"), termutil.DisplayWidth(short[0]))
+	require.Equal(t, "a    ssh    ", short[3])
+
+	long := render(80, []string{"very-long-hostname.example.com", "elasticsearch"}, []string{"a", "ssh"})
+	require.Equal(t, len("very-long-hostname.example.com elasticsearch"), termutil.DisplayWidth(long[0]))
+	require.Equal(t, "very-long-hostname.example.com elasticsearch", long[3])
+	require.Greater(t, termutil.DisplayWidth(long[0]), termutil.DisplayWidth(short[0]))
+
+	// 超出上限时收窄最宽的列
+	capped := render(30, []string{"very-long-hostname.example.com", "elasticsearch"})
+	for _, line := range capped {
+		require.Equal(t, 30, termutil.DisplayWidth(line))
+	}
+	require.Equal(t, "very-long-hostn… elasticsearch", strings.TrimRight(capped[3], " "))
+
+	// 宽模式不受上限限制
+	SetWideTables(true)
+	defer SetWideTables(false)
+	require.Equal(t, long[3], render(30, []string{"very-long-hostname.example.com", "elasticsearch"})[3])
+}
@@ -25,14 +25,22 @@ func NewTextFormatter(colorEnabled bool) *TextFormatter {
 
 // PrintHeader 打印标题和分隔线
 func (f *TextFormatter) PrintHeader(title string) {
-	fmt.Println(f.divider("="))
-	fmt.Println(f.styledTitle(title, true))
-	fmt.Println(f.divider("="))
+	fmt.Print(f.Header(title))
 }
 
 // PrintSubHeader 打印子标题
 func (f *TextFormatter) PrintSubHeader(title string) {
-	fmt.Println(f.styledTitle(title, false))
+	fmt.Print(f.SubHeader(title))
+}
+
+// Header 返回标题和上下分隔线，以换行结尾
+func (f *TextFormatter) Header(title string) string {
+	return f.divider("=") + "\n" + f.styledTitle(title, true) + "\n" + f.divider("=") + "\n"
+}
+
+// SubHeader 返回子标题，以换行结尾
+func (f *TextFormatter) SubHeader(title string) string {
+	return f.styledTitle(title, false) + "\n"
 }
 
 func (f *TextFormatter) divider(char string) string {
@@ -0,0 +1,40 @@
+// Package formatter 提供 TLS 证书信息格式化
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// certDateLayout 证书有效期的显示格式
+const certDateLayout = "2006-01-02"
+
+// FormatCertValidity 格式化证书有效期与剩余天数，如 "2025-01-01 → 2025-04-01 (45 days left)"
+func FormatCertValidity(c *types.CertificateInfo) string {
+	return fmt.Sprintf("%s → %s (%s)",
+		c.NotBefore.Format(certDateLayout),
+		c.NotAfter.Format(certDateLayout),
+		FormatCertExpiry(c))
+}
+
+// FormatCertExpiry 格式化证书剩余有效期
+func FormatCertExpiry(c *types.CertificateInfo) string {
+	switch {
+	case c.DaysUntilExpiry < 0:
+		return fmt.Sprintf("expired %d days ago", -c.DaysUntilExpiry)
+	case c.DaysUntilExpiry == 1:
+		return "1 day left"
+	default:
+		return fmt.Sprintf("%d days left", c.DaysUntilExpiry)
+	}
+}
+
+// FormatCertSANs 合并证书 SAN 中的域名与 IP 地址
+func FormatCertSANs(c *types.CertificateInfo) string {
+	sans := append(append([]string(nil), c.DNSNames...), c.IPAddresses...)
+	return strings.Join(sans, ", ")
+}
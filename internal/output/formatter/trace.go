@@ -32,6 +32,8 @@ func FormatTraceText(result *types.TraceResult, noColor bool) string {
 		result.Protocol)))
 	sb.WriteString(strings.Repeat("-", types.TableWidthTraceText) + "\n")
 
+	showOrigin := traceHasOrigin(result.Hops)
+
 	// 跳信息
 	for _, hop := range result.Hops {
 		// TTL
@@ -40,15 +42,18 @@ func FormatTraceText(result *types.TraceResult, noColor bool) string {
 		// 主机名和 IP
 		if hop.IP != "" {
 			if hop.Hostname != "" && hop.Hostname != hop.IP {
-				sb.WriteString(fmt.Sprintf("%-40s (%s)", hop.Hostname, hop.IP))
+				sb.WriteString(FitCell(hop.Hostname, types.ColumnWidthTraceHost) + " (" + hop.IP + ")")
 			} else {
-				sb.WriteString(fmt.Sprintf("%-40s", hop.IP))
+				sb.WriteString(FitCell(hop.IP, types.ColumnWidthTraceHost))
 			}
 		} else {
 			sb.WriteString(gray("* * *"))
 			sb.WriteString("\n")
 			continue
 		}
+		if showOrigin {
+			sb.WriteString("  " + gray(FitCell(traceOriginLabel(hop), types.ColumnWidthTraceAS)))
+		}
 
 		// 探测结果
 		sb.WriteString("  ")
@@ -71,6 +76,9 @@ func FormatTraceText(result *types.TraceResult, noColor bool) string {
 		// 目标标记
 		if hop.IsDestination {
 			sb.WriteString(bold(green("  [DEST]")))
+			if state := tracePortState(hop); state != "" {
+				sb.WriteString(" " + portStateColor(printer, state))
+			}
 		}
 
 		sb.WriteString("\n")
@@ -116,28 +124,17 @@ func FormatTraceTable(result *types.TraceResult, noColor bool) string {
 	// 标题
 	sb.WriteString(bold(fmt.Sprintf("TRACEROUTE %s (%s)\n\n", result.Target.Hostname, result.Target.IP)))
 
-	// 表头
-	headerFormat := fmt.Sprintf("%%-%ds %%-%ds %%-%ds %%-%ds %%-%ds %%-%ds",
-		types.ColumnWidthTraceHop,
-		types.ColumnWidthTraceHost,
-		types.ColumnWidthTraceProbe,
-		types.ColumnWidthTraceProbe,
-		types.ColumnWidthTraceProbe,
-		types.ColumnWidthTraceAvg)
-	rowFormat := fmt.Sprintf("%%-%dd %%-%ds %%-%ds %%-%ds %%-%ds %%-%ds",
-		types.ColumnWidthTraceHop,
-		types.ColumnWidthTraceHost,
-		types.ColumnWidthTraceProbe,
-		types.ColumnWidthTraceProbe,
-		types.ColumnWidthTraceProbe,
-		types.ColumnWidthTraceAvg)
-	header := fmt.Sprintf(headerFormat,
-		"HOP",
-		"HOSTNAME (IP)",
-		"PROBE 1",
-		"PROBE 2",
-		"PROBE 3",
-		"AVG RTT")
+	// 表头，存在 AS 信息时在主机名后增加 AS / GEO 列
+	showOrigin := traceHasOrigin(result.Hops)
+	widths := []int{types.ColumnWidthTraceHop, types.ColumnWidthTraceHost}
+	headers := []string{"HOP", "HOSTNAME (IP)"}
+	if showOrigin {
+		widths = append(widths, types.ColumnWidthTraceAS)
+		headers = append(headers, "AS / GEO")
+	}
+	widths = append(widths, types.ColumnWidthTraceProbe, types.ColumnWidthTraceProbe, types.ColumnWidthTraceProbe, types.ColumnWidthTraceAvg)
+	headers = append(headers, "PROBE 1", "PROBE 2", "PROBE 3", "AVG RTT")
+	header := traceTableRow(widths, headers...)
 	sb.WriteString(bold(header) + "\n")
 	sb.WriteString(strings.Repeat("-", types.TableWidthTraceTable) + "\n")
 
@@ -148,6 +145,9 @@ func FormatTraceTable(result *types.TraceResult, noColor bool) string {
 			hostname = gray("*")
 		} else if hop.IsDestination {
 			hostname = green(hostname)
+			if state := tracePortState(hop); state != "" {
+				hostname += " " + portStateColor(printer, state)
+			}
 		}
 
 		// 格式化探测时间
@@ -177,13 +177,12 @@ func FormatTraceTable(result *types.TraceResult, noColor bool) string {
 			avgStr = cyan(formatDuration(avgRTT))
 		}
 
-		row := fmt.Sprintf(rowFormat,
-			hop.TTL,
-			hostname,
-			probeStrs[0],
-			probeStrs[1],
-			probeStrs[2],
-			avgStr)
+		cells := []string{fmt.Sprintf("%d", hop.TTL), hostname}
+		if showOrigin {
+			cells = append(cells, traceOriginLabel(hop))
+		}
+		cells = append(cells, probeStrs[0], probeStrs[1], probeStrs[2], avgStr)
+		row := traceTableRow(widths, cells...)
 
 		sb.WriteString(row + "\n")
 	}
@@ -203,3 +202,130 @@ func FormatTraceTable(result *types.TraceResult, noColor bool) string {
 
 	return sb.String()
 }
+
+// tracePortState 返回该跳首个带端口状态的探测结果（TCP 追踪的目标跳）
+func tracePortState(hop *types.TraceHop) string {
+	for _, probe := range hop.Probes {
+		if probe.PortState != "" {
+			return probe.PortState
+		}
+	}
+	return ""
+}
+
+// portStateColor 开放端口以绿色显示，其余以黄色显示
+func portStateColor(printer *termutil.ColorPrinter, state string) string {
+	if state == types.PortOpen.String() {
+		return printer.Success(state)
+	}
+	return printer.Warning(state)
+}
+
+// traceHasOrigin 判断是否有任一跳带有 AS 或国家信息
+func traceHasOrigin(hops []*types.TraceHop) bool {
+	for _, hop := range hops {
+		if hop.ASN != "" || hop.Country != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// traceOriginLabel 返回形如 "AS15169 US GOOGLE" 的 AS / 地理信息，无信息时返回 "-"
+func traceOriginLabel(hop *types.TraceHop) string {
+	var parts []string
+	if hop.ASN != "" {
+		parts = append(parts, "AS"+hop.ASN)
+	}
+	if hop.Country != "" {
+		parts = append(parts, hop.Country)
+	}
+	if hop.ASName != "" {
+		parts = append(parts, hop.ASName)
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// traceTableRow 按列宽拼接表格行，超出列宽的单元格被截断
+func traceTableRow(widths []int, cells ...string) string {
+	fitted := make([]string, len(cells))
+	for i, cell := range cells {
+		fitted[i] = FitCell(cell, widths[i])
+	}
+	return strings.Join(fitted, " ")
+}
+
+// FormatTraceAggregateText 格式化多轮 Traceroute 汇总结果为文本
+func FormatTraceAggregateText(agg *types.TraceAggregate, noColor bool) string {
+	var sb strings.Builder
+
+	printer := termutil.NewColorPrinter(noColor)
+	green := printer.Success
+	yellow := printer.Warning
+	cyan := printer.Info
+	bold := printer.Bold
+	gray := printer.Muted
+
+	hostname, ip := "", ""
+	if agg.Target != nil {
+		hostname, ip = agg.Target.Hostname, agg.Target.IP
+	}
+	sb.WriteString(bold(fmt.Sprintf("traceroute to %s (%s), %d runs, %s protocol\n",
+		hostname, ip, agg.Runs, agg.Protocol)))
+	sb.WriteString(strings.Repeat("-", types.TableWidthTraceText) + "\n")
+	sb.WriteString(bold(fmt.Sprintf("%-4s %-40s %6s %10s %10s %10s\n", "HOP", "ADDRESS", "SEEN", "MIN", "AVG", "MAX")))
+
+	for _, hop := range agg.Hops {
+		seen := fmt.Sprintf("%5.0f%%", hop.AppearanceRate)
+		if len(hop.Addresses) == 0 {
+			sb.WriteString(cyan(fmt.Sprintf("%2d   ", hop.TTL)))
+			sb.WriteString(gray(fmt.Sprintf("%-40s %6s", "*", seen)) + "\n")
+			continue
+		}
+
+		sb.WriteString(cyan(fmt.Sprintf("%2d   ", hop.TTL)))
+		sb.WriteString(fmt.Sprintf("%s %6s %10s %10s %10s",
+			FitCell(traceAddressLabel(hop.Addresses[0]), types.ColumnWidthTraceHost),
+			seen,
+			formatDuration(hop.MinRTT),
+			formatDuration(hop.AvgRTT),
+			formatDuration(hop.MaxRTT)))
+		if !hop.Stable {
+			sb.WriteString(yellow("  [multipath]"))
+		}
+		sb.WriteString("\n")
+		// 其余地址逐行列出，便于识别负载均衡路径
+		for _, addr := range hop.Addresses[1:] {
+			sb.WriteString(yellow(fmt.Sprintf("     %-40s", traceAddressLabel(addr))) + "\n")
+		}
+	}
+
+	sb.WriteString("\n" + strings.Repeat("-", types.TableWidthTraceText) + "\n")
+	if agg.Reached == agg.Runs {
+		sb.WriteString(green(fmt.Sprintf("Reached %s in %d/%d runs\n", hostname, agg.Reached, agg.Runs)))
+	} else {
+		sb.WriteString(yellow(fmt.Sprintf("Reached %s in %d/%d runs\n", hostname, agg.Reached, agg.Runs)))
+	}
+	if len(agg.Changes) == 0 {
+		sb.WriteString(green("Path stable across runs\n"))
+	} else {
+		sb.WriteString(yellow(fmt.Sprintf("Path changes (%d):\n", len(agg.Changes))))
+		for _, c := range agg.Changes {
+			sb.WriteString(fmt.Sprintf("  run %d, hop %d: %s -> %s\n", c.Run, c.TTL, c.From, c.To))
+		}
+	}
+
+	return sb.String()
+}
+
+// traceAddressLabel 返回 "主机名 (IP) xN" 形式的地址描述
+func traceAddressLabel(addr *types.TraceHopAddress) string {
+	label := addr.IP
+	if addr.Hostname != "" && addr.Hostname != addr.IP {
+		label = fmt.Sprintf("%s (%s)", addr.Hostname, addr.IP)
+	}
+	return fmt.Sprintf("%s x%d", label, addr.Responses)
+}
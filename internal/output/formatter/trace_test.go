@@ -0,0 +1,46 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTraceOriginColumn(t *testing.T) {
+	result := &types.TraceResult{
+		Target:   &types.Host{Hostname: "example.com", IP: "203.0.113.9"},
+		Protocol: types.ProtocolICMP,
+		Hops: []*types.TraceHop{
+			{TTL: 1, IP: "10.0.0.1", Hostname: "10.0.0.1",
+				Probes: []*types.TraceProbe{{Status: types.StatusSuccess}}},
+			{TTL: 2, IP: "203.0.113.9", Hostname: "203.0.113.9", IsDestination: true,
+				ASN: "64500", ASName: "EXAMPLE-NET", Country: "NL",
+				Probes: []*types.TraceProbe{{Status: types.StatusSuccess}}},
+		},
+	}
+
+	require.Contains(t, FormatTraceText(result, true), "AS64500 NL EXAMPLE-NET")
+	table := FormatTraceTable(result, true)
+	require.Contains(t, table, "AS / GEO")
+	require.Contains(t, table, "AS64500 NL EXAMPLE-NET")
+
+	// 没有任何 AS 信息时不显示该列
+	result.Hops[1].ASN, result.Hops[1].ASName, result.Hops[1].Country = "", "", ""
+	require.NotContains(t, FormatTraceTable(result, true), "AS / GEO")
+}
+
+func TestFormatTracePortState(t *testing.T) {
+	result := &types.TraceResult{
+		Target:   &types.Host{Hostname: "example.com", IP: "192.0.2.10"},
+		Protocol: types.ProtocolTCP,
+		Hops: []*types.TraceHop{
+			{TTL: 1, IP: "192.0.2.1", Hostname: "192.0.2.1",
+				Probes: []*types.TraceProbe{{Status: types.StatusSuccess}}},
+			{TTL: 2, IP: "192.0.2.10", Hostname: "192.0.2.10", IsDestination: true,
+				Probes: []*types.TraceProbe{{Status: types.StatusSuccess, PortState: "closed"}}},
+		},
+	}
+	require.Contains(t, FormatTraceText(result, true), "[DEST] closed")
+	require.Contains(t, FormatTraceTable(result, true), "192.0.2.10 closed")
+}
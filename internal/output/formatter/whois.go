@@ -0,0 +1,170 @@
+// Package formatter 提供 Whois 查询结果格式化
+//
+// 作者: Catsayer
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/termutil"
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// WhoisTextOptions Whois 文本输出选项
+type WhoisTextOptions struct {
+	// Raw 显示原始响应而非解析后的字段
+	Raw bool
+	// ExpiryWarnDays 到期预警天数，0 表示不预警
+	ExpiryWarnDays int
+	// Now 计算到期剩余天数的参考时间，零值表示当前时间
+	Now time.Time
+}
+
+// WhoisExpiry 返回到期剩余时间的描述，以及是否已过期或处于 warnDays 预警窗口内；
+// 到期日期无法解析时返回 "unknown"
+func WhoisExpiry(data *types.WhoisData, warnDays int, now time.Time) (string, bool) {
+	days, ok := data.DaysUntilExpiry(now)
+	switch {
+	case !ok:
+		return "unknown", false
+	case days < 0:
+		return fmt.Sprintf("已过期 %d 天", -days), warnDays > 0
+	}
+	return fmt.Sprintf("%d 天", days), warnDays > 0 && days <= warnDays
+}
+
+// FormatWhoisText 格式化单个 Whois 查询结果为文本
+func FormatWhoisText(result *types.WhoisResult, opts WhoisTextOptions, noColor bool) string {
+	var sb strings.Builder
+	printer := termutil.NewColorPrinter(noColor)
+	tf := NewTextFormatter(!noColor)
+
+	sb.WriteString(tf.Header(fmt.Sprintf("Whois 查询: %s", result.Query)))
+	fmt.Fprintf(&sb, "查询服务器: %s\n", result.Server)
+	if result.ReferralServer != "" {
+		fmt.Fprintf(&sb, "注册商服务器: %s\n", result.ReferralServer)
+	}
+	if result.Error != "" {
+		fmt.Fprintf(&sb, "查询失败:   %s\n", printer.Error(result.Error))
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "查询耗时:   %s\n\n", result.QueryTime)
+
+	if opts.Raw {
+		sb.WriteString(tf.SubHeader("原始响应"))
+		sb.WriteString(result.RawResponse + "\n")
+		if result.ReferralResponse != "" {
+			sb.WriteString(tf.SubHeader(fmt.Sprintf("原始响应 (%s)", result.ReferralServer)))
+			sb.WriteString(result.ReferralResponse + "\n")
+		}
+		return sb.String()
+	}
+
+	data := result.ParsedData
+	if data == nil {
+		sb.WriteString(printer.Warning("无法解析响应数据") + "\n")
+		return sb.String()
+	}
+
+	switch result.Type {
+	case types.WhoisDomain:
+		writeWhoisDomain(&sb, data, opts, tf, printer)
+	case types.WhoisIP:
+		writeWhoisIP(&sb, data, tf, printer)
+	case types.WhoisAS:
+		writeWhoisAS(&sb, data, tf, printer)
+	}
+	return sb.String()
+}
+
+// writeWhoisDomain 输出域名信息
+func writeWhoisDomain(sb *strings.Builder, data *types.WhoisData, opts WhoisTextOptions, tf *TextFormatter, printer *termutil.ColorPrinter) {
+	sb.WriteString(tf.SubHeader("域名信息"))
+	if data.Domain != "" {
+		fmt.Fprintf(sb, "域名:         %s\n", printer.Info(data.Domain))
+	}
+	if data.Registrar != "" {
+		fmt.Fprintf(sb, "注册商:       %s\n", data.Registrar)
+	}
+	if data.RegistrantOrg != "" {
+		fmt.Fprintf(sb, "注册组织:     %s\n", data.RegistrantOrg)
+	}
+	if data.RegistrantName != "" {
+		fmt.Fprintf(sb, "注册人:       %s\n", data.RegistrantName)
+	}
+	if !data.CreationDate.IsZero() {
+		fmt.Fprintf(sb, "创建日期:     %s\n", data.CreationDate.Format("2006-01-02"))
+	}
+	if !data.ExpirationDate.IsZero() {
+		fmt.Fprintf(sb, "过期日期:     %s\n", data.ExpirationDate.Format("2006-01-02"))
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	expiry, warn := WhoisExpiry(data, opts.ExpiryWarnDays, now)
+	switch {
+	case warn:
+		expiry = printer.Error(fmt.Sprintf("%s (预警阈值 %d 天)", expiry, opts.ExpiryWarnDays))
+	case !data.ExpirationDate.IsZero():
+		expiry = printer.Success(expiry)
+	default:
+		expiry = printer.Warning(expiry)
+	}
+	fmt.Fprintf(sb, "到期剩余:     %s\n", expiry)
+	if !data.UpdatedDate.IsZero() {
+		fmt.Fprintf(sb, "更新日期:     %s\n", data.UpdatedDate.Format("2006-01-02"))
+	}
+
+	if len(data.NameServers) > 0 {
+		sb.WriteString("\n" + tf.SubHeader("域名服务器"))
+		for _, ns := range data.NameServers {
+			fmt.Fprintf(sb, "  • %s\n", ns)
+		}
+	}
+
+	if len(data.Status) > 0 {
+		sb.WriteString("\n" + tf.SubHeader("域名状态"))
+		for _, status := range data.Status {
+			fmt.Fprintf(sb, "  • %s\n", status)
+		}
+	}
+}
+
+// writeWhoisIP 输出 IP 信息
+func writeWhoisIP(sb *strings.Builder, data *types.WhoisData, tf *TextFormatter, printer *termutil.ColorPrinter) {
+	sb.WriteString(tf.SubHeader("IP 信息"))
+	if data.IPRange != "" {
+		fmt.Fprintf(sb, "IP 范围:      %s\n", printer.Info(data.IPRange))
+	}
+	if data.Organization != "" {
+		fmt.Fprintf(sb, "组织:         %s\n", data.Organization)
+	}
+	if data.Country != "" {
+		fmt.Fprintf(sb, "国家:         %s\n", data.Country)
+	}
+	if data.City != "" {
+		fmt.Fprintf(sb, "城市:         %s\n", data.City)
+	}
+
+	if len(data.Address) > 0 {
+		sb.WriteString("\n" + tf.SubHeader("地址信息"))
+		for _, addr := range data.Address {
+			fmt.Fprintf(sb, "  %s\n", addr)
+		}
+	}
+}
+
+// writeWhoisAS 输出 AS 信息
+func writeWhoisAS(sb *strings.Builder, data *types.WhoisData, tf *TextFormatter, printer *termutil.ColorPrinter) {
+	sb.WriteString(tf.SubHeader("AS 信息"))
+	if data.ASName != "" {
+		fmt.Fprintf(sb, "AS 名称:      %s\n", printer.Info(data.ASName))
+	}
+	if data.Organization != "" {
+		fmt.Fprintf(sb, "组织:         %s\n", data.Organization)
+	}
+}
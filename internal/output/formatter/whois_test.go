@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhoisExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		expires  time.Time
+		warnDays int
+		label    string
+		warn     bool
+	}{
+		{"已过期", now.AddDate(0, 0, -3), 30, "已过期 3 天", true},
+		{"已过期但未启用预警", now.AddDate(0, 0, -3), 0, "已过期 3 天", false},
+		{"即将到期", now.AddDate(0, 0, 10), 30, "10 天", true},
+		{"恰好在阈值上", now.AddDate(0, 0, 30), 30, "30 天", true},
+		{"远期到期", now.AddDate(2, 0, 0), 30, "731 天", false},
+		{"到期日期未知", time.Time{}, 30, "unknown", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, warn := WhoisExpiry(&types.WhoisData{ExpirationDate: tt.expires}, tt.warnDays, now)
+			require.Equal(t, tt.label, label)
+			require.Equal(t, tt.warn, warn)
+		})
+	}
+}
+
+func TestFormatWhoisTextDomain(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	result := &types.WhoisResult{
+		Query:          "example.com",
+		Type:           types.WhoisDomain,
+		Server:         "whois.verisign-grs.com",
+		ReferralServer: "whois.iana.org",
+		QueryTime:      120 * time.Millisecond,
+		ParsedData: &types.WhoisData{
+			Domain:         "example.com",
+			Registrar:      "RESERVED-Internet Assigned Numbers Authority",
+			CreationDate:   time.Date(1995, 8, 14, 0, 0, 0, 0, time.UTC),
+			ExpirationDate: now.AddDate(0, 0, 10),
+			NameServers:    []string{"a.iana-servers.net", "b.iana-servers.net"},
+			Status:         []string{"clientDeleteProhibited"},
+		},
+	}
+
+	out := FormatWhoisText(result, WhoisTextOptions{ExpiryWarnDays: 30, Now: now}, true)
+	for _, want := range []string{
+		"Whois 查询: example.com",
+		"查询服务器: whois.verisign-grs.com",
+		"注册商服务器: whois.iana.org",
+		"域名:         example.com",
+		"创建日期:     1995-08-14",
+		"过期日期:     2026-03-11",
+		"到期剩余:     10 天 (预警阈值 30 天)",
+		"  • b.iana-servers.net",
+		"  • clientDeleteProhibited",
+	} {
+		require.Contains(t, out, want)
+	}
+	require.NotContains(t, out, "\x1b[")
+}
+
+func TestFormatWhoisTextIPAndAS(t *testing.T) {
+	ip := FormatWhoisText(&types.WhoisResult{
+		Query: "192.0.2.1",
+		Type:  types.WhoisIP,
+		ParsedData: &types.WhoisData{
+			IPRange:      "192.0.2.0 - 192.0.2.255",
+			Organization: "TEST-NET-1",
+			Country:      "US",
+			Address:      []string{"Los Angeles"},
+		},
+	}, WhoisTextOptions{}, true)
+	require.Contains(t, ip, "IP 范围:      192.0.2.0 - 192.0.2.255")
+	require.Contains(t, ip, "国家:         US")
+	require.Contains(t, ip, "  Los Angeles")
+
+	as := FormatWhoisText(&types.WhoisResult{
+		Query:      "AS15169",
+		Type:       types.WhoisAS,
+		ParsedData: &types.WhoisData{ASName: "GOOGLE", Organization: "Google LLC"},
+	}, WhoisTextOptions{}, true)
+	require.Contains(t, as, "AS 名称:      GOOGLE")
+	require.Contains(t, as, "组织:         Google LLC")
+}
+
+func TestFormatWhoisTextRawAndError(t *testing.T) {
+	raw := FormatWhoisText(&types.WhoisResult{
+		Query:       "example.com",
+		RawResponse: "Domain Name: EXAMPLE.COM",
+		ParsedData:  &types.WhoisData{Domain: "example.com"},
+	}, WhoisTextOptions{Raw: true}, true)
+	require.Contains(t, raw, "原始响应")
+	require.Contains(t, raw, "Domain Name: EXAMPLE.COM")
+	require.NotContains(t, raw, "域名信息")
+
+	failed := FormatWhoisText(&types.WhoisResult{Query: "bad.example", Error: "connection refused"}, WhoisTextOptions{}, true)
+	require.Contains(t, failed, "查询失败:   connection refused")
+	require.NotContains(t, failed, "查询耗时")
+}
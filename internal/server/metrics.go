@@ -0,0 +1,163 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// metric 一个指标族及其样本
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+type sample struct {
+	labels [][2]string
+	value  float64
+}
+
+// metricSet 按首次出现顺序收集指标族
+type metricSet struct {
+	order []string
+	byKey map[string]*metric
+}
+
+func newMetricSet() *metricSet {
+	return &metricSet{byKey: make(map[string]*metric)}
+}
+
+func (m *metricSet) add(name, kind, help string, value float64, labels ...[2]string) {
+	fam, ok := m.byKey[name]
+	if !ok {
+		fam = &metric{name: name, help: help, kind: kind}
+		m.byKey[name] = fam
+		m.order = append(m.order, name)
+	}
+	fam.samples = append(fam.samples, sample{labels: labels, value: value})
+}
+
+// writeMetrics 以 Prometheus 文本格式输出任务状态
+func writeMetrics(w io.Writer, tasks []TaskStatus) {
+	m := newMetricSet()
+	for _, st := range tasks {
+		task := [2]string{"task", st.Name}
+		kind := [2]string{"type", st.Type}
+
+		up := 0.0
+		if st.Success {
+			up = 1
+		}
+		m.add("ntx_task_up", "gauge", "最近一次执行是否成功 (1 成功, 0 失败或尚未执行)", up, task, kind)
+		m.add("ntx_task_runs_total", "counter", "服务启动以来的执行次数", float64(st.Runs), task, kind)
+		m.add("ntx_task_failures_total", "counter", "服务启动以来的失败次数", float64(st.Failures), task, kind)
+		m.add("ntx_task_duration_seconds", "gauge", "最近一次执行耗时", st.Duration.Seconds(), task, kind)
+		if !st.LastRun.IsZero() {
+			m.add("ntx_task_last_run_timestamp_seconds", "gauge", "最近一次执行开始时间", float64(st.LastRun.UnixNano())/1e9, task, kind)
+		}
+
+		for _, r := range st.Results {
+			switch v := r.(type) {
+			case *types.PingResult:
+				addPingMetrics(m, task, v)
+			case *types.DNSResult:
+				target := [2]string{"target", v.Domain}
+				m.add("ntx_dns_query_seconds", "gauge", "DNS 查询耗时", v.QueryTime.Seconds(), task, target)
+				m.add("ntx_dns_records", "gauge", "DNS 应答记录数", float64(len(v.Records)), task, target)
+			case *types.ScanResult:
+				if v.Summary == nil {
+					continue
+				}
+				target := [2]string{"target", v.Target}
+				m.add("ntx_scan_open_ports", "gauge", "开放端口数", float64(v.Summary.OpenPorts), task, target)
+				m.add("ntx_scan_duration_seconds", "gauge", "扫描耗时", v.Summary.Duration.Seconds(), task, target)
+			case *types.HTTPResult:
+				addHTTPMetrics(m, task, v)
+			case *types.TraceResult:
+				addTraceMetrics(m, task, v)
+			}
+		}
+	}
+
+	for _, name := range m.order {
+		fam := m.byKey[name]
+		fmt.Fprintf(w, "# HELP %s %s\n", fam.name, fam.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", fam.name, fam.kind)
+		for _, s := range fam.samples {
+			fmt.Fprintf(w, "%s%s %s\n", fam.name, formatLabels(s.labels), strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+}
+
+// addPingMetrics 输出单个 Ping 目标的延迟与丢包指标
+func addPingMetrics(m *metricSet, task [2]string, r *types.PingResult) {
+	if r.Target == nil || r.Statistics == nil {
+		return
+	}
+	target := [2]string{"target", r.Target.Hostname}
+	if target[1] == "" {
+		target[1] = r.Target.IP
+	}
+	stats := r.Statistics
+	m.add("ntx_ping_sent", "gauge", "最近一次执行发送的探测数", float64(stats.Sent), task, target)
+	m.add("ntx_ping_received", "gauge", "最近一次执行收到的响应数", float64(stats.Received), task, target)
+	m.add("ntx_ping_loss_ratio", "gauge", "丢包率 (0-1)", stats.LossRate/100, task, target)
+	if stats.Received > 0 {
+		m.add("ntx_ping_rtt_min_seconds", "gauge", "最小往返时间", stats.MinRTT.Seconds(), task, target)
+		m.add("ntx_ping_rtt_avg_seconds", "gauge", "平均往返时间", stats.AvgRTT.Seconds(), task, target)
+		m.add("ntx_ping_rtt_max_seconds", "gauge", "最大往返时间", stats.MaxRTT.Seconds(), task, target)
+	}
+}
+
+// addHTTPMetrics 输出单个 HTTP 请求的状态码与耗时指标
+func addHTTPMetrics(m *metricSet, task [2]string, r *types.HTTPResult) {
+	target := [2]string{"target", r.URL}
+	m.add("ntx_http_status_code", "gauge", "最近一次请求的 HTTP 状态码", float64(r.StatusCode), task, target)
+	m.add("ntx_http_duration_seconds", "gauge", "最近一次请求总耗时", r.Duration.Seconds(), task, target)
+	if r.Timings != nil && r.Timings.TTFB > 0 {
+		m.add("ntx_http_ttfb_seconds", "gauge", "最近一次请求的首字节时间", r.Timings.TTFB.Seconds(), task, target)
+	}
+}
+
+// addTraceMetrics 输出单个 Traceroute 目标的跳数与是否到达
+func addTraceMetrics(m *metricSet, task [2]string, r *types.TraceResult) {
+	if r.Target == nil {
+		return
+	}
+	target := [2]string{"target", r.Target.Hostname}
+	if target[1] == "" {
+		target[1] = r.Target.IP
+	}
+	reached := 0.0
+	if r.ReachedDestination {
+		reached = 1
+	}
+	m.add("ntx_trace_hops", "gauge", "最近一次追踪的跳数", float64(r.HopCount), task, target)
+	m.add("ntx_trace_reached", "gauge", "最近一次追踪是否到达目标 (1 到达, 0 未到达)", reached, task, target)
+}
+
+// formatLabels 格式化标签集合，按标签名排序并转义取值
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	sorted := append([][2]string(nil), labels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+
+	parts := make([]string, len(sorted))
+	for i, l := range sorted {
+		parts[i] = fmt.Sprintf("%s=\"%s\"", l[0], escapeLabel(l[1]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// escapeLabel 按 Prometheus 文本格式转义标签值
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
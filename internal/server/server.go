@@ -0,0 +1,251 @@
+// Package server 提供常驻监控服务
+//
+// 按各任务的 schedule 周期执行批量任务，并通过 HTTP 暴露最近一次结果:
+//   - /results: JSON 格式的任务结果
+//   - /metrics: Prometheus 文本格式指标
+//   - /healthz: 健康检查
+//
+// 作者: Catsayer
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/batch"
+	"github.com/catsayer/ntx/internal/logger"
+	"go.uber.org/zap"
+)
+
+// TaskRunner 执行单个任务，*batch.Executor 实现了该接口
+type TaskRunner interface {
+	ExecuteTask(ctx context.Context, task batch.Task) *batch.TaskResult
+}
+
+// TaskStatus 任务的最近一次执行结果与累计计数
+type TaskStatus struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Interval time.Duration `json:"interval"`
+	// Runs/Failures 服务启动以来的执行与失败次数
+	Runs     int           `json:"runs"`
+	Failures int           `json:"failures"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+	LastRun  time.Time     `json:"last_run,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Results  []interface{} `json:"results"`
+}
+
+// scheduledTask 已解析执行间隔的任务
+type scheduledTask struct {
+	task     batch.Task
+	interval time.Duration
+}
+
+// Server 周期执行任务并通过 HTTP 暴露结果
+type Server struct {
+	runner  TaskRunner
+	tasks   []scheduledTask
+	started time.Time
+
+	mu     sync.RWMutex
+	status map[string]*TaskStatus
+}
+
+// New 创建 Server，仅调度已启用的任务；未配置 schedule 的任务使用 defaultInterval
+func New(runner TaskRunner, tasks []batch.Task, defaultInterval time.Duration) (*Server, error) {
+	if defaultInterval <= 0 {
+		return nil, fmt.Errorf("无效的默认间隔: %s", defaultInterval)
+	}
+
+	s := &Server{
+		runner:  runner,
+		started: time.Now(),
+		status:  make(map[string]*TaskStatus),
+	}
+	for _, task := range tasks {
+		if !task.Enabled {
+			continue
+		}
+		if _, dup := s.status[task.Name]; dup {
+			return nil, fmt.Errorf("任务名称重复: %s", task.Name)
+		}
+		interval, err := ParseSchedule(task.Schedule, defaultInterval)
+		if err != nil {
+			return nil, fmt.Errorf("任务 %s: %w", task.Name, err)
+		}
+		s.tasks = append(s.tasks, scheduledTask{task: task, interval: interval})
+		s.status[task.Name] = &TaskStatus{
+			Name:     task.Name,
+			Type:     string(task.Type),
+			Interval: interval,
+			Results:  []interface{}{},
+		}
+	}
+	if len(s.tasks) == 0 {
+		return nil, fmt.Errorf("没有已启用的任务")
+	}
+	return s, nil
+}
+
+// ParseSchedule 解析任务的 schedule 字段，支持 "30s" 与 "@every 30s" 两种写法，空值返回 def
+func ParseSchedule(schedule string, def time.Duration) (time.Duration, error) {
+	schedule = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(schedule), "@every"))
+	if schedule == "" {
+		return def, nil
+	}
+	interval, err := time.ParseDuration(schedule)
+	if err != nil {
+		return 0, fmt.Errorf("无效的 schedule %q: 需要时间间隔 (如 30s、5m)", schedule)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("无效的 schedule %q: 间隔必须大于 0", schedule)
+	}
+	return interval, nil
+}
+
+// Run 启动全部任务的调度，阻塞直到 ctx 取消；每个任务启动时立即执行一次，
+// 执行耗时超过间隔时下一轮顺延
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, st := range s.tasks {
+		wg.Add(1)
+		go func(st scheduledTask) {
+			defer wg.Done()
+			ticker := time.NewTicker(st.interval)
+			defer ticker.Stop()
+			for {
+				s.runOnce(ctx, st.task)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(st)
+	}
+	wg.Wait()
+}
+
+// runOnce 执行一次任务并记录结果
+func (s *Server) runOnce(ctx context.Context, task batch.Task) {
+	result := s.runner.ExecuteTask(ctx, task)
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.status[task.Name]
+	st.Runs++
+	st.Success = result.Success
+	st.Error = ""
+	if result.Error != nil {
+		st.Error = result.Error.Error()
+	}
+	if !result.Success {
+		st.Failures++
+		logger.Warn("监控任务失败", zap.String("task", task.Name), zap.String("error", st.Error))
+	}
+	st.LastRun = result.StartTime
+	st.Duration = result.Duration
+	st.Results = result.Results
+}
+
+// Snapshot 返回按配置顺序排列的任务状态副本
+func (s *Server) Snapshot() []TaskStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]TaskStatus, 0, len(s.tasks))
+	for _, st := range s.tasks {
+		out = append(out, *s.status[st.task.Name])
+	}
+	return out
+}
+
+// Handler 返回暴露结果的 HTTP 处理器
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/results", s.handleResults)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *Server) handleResults(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]interface{}{
+		"started": s.started,
+		"tasks":   s.Snapshot(),
+	}); err != nil {
+		logger.Warn("写入结果失败", zap.Error(err))
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, s.Snapshot())
+}
+
+// ListenAndServe 在 addr 上启动 HTTP 服务并调度任务，直到 ctx 取消或监听失败
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %w", addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve 在已有的监听器上提供 HTTP 服务并调度任务
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	logger.Info("监控服务已启动", zap.String("addr", listener.Addr().String()), zap.Int("tasks", len(s.tasks)))
+
+	var err error
+	select {
+	case <-ctx.Done():
+		shutdownCtx, stop := context.WithTimeout(context.Background(), 5*time.Second)
+		defer stop()
+		err = httpServer.Shutdown(shutdownCtx)
+	case err = <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+	}
+	cancel()
+	<-done
+	return err
+}
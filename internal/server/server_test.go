@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/catsayer/ntx/internal/core/batch"
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunner 返回固定结果并统计执行次数
+type fakeRunner struct {
+	calls atomic.Int32
+}
+
+func (f *fakeRunner) ExecuteTask(_ context.Context, task batch.Task) *batch.TaskResult {
+	f.calls.Add(1)
+	result := &batch.TaskResult{TaskName: task.Name, TaskType: task.Type, StartTime: time.Now(), Duration: 5 * time.Millisecond}
+	if task.Name == "broken" {
+		result.Error = errors.New("boom")
+		return result
+	}
+	result.Success = true
+	result.Results = []interface{}{&types.PingResult{
+		Target: &types.Host{Hostname: "example.com", IP: "192.0.2.1"},
+		Statistics: &types.Statistics{
+			Sent: 4, Received: 3, LossRate: 25,
+			MinRTT: time.Millisecond, AvgRTT: 2 * time.Millisecond, MaxRTT: 3 * time.Millisecond,
+		},
+	}}
+	return result
+}
+
+func TestParseSchedule(t *testing.T) {
+	d, err := ParseSchedule("", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, d)
+
+	d, err = ParseSchedule("@every 30s", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, d)
+
+	_, err = ParseSchedule("*/5 * * * *", time.Minute)
+	require.Error(t, err)
+	_, err = ParseSchedule("-1s", time.Minute)
+	require.Error(t, err)
+}
+
+func TestNewRejectsInvalidTasks(t *testing.T) {
+	_, err := New(&fakeRunner{}, []batch.Task{{Name: "a", Enabled: false}}, time.Minute)
+	require.Error(t, err)
+
+	_, err = New(&fakeRunner{}, []batch.Task{{Name: "a", Enabled: true}, {Name: "a", Enabled: true}}, time.Minute)
+	require.ErrorContains(t, err, "重复")
+}
+
+func TestServeEndpoints(t *testing.T) {
+	runner := &fakeRunner{}
+	srv, err := New(runner, []batch.Task{
+		{Name: "web", Type: batch.TaskTypePing, Enabled: true, Schedule: "1h"},
+		{Name: "broken", Type: batch.TaskTypeDNS, Enabled: true},
+		{Name: "off", Type: batch.TaskTypePing},
+	}, time.Hour)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, listener) }()
+
+	base := "http://" + listener.Addr().String()
+	get := func(path string) string {
+		resp, err := http.Get(base + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	require.Eventually(t, func() bool { return runner.calls.Load() == 2 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return srv.Snapshot()[1].Runs == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, "ok\n", get("/healthz"))
+
+	var results struct {
+		Tasks []TaskStatus `json:"tasks"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(get("/results")), &results))
+	require.Len(t, results.Tasks, 2)
+	require.Equal(t, "web", results.Tasks[0].Name)
+	require.True(t, results.Tasks[0].Success)
+	require.Equal(t, "boom", results.Tasks[1].Error)
+
+	metrics := get("/metrics")
+	require.Contains(t, metrics, "# TYPE ntx_task_up gauge\n")
+	require.Contains(t, metrics, `ntx_task_up{task="web",type="ping"} 1`)
+	require.Contains(t, metrics, `ntx_task_failures_total{task="broken",type="dns"} 1`)
+	require.Contains(t, metrics, `ntx_ping_loss_ratio{target="example.com",task="web"} 0.25`)
+	require.Contains(t, metrics, `ntx_ping_rtt_avg_seconds{target="example.com",task="web"} 0.002`)
+	require.Equal(t, 1, strings.Count(metrics, "# TYPE ntx_ping_sent "))
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWriteMetricsHTTPAndTrace(t *testing.T) {
+	var buf strings.Builder
+	writeMetrics(&buf, []TaskStatus{
+		{Name: "site", Type: "http", Success: true, Results: []interface{}{&types.HTTPResult{
+			URL: "https://example.com/health", StatusCode: 503, Duration: 120 * time.Millisecond,
+			Timings: &types.HTTPTimings{TTFB: 80 * time.Millisecond, Total: 120 * time.Millisecond},
+		}}},
+		{Name: "path", Type: "trace", Success: true, Results: []interface{}{&types.TraceResult{
+			Target: &types.Host{Hostname: "example.com", IP: "192.0.2.1"}, HopCount: 7, ReachedDestination: true,
+		}}},
+	})
+	metrics := buf.String()
+
+	require.Contains(t, metrics, `ntx_http_status_code{target="https://example.com/health",task="site"} 503`)
+	require.Contains(t, metrics, `ntx_http_duration_seconds{target="https://example.com/health",task="site"} 0.12`)
+	require.Contains(t, metrics, `ntx_http_ttfb_seconds{target="https://example.com/health",task="site"} 0.08`)
+	require.Contains(t, metrics, `ntx_trace_hops{target="example.com",task="path"} 7`)
+	require.Contains(t, metrics, `ntx_trace_reached{target="example.com",task="path"} 1`)
+}
+
+func TestEscapeLabel(t *testing.T) {
+	require.Equal(t, `a\"b\\c\n`, escapeLabel("a\"b\\c\n"))
+}
@@ -9,9 +9,7 @@ import (
 	"github.com/catsayer/ntx/pkg/types"
 )
 
-const defaultDNSTTL = 5 * time.Minute
-
-var defaultDNSCache = newDNSCache(defaultDNSTTL)
+var defaultDNSCache = newDNSCache(types.DefaultDNSCacheTTL)
 
 type cacheEntry struct {
 	host      *types.Host
@@ -31,15 +29,34 @@ func newDNSCache(ttl time.Duration) *dnsCache {
 	}
 }
 
+// SetDNSCacheTTL 设置进程内正向与反向解析缓存的有效期，ttl <= 0 时禁用缓存并清空已有条目
+func SetDNSCacheTTL(ttl time.Duration) {
+	defaultDNSCache.setTTL(ttl)
+	defaultReverseResolver.setTTL(ttl)
+}
+
+func (c *dnsCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	c.ttl = ttl
+	if ttl <= 0 {
+		c.entries = make(map[string]cacheEntry)
+	}
+	c.mu.Unlock()
+}
+
 func (c *dnsCache) get(key string) (*types.Host, bool) {
-	if c == nil || c.ttl <= 0 {
+	if c == nil {
 		return nil, false
 	}
 
 	now := time.Now()
 	c.mu.RLock()
+	ttl := c.ttl
 	entry, ok := c.entries[key]
 	c.mu.RUnlock()
+	if ttl <= 0 {
+		return nil, false
+	}
 	if !ok {
 		return nil, false
 	}
@@ -56,16 +73,19 @@ func (c *dnsCache) get(key string) (*types.Host, bool) {
 }
 
 func (c *dnsCache) set(key string, host *types.Host) {
-	if c == nil || c.ttl <= 0 {
+	if c == nil {
 		return
 	}
 
 	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return
+	}
 	c.entries[key] = cacheEntry{
 		host:      cloneHost(host),
 		expiresAt: time.Now().Add(c.ttl),
 	}
-	c.mu.Unlock()
 }
 
 func cloneHost(h *types.Host) *types.Host {
@@ -31,3 +31,17 @@ func TestDNSCacheStoresAndClonesHost(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, "1.1.1.1", cachedAgain.IP)
 }
+
+func TestDNSCacheDisabledByZeroTTL(t *testing.T) {
+	cache := newDNSCache(5 * time.Minute)
+	key := cacheKey("example.com", types.IPvAny)
+	cache.set(key, &types.Host{Hostname: "example.com", IP: "1.1.1.1"})
+
+	cache.setTTL(0)
+	_, ok := cache.get(key)
+	require.False(t, ok)
+
+	cache.set(key, &types.Host{Hostname: "example.com", IP: "1.1.1.1"})
+	_, ok = cache.get(key)
+	require.False(t, ok)
+}
@@ -0,0 +1,74 @@
+package netutil
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// PhaseTimer 通过 httptrace 记录一次 HTTP 请求各阶段的起止时间
+type PhaseTimer struct {
+	// 并发建连（Happy Eyeballs）时回调可能来自不同协程
+	mu        sync.Mutex
+	dnsStart  time.Time
+	dnsDone   time.Time
+	connStart time.Time
+	connDone  time.Time
+	tlsStart  time.Time
+	tlsDone   time.Time
+	firstByte time.Time
+	reused    bool
+}
+
+// Trace 返回记录时间点的 ClientTrace；多次建连时取首个开始与最后一个完成
+func (t *PhaseTimer) Trace() *httptrace.ClientTrace {
+	mark := func(field *time.Time, overwrite bool) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if overwrite || field.IsZero() {
+			*field = time.Now()
+		}
+	}
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&t.dnsStart, false) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { mark(&t.dnsDone, true) },
+		ConnectStart:         func(string, string) { mark(&t.connStart, false) },
+		ConnectDone:          func(string, string, error) { mark(&t.connDone, true) },
+		TLSHandshakeStart:    func() { mark(&t.tlsStart, false) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { mark(&t.tlsDone, true) },
+		GotFirstResponseByte: func() { mark(&t.firstByte, false) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+	}
+}
+
+// Timings 汇总各阶段耗时，start 为发起请求的时间，end 为读完响应体的时间
+func (t *PhaseTimer) Timings(start, end time.Time) *types.HTTPTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timings := &types.HTTPTimings{
+		DNS:     span(t.dnsStart, t.dnsDone),
+		Connect: span(t.connStart, t.connDone),
+		TLS:     span(t.tlsStart, t.tlsDone),
+		Total:   end.Sub(start),
+		Reused:  t.reused,
+	}
+	if !t.firstByte.IsZero() {
+		timings.TTFB = t.firstByte.Sub(start)
+	}
+	return timings
+}
+
+// span 返回两个时间点之间的间隔，任一未记录时返回 0
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() {
+		return 0
+	}
+	return to.Sub(from)
+}
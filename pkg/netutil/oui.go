@@ -0,0 +1,168 @@
+package netutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LocallyAdministered 本地管理（随机化）MAC 地址的厂商描述
+const LocallyAdministered = "Locally Administered"
+
+// ouiVendors 常见厂商 OUI 前缀（大写十六进制，不含分隔符）
+var ouiVendors = map[string]string{
+	"00000C": "Cisco",
+	"000393": "Apple",
+	"0003FF": "Microsoft",
+	"00044B": "NVIDIA",
+	"000569": "VMware",
+	"000585": "Juniper Networks",
+	"00089B": "QNAP Systems",
+	"00090F": "Fortinet",
+	"000C29": "VMware",
+	"000DB9": "PC Engines",
+	"001132": "Synology",
+	"00155D": "Microsoft Hyper-V",
+	"00163E": "Xensource",
+	"0017F2": "Apple",
+	"00180A": "Cisco Meraki",
+	"001A11": "Google",
+	"001B17": "Palo Alto Networks",
+	"001B21": "Intel",
+	"001B63": "Apple",
+	"001C42": "Parallels",
+	"002590": "Super Micro Computer",
+	"0026BB": "Apple",
+	"002722": "Ubiquiti Networks",
+	"005056": "VMware",
+	"009027": "Intel",
+	"00E04C": "Realtek",
+	"00E0FC": "Huawei",
+	"080027": "Oracle VirtualBox",
+	"0242AC": "Docker",
+	"18B430": "Nest Labs",
+	"24A43C": "Ubiquiti Networks",
+	"3C5AB4": "Google",
+	"44650D": "Amazon",
+	"50C7BF": "TP-Link",
+	"525400": "QEMU/KVM",
+	"B827EB": "Raspberry Pi Foundation",
+	"DCA632": "Raspberry Pi Trading",
+	"E45F01": "Raspberry Pi Trading",
+	"F4F5D8": "Google",
+	"FCA183": "Amazon",
+}
+
+var (
+	customVendorsMu sync.RWMutex
+	// customVendors 从外部文件加载的 OUI 前缀，优先于内置表
+	customVendors map[string]string
+)
+
+// LoadVendorFile 加载 OUI 厂商文件，加载的条目优先于内置表
+//
+// 支持 IEEE oui.txt 格式（"00-00-0C   (hex)		Cisco Systems, Inc"）
+// 以及每行 "前缀 厂商" 的简单格式，前缀可使用 ':'、'-' 分隔或不分隔，'#' 开头为注释。
+func LoadVendorFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开 OUI 文件失败: %w", err)
+	}
+	defer file.Close()
+
+	vendors, err := parseVendorFile(file)
+	if err != nil {
+		return fmt.Errorf("解析 OUI 文件 %s 失败: %w", path, err)
+	}
+
+	customVendorsMu.Lock()
+	customVendors = vendors
+	customVendorsMu.Unlock()
+	return nil
+}
+
+// HasCustomVendors 报告是否已通过 LoadVendorFile 加载外部厂商表
+func HasCustomVendors() bool {
+	customVendorsMu.RLock()
+	defer customVendorsMu.RUnlock()
+	return len(customVendors) > 0
+}
+
+func parseVendorFile(r io.Reader) (map[string]string, error) {
+	vendors := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		prefix := normalizeOUI(fields[0])
+		if prefix == "" {
+			continue
+		}
+
+		rest := fields[1:]
+		if rest[0] == "(hex)" || rest[0] == "(base" {
+			// IEEE 格式: 仅取 (hex) 行，(base 16) 行与其重复
+			if rest[0] != "(hex)" {
+				continue
+			}
+			rest = rest[1:]
+		}
+		if vendor := strings.Join(rest, " "); vendor != "" {
+			vendors[prefix] = vendor
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vendors, nil
+}
+
+// normalizeOUI 将 OUI 前缀统一为 6 位大写十六进制，非法时返回空字符串
+func normalizeOUI(s string) string {
+	s = strings.ToUpper(strings.NewReplacer(":", "", "-", "", ".", "").Replace(s))
+	if len(s) != 6 {
+		return ""
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789ABCDEF", c) {
+			return ""
+		}
+	}
+	return s
+}
+
+// LookupVendor 根据 MAC 地址的 OUI 前缀返回厂商名称，未知时返回空字符串
+func LookupVendor(mac string) string {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil || len(hw) < 3 {
+		return ""
+	}
+
+	prefix := strings.ToUpper(strings.ReplaceAll(hw[:3].String(), ":", ""))
+	customVendorsMu.RLock()
+	vendor, ok := customVendors[prefix]
+	customVendorsMu.RUnlock()
+	if ok {
+		return vendor
+	}
+	if vendor, ok := ouiVendors[prefix]; ok {
+		return vendor
+	}
+
+	// 第一个字节的次低位为 1 表示本地管理地址（虚拟网卡、隐私随机 MAC 等）
+	if hw[0]&0x02 != 0 {
+		return LocallyAdministered
+	}
+	return ""
+}
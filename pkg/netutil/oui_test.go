@@ -0,0 +1,32 @@
+package netutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupVendor(t *testing.T) {
+	require.Equal(t, "VMware", LookupVendor("00:50:56:c0:00:08"))
+	require.Equal(t, "Raspberry Pi Foundation", LookupVendor("B8-27-EB-12-34-56"))
+	require.Equal(t, "Docker", LookupVendor("02:42:ac:11:00:02"))
+	require.Equal(t, LocallyAdministered, LookupVendor("da:a1:19:00:00:01"))
+	require.Equal(t, "", LookupVendor("00:11:22:33:44:55"))
+	require.Equal(t, "", LookupVendor("not-a-mac"))
+}
+
+func TestParseVendorFile(t *testing.T) {
+	input := `# custom vendors
+00-00-0C   (hex)		Cisco Systems, Inc
+00000C     (base 16)		Cisco Systems, Inc
+00:11:22 Lab Switch
+bad line
+`
+	vendors, err := parseVendorFile(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"00000C": "Cisco Systems, Inc",
+		"001122": "Lab Switch",
+	}, vendors)
+}
@@ -0,0 +1,67 @@
+package netutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseResolveOverrides 解析 curl 风格的 --resolve 参数（host:port:addr），
+// 返回 "host:port" → "addr:port" 的映射。addr 为 IPv6 时可使用方括号。
+func ParseResolveOverrides(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	overrides := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("无效的 --resolve %q，格式应为 host:port:addr", spec)
+		}
+		port, err := strconv.Atoi(parts[1])
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("无效的 --resolve %q: 端口无效", spec)
+		}
+		addr := strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("无效的 --resolve %q: %s 不是 IP 地址", spec, parts[2])
+		}
+		overrides[overrideKey(parts[0], parts[1])] = net.JoinHostPort(addr, parts[1])
+	}
+	return overrides, nil
+}
+
+// LookupOverride 返回 host:port 对应的覆盖地址（不含端口）
+func LookupOverride(overrides map[string]string, host string, port int) (string, bool) {
+	target, ok := overrides[overrideKey(host, strconv.Itoa(port))]
+	if !ok {
+		return "", false
+	}
+	ip, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return "", false
+	}
+	return ip, true
+}
+
+// OverrideDialContext 包装 DialContext，命中覆盖表时改为连接指定地址；
+// 只改写拨号目标，TLS SNI 与 Host 头仍使用原始主机名。
+func OverrideDialContext(overrides map[string]string, dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if len(overrides) == 0 {
+		return dial
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if target, ok := overrides[overrideKey(host, port)]; ok {
+				addr = target
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+func overrideKey(host, port string) string {
+	return net.JoinHostPort(strings.ToLower(host), port)
+}
@@ -0,0 +1,41 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResolveOverrides(t *testing.T) {
+	overrides, err := ParseResolveOverrides([]string{"Example.com:443:192.0.2.10", "v6.example:80:[2001:db8::1]"})
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.10:443", overrides["example.com:443"])
+	require.Equal(t, "[2001:db8::1]:80", overrides["v6.example:80"])
+
+	ip, ok := LookupOverride(overrides, "EXAMPLE.com", 443)
+	require.True(t, ok)
+	require.Equal(t, "192.0.2.10", ip)
+	_, ok = LookupOverride(overrides, "example.com", 80)
+	require.False(t, ok)
+
+	for _, spec := range []string{"example.com", "example.com:x:1.2.3.4", ":443:1.2.3.4", "example.com:443:not-an-ip"} {
+		_, err := ParseResolveOverrides([]string{spec})
+		require.Error(t, err, spec)
+	}
+}
+
+func TestOverrideDialContext(t *testing.T) {
+	var dialed string
+	dial := OverrideDialContext(map[string]string{"example.com:443": "192.0.2.10:443"},
+		func(_ context.Context, _, addr string) (net.Conn, error) {
+			dialed = addr
+			return nil, nil
+		})
+
+	_, _ = dial(context.Background(), "tcp", "example.com:443")
+	require.Equal(t, "192.0.2.10:443", dialed)
+	_, _ = dial(context.Background(), "tcp", "other.example:443")
+	require.Equal(t, "other.example:443", dialed)
+}
@@ -1,10 +1,21 @@
 package netutil
 
 import (
+	stderrors "errors"
 	"net"
+	"time"
 
 	"github.com/catsayer/ntx/pkg/errors"
 	"github.com/catsayer/ntx/pkg/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// lookupRetryDelay 临时性解析失败后的重试间隔
+const lookupRetryDelay = 200 * time.Millisecond
+
+var (
+	lookupGroup singleflight.Group
+	lookupIP    = net.LookupIP
 )
 
 // ResolveHost 解析主机名或 IP 文本，按照 IP 版本偏好返回匹配的地址
@@ -21,11 +32,26 @@ func ResolveHost(host string, ipVersion types.IPVersion) (*types.Host, error) {
 		}, nil
 	}
 
-	if cached, ok := defaultDNSCache.get(cacheKey(host, ipVersion)); ok {
+	key := cacheKey(host, ipVersion)
+	if cached, ok := defaultDNSCache.get(key); ok {
 		return cached, nil
 	}
 
-	ips, err := net.LookupIP(host)
+	// 同一名称的并发解析合并为一次查询
+	v, err, _ := lookupGroup.Do(key, func() (interface{}, error) {
+		return resolveUncached(host, ipVersion)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := v.(*types.Host)
+	defaultDNSCache.set(key, resolved)
+	return cloneHost(resolved), nil
+}
+
+func resolveUncached(host string, ipVersion types.IPVersion) (*types.Host, error) {
+	ips, err := lookupWithRetry(host)
 	if err != nil {
 		return nil, errors.ErrDNSResolution
 	}
@@ -43,14 +69,26 @@ func ResolveHost(host string, ipVersion types.IPVersion) (*types.Host, error) {
 		ver = types.IPv6
 	}
 
-	resolved := &types.Host{
+	return &types.Host{
 		Hostname:  host,
 		IP:        selectedIP.String(),
 		IPVersion: ver,
+	}, nil
+}
+
+// lookupWithRetry 解析失败且错误为临时性时重试一次
+func lookupWithRetry(host string) ([]net.IP, error) {
+	ips, err := lookupIP(host)
+	if err == nil {
+		return ips, nil
 	}
 
-	defaultDNSCache.set(cacheKey(host, ipVersion), resolved)
-	return resolved, nil
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) && (dnsErr.IsTemporary || dnsErr.IsTimeout) {
+		time.Sleep(lookupRetryDelay)
+		return lookupIP(host)
+	}
+	return nil, err
 }
 
 func selectIPByVersion(ips []net.IP, version types.IPVersion) net.IP {
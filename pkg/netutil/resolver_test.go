@@ -30,3 +30,31 @@ func TestSelectIPByVersion(t *testing.T) {
 	require.Equal(t, ipv6, selectIPByVersion(ips, types.IPvAny))
 	require.Nil(t, selectIPByVersion([]net.IP{ipv4}, types.IPv6))
 }
+
+func TestLookupWithRetryRetriesTemporaryErrors(t *testing.T) {
+	original := lookupIP
+	defer func() { lookupIP = original }()
+
+	calls := 0
+	lookupIP = func(host string) ([]net.IP, error) {
+		calls++
+		if calls == 1 {
+			return nil, &net.DNSError{Err: "temporary", Name: host, IsTemporary: true}
+		}
+		return []net.IP{net.ParseIP("192.0.2.1")}, nil
+	}
+
+	ips, err := lookupWithRetry("example.com")
+	require.NoError(t, err)
+	require.Len(t, ips, 1)
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	lookupIP = func(host string) ([]net.IP, error) {
+		calls++
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	_, err = lookupWithRetry("example.com")
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+}
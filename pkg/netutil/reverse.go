@@ -0,0 +1,178 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// maxReverseEntries 反向解析缓存的最大条目数
+	maxReverseEntries = 4096
+	// reverseConcurrency 同时进行的反向解析查询上限
+	reverseConcurrency = 16
+)
+
+var defaultReverseResolver = newReverseResolver(types.DefaultDNSCacheTTL)
+
+// ReverseStats 反向解析缓存统计
+type ReverseStats struct {
+	Hits    int
+	Misses  int
+	Entries int
+}
+
+type reverseEntry struct {
+	name      string
+	expiresAt time.Time
+}
+
+// reverseResolver 带缓存的反向 DNS 解析器
+//
+// 成功结果按 ttl 缓存，失败结果按较短的负缓存时间缓存；
+// 同一地址的并发查询合并为一次，总查询并发数受 sem 限制。
+type reverseResolver struct {
+	mu          sync.Mutex
+	entries     map[string]reverseEntry
+	ttl         time.Duration
+	negativeTTL time.Duration
+	hits        int
+	misses      int
+
+	group  singleflight.Group
+	sem    chan struct{}
+	lookup func(ctx context.Context, addr string) ([]string, error)
+}
+
+func newReverseResolver(ttl time.Duration) *reverseResolver {
+	r := &reverseResolver{
+		entries: make(map[string]reverseEntry),
+		sem:     make(chan struct{}, reverseConcurrency),
+		lookup:  net.DefaultResolver.LookupAddr,
+	}
+	r.setTTL(ttl)
+	return r
+}
+
+// ReverseLookup 返回 IP 的主机名（去除末尾的点），解析失败或超时返回 false
+//
+// 结果在进程内缓存，有效期与 SetDNSCacheTTL 一致。
+func ReverseLookup(ctx context.Context, ip string) (string, bool) {
+	return defaultReverseResolver.resolve(ctx, ip)
+}
+
+// ReverseCacheStats 返回进程内反向解析缓存统计
+func ReverseCacheStats() ReverseStats {
+	return defaultReverseResolver.stats()
+}
+
+func (r *reverseResolver) setTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ttl = ttl
+	r.negativeTTL = types.DefaultReverseDNSNegativeTTL
+	if ttl < r.negativeTTL {
+		r.negativeTTL = ttl
+	}
+	if ttl <= 0 {
+		r.entries = make(map[string]reverseEntry)
+	}
+}
+
+func (r *reverseResolver) resolve(ctx context.Context, ip string) (string, bool) {
+	if name, ok := r.get(ip); ok {
+		return name, name != ""
+	}
+
+	v, _, _ := r.group.Do(ip, func() (interface{}, error) {
+		select {
+		case r.sem <- struct{}{}:
+		case <-ctx.Done():
+			// 取消导致的失败不写入负缓存
+			return "", nil
+		}
+		defer func() { <-r.sem }()
+
+		lookupCtx, cancel := context.WithTimeout(ctx, types.DefaultReverseDNSTimeout)
+		defer cancel()
+
+		var name string
+		if names, err := r.lookup(lookupCtx, ip); err == nil && len(names) > 0 {
+			name = strings.TrimSuffix(names[0], ".")
+		}
+		if ctx.Err() == nil {
+			r.set(ip, name)
+		}
+		return name, nil
+	})
+
+	name := v.(string)
+	return name, name != ""
+}
+
+// get 查询缓存，命中时 name 为空表示负缓存
+func (r *reverseResolver) get(ip string) (string, bool) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[ip]
+	if ok && now.After(entry.expiresAt) {
+		delete(r.entries, ip)
+		ok = false
+	}
+	if ok {
+		r.hits++
+	} else {
+		r.misses++
+	}
+	return entry.name, ok
+}
+
+func (r *reverseResolver) set(ip, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ttl := r.ttl
+	if name == "" {
+		ttl = r.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	if len(r.entries) >= maxReverseEntries {
+		r.evictLocked()
+	}
+	r.entries[ip] = reverseEntry{name: name, expiresAt: time.Now().Add(ttl)}
+}
+
+// evictLocked 清理过期条目，仍然已满时淘汰最早过期的条目
+func (r *reverseResolver) evictLocked() {
+	now := time.Now()
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range r.entries {
+		if now.After(entry.expiresAt) {
+			delete(r.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(r.entries) >= maxReverseEntries && oldestKey != "" {
+		delete(r.entries, oldestKey)
+	}
+}
+
+func (r *reverseResolver) stats() ReverseStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return ReverseStats{Hits: r.hits, Misses: r.misses, Entries: len(r.entries)}
+}
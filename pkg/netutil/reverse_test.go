@@ -0,0 +1,44 @@
+package netutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseResolverCachesPositiveAndNegative(t *testing.T) {
+	var calls atomic.Int32
+	r := newReverseResolver(5 * time.Minute)
+	r.lookup = func(_ context.Context, addr string) ([]string, error) {
+		calls.Add(1)
+		if addr == "192.0.2.1" {
+			return []string{"gw.example."}, nil
+		}
+		return nil, errors.New("no PTR")
+	}
+
+	for i := 0; i < 3; i++ {
+		name, ok := r.resolve(context.Background(), "192.0.2.1")
+		require.True(t, ok)
+		require.Equal(t, "gw.example", name)
+
+		_, ok = r.resolve(context.Background(), "192.0.2.2")
+		require.False(t, ok)
+	}
+	require.EqualValues(t, 2, calls.Load())
+
+	stats := r.stats()
+	require.Equal(t, 4, stats.Hits)
+	require.Equal(t, 2, stats.Misses)
+	require.Equal(t, 2, stats.Entries)
+
+	r.setTTL(0)
+	_, ok := r.resolve(context.Background(), "192.0.2.1")
+	require.True(t, ok)
+	require.EqualValues(t, 3, calls.Load())
+	require.Zero(t, r.stats().Entries)
+}
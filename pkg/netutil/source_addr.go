@@ -0,0 +1,57 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// ResolveSourceAddr 解析探测使用的源地址，source 可以是 IP 地址或接口名。
+// 接口名取该接口上与 version 同族的第一个地址；IP 地址与 version 不同族时返回错误。
+// version 为 IPvAny 时不做地址族检查，接口名取第一个地址。
+func ResolveSourceAddr(source string, version types.IPVersion) (net.IP, error) {
+	if ip := net.ParseIP(source); ip != nil {
+		if v := ipVersionOf(ip); version != types.IPvAny && v != version {
+			return nil, fmt.Errorf("源地址 %s 为 IPv%d，与目标地址族 IPv%d 不一致", source, v, version)
+		}
+		return ip, nil
+	}
+
+	ifc, err := net.InterfaceByName(source)
+	if err != nil {
+		return nil, fmt.Errorf("无效的源地址或接口 %q: %w", source, err)
+	}
+	addrs, err := ifc.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("读取接口 %s 的地址失败: %w", source, err)
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	if ip := selectIPByVersion(ips, version); ip != nil {
+		return ip, nil
+	}
+	if version == types.IPvAny {
+		return nil, fmt.Errorf("接口 %s 上没有可用的地址", source)
+	}
+	return nil, fmt.Errorf("接口 %s 上没有 IPv%d 地址，与目标地址族不一致", source, version)
+}
+
+// SourceIPVersion 返回 IP 字面量源地址的地址族，接口名或空值返回 IPvAny
+func SourceIPVersion(source string) types.IPVersion {
+	if ip := net.ParseIP(source); ip != nil {
+		return ipVersionOf(ip)
+	}
+	return types.IPvAny
+}
+
+func ipVersionOf(ip net.IP) types.IPVersion {
+	if ip.To4() != nil {
+		return types.IPv4
+	}
+	return types.IPv6
+}
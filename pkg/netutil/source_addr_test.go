@@ -0,0 +1,38 @@
+package netutil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/catsayer/ntx/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSourceAddr(t *testing.T) {
+	ip, err := ResolveSourceAddr("192.0.2.10", types.IPv4)
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.10", ip.String())
+
+	_, err = ResolveSourceAddr("192.0.2.10", types.IPv6)
+	require.ErrorContains(t, err, "不一致")
+
+	_, err = ResolveSourceAddr("no-such-if0", types.IPv4)
+	require.Error(t, err)
+
+	// 回环接口名因平台而异，取带 IPv4 地址的回环接口验证接口名解析
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	for _, ifc := range ifaces {
+		if ifc.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		ip, err := ResolveSourceAddr(ifc.Name, types.IPv4)
+		if err != nil {
+			continue
+		}
+		require.True(t, ip.IsLoopback())
+		require.NotNil(t, ip.To4())
+		return
+	}
+	t.Skip("没有带 IPv4 地址的回环接口")
+}
@@ -0,0 +1,54 @@
+package netutil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// BindSourcePort 将 Dialer 的本地端口固定为 port（<=0 时不做修改）
+//
+// 同时开启 SO_REUSEADDR，使同一源端口可并发连接不同目标端口；
+// 调用方关闭连接前应 SetLinger(0)，避免连接进入 TIME_WAIT 后无法复用同一四元组。
+func BindSourcePort(d *net.Dialer, port int) {
+	if port <= 0 {
+		return
+	}
+	d.LocalAddr = &net.TCPAddr{Port: port}
+
+	prev := d.Control
+	d.Control = func(network, address string, c syscall.RawConn) error {
+		if prev != nil {
+			if err := prev(network, address, c); err != nil {
+				return err
+			}
+		}
+		var sockErr error
+		if err := c.Control(func(fd uintptr) {
+			sockErr = setReuseAddr(fd)
+		}); err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
+
+// SourcePortError 将源端口占用类错误转换为更明确的提示，其他错误原样返回
+func SourcePortError(err error, port int) error {
+	if err == nil || port <= 0 {
+		return err
+	}
+	if IsSourcePortConflict(err) {
+		return fmt.Errorf("源端口 %d 不可用（已被占用或连接仍处于 TIME_WAIT）: %w", port, err)
+	}
+	if errors.Is(err, syscall.EACCES) {
+		return fmt.Errorf("绑定源端口 %d 需要 root 权限: %w", port, err)
+	}
+	return err
+}
+
+// IsSourcePortConflict 判断错误是否由本地地址/端口不可用引起
+func IsSourcePortConflict(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE) || errors.Is(err, syscall.EADDRNOTAVAIL)
+}
@@ -0,0 +1,37 @@
+package netutil
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindSourcePort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// 借用一个空闲端口作为源端口
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	sourcePort := probe.Addr().(*net.TCPAddr).Port
+	probe.Close()
+
+	d := &net.Dialer{Timeout: time.Second}
+	BindSourcePort(d, sourcePort)
+
+	// 连续两次连接同一目标，验证以 RST 关闭后源端口可复用
+	for i := 0; i < 2; i++ {
+		conn, err := d.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		require.Equal(t, sourcePort, conn.LocalAddr().(*net.TCPAddr).Port)
+
+		peer, err := ln.Accept()
+		require.NoError(t, err)
+		require.NoError(t, conn.(*net.TCPConn).SetLinger(0))
+		conn.Close()
+		peer.Close()
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package netutil
+
+import "golang.org/x/sys/unix"
+
+func setReuseAddr(fd uintptr) error {
+	return unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package netutil
+
+import "golang.org/x/sys/windows"
+
+func setReuseAddr(fd uintptr) error {
+	return windows.SetsockoptInt(windows.Handle(fd), windows.SOL_SOCKET, windows.SO_REUSEADDR, 1)
+}
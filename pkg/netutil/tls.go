@@ -0,0 +1,40 @@
+package netutil
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/catsayer/ntx/pkg/types"
+)
+
+// TLSInfoFromState 从 TLS 连接状态提取协议信息与证书链，state 为 nil 时返回 nil
+func TLSInfoFromState(state *tls.ConnectionState, now time.Time) *types.TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &types.TLSInfo{
+		Version:      tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		ServerName:   state.ServerName,
+		Certificates: make([]*types.CertificateInfo, 0, len(state.PeerCertificates)),
+	}
+	for _, cert := range state.PeerCertificates {
+		c := &types.CertificateInfo{
+			Subject:         cert.Subject.String(),
+			Issuer:          cert.Issuer.String(),
+			DNSNames:        cert.DNSNames,
+			SerialNumber:    fmt.Sprintf("%X", cert.SerialNumber),
+			NotBefore:       cert.NotBefore,
+			NotAfter:        cert.NotAfter,
+			DaysUntilExpiry: int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+		}
+		for _, ip := range cert.IPAddresses {
+			c.IPAddresses = append(c.IPAddresses, ip.String())
+		}
+		info.Certificates = append(info.Certificates, c)
+	}
+	return info
+}
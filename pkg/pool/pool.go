@@ -0,0 +1,68 @@
+// Package pool 提供有界并发的工作池
+//
+// 作者: Catsayer
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Run 以最多 concurrency 个协程对 items 逐个执行 fn，结果按输入顺序返回。
+//
+// fn 返回错误的项不计入结果，所有错误按输入顺序合并返回。
+// ctx 取消后不再启动新任务，未执行的项同样不计入结果，并附带 ctx.Err()；
+// 已在执行的 fn 由其自身负责响应 ctx。concurrency <= 0 时不限制并发。
+func Run[T, R any](ctx context.Context, items []T, concurrency int, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if concurrency <= 0 || concurrency > len(items) {
+		concurrency = len(items)
+	}
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	done := make([]bool, len(items))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r, err := fn(ctx, items[i])
+				results[i], errs[i], done[i] = r, err, err == nil
+			}
+		}()
+	}
+
+	dispatched := 0
+dispatch:
+	for i := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+			dispatched++
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	out := make([]R, 0, len(items))
+	for i := range items {
+		if done[i] {
+			out = append(out, results[i])
+		}
+	}
+	if dispatched < len(items) {
+		errs = append(errs, ctx.Err())
+	}
+	return out, errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunKeepsOrderAndBoundsConcurrency(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	var running, peak int32
+	out, err := Run(context.Background(), items, 4, func(_ context.Context, n int) (int, error) {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if cur <= old || atomic.CompareAndSwapInt32(&peak, old, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return n * 2, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, out, len(items))
+	for i, v := range out {
+		require.Equal(t, i*2, v)
+	}
+	require.LessOrEqual(t, peak, int32(4))
+}
+
+func TestRunCollectsErrors(t *testing.T) {
+	out, err := Run(context.Background(), []int{1, 2, 3, 4}, 2, func(_ context.Context, n int) (int, error) {
+		if n%2 == 0 {
+			return 0, fmt.Errorf("bad %d", n)
+		}
+		return n, nil
+	})
+	require.Equal(t, []int{1, 3}, out)
+	require.EqualError(t, err, "bad 2\nbad 4")
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make([]int, 100)
+
+	var calls int32
+	out, err := Run(ctx, items, 1, func(_ context.Context, n int) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			cancel()
+		}
+		return n, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, len(out), len(items))
+	require.Equal(t, int(atomic.LoadInt32(&calls)), len(out))
+}
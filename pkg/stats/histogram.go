@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultHistogramBuckets 默认直方图桶数量
+const DefaultHistogramBuckets = 10
+
+// HistogramBucket 直方图中的单个区间 [Lower, Upper]
+type HistogramBucket struct {
+	Lower time.Duration `json:"lower" yaml:"lower"`
+	Upper time.Duration `json:"upper" yaml:"upper"`
+	Count int           `json:"count" yaml:"count"`
+}
+
+// BuildHistogram 将耗时样本按等宽区间分桶
+func BuildHistogram(samples []time.Duration, buckets int) []HistogramBucket {
+	if len(samples) == 0 {
+		return nil
+	}
+	if buckets <= 0 {
+		buckets = DefaultHistogramBuckets
+	}
+
+	min, max, _, _ := ComputeRTTStats(samples)
+	span := max - min
+	if span == 0 {
+		return []HistogramBucket{{Lower: min, Upper: max, Count: len(samples)}}
+	}
+
+	width := span / time.Duration(buckets)
+	if width == 0 {
+		width = 1
+		buckets = int(span) + 1
+	}
+
+	result := make([]HistogramBucket, buckets)
+	for i := range result {
+		result[i].Lower = min + time.Duration(i)*width
+		result[i].Upper = min + time.Duration(i+1)*width
+	}
+	result[buckets-1].Upper = max
+
+	for _, s := range samples {
+		idx := int((s - min) / width)
+		if idx >= buckets {
+			idx = buckets - 1
+		}
+		result[idx].Count++
+	}
+
+	return result
+}
+
+// RenderHistogram 将直方图渲染为文本，width 为整行可用宽度
+func RenderHistogram(buckets []HistogramBucket, width int) string {
+	if len(buckets) == 0 {
+		return ""
+	}
+
+	peak := 0
+	for _, b := range buckets {
+		if b.Count > peak {
+			peak = b.Count
+		}
+	}
+
+	labels := make([]string, len(buckets))
+	labelWidth := 0
+	for i, b := range buckets {
+		labels[i] = fmt.Sprintf("%v [%d]", b.Upper.Round(10*time.Microsecond), b.Count)
+		if len(labels[i]) > labelWidth {
+			labelWidth = len(labels[i])
+		}
+	}
+
+	barWidth := width - labelWidth - 3
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	var sb strings.Builder
+	for i, b := range buckets {
+		bar := 0
+		if peak > 0 {
+			bar = b.Count * barWidth / peak
+		}
+		if bar == 0 && b.Count > 0 {
+			bar = 1
+		}
+		fmt.Fprintf(&sb, "  %-*s %s\n", labelWidth, labels[i], strings.Repeat("■", bar))
+	}
+	return sb.String()
+}
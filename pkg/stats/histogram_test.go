@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildHistogram(t *testing.T) {
+	samples := []time.Duration{
+		10 * time.Millisecond,
+		11 * time.Millisecond,
+		12 * time.Millisecond,
+		20 * time.Millisecond,
+		30 * time.Millisecond,
+	}
+
+	buckets := BuildHistogram(samples, 4)
+	if len(buckets) != 4 {
+		t.Fatalf("expected 4 buckets, got %d", len(buckets))
+	}
+
+	total := 0
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total != len(samples) {
+		t.Fatalf("expected %d samples in buckets, got %d", len(samples), total)
+	}
+	if buckets[0].Count != 3 {
+		t.Fatalf("expected first bucket to hold 3 samples, got %d", buckets[0].Count)
+	}
+	if buckets[3].Upper != 30*time.Millisecond {
+		t.Fatalf("expected last bucket upper bound 30ms, got %v", buckets[3].Upper)
+	}
+}
+
+func TestBuildHistogramSingleValue(t *testing.T) {
+	buckets := BuildHistogram([]time.Duration{time.Millisecond, time.Millisecond}, 5)
+	if len(buckets) != 1 || buckets[0].Count != 2 {
+		t.Fatalf("expected single bucket with 2 samples, got %+v", buckets)
+	}
+}
+
+func TestRenderHistogram(t *testing.T) {
+	buckets := []HistogramBucket{
+		{Lower: 0, Upper: time.Millisecond, Count: 4},
+		{Lower: time.Millisecond, Upper: 2 * time.Millisecond, Count: 2},
+		{Lower: 2 * time.Millisecond, Upper: 3 * time.Millisecond, Count: 0},
+	}
+
+	out := RenderHistogram(buckets, 40)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	full := strings.Count(lines[0], "■")
+	half := strings.Count(lines[1], "■")
+	if full == 0 || half*2 != full {
+		t.Fatalf("expected bar of second bucket to be half of first, got %d and %d", half, full)
+	}
+	if strings.Contains(lines[2], "■") {
+		t.Fatalf("expected empty bucket to have no bar: %q", lines[2])
+	}
+}
@@ -2,6 +2,7 @@ package stats
 
 import (
 	"math"
+	"sort"
 	"time"
 )
 
@@ -38,3 +39,48 @@ func ComputeRTTStats(rtts []time.Duration) (min, max, avg, stddev time.Duration)
 
 	return
 }
+
+// ComputeRTTPercentiles 计算 RTT 样本的 p50/p95/p99 与抖动。
+// 百分位在排序后的样本间线性插值；抖动为相邻样本（按采集顺序）差值绝对值的平均
+func ComputeRTTPercentiles(rtts []time.Duration) (p50, p95, p99, jitter time.Duration) {
+	if len(rtts) == 0 {
+		return
+	}
+
+	sorted := append([]time.Duration(nil), rtts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	p50 = Percentile(sorted, 50)
+	p95 = Percentile(sorted, 95)
+	p99 = Percentile(sorted, 99)
+
+	if len(rtts) > 1 {
+		var sum time.Duration
+		for i := 1; i < len(rtts); i++ {
+			diff := rtts[i] - rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			sum += diff
+		}
+		jitter = sum / time.Duration(len(rtts)-1)
+	}
+	return
+}
+
+// Percentile 返回已升序排列样本的第 p 百分位（0-100），在相邻样本间线性插值
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := rank - float64(lower)
+	return sorted[lower] + time.Duration(math.Round(frac*float64(sorted[upper]-sorted[lower])))
+}
@@ -54,3 +54,38 @@ func TestComputeRTTStats(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeRTTPercentiles(t *testing.T) {
+	// 1..10ms 乱序输入：p50 在 5ms 与 6ms 之间插值
+	ms := time.Millisecond
+	rtts := []time.Duration{3 * ms, 1 * ms, 4 * ms, 10 * ms, 5 * ms, 9 * ms, 2 * ms, 6 * ms, 8 * ms, 7 * ms}
+
+	p50, p95, p99, jitter := ComputeRTTPercentiles(rtts)
+	if p50 != 5500*time.Microsecond {
+		t.Fatalf("p50: expected 5.5ms, got %v", p50)
+	}
+	if p95 != 9550*time.Microsecond {
+		t.Fatalf("p95: expected 9.55ms, got %v", p95)
+	}
+	if p99 != 9910*time.Microsecond {
+		t.Fatalf("p99: expected 9.91ms, got %v", p99)
+	}
+	// 相邻差值: 2,3,6,5,4,7,4,2,1 → 34/9 ms
+	if want := 34 * ms / 9; jitter != want {
+		t.Fatalf("jitter: expected %v, got %v", want, jitter)
+	}
+	// 不修改调用方的样本顺序
+	if rtts[0] != 3*ms || rtts[9] != 7*ms {
+		t.Fatalf("input slice was reordered: %v", rtts)
+	}
+
+	p50, p95, p99, jitter = ComputeRTTPercentiles([]time.Duration{4 * ms})
+	if p50 != 4*ms || p95 != 4*ms || p99 != 4*ms || jitter != 0 {
+		t.Fatalf("single sample: got %v/%v/%v/%v", p50, p95, p99, jitter)
+	}
+
+	p50, _, _, jitter = ComputeRTTPercentiles(nil)
+	if p50 != 0 || jitter != 0 {
+		t.Fatalf("empty: got %v/%v", p50, jitter)
+	}
+}
@@ -0,0 +1,19 @@
+package termutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ClearScreen 清屏并将光标移到左上角，用于原地刷新输出
+func ClearScreen(w io.Writer) {
+	fmt.Fprint(w, "\033[H\033[2J")
+}
+
+// IsTerminal 判断 f 是否连接到终端
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
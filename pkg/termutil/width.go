@@ -0,0 +1,107 @@
+package termutil
+
+import (
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// DefaultWidth 无法获取终端宽度时使用的列数
+const DefaultWidth = 80
+
+// Width 返回标准输出所在终端的列数，非终端时返回 DefaultWidth
+func Width() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return DefaultWidth
+	}
+	return width
+}
+
+// DisplayWidth 返回字符串在终端中占用的列数：忽略 ANSI 转义序列，
+// 东亚宽字符计 2 列，组合字符计 0 列
+func DisplayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := ansiSeqLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += RuneWidth(r)
+		i += size
+	}
+	return width
+}
+
+// RuneWidth 返回单个字符占用的终端列数
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
+
+// ansiSeqLen 返回 s 开头的 ANSI CSI 转义序列长度（如 "\x1b[32m"），不是转义序列时返回 0
+func ansiSeqLen(s string) int {
+	if len(s) < 2 || s[0] != 0x1b || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// Truncate 将 s 截断到不超过 width 列，截断时以 tail 结尾；保留其中的 ANSI 转义序列，
+// 截断后追加重置序列避免颜色溢出到后续内容
+func Truncate(s string, width int, tail string) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	limit := width - DisplayWidth(tail)
+	if limit < 0 {
+		limit = 0
+		tail = ""
+	}
+
+	var sb strings.Builder
+	used, styled := 0, false
+	for i := 0; i < len(s); {
+		if n := ansiSeqLen(s[i:]); n > 0 {
+			sb.WriteString(s[i : i+n])
+			styled = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if used+RuneWidth(r) > limit {
+			break
+		}
+		sb.WriteString(s[i : i+size])
+		used += RuneWidth(r)
+		i += size
+	}
+	sb.WriteString(tail)
+	if styled {
+		sb.WriteString("\x1b[0m")
+	}
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+// Package types 提供带宽测试相关类型定义
+//
+// 作者: Catsayer
+package types
+
+import "time"
+
+// BenchOptions 带宽测试选项
+type BenchOptions struct {
+	// Protocol 测试协议（tcp/udp）
+	Protocol Protocol `json:"protocol" yaml:"protocol"`
+
+	// Port 服务端端口
+	Port int `json:"port" yaml:"port"`
+
+	// Duration 测试时长
+	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// Parallel 并行流数量
+	Parallel int `json:"parallel" yaml:"parallel"`
+
+	// Bandwidth UDP 目标带宽（bit/s），0 表示默认值
+	Bandwidth int64 `json:"bandwidth,omitempty" yaml:"bandwidth,omitempty"`
+
+	// PacketSize UDP 数据报大小（字节）
+	PacketSize int `json:"packet_size,omitempty" yaml:"packet_size,omitempty"`
+
+	// Timeout 连接建立超时
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// DefaultBenchOptions 返回默认带宽测试选项
+func DefaultBenchOptions() *BenchOptions {
+	return &BenchOptions{
+		Protocol:   ProtocolTCP,
+		Port:       DefaultBenchPort,
+		Duration:   10 * time.Second,
+		Parallel:   1,
+		Bandwidth:  DefaultUDPBandwidth,
+		PacketSize: DefaultUDPPacketSize,
+		Timeout:    DefaultPingTimeout,
+	}
+}
+
+const (
+	// DefaultUDPBandwidth UDP 测试默认目标带宽 1 Mbit/s
+	DefaultUDPBandwidth = 1_000_000
+	// DefaultUDPPacketSize UDP 测试默认数据报大小
+	DefaultUDPPacketSize = 1400
+)
+
+// BenchStreamResult 单条数据流的测试结果
+type BenchStreamResult struct {
+	// ID 流编号
+	ID int `json:"id" yaml:"id"`
+
+	// Bytes 服务端接收字节数
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+
+	// Duration 实际传输时长
+	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// BitsPerSecond 吞吐量
+	BitsPerSecond float64 `json:"bits_per_second" yaml:"bits_per_second"`
+
+	// Retransmits TCP 重传次数（平台不支持时为 -1）
+	Retransmits int64 `json:"retransmits,omitempty" yaml:"retransmits,omitempty"`
+
+	// PacketsSent UDP 发送数据报数
+	PacketsSent int64 `json:"packets_sent,omitempty" yaml:"packets_sent,omitempty"`
+
+	// PacketsReceived UDP 服务端接收数据报数
+	PacketsReceived int64 `json:"packets_received,omitempty" yaml:"packets_received,omitempty"`
+
+	// LossRate UDP 丢包率 (0-100)
+	LossRate float64 `json:"loss_rate,omitempty" yaml:"loss_rate,omitempty"`
+}
+
+// BenchResult 带宽测试汇总结果
+type BenchResult struct {
+	// Target 服务端地址
+	Target string `json:"target" yaml:"target"`
+
+	// Protocol 测试协议
+	Protocol Protocol `json:"protocol" yaml:"protocol"`
+
+	// Streams 各数据流结果
+	Streams []*BenchStreamResult `json:"streams" yaml:"streams"`
+
+	// Bytes 总接收字节数
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+
+	// Duration 测试时长
+	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// BitsPerSecond 总吞吐量
+	BitsPerSecond float64 `json:"bits_per_second" yaml:"bits_per_second"`
+
+	// Retransmits TCP 总重传次数（平台不支持时为 -1）
+	Retransmits int64 `json:"retransmits,omitempty" yaml:"retransmits,omitempty"`
+
+	// PacketsSent UDP 总发送数据报数
+	PacketsSent int64 `json:"packets_sent,omitempty" yaml:"packets_sent,omitempty"`
+
+	// PacketsLost UDP 总丢失数据报数
+	PacketsLost int64 `json:"packets_lost,omitempty" yaml:"packets_lost,omitempty"`
+
+	// LossRate UDP 丢包率 (0-100)
+	LossRate float64 `json:"loss_rate,omitempty" yaml:"loss_rate,omitempty"`
+}
@@ -41,6 +41,8 @@ const (
 	OutputYAML OutputFormat = "yaml"
 	// OutputTable 表格格式
 	OutputTable OutputFormat = "table"
+	// OutputNDJSON 换行分隔的 JSON，流式模式下每个事件一行
+	OutputNDJSON OutputFormat = "ndjson"
 )
 
 // Status 状态类型
@@ -75,6 +77,8 @@ type Statistics struct {
 	Sent int `json:"sent" yaml:"sent"`
 	// Received 接收的数据包数量
 	Received int `json:"received" yaml:"received"`
+	// Duplicates 重复响应数量（DUP!），不计入 Received
+	Duplicates int `json:"duplicates,omitempty" yaml:"duplicates,omitempty"`
 	// Loss 丢包数量
 	Loss int `json:"loss" yaml:"loss"`
 	// LossRate 丢包率 (0-100)
@@ -87,6 +91,12 @@ type Statistics struct {
 	AvgRTT time.Duration `json:"avg_rtt" yaml:"avg_rtt"`
 	// StdDevRTT RTT 标准差
 	StdDevRTT time.Duration `json:"stddev_rtt" yaml:"stddev_rtt"`
+	// P50RTT/P95RTT/P99RTT RTT 百分位
+	P50RTT time.Duration `json:"p50_rtt,omitempty" yaml:"p50_rtt,omitempty"`
+	P95RTT time.Duration `json:"p95_rtt,omitempty" yaml:"p95_rtt,omitempty"`
+	P99RTT time.Duration `json:"p99_rtt,omitempty" yaml:"p99_rtt,omitempty"`
+	// JitterRTT 抖动：相邻 RTT 差值绝对值的平均
+	JitterRTT time.Duration `json:"jitter_rtt,omitempty" yaml:"jitter_rtt,omitempty"`
 	// TotalTime 总耗时
 	TotalTime time.Duration `json:"total_time" yaml:"total_time"`
 }
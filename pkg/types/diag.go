@@ -0,0 +1,98 @@
+// Package types 定义 NTX 工具的公共类型
+//
+// 本文件定义网络诊断结果相关的类型
+//
+// 作者: Catsayer
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DiagnosticResult 诊断结果
+type DiagnosticResult struct {
+	Timestamp   time.Time        `json:"timestamp" yaml:"timestamp"`
+	Duration    time.Duration    `json:"duration" yaml:"duration"`
+	Status      DiagnosticStatus `json:"status" yaml:"status"`
+	Checks      []*CheckResult   `json:"checks" yaml:"checks"`
+	Issues      []*Issue         `json:"issues" yaml:"issues"`
+	Suggestions []string         `json:"suggestions" yaml:"suggestions"`
+}
+
+// DiagnosticStatus 诊断状态
+type DiagnosticStatus int
+
+const (
+	// DiagStatusHealthy 正常
+	DiagStatusHealthy DiagnosticStatus = iota
+	// DiagStatusWarning 存在警告
+	DiagStatusWarning
+	// DiagStatusCritical 存在严重问题
+	DiagStatusCritical
+)
+
+// String 返回诊断状态的字符串表示
+func (s DiagnosticStatus) String() string {
+	switch s {
+	case DiagStatusHealthy:
+		return "HEALTHY"
+	case DiagStatusWarning:
+		return "WARNING"
+	case DiagStatusCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalJSON 将诊断状态序列化为字符串（HEALTHY/WARNING/CRITICAL）
+func (s DiagnosticStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON 从字符串解析诊断状态
+func (s *DiagnosticStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	switch strings.ToUpper(name) {
+	case "HEALTHY":
+		*s = DiagStatusHealthy
+	case "WARNING":
+		*s = DiagStatusWarning
+	case "CRITICAL":
+		*s = DiagStatusCritical
+	default:
+		return fmt.Errorf("未知的诊断状态: %s", name)
+	}
+	return nil
+}
+
+// MarshalYAML 将诊断状态序列化为字符串
+func (s DiagnosticStatus) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Name     string                 `json:"name" yaml:"name"`
+	Category string                 `json:"category" yaml:"category"`
+	Status   DiagnosticStatus       `json:"status" yaml:"status"`
+	Message  string                 `json:"message" yaml:"message"`
+	Duration time.Duration          `json:"duration" yaml:"duration"`
+	Details  map[string]interface{} `json:"details,omitempty" yaml:"details,omitempty"`
+	// Suggestion 检查未通过时的修复建议
+	Suggestion string `json:"suggestion,omitempty" yaml:"suggestion,omitempty"`
+}
+
+// Issue 发现的问题
+type Issue struct {
+	Severity    DiagnosticStatus `json:"severity" yaml:"severity"`
+	Category    string           `json:"category" yaml:"category"`
+	Description string           `json:"description" yaml:"description"`
+	Suggestion  string           `json:"suggestion" yaml:"suggestion"`
+}
@@ -5,6 +5,7 @@ package types
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -28,42 +29,86 @@ var (
 type DNSRecordType uint16
 
 const (
-	DNSTypeA     DNSRecordType = 1   // IPv4 地址
-	DNSTypeNS    DNSRecordType = 2   // 名称服务器
-	DNSTypeCNAME DNSRecordType = 5   // 规范名称
-	DNSTypeSOA   DNSRecordType = 6   // 授权起始
-	DNSTypePTR   DNSRecordType = 12  // 指针记录
-	DNSTypeMX    DNSRecordType = 15  // 邮件交换
-	DNSTypeTXT   DNSRecordType = 16  // 文本记录
-	DNSTypeAAAA  DNSRecordType = 28  // IPv6 地址
-	DNSTypeSRV   DNSRecordType = 33  // 服务记录
-	DNSTypeANY   DNSRecordType = 255 // 所有记录
+	DNSTypeA      DNSRecordType = 1   // IPv4 地址
+	DNSTypeNS     DNSRecordType = 2   // 名称服务器
+	DNSTypeCNAME  DNSRecordType = 5   // 规范名称
+	DNSTypeSOA    DNSRecordType = 6   // 授权起始
+	DNSTypePTR    DNSRecordType = 12  // 指针记录
+	DNSTypeMX     DNSRecordType = 15  // 邮件交换
+	DNSTypeTXT    DNSRecordType = 16  // 文本记录
+	DNSTypeAAAA   DNSRecordType = 28  // IPv6 地址
+	DNSTypeSRV    DNSRecordType = 33  // 服务记录
+	DNSTypeNAPTR  DNSRecordType = 35  // 名称权威指针
+	DNSTypeDS     DNSRecordType = 43  // 委派签名者
+	DNSTypeRRSIG  DNSRecordType = 46  // DNSSEC 签名
+	DNSTypeDNSKEY DNSRecordType = 48  // DNSSEC 公钥
+	DNSTypeSVCB   DNSRecordType = 64  // 服务绑定
+	DNSTypeHTTPS  DNSRecordType = 65  // HTTPS 服务绑定
+	DNSTypeANY    DNSRecordType = 255 // 所有记录
+	DNSTypeCAA    DNSRecordType = 257 // 证书颁发机构授权
 )
 
-// String 返回记录类型的字符串表示
+// dnsTypeNames 记录类型名称映射
+var dnsTypeNames = map[DNSRecordType]string{
+	DNSTypeA:      "A",
+	DNSTypeNS:     "NS",
+	DNSTypeCNAME:  "CNAME",
+	DNSTypeSOA:    "SOA",
+	DNSTypePTR:    "PTR",
+	DNSTypeMX:     "MX",
+	DNSTypeTXT:    "TXT",
+	DNSTypeAAAA:   "AAAA",
+	DNSTypeSRV:    "SRV",
+	DNSTypeNAPTR:  "NAPTR",
+	DNSTypeDS:     "DS",
+	DNSTypeRRSIG:  "RRSIG",
+	DNSTypeDNSKEY: "DNSKEY",
+	DNSTypeSVCB:   "SVCB",
+	DNSTypeHTTPS:  "HTTPS",
+	DNSTypeANY:    "ANY",
+	DNSTypeCAA:    "CAA",
+}
+
+// String 返回记录类型的字符串表示，未知类型使用 RFC 3597 的 TYPEnnn 形式
 func (t DNSRecordType) String() string {
-	switch t {
-	case DNSTypeA:
-		return "A"
-	case DNSTypeNS:
-		return "NS"
-	case DNSTypeCNAME:
-		return "CNAME"
-	case DNSTypeSOA:
-		return "SOA"
-	case DNSTypePTR:
-		return "PTR"
-	case DNSTypeMX:
-		return "MX"
-	case DNSTypeTXT:
-		return "TXT"
-	case DNSTypeAAAA:
-		return "AAAA"
-	case DNSTypeSRV:
-		return "SRV"
-	default:
-		return "UNKNOWN"
+	if name, ok := dnsTypeNames[t]; ok {
+		return name
 	}
+	return fmt.Sprintf("TYPE%d", uint16(t))
+}
+
+// ParseDNSRecordType 解析记录类型，支持名称（如 CAA）、数值（如 65）及 TYPEnnn 形式
+func ParseDNSRecordType(s string) (DNSRecordType, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	for t, n := range dnsTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+
+	num := strings.TrimPrefix(name, "TYPE")
+	v, err := strconv.ParseUint(num, 10, 16)
+	if err != nil || v == 0 {
+		return 0, fmt.Errorf("无效的 DNS 记录类型: %s", s)
+	}
+	return DNSRecordType(v), nil
+}
+
+// ParseDNSRecordTypes 解析逗号分隔的记录类型列表（如 "A,AAAA,MX"），保持顺序并去重
+func ParseDNSRecordTypes(s string) ([]DNSRecordType, error) {
+	var result []DNSRecordType
+	seen := make(map[DNSRecordType]bool)
+	for _, part := range strings.Split(s, ",") {
+		t, err := ParseDNSRecordType(part)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result, nil
 }
 
 // FormatDNSServer 将裸地址转换为 host:port 形式
@@ -96,8 +141,14 @@ type DNSOptions struct {
 	// Timeout 查询超时时间
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
 
+	// FallbackServers 主服务器超时或返回 SERVFAIL 时依次尝试的备用服务器
+	FallbackServers []string `json:"fallback_servers,omitempty" yaml:"fallback_servers,omitempty"`
+
 	// Recursive 是否递归查询
 	Recursive bool `json:"recursive" yaml:"recursive"`
+
+	// DNSSEC 设置 EDNS0 DO 位请求 DNSSEC 记录，并报告应答的验证状态
+	DNSSEC bool `json:"dnssec,omitempty" yaml:"dnssec,omitempty"`
 }
 
 // DNSResult DNS 查询结果
@@ -129,10 +180,41 @@ type DNSResult struct {
 	// Additional 附加记录
 	Additional []*DNSRecord `json:"additional,omitempty" yaml:"additional,omitempty"`
 
+	// Authenticated 应答带有 AD 标志，即解析器已验证 DNSSEC 签名
+	Authenticated bool `json:"authenticated,omitempty" yaml:"authenticated,omitempty"`
+
+	// Signed 应答中包含 RRSIG 记录
+	Signed bool `json:"signed,omitempty" yaml:"signed,omitempty"`
+
+	// Bogus 签名验证失败：验证型解析器返回 SERVFAIL，关闭验证 (CD) 后才得到应答
+	Bogus bool `json:"bogus,omitempty" yaml:"bogus,omitempty"`
+
 	// Error 错误信息
 	Error error `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
+// DNSSEC 验证状态
+const (
+	DNSSECAuthenticated = "AUTHENTICATED"
+	DNSSECUnsigned      = "UNSIGNED"
+	DNSSECBogus         = "BOGUS"
+	// DNSSECUnverified 应答带签名但解析器未验证（非验证型解析器或权威服务器）
+	DNSSECUnverified = "UNVERIFIED"
+)
+
+// DNSSECStatus 返回应答的 DNSSEC 验证状态
+func (r *DNSResult) DNSSECStatus() string {
+	switch {
+	case r.Bogus:
+		return DNSSECBogus
+	case r.Authenticated:
+		return DNSSECAuthenticated
+	case r.Signed:
+		return DNSSECUnverified
+	}
+	return DNSSECUnsigned
+}
+
 // DNSRecord DNS 记录
 type DNSRecord struct {
 	// Name 记录名称
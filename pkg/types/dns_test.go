@@ -0,0 +1,56 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDNSRecordType(t *testing.T) {
+	cases := map[string]DNSRecordType{
+		"a":       DNSTypeA,
+		"CAA":     DNSTypeCAA,
+		"https":   DNSTypeHTTPS,
+		"65":      DNSTypeHTTPS,
+		"257":     DNSTypeCAA,
+		"TYPE99":  DNSRecordType(99),
+		" naptr ": DNSTypeNAPTR,
+		"dnskey":  DNSTypeDNSKEY,
+		"48":      DNSTypeDNSKEY,
+	}
+	for input, want := range cases {
+		got, err := ParseDNSRecordType(input)
+		if err != nil {
+			t.Fatalf("ParseDNSRecordType(%q) error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ParseDNSRecordType(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "BOGUS", "0", "70000"} {
+		if _, err := ParseDNSRecordType(input); err == nil {
+			t.Fatalf("ParseDNSRecordType(%q) expected error", input)
+		}
+	}
+
+	if got := DNSRecordType(99).String(); got != "TYPE99" {
+		t.Fatalf("String() = %q, want TYPE99", got)
+	}
+}
+
+func TestParseDNSRecordTypes(t *testing.T) {
+	got, err := ParseDNSRecordTypes("A, aaaa,MX,A")
+	if err != nil {
+		t.Fatalf("ParseDNSRecordTypes error: %v", err)
+	}
+	want := []DNSRecordType{DNSTypeA, DNSTypeAAAA, DNSTypeMX}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseDNSRecordTypes = %v, want %v", got, want)
+	}
+
+	for _, input := range []string{"A,,MX", "A,BOGUS"} {
+		if _, err := ParseDNSRecordTypes(input); err == nil {
+			t.Fatalf("ParseDNSRecordTypes(%q) expected error", input)
+		}
+	}
+}
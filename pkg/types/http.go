@@ -5,6 +5,8 @@ package types
 
 import (
 	"time"
+
+	"github.com/catsayer/ntx/pkg/stats"
 )
 
 // HTTPOptions HTTP 请求选项
@@ -23,6 +25,12 @@ type HTTPOptions struct {
 
 	// UserAgent 默认 User-Agent
 	UserAgent string `json:"user_agent" yaml:"user_agent"`
+
+	// Resolve 拨号地址覆盖，"host:port" → "addr:port"，SNI 与 Host 保持不变
+	Resolve map[string]string `json:"resolve,omitempty" yaml:"resolve,omitempty"`
+
+	// Decompress 发送 Accept-Encoding 并解码 gzip/deflate 响应
+	Decompress bool `json:"decompress" yaml:"decompress"`
 }
 
 // HTTPResult HTTP 请求结果
@@ -60,18 +68,27 @@ type HTTPResult struct {
 	// Duration 请求耗时
 	Duration time.Duration `json:"duration" yaml:"duration"`
 
+	// Timings 各阶段耗时
+	Timings *HTTPTimings `json:"timings,omitempty" yaml:"timings,omitempty"`
+
 	// TLSUsed 是否使用 TLS
 	TLSUsed bool `json:"tls_used" yaml:"tls_used"`
 
-	// Uncompressed 是否解压缩
+	// TLS TLS 连接与服务端证书信息，明文请求为 nil
+	TLS *TLSInfo `json:"tls,omitempty" yaml:"tls,omitempty"`
+
+	// Uncompressed 响应体是否已按 Content-Encoding 解码
 	Uncompressed bool `json:"uncompressed" yaml:"uncompressed"`
 
 	// ContentType 内容类型
 	ContentType string `json:"content_type" yaml:"content_type"`
 
-	// TransferredSize 实际传输大小
+	// TransferredSize 实际传输大小（解码前）
 	TransferredSize int64 `json:"transferred_size" yaml:"transferred_size"`
 
+	// DecodedSize 解码后的响应体大小，未压缩时与 TransferredSize 相同
+	DecodedSize int64 `json:"decoded_size" yaml:"decoded_size"`
+
 	// Error 错误信息
 	Error error `json:"error,omitempty" yaml:"error,omitempty"`
 }
@@ -84,7 +101,10 @@ type HTTPBenchmarkResult struct {
 	// URL 请求 URL
 	URL string `json:"url" yaml:"url"`
 
-	// TotalRequests 总请求数
+	// Concurrency 并发请求数
+	Concurrency int `json:"concurrency" yaml:"concurrency"`
+
+	// TotalRequests 已完成的请求数
 	TotalRequests int `json:"total_requests" yaml:"total_requests"`
 
 	// SuccessCount 成功请求数
@@ -105,6 +125,24 @@ type HTTPBenchmarkResult struct {
 	// AvgDuration 平均耗时
 	AvgDuration time.Duration `json:"avg_duration" yaml:"avg_duration"`
 
+	// P50Duration 耗时中位数
+	P50Duration time.Duration `json:"p50_duration" yaml:"p50_duration"`
+
+	// P90Duration 第 90 百分位耗时
+	P90Duration time.Duration `json:"p90_duration" yaml:"p90_duration"`
+
+	// P99Duration 第 99 百分位耗时
+	P99Duration time.Duration `json:"p99_duration" yaml:"p99_duration"`
+
 	// RequestsPerSec 每秒请求数
 	RequestsPerSec float64 `json:"requests_per_sec" yaml:"requests_per_sec"`
+
+	// StatusCodes 各响应状态码的请求数
+	StatusCodes map[int]int `json:"status_codes,omitempty" yaml:"status_codes,omitempty"`
+
+	// Histogram 请求耗时分布
+	Histogram []stats.HistogramBucket `json:"histogram,omitempty" yaml:"histogram,omitempty"`
+
+	// Interrupted 测试被中断，统计仅包含已完成的请求
+	Interrupted bool `json:"interrupted,omitempty" yaml:"interrupted,omitempty"`
 }
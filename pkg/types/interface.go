@@ -20,6 +20,9 @@ type Interface struct {
 	// HardwareAddr MAC 地址
 	HardwareAddr string `json:"hardware_addr" yaml:"hardware_addr"`
 
+	// Vendor 根据 MAC 地址 OUI 推断的厂商
+	Vendor string `json:"vendor,omitempty" yaml:"vendor,omitempty"`
+
 	// IPv4Addrs IPv4 地址列表 (CIDR 格式)
 	IPv4Addrs []string `json:"ipv4_addrs,omitempty" yaml:"ipv4_addrs,omitempty"`
 
@@ -74,3 +77,24 @@ type Route struct {
 	// Metric 路由度量值
 	Metric string `json:"metric" yaml:"metric"`
 }
+
+// NetworkConfig 系统网络配置概览
+type NetworkConfig struct {
+	// DNSServers 系统配置的 DNS 服务器
+	DNSServers []string `json:"dns_servers" yaml:"dns_servers"`
+
+	// SearchDomains DNS 搜索域
+	SearchDomains []string `json:"search_domains,omitempty" yaml:"search_domains,omitempty"`
+
+	// Gateways 各网卡的默认网关
+	Gateways []*InterfaceGateway `json:"gateways,omitempty" yaml:"gateways,omitempty"`
+}
+
+// InterfaceGateway 网卡默认网关
+type InterfaceGateway struct {
+	// Interface 网卡名称
+	Interface string `json:"interface" yaml:"interface"`
+
+	// Gateway 网关地址
+	Gateway string `json:"gateway" yaml:"gateway"`
+}
@@ -0,0 +1,22 @@
+// Package types 提供邻居表（ARP/NDP）相关类型定义
+//
+// 作者: Catsayer
+package types
+
+// Neighbor 邻居表条目
+type Neighbor struct {
+	// IP 邻居 IP 地址
+	IP string `json:"ip" yaml:"ip"`
+
+	// MAC 硬件地址
+	MAC string `json:"mac" yaml:"mac"`
+
+	// Vendor 根据 OUI 推断的厂商
+	Vendor string `json:"vendor,omitempty" yaml:"vendor,omitempty"`
+
+	// Interface 所在网卡
+	Interface string `json:"interface" yaml:"interface"`
+
+	// State 条目状态（reachable/stale/incomplete/permanent 等）
+	State string `json:"state,omitempty" yaml:"state,omitempty"`
+}
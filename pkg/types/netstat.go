@@ -21,6 +21,14 @@ const (
 	StateUnknown     ConnectionState = "UNKNOWN"
 )
 
+// ConnectionStates 返回可用于过滤的连接状态（不含 UNKNOWN）
+func ConnectionStates() []ConnectionState {
+	return []ConnectionState{
+		StateEstablished, StateSynSent, StateSynRecv, StateFinWait1, StateFinWait2, StateTimeWait,
+		StateClose, StateCloseWait, StateLastAck, StateListen, StateClosing,
+	}
+}
+
 // Connection 网络连接信息
 type Connection struct {
 	// Protocol 协议类型 (tcp, tcp6, udp, udp6)
@@ -38,6 +46,9 @@ type Connection struct {
 	// RemotePort 远程端口
 	RemotePort int `json:"remote_port" yaml:"remote_port"`
 
+	// RemoteHost 远程地址反向解析得到的主机名（仅 --resolve）
+	RemoteHost string `json:"remote_host,omitempty" yaml:"remote_host,omitempty"`
+
 	// State 连接状态
 	State ConnectionState `json:"state" yaml:"state"`
 
@@ -46,6 +57,9 @@ type Connection struct {
 
 	// ProcessName 进程名称
 	ProcessName string `json:"process_name,omitempty" yaml:"process_name,omitempty"`
+
+	// Inode 套接字 inode（Linux），仅用于关联所属进程
+	Inode uint64 `json:"-" yaml:"-"`
 }
 
 // Listener 监听端口信息
@@ -88,6 +102,66 @@ type NetStatistics struct {
 
 	// TotalConnections 总连接数
 	TotalConnections int `json:"total_connections" yaml:"total_connections"`
+
+	// Counters 协议栈计数器（仅 --summary，平台不支持时为 nil）
+	Counters *StackCounters `json:"counters,omitempty" yaml:"counters,omitempty"`
+}
+
+// StackCounters 内核 TCP/UDP 协议栈累计计数器（Linux /proc/net/snmp 与 /proc/net/netstat）
+type StackCounters struct {
+	// TCPActiveOpens 主动打开的连接数
+	TCPActiveOpens uint64 `json:"tcp_active_opens" yaml:"tcp_active_opens"`
+
+	// TCPPassiveOpens 被动打开的连接数
+	TCPPassiveOpens uint64 `json:"tcp_passive_opens" yaml:"tcp_passive_opens"`
+
+	// TCPAttemptFails 连接建立失败次数
+	TCPAttemptFails uint64 `json:"tcp_attempt_fails" yaml:"tcp_attempt_fails"`
+
+	// TCPEstabResets 已建立连接被重置的次数
+	TCPEstabResets uint64 `json:"tcp_estab_resets" yaml:"tcp_estab_resets"`
+
+	// TCPInSegs 接收的分段数
+	TCPInSegs uint64 `json:"tcp_in_segs" yaml:"tcp_in_segs"`
+
+	// TCPOutSegs 发送的分段数
+	TCPOutSegs uint64 `json:"tcp_out_segs" yaml:"tcp_out_segs"`
+
+	// TCPRetransSegs 重传的分段数
+	TCPRetransSegs uint64 `json:"tcp_retrans_segs" yaml:"tcp_retrans_segs"`
+
+	// TCPInErrs 接收错误的分段数
+	TCPInErrs uint64 `json:"tcp_in_errs" yaml:"tcp_in_errs"`
+
+	// TCPOutRsts 发送的 RST 分段数
+	TCPOutRsts uint64 `json:"tcp_out_rsts" yaml:"tcp_out_rsts"`
+
+	// TCPListenOverflows 监听队列溢出次数
+	TCPListenOverflows uint64 `json:"tcp_listen_overflows" yaml:"tcp_listen_overflows"`
+
+	// TCPListenDrops 监听套接字丢弃的 SYN 数
+	TCPListenDrops uint64 `json:"tcp_listen_drops" yaml:"tcp_listen_drops"`
+
+	// TCPTimeouts 重传超时次数
+	TCPTimeouts uint64 `json:"tcp_timeouts" yaml:"tcp_timeouts"`
+
+	// UDPInDatagrams 接收的数据报数
+	UDPInDatagrams uint64 `json:"udp_in_datagrams" yaml:"udp_in_datagrams"`
+
+	// UDPOutDatagrams 发送的数据报数
+	UDPOutDatagrams uint64 `json:"udp_out_datagrams" yaml:"udp_out_datagrams"`
+
+	// UDPNoPorts 目的端口无监听的数据报数
+	UDPNoPorts uint64 `json:"udp_no_ports" yaml:"udp_no_ports"`
+
+	// UDPInErrors 接收错误的数据报数
+	UDPInErrors uint64 `json:"udp_in_errors" yaml:"udp_in_errors"`
+
+	// UDPRcvbufErrors 接收缓冲区满丢弃的数据报数
+	UDPRcvbufErrors uint64 `json:"udp_rcvbuf_errors" yaml:"udp_rcvbuf_errors"`
+
+	// UDPSndbufErrors 发送缓冲区满丢弃的数据报数
+	UDPSndbufErrors uint64 `json:"udp_sndbuf_errors" yaml:"udp_sndbuf_errors"`
 }
 
 // NetStatOptions 查询选项
@@ -8,4 +8,6 @@ const (
 	StandardMTU = 1500
 	// TCPHandshakeBytes TCP SYN+ACK 估算字节数
 	TCPHandshakeBytes = 40
+	// DefaultCaptivePortalURL 强制门户检测的控制地址，正常网络下返回 204 No Content
+	DefaultCaptivePortalURL = "http://connectivitycheck.gstatic.com/generate_204"
 )
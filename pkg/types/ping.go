@@ -26,10 +26,19 @@ type PingOptions struct {
 
 	Interval time.Duration `json:"interval" yaml:"interval"`
 
+	// Jitter 每次发送间隔在 Interval±Jitter 内随机，0 表示固定间隔
+	Jitter time.Duration `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+
+	// Flood 洪泛模式：收到响应或超时后立即发送下一个探测，忽略 Interval
+	Flood bool `json:"flood,omitempty" yaml:"flood,omitempty"`
+
 	// Timeout 超时时间
 
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
 
+	// Deadline 总运行时间上限，到期后停止发送并按已收到的响应统计，0 表示不限制
+	Deadline time.Duration `json:"deadline,omitempty" yaml:"deadline,omitempty"`
+
 	// Size 数据包大小（字节）
 
 	Size int `json:"size" yaml:"size"`
@@ -50,10 +59,26 @@ type PingOptions struct {
 
 	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
+	// SourcePort 固定的本地源端口（仅 TCP），0 表示由系统分配
+
+	SourcePort int `json:"source_port,omitempty" yaml:"source_port,omitempty"`
+
+	// TCPReadProbe 建连后（可选发送 TCPSendData）读取一个响应字节再计时，测量应用层响应（仅 TCP）
+	TCPReadProbe bool `json:"tcp_read_probe,omitempty" yaml:"tcp_read_probe,omitempty"`
+
+	// TCPSendData TCPReadProbe 模式下建连后发送的探测数据，为空时只等待服务端先发送（如 SSH/SMTP 横幅）
+	TCPSendData []byte `json:"tcp_send_data,omitempty" yaml:"tcp_send_data,omitempty"`
+
 	// DontFragment 不分片标志
 
 	DontFragment bool `json:"dont_fragment" yaml:"dont_fragment"`
 
+	// ICMPID 固定的 ICMP 标识符（仅 ICMP），0 表示使用进程 PID
+	ICMPID int `json:"icmp_id,omitempty" yaml:"icmp_id,omitempty"`
+
+	// StartSeq 起始序列号（仅 ICMP），0 表示从 1 开始
+	StartSeq int `json:"start_seq,omitempty" yaml:"start_seq,omitempty"`
+
 	// TOS 服务类型 (Type of Service)
 
 	TOS int `json:"tos,omitempty" yaml:"tos,omitempty"`
@@ -65,6 +90,23 @@ type PingOptions struct {
 	// HTTPPath HTTP 路径（HTTP Ping）
 
 	HTTPPath string `json:"http_path,omitempty" yaml:"http_path,omitempty"`
+
+	// HTTPUserAgent 覆盖默认的 User-Agent（HTTP Ping），为空时使用 NTX/<版本>
+	HTTPUserAgent string `json:"http_user_agent,omitempty" yaml:"http_user_agent,omitempty"`
+
+	// HTTPHeaders 附加请求头（HTTP Ping），Host 头会覆盖请求的 Host
+	HTTPHeaders map[string]string `json:"http_headers,omitempty" yaml:"http_headers,omitempty"`
+
+	// HTTPResolve 拨号地址覆盖（HTTP Ping），"host:port" → "addr:port"，SNI 与 Host 保持不变
+	HTTPResolve map[string]string `json:"http_resolve,omitempty" yaml:"http_resolve,omitempty"`
+
+	// HTTPExpectStatus 视为成功的状态码集合（HTTP Ping），为空时状态码 < 400 即成功
+
+	HTTPExpectStatus []int `json:"http_expect_status,omitempty" yaml:"http_expect_status,omitempty"`
+
+	// HTTPExpectBody 响应体需匹配的正则表达式（HTTP Ping），为空时不检查
+
+	HTTPExpectBody string `json:"http_expect_body,omitempty" yaml:"http_expect_body,omitempty"`
 }
 
 // DefaultPingOptions 返回默认 Ping 选项
@@ -96,6 +138,14 @@ func DefaultPingOptions() *PingOptions {
 
 }
 
+// DefaultPingTimeoutFor 返回协议对应的默认超时时间
+func DefaultPingTimeoutFor(protocol Protocol) time.Duration {
+	if protocol == ProtocolHTTP {
+		return DefaultHTTPPingTimeout
+	}
+	return DefaultPingTimeout
+}
+
 // PingReply Ping 响应
 
 type PingReply struct {
@@ -116,6 +166,16 @@ type PingReply struct {
 
 	TTL int `json:"ttl" yaml:"ttl"`
 
+	// ReceivedTOS 响应包 IP 头中的 TOS/Traffic Class（仅 ICMP），0 表示未标记或不可用
+
+	ReceivedTOS int `json:"received_tos,omitempty" yaml:"received_tos,omitempty"`
+
+	// MTU 收到 "需要分片"/"Packet Too Big" 时报告的下一跳 MTU，0 表示无或未知
+	MTU int `json:"mtu,omitempty" yaml:"mtu,omitempty"`
+
+	// Duplicate 同一序列号的重复响应（DUP!），不计入发送与接收数
+	Duplicate bool `json:"duplicate,omitempty" yaml:"duplicate,omitempty"`
+
 	// RTT 往返时间
 
 	RTT time.Duration `json:"rtt" yaml:"rtt"`
@@ -131,6 +191,31 @@ type PingReply struct {
 	// Error 错误信息
 
 	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// HTTPTimings HTTP 请求各阶段耗时（仅 HTTP Ping）
+	HTTPTimings *HTTPTimings `json:"http_timings,omitempty" yaml:"http_timings,omitempty"`
+
+	// TLS 本次探测新建 TLS 连接时的证书信息（仅 HTTPS Ping，复用连接时为空）
+	TLS *TLSInfo `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// HTTPTimings HTTP 请求各阶段耗时
+//
+// DNS/Connect/TLS 为各阶段自身的持续时间（从该阶段开始到结束），复用已有连接时为 0；
+// TTFB 与 Total 从发起请求开始计时，因此包含前面各阶段的耗时。
+type HTTPTimings struct {
+	// DNS 域名解析阶段耗时
+	DNS time.Duration `json:"dns" yaml:"dns"`
+	// Connect TCP 建连阶段耗时
+	Connect time.Duration `json:"connect" yaml:"connect"`
+	// TLS TLS 握手阶段耗时，HTTP 明文请求为 0
+	TLS time.Duration `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// TTFB 从发起请求到收到响应首字节的时间
+	TTFB time.Duration `json:"ttfb" yaml:"ttfb"`
+	// Total 从发起请求到读完响应体的时间
+	Total time.Duration `json:"total" yaml:"total"`
+	// Reused 是否复用了已有连接
+	Reused bool `json:"reused,omitempty" yaml:"reused,omitempty"`
 }
 
 // PingResult Ping 结果
@@ -206,11 +291,17 @@ func (r *PingResult) UpdateStatistics() {
 	}
 
 	statsData := r.Statistics
-	statsData.Sent = len(r.Replies)
+	statsData.Sent = 0
 	statsData.Received = 0
+	statsData.Duplicates = 0
 
 	rtts := make([]time.Duration, 0, len(r.Replies))
 	for _, reply := range r.Replies {
+		if reply.Duplicate {
+			statsData.Duplicates++
+			continue
+		}
+		statsData.Sent++
 		if reply.Status == StatusSuccess {
 			statsData.Received++
 			rtts = append(rtts, reply.RTT)
@@ -218,6 +309,9 @@ func (r *PingResult) UpdateStatistics() {
 	}
 
 	statsData.Loss = statsData.Sent - statsData.Received
+	if r.Context != nil {
+		statsData.TotalTime = r.Context.Duration
+	}
 	if statsData.Sent > 0 {
 		statsData.LossRate = float64(statsData.Loss) / float64(statsData.Sent) * 100
 	}
@@ -228,14 +322,37 @@ func (r *PingResult) UpdateStatistics() {
 		statsData.MaxRTT = max
 		statsData.AvgRTT = avg
 		statsData.StdDevRTT = stddev
+		statsData.P50RTT, statsData.P95RTT, statsData.P99RTT, statsData.JitterRTT = stats.ComputeRTTPercentiles(rtts)
 	} else {
 		statsData.MinRTT = 0
 		statsData.MaxRTT = 0
 		statsData.AvgRTT = 0
 		statsData.StdDevRTT = 0
+		statsData.P50RTT, statsData.P95RTT, statsData.P99RTT, statsData.JitterRTT = 0, 0, 0, 0
 	}
 }
 
+// MTUResult 路径 MTU 探测结果
+type MTUResult struct {
+	// Target 目标主机信息
+	Target *Host `json:"target" yaml:"target"`
+	// PathMTU 可不分片通过的最大 IP 包大小，0 表示无法确定（目标无响应）
+	PathMTU int `json:"path_mtu" yaml:"path_mtu"`
+	// MaxPayload 不分片时 ICMP Echo 可携带的最大数据长度
+	MaxPayload int `json:"max_payload" yaml:"max_payload"`
+	// LocalMTU 出口网卡的 MTU，作为搜索上界
+	LocalMTU int `json:"local_mtu" yaml:"local_mtu"`
+	// Probes 发送的探测次数
+	Probes int `json:"probes" yaml:"probes"`
+	// Duration 探测耗时
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// Determinate 是否探测到了路径 MTU
+func (r *MTUResult) Determinate() bool {
+	return r.PathMTU > 0
+}
+
 // Pinger Ping 执行器接口
 
 type Pinger interface {
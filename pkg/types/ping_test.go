@@ -12,6 +12,7 @@ func TestPingResultUpdateStatistics(t *testing.T) {
 		replies  []*PingReply
 		sent     int
 		received int
+		dups     int
 		loss     int
 		lossRate float64
 		minRTT   time.Duration
@@ -38,6 +39,22 @@ func TestPingResultUpdateStatistics(t *testing.T) {
 			avgRTT:   15 * time.Millisecond,
 			stddev:   5 * time.Millisecond,
 		},
+		{
+			name: "duplicate replies",
+			replies: []*PingReply{
+				{Seq: 1, Status: StatusSuccess, RTT: 10 * time.Millisecond},
+				{Seq: 1, Status: StatusSuccess, RTT: 30 * time.Millisecond, Duplicate: true},
+				{Seq: 2, Status: StatusTimeout},
+			},
+			sent:     2,
+			received: 1,
+			dups:     1,
+			loss:     1,
+			lossRate: 50,
+			minRTT:   10 * time.Millisecond,
+			maxRTT:   10 * time.Millisecond,
+			avgRTT:   10 * time.Millisecond,
+		},
 	}
 
 	for _, tc := range cases {
@@ -56,6 +73,9 @@ func TestPingResultUpdateStatistics(t *testing.T) {
 			if stats.Received != tc.received {
 				t.Fatalf("Received: expected %d, got %d", tc.received, stats.Received)
 			}
+			if stats.Duplicates != tc.dups {
+				t.Fatalf("Duplicates: expected %d, got %d", tc.dups, stats.Duplicates)
+			}
 			if stats.Loss != tc.loss {
 				t.Fatalf("Loss: expected %d, got %d", tc.loss, stats.Loss)
 			}
@@ -77,3 +97,14 @@ func TestPingResultUpdateStatistics(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultPingTimeoutFor(t *testing.T) {
+	if got := DefaultPingTimeoutFor(ProtocolHTTP); got != DefaultHTTPPingTimeout {
+		t.Fatalf("HTTP timeout = %v, want %v", got, DefaultHTTPPingTimeout)
+	}
+	for _, p := range []Protocol{ProtocolICMP, ProtocolTCP} {
+		if got := DefaultPingTimeoutFor(p); got != DefaultPingTimeout {
+			t.Fatalf("%s timeout = %v, want %v", p, got, DefaultPingTimeout)
+		}
+	}
+}
@@ -12,8 +12,14 @@ const (
 	ProcNetUDP = "/proc/net/udp"
 	// ProcNetUDP6 Linux UDP IPv6 表路径
 	ProcNetUDP6 = "/proc/net/udp6"
+	// ProcNetSNMP Linux 协议栈 SNMP 计数器路径
+	ProcNetSNMP = "/proc/net/snmp"
+	// ProcNetNetstat Linux 协议栈扩展计数器路径
+	ProcNetNetstat = "/proc/net/netstat"
 	// ProcNetDev Linux 网卡统计路径
 	ProcNetDev = "/proc/net/dev"
 	// ProcNetRoute Linux 路由表路径
 	ProcNetRoute = "/proc/net/route"
+	// ProcNetARP Linux ARP 表路径
+	ProcNetARP = "/proc/net/arp"
 )
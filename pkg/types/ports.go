@@ -14,6 +14,8 @@ const (
 	DefaultDNSPort = 53
 	// DefaultTraceroutePort Traceroute 默认起始端口
 	DefaultTraceroutePort = 33434
+	// DefaultBenchPort 带宽测试服务端默认端口
+	DefaultBenchPort = 5201
 	// MinPort 最小有效端口
 	MinPort = 1
 	// MaxPort 最大有效端口
@@ -22,6 +22,8 @@ const (
 	PortFiltered
 	// PortUnknown 状态未知
 	PortUnknown
+	// PortOpenFiltered 开放或被过滤（UDP 扫描未收到任何响应）
+	PortOpenFiltered
 )
 
 // String 返回端口状态的字符串表示
@@ -35,6 +37,8 @@ func (s PortState) String() string {
 		return "filtered"
 	case PortUnknown:
 		return "unknown"
+	case PortOpenFiltered:
+		return "open|filtered"
 	default:
 		return "unknown"
 	}
@@ -66,6 +70,14 @@ func (m ScanMode) String() string {
 	}
 }
 
+// 扫描时序模板
+const (
+	// ScanTimingNormal 默认时序
+	ScanTimingNormal = "normal"
+	// ScanTimingFast 快速模式（--fast），缩短超时并提高并发
+	ScanTimingFast = "fast"
+)
+
 // ScanOptions 扫描参数配置
 type ScanOptions struct {
 	// Ports 要扫描的端口列表
@@ -80,8 +92,14 @@ type ScanOptions struct {
 	ServiceDetect bool
 	// VersionDetect 是否进行版本探测
 	VersionDetect bool
+	// BannerGrab 是否读取开放端口的 Banner（HTTP 端口发送 GET 请求，其余端口读取问候语）
+	BannerGrab bool
 	// RateLimit 速率限制（每秒扫描包数）
 	RateLimit int
+	// SourcePort 固定的本地源端口，0 表示由系统分配
+	SourcePort int
+	// Timing 时序模板名称，仅用于记录，实际参数由 Timeout/Concurrency 决定
+	Timing string
 }
 
 // DefaultScanOptions 返回默认扫描选项
@@ -94,6 +112,7 @@ func DefaultScanOptions() ScanOptions {
 		ServiceDetect: false,
 		VersionDetect: false,
 		RateLimit:     0, // 0 表示不限制
+		Timing:        ScanTimingNormal,
 	}
 }
 
@@ -124,6 +143,8 @@ type ScanPort struct {
 	Banner string
 	// ResponseTime 响应时间
 	ResponseTime time.Duration
+	// Retried 是否因本地资源不足（如文件描述符耗尽）降低并发后重试过
+	Retried bool
 	// Error 错误信息（如果有）
 	Error error
 }
@@ -134,12 +155,18 @@ type ScanResult struct {
 	Target string
 	// IP 解析后的 IP 地址
 	IP net.IP
+	// Hostname IP 反向解析得到的主机名（仅 --resolve）
+	Hostname string
 	// Ports 扫描到的端口列表
 	Ports []*ScanPort
 	// StartTime 扫描开始时间
 	StartTime time.Time
 	// EndTime 扫描结束时间
 	EndTime time.Time
+	// Technique 实际使用的扫描方式（tcp-connect/tcp-syn/udp）
+	Technique string
+	// Timing 使用的时序模板
+	Timing string
 	// Summary 统计摘要
 	Summary *ScanSummary
 }
@@ -154,6 +181,8 @@ type ScanSummary struct {
 	ClosedPorts int
 	// FilteredPorts 过滤端口数
 	FilteredPorts int
+	// OpenFilteredPorts 开放或被过滤的端口数（仅 UDP 扫描）
+	OpenFilteredPorts int
 	// Duration 扫描总耗时
 	Duration time.Duration
 }
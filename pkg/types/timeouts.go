@@ -4,8 +4,10 @@ package types
 import "time"
 
 const (
-	// DefaultPingTimeout Ping 默认超时时间
+	// DefaultPingTimeout Ping 默认超时时间（ICMP/TCP）
 	DefaultPingTimeout = 5 * time.Second
+	// DefaultHTTPPingTimeout HTTP Ping 默认超时时间，需覆盖 DNS、TCP 与 TLS 握手
+	DefaultHTTPPingTimeout = 10 * time.Second
 	// DefaultScanTimeout 扫描默认超时时间
 	DefaultScanTimeout = 3 * time.Second
 	// DefaultDNSTimeout DNS 查询默认超时时间
@@ -14,9 +16,17 @@ const (
 	DefaultTraceTimeout = 3 * time.Second
 	// DefaultHTTPTimeout HTTP 客户端默认超时时间
 	DefaultHTTPTimeout = 30 * time.Second
+	// DefaultDNSCacheTTL 进程内主机名解析缓存默认有效期
+	DefaultDNSCacheTTL = 5 * time.Minute
+	// DefaultReverseDNSTimeout 单次反向 DNS 解析超时时间
+	DefaultReverseDNSTimeout = 2 * time.Second
+	// DefaultReverseDNSNegativeTTL 反向解析失败结果的缓存有效期
+	DefaultReverseDNSNegativeTTL = time.Minute
 
 	// DiagnosticGatewayTimeout 本地网关检查超时时间
 	DiagnosticGatewayTimeout = 2 * time.Second
 	// DiagnosticTargetTimeout 目标可达性检查超时时间
 	DiagnosticTargetTimeout = 3 * time.Second
+	// DiagnosticMTUTimeout 路径 MTU 检查单次探测超时时间，大包被静默丢弃时每个大小都会等满
+	DiagnosticMTUTimeout = time.Second
 )
@@ -0,0 +1,66 @@
+// Package types 定义 NTX 工具的公共类型
+//
+// 本文件定义 TLS 连接与证书信息
+//
+// 作者: Catsayer
+package types
+
+import "time"
+
+// DefaultCertExpiryWarnDays 证书剩余有效期低于该天数时给出告警
+const DefaultCertExpiryWarnDays = 30
+
+// TLSInfo TLS 连接信息
+type TLSInfo struct {
+	// Version 协议版本，如 "TLS 1.3"
+	Version string `json:"version" yaml:"version"`
+
+	// CipherSuite 协商的密码套件
+	CipherSuite string `json:"cipher_suite" yaml:"cipher_suite"`
+
+	// ServerName 客户端发送的 SNI
+	ServerName string `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+
+	// Certificates 服务端证书链，第一个为叶子证书
+	Certificates []*CertificateInfo `json:"certificates" yaml:"certificates"`
+}
+
+// Leaf 返回叶子证书，证书链为空时返回 nil
+func (t *TLSInfo) Leaf() *CertificateInfo {
+	if t == nil || len(t.Certificates) == 0 {
+		return nil
+	}
+	return t.Certificates[0]
+}
+
+// CertificateInfo X.509 证书摘要
+type CertificateInfo struct {
+	// Subject 证书主体
+	Subject string `json:"subject" yaml:"subject"`
+
+	// Issuer 签发者
+	Issuer string `json:"issuer" yaml:"issuer"`
+
+	// DNSNames SAN 中的域名
+	DNSNames []string `json:"dns_names,omitempty" yaml:"dns_names,omitempty"`
+
+	// IPAddresses SAN 中的 IP 地址
+	IPAddresses []string `json:"ip_addresses,omitempty" yaml:"ip_addresses,omitempty"`
+
+	// SerialNumber 序列号（十六进制）
+	SerialNumber string `json:"serial_number" yaml:"serial_number"`
+
+	// NotBefore 生效时间
+	NotBefore time.Time `json:"not_before" yaml:"not_before"`
+
+	// NotAfter 到期时间
+	NotAfter time.Time `json:"not_after" yaml:"not_after"`
+
+	// DaysUntilExpiry 采集时距到期的整天数，已过期为负数
+	DaysUntilExpiry int `json:"days_until_expiry" yaml:"days_until_expiry"`
+}
+
+// ExpiresWithin 判断证书是否已过期或将在 days 天内到期
+func (c *CertificateInfo) ExpiresWithin(days int) bool {
+	return c != nil && c.DaysUntilExpiry < days
+}
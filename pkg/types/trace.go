@@ -28,6 +28,16 @@ type TraceOptions struct {
 	FirstTTL int `json:"first_ttl" yaml:"first_ttl"`
 	// DontFragment 不分片标志
 	DontFragment bool `json:"dont_fragment" yaml:"dont_fragment"`
+	// NoResolve 不对各跳地址做反向 DNS 解析
+	NoResolve bool `json:"no_resolve,omitempty" yaml:"no_resolve,omitempty"`
+	// Paris 在整个追踪中保持流标识不变（Paris traceroute），避免 ECMP 负载均衡造成虚假跳
+	Paris bool `json:"paris,omitempty" yaml:"paris,omitempty"`
+	// FlowID Paris 模式下的 ICMP 流标识，不同取值可能经过不同的等价路径
+	FlowID int `json:"flow_id,omitempty" yaml:"flow_id,omitempty"`
+	// Parallel 并发探测各跳而非逐跳等待，缩短总耗时（仅 ICMP 支持）
+	Parallel bool `json:"parallel,omitempty" yaml:"parallel,omitempty"`
+	// ResolveASN 查询各跳地址所属的 AS 与国家
+	ResolveASN bool `json:"resolve_asn,omitempty" yaml:"resolve_asn,omitempty"`
 }
 
 // DefaultTraceOptions 返回默认 Traceroute 选项
@@ -57,6 +67,12 @@ type TraceHop struct {
 	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
 	// IsDestination 是否为目标主机
 	IsDestination bool `json:"is_destination" yaml:"is_destination"`
+	// ASN 响应地址所属的 AS 号（如 "15169"），仅在启用 ResolveASN 时填充
+	ASN string `json:"asn,omitempty" yaml:"asn,omitempty"`
+	// ASName AS 名称
+	ASName string `json:"as_name,omitempty" yaml:"as_name,omitempty"`
+	// Country 国家代码 (ISO 3166)
+	Country string `json:"country,omitempty" yaml:"country,omitempty"`
 }
 
 // TraceProbe 单次探测结果
@@ -71,6 +87,8 @@ type TraceProbe struct {
 	Status Status `json:"status" yaml:"status"`
 	// Error 错误信息
 	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// PortState 到达目标时的端口状态（open/closed），仅 TCP 追踪设置
+	PortState string `json:"port_state,omitempty" yaml:"port_state,omitempty"`
 }
 
 // GetBestProbe 获取最佳探测结果（最快响应）
@@ -170,3 +188,63 @@ type Tracer interface {
 	// Close 关闭资源
 	Close() error
 }
+
+// TraceHopAddress 多轮 Traceroute 中某一跳出现过的地址
+type TraceHopAddress struct {
+	// IP 响应地址
+	IP string `json:"ip" yaml:"ip"`
+	// Hostname 主机名（未解析时与 IP 相同）
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	// Responses 该地址的响应次数
+	Responses int `json:"responses" yaml:"responses"`
+}
+
+// TraceHopStats 多轮 Traceroute 中某一跳的汇总统计
+type TraceHopStats struct {
+	// TTL Time To Live
+	TTL int `json:"ttl" yaml:"ttl"`
+	// Runs 探测到该跳的轮数（到达目标后的 TTL 不再探测）
+	Runs int `json:"runs" yaml:"runs"`
+	// Responded 该跳有响应的轮数
+	Responded int `json:"responded" yaml:"responded"`
+	// AppearanceRate 出现率 (0-100)
+	AppearanceRate float64 `json:"appearance_rate" yaml:"appearance_rate"`
+	// Addresses 出现过的地址，按首次出现顺序
+	Addresses []*TraceHopAddress `json:"addresses" yaml:"addresses"`
+	// MinRTT/AvgRTT/MaxRTT 所有成功探测的 RTT 统计
+	MinRTT time.Duration `json:"min_rtt" yaml:"min_rtt"`
+	AvgRTT time.Duration `json:"avg_rtt" yaml:"avg_rtt"`
+	MaxRTT time.Duration `json:"max_rtt" yaml:"max_rtt"`
+	// Stable 所有响应均来自同一地址
+	Stable bool `json:"stable" yaml:"stable"`
+}
+
+// TracePathChange 相邻两轮之间某一跳的地址变化
+type TracePathChange struct {
+	// Run 发生变化的轮次（从 1 开始）
+	Run int `json:"run" yaml:"run"`
+	// TTL 发生变化的跳
+	TTL int `json:"ttl" yaml:"ttl"`
+	// From 上一轮的地址
+	From string `json:"from" yaml:"from"`
+	// To 本轮的地址
+	To string `json:"to" yaml:"to"`
+}
+
+// TraceAggregate 多轮 Traceroute 的汇总结果
+type TraceAggregate struct {
+	// Target 目标主机信息
+	Target *Host `json:"target" yaml:"target"`
+	// Protocol 使用的协议
+	Protocol Protocol `json:"protocol" yaml:"protocol"`
+	// Runs 轮数
+	Runs int `json:"runs" yaml:"runs"`
+	// Reached 到达目标的轮数
+	Reached int `json:"reached" yaml:"reached"`
+	// Hops 各跳统计
+	Hops []*TraceHopStats `json:"hops" yaml:"hops"`
+	// Changes 路径变化记录
+	Changes []*TracePathChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	// Results 各轮原始结果
+	Results []*TraceResult `json:"results,omitempty" yaml:"results,omitempty"`
+}
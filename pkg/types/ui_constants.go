@@ -28,4 +28,5 @@ const (
 	ColumnWidthTraceHost  = 40
 	ColumnWidthTraceProbe = 12
 	ColumnWidthTraceAvg   = 8
+	ColumnWidthTraceAS    = 24
 )
@@ -6,6 +6,7 @@
 package types
 
 import (
+	"math"
 	"net"
 	"time"
 )
@@ -28,8 +29,12 @@ type WhoisOptions struct {
 	Server string
 	// Timeout 查询超时时间
 	Timeout time.Duration
-	// FollowReferrals 是否跟随 referral
+	// FollowReferrals 是否跟随响应中的注册商 Whois 服务器（Registrar WHOIS Server）再次查询
 	FollowReferrals bool
+	// Concurrency 批量查询时的最大并发数（同一 Whois 服务器的查询始终串行）
+	Concurrency int
+	// RateLimit 批量查询时同一 Whois 服务器两次查询之间的最小间隔，0 表示不限制
+	RateLimit time.Duration
 }
 
 // DefaultWhoisOptions 返回默认 Whois 选项
@@ -38,6 +43,8 @@ func DefaultWhoisOptions() WhoisOptions {
 		Server:          "",
 		Timeout:         10 * time.Second,
 		FollowReferrals: true,
+		Concurrency:     4,
+		RateLimit:       time.Second,
 	}
 }
 
@@ -51,12 +58,18 @@ type WhoisResult struct {
 	Server string
 	// RawResponse 原始响应数据
 	RawResponse string
+	// ReferralServer 跟随查询的注册商 Whois 服务器，未跟随时为空
+	ReferralServer string
+	// ReferralResponse 注册商 Whois 服务器的原始响应
+	ReferralResponse string
 	// ParsedData 解析后的数据
 	ParsedData *WhoisData
 	// QueryTime 查询耗时
 	QueryTime time.Duration
 	// Timestamp 查询时间戳
 	Timestamp time.Time
+	// Error 批量查询中该项失败时的错误信息
+	Error string
 }
 
 // WhoisData 解析后的 Whois 数据
@@ -89,3 +102,11 @@ type WhoisData struct {
 	AdminContact string `json:"admin_contact,omitempty"`
 	TechContact  string `json:"tech_contact,omitempty"`
 }
+
+// DaysUntilExpiry 返回距到期日的整天数（已过期为负数），到期日期未知时 ok 为 false
+func (d *WhoisData) DaysUntilExpiry(now time.Time) (days int, ok bool) {
+	if d == nil || d.ExpirationDate.IsZero() {
+		return 0, false
+	}
+	return int(math.Floor(d.ExpirationDate.Sub(now).Hours() / 24)), true
+}